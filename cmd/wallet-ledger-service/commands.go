@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/config"
+	"github.com/kodra-pay/wallet-ledger-service/internal/container"
+	"github.com/kodra-pay/wallet-ledger-service/internal/migrate"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+	"github.com/kodra-pay/wallet-ledger-service/migrations"
+)
+
+// command is a maintenance subcommand run instead of the HTTP server. Commands
+// that only touch the schema set runDB so they work before the services can
+// be built, e.g. on an empty database.
+type command struct {
+	usage string
+	run   func(ctx context.Context, c *container.Container, args []string) (exitCode int, err error)
+	runDB func(ctx context.Context, db *sql.DB, args []string) (exitCode int, err error)
+}
+
+var commands = map[string]command{
+	"verify-chain": {
+		usage: "verify-chain [-wallet-id N]   walk the ledger hash chains and report the first broken link",
+		run:   runVerifyChain,
+	},
+	"checkpoint": {
+		usage: "checkpoint                    sign a checkpoint of every wallet's chain head now",
+		run:   runCheckpoint,
+	},
+	"consistency-check": {
+		usage: "consistency-check [-format json|csv] [-output FILE]   recompute wallet balances from the ledger and report drift",
+		run:   runConsistencyCheck,
+	},
+	"migrate": {
+		usage: "migrate up|down [-steps N]|status   apply, roll back or list the embedded schema migrations",
+		runDB: runMigrate,
+	},
+}
+
+// runCommand executes a subcommand and returns the process exit code
+func runCommand(cfg config.Config, name string, args []string) int {
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\nusage: wallet-ledger-service [command]\n\ncommands:\n", name)
+		names := make([]string, 0, len(commands))
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(os.Stderr, "  %s\n", commands[n].usage)
+		}
+		return 2
+	}
+
+	db, err := repositories.InitDB(cfg.PostgresDSN)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	var code int
+	if cmd.runDB != nil {
+		code, err = cmd.runDB(context.Background(), db, args)
+	} else {
+		c, buildErr := container.New(cfg, db)
+		if buildErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to build services: %v\n", buildErr)
+			return 1
+		}
+		code, err = cmd.run(context.Background(), c, args)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	return code
+}
+
+func runVerifyChain(ctx context.Context, c *container.Container, args []string) (int, error) {
+	fs := flag.NewFlagSet("verify-chain", flag.ContinueOnError)
+	walletID := fs.Int("wallet-id", 0, "verify a single wallet instead of all wallets")
+	if err := fs.Parse(args); err != nil {
+		return 2, nil
+	}
+
+	if *walletID != 0 {
+		result, err := c.LedgerIntegrityService.VerifyWallet(ctx, *walletID)
+		if err != nil {
+			return 1, err
+		}
+		printJSON(result)
+		if !result.Valid {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	report, err := c.LedgerIntegrityService.VerifyAll(ctx)
+	if err != nil {
+		return 1, err
+	}
+	printJSON(report)
+	if !report.Valid {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func runCheckpoint(ctx context.Context, c *container.Container, _ []string) (int, error) {
+	checkpoint, err := c.LedgerIntegrityService.CreateCheckpoint(ctx)
+	if err != nil {
+		return 1, err
+	}
+	if checkpoint == nil {
+		fmt.Println("no new ledger entries since the last checkpoint")
+		return 0, nil
+	}
+	printJSON(checkpoint)
+	return 0, nil
+}
+
+func runConsistencyCheck(ctx context.Context, c *container.Container, args []string) (int, error) {
+	fs := flag.NewFlagSet("consistency-check", flag.ContinueOnError)
+	format := fs.String("format", "json", "report format: json or csv")
+	output := fs.String("output", "", "write the report to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2, nil
+	}
+	if *format != "json" && *format != "csv" {
+		return 2, fmt.Errorf("unknown format %q", *format)
+	}
+
+	report, err := c.ConsistencyService.Check(ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			return 1, err
+		}
+		defer out.Close()
+	}
+	if *format == "csv" {
+		err = services.WriteConsistencyCSV(out, report)
+	} else {
+		err = writeJSON(out, report)
+	}
+	if err != nil {
+		return 1, err
+	}
+	if !report.OK {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func runMigrate(ctx context.Context, db *sql.DB, args []string) (int, error) {
+	if len(args) == 0 {
+		return 2, errors.New("expected up, down or status")
+	}
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return 1, err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return 1, err
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return 0, nil
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2, nil
+		}
+		rolledBack, err := migrator.Down(ctx, *steps)
+		for _, m := range rolledBack {
+			fmt.Printf("rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return 1, err
+		}
+		return 0, nil
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return 1, err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		code := 0
+		for _, s := range statuses {
+			state, appliedAt := "pending", ""
+			if s.AppliedAt != nil {
+				state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+			}
+			switch {
+			case s.Missing:
+				state = "applied, missing from binary"
+			case s.Modified:
+				state, code = "applied, modified since", 1
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return code, w.Flush()
+	default:
+		return 2, fmt.Errorf("unknown migrate action %q", args[0])
+	}
+}
+
+func printJSON(v interface{}) {
+	_ = writeJSON(os.Stdout, v)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -1,18 +1,31 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata" // Schedule time zones must resolve in the minimal runtime image
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/kodra-pay/wallet-ledger-service/internal/config"
+	"github.com/kodra-pay/wallet-ledger-service/internal/container"
 	"github.com/kodra-pay/wallet-ledger-service/internal/middleware"
+	"github.com/kodra-pay/wallet-ledger-service/internal/migrate"
 	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
 	"github.com/kodra-pay/wallet-ledger-service/internal/routes"
+	"github.com/kodra-pay/wallet-ledger-service/migrations"
 )
 
 func main() {
 	cfg := config.Load("wallet-ledger-service", "7007")
 
+	// Maintenance subcommands, e.g. `wallet-ledger-service verify-chain`
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(cfg, os.Args[1], os.Args[2:]))
+	}
+
 	// Initialize database
 	db, err := repositories.InitDB(cfg.PostgresDSN)
 	if err != nil {
@@ -20,11 +33,37 @@ func main() {
 	}
 	defer db.Close()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.AutoMigrate {
+		migrator, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		log.Printf("applied %d migrations", len(applied))
+	}
+
+	c, err := container.New(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to build services: %v", err)
+	}
+	c.StartWorkers(ctx)
+
 	app := fiber.New()
 	app.Use(middleware.RequestID())
 
-	// Pass the database instance to the routes registration
-	routes.Register(app, cfg.ServiceName, db)
+	// Pass the service container to the routes registration
+	routes.Register(app, cfg.ServiceName, c)
+
+	go func() {
+		<-ctx.Done()
+		_ = app.Shutdown()
+	}()
 
 	log.Printf("%s listening on :%s", cfg.ServiceName, cfg.Port)
 	if err := app.Listen(":" + cfg.Port); err != nil {
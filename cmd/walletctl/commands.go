@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/container"
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// command is one walletctl subcommand. run returns the value to print.
+type command struct {
+	usage string
+	run   func(ctx context.Context, c *container.Container, args []string) (interface{}, error)
+}
+
+// usageError reports bad command-line arguments
+type usageError struct{ error }
+
+var commands = map[string]command{
+	"create-wallet": {
+		usage: "create-wallet -user-id N -currency CUR",
+		run:   runCreateWallet,
+	},
+	"get-wallet": {
+		usage: "get-wallet -wallet-id N",
+		run:   runGetWallet,
+	},
+	"find-wallet": {
+		usage: "find-wallet -user-id N -currency CUR",
+		run:   runFindWallet,
+	},
+	"freeze-wallet": {
+		usage: "freeze-wallet -wallet-id N -reason TEXT",
+		run:   statusCommand(models.WalletStatusFrozen),
+	},
+	"unfreeze-wallet": {
+		usage: "unfreeze-wallet -wallet-id N -reason TEXT",
+		run:   statusCommand(models.WalletStatusActive),
+	},
+	"close-wallet": {
+		usage: "close-wallet -wallet-id N -reason TEXT",
+		run:   statusCommand(models.WalletStatusClosed),
+	},
+	"propose-adjustment": {
+		usage: "propose-adjustment -wallet-id N -type credit|debit -amount N -reason TEXT [-reference N]",
+		run:   runProposeAdjustment,
+	},
+	"approve-adjustment": {
+		usage: "approve-adjustment -adjustment-id N [-comment TEXT]",
+		run: decisionCommand("approve-adjustment", func(c *container.Container) func(context.Context, int, dto.AdjustmentDecisionRequest) (*dto.AdjustmentResponse, error) {
+			return c.AdjustmentService.Approve
+		}),
+	},
+	"reject-adjustment": {
+		usage: "reject-adjustment -adjustment-id N [-comment TEXT]",
+		run: decisionCommand("reject-adjustment", func(c *container.Container) func(context.Context, int, dto.AdjustmentDecisionRequest) (*dto.AdjustmentResponse, error) {
+			return c.AdjustmentService.Reject
+		}),
+	},
+	"get-adjustment": {
+		usage: "get-adjustment -adjustment-id N",
+		run:   runGetAdjustment,
+	},
+	"list-adjustments": {
+		usage: "list-adjustments [-wallet-id N] [-status STATUS] [-limit N]",
+		run:   runListAdjustments,
+	},
+	"reverse": {
+		usage: "reverse -wallet-id N -entry-id N -reason TEXT [-reference N]",
+		run:   runReverse,
+	},
+	"statement": {
+		usage: "statement -wallet-id N [-from DATE] [-to DATE]",
+		run:   runStatement,
+	},
+	"consistency-check": {
+		usage: "consistency-check",
+		run:   runConsistencyCheck,
+	},
+	"tax-summary": {
+		usage: "tax-summary -from DATE -to DATE",
+		run:   runTaxSummary,
+	},
+}
+
+// parse parses a subcommand's flags and checks that every required flag was set
+func parse(fs *flag.FlagSet, args []string, required ...string) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, name := range required {
+		if !set[name] {
+			return usageError{fmt.Errorf("-%s is required", name)}
+		}
+	}
+	return nil
+}
+
+func runCreateWallet(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("create-wallet", flag.ContinueOnError)
+	userID := fs.Int("user-id", 0, "owner of the wallet")
+	currency := fs.String("currency", "", "wallet currency")
+	if err := parse(fs, args, "user-id", "currency"); err != nil {
+		return nil, err
+	}
+	return c.WalletService.CreateWallet(ctx, dto.CreateWalletRequest{UserID: *userID, Currency: *currency})
+}
+
+func runGetWallet(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("get-wallet", flag.ContinueOnError)
+	walletID := fs.Int("wallet-id", 0, "wallet to show")
+	if err := parse(fs, args, "wallet-id"); err != nil {
+		return nil, err
+	}
+	return c.WalletService.GetWalletByID(ctx, *walletID)
+}
+
+func runFindWallet(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("find-wallet", flag.ContinueOnError)
+	userID := fs.Int("user-id", 0, "owner of the wallet")
+	currency := fs.String("currency", "", "wallet currency")
+	if err := parse(fs, args, "user-id", "currency"); err != nil {
+		return nil, err
+	}
+	return c.WalletService.GetWalletByUserIDAndCurrency(ctx, *userID, *currency)
+}
+
+// statusCommand builds the freeze, unfreeze and close commands
+func statusCommand(status string) func(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	return func(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+		fs := flag.NewFlagSet(status, flag.ContinueOnError)
+		walletID := fs.Int("wallet-id", 0, "wallet to update")
+		reason := fs.String("reason", "", "why the status is changing")
+		if err := parse(fs, args, "wallet-id", "reason"); err != nil {
+			return nil, err
+		}
+		return c.WalletService.SetWalletStatus(ctx, *walletID, dto.UpdateWalletStatusRequest{Status: status, Reason: *reason})
+	}
+}
+
+func runProposeAdjustment(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("propose-adjustment", flag.ContinueOnError)
+	walletID := fs.Int("wallet-id", 0, "wallet to adjust")
+	entryType := fs.String("type", "", "credit or debit")
+	amount := fs.Int64("amount", 0, "amount in the smallest currency unit")
+	reason := fs.String("reason", "", "why the adjustment is needed")
+	reference := fs.Int("reference", 0, "external reference, e.g. a support ticket number")
+	if err := parse(fs, args, "wallet-id", "type", "amount", "reason"); err != nil {
+		return nil, err
+	}
+	return c.AdjustmentService.Propose(ctx, dto.ProposeAdjustmentRequest{
+		WalletID:  *walletID,
+		Type:      *entryType,
+		Amount:    *amount,
+		Reason:    *reason,
+		Reference: *reference,
+	})
+}
+
+// decisionCommand builds the approve and reject commands
+func decisionCommand(name string, decide func(*container.Container) func(context.Context, int, dto.AdjustmentDecisionRequest) (*dto.AdjustmentResponse, error)) func(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	return func(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		adjustmentID := fs.Int("adjustment-id", 0, "adjustment to decide on")
+		comment := fs.String("comment", "", "note recorded with the decision")
+		if err := parse(fs, args, "adjustment-id"); err != nil {
+			return nil, err
+		}
+		return decide(c)(ctx, *adjustmentID, dto.AdjustmentDecisionRequest{Comment: *comment})
+	}
+}
+
+func runGetAdjustment(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("get-adjustment", flag.ContinueOnError)
+	adjustmentID := fs.Int("adjustment-id", 0, "adjustment to show")
+	if err := parse(fs, args, "adjustment-id"); err != nil {
+		return nil, err
+	}
+	return c.AdjustmentService.GetAdjustment(ctx, *adjustmentID)
+}
+
+func runListAdjustments(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("list-adjustments", flag.ContinueOnError)
+	walletID := fs.Int("wallet-id", 0, "only adjustments to this wallet")
+	status := fs.String("status", models.AdjustmentPending, "pending, approved, rejected, expired, or empty for all")
+	limit := fs.Int("limit", 100, "maximum number of adjustments")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	return c.AdjustmentService.ListAdjustments(ctx, models.AdjustmentFilter{WalletID: *walletID, Status: *status, Limit: *limit})
+}
+
+func runReverse(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("reverse", flag.ContinueOnError)
+	walletID := fs.Int("wallet-id", 0, "wallet the entry belongs to")
+	entryID := fs.Int("entry-id", 0, "ledger entry to reverse")
+	reason := fs.String("reason", "", "why the entry is reversed")
+	reference := fs.Int("reference", 0, "reference for the reversal; defaults to the original entry's")
+	if err := parse(fs, args, "wallet-id", "entry-id", "reason"); err != nil {
+		return nil, err
+	}
+	return c.WalletService.ReverseLedgerEntry(ctx, *walletID, *entryID, dto.ReverseEntryRequest{Reason: *reason, Reference: *reference})
+}
+
+func runStatement(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("statement", flag.ContinueOnError)
+	walletID := fs.Int("wallet-id", 0, "wallet to export")
+	fromFlag := fs.String("from", "", "start of the period (YYYY-MM-DD or RFC 3339), inclusive")
+	toFlag := fs.String("to", "", "end of the period (YYYY-MM-DD or RFC 3339), exclusive")
+	if err := parse(fs, args, "wallet-id"); err != nil {
+		return nil, err
+	}
+	from, err := parseTime(*fromFlag)
+	if err != nil {
+		return nil, usageError{fmt.Errorf("-from: %w", err)}
+	}
+	to, err := parseTime(*toFlag)
+	if err != nil {
+		return nil, usageError{fmt.Errorf("-to: %w", err)}
+	}
+	return c.WalletService.GetStatement(ctx, *walletID, from, to)
+}
+
+func runConsistencyCheck(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	if err := parse(flag.NewFlagSet("consistency-check", flag.ContinueOnError), args); err != nil {
+		return nil, err
+	}
+	return c.ConsistencyService.Check(ctx)
+}
+
+func runTaxSummary(ctx context.Context, c *container.Container, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("tax-summary", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", "start of the filing period (YYYY-MM-DD or RFC 3339), inclusive")
+	toFlag := fs.String("to", "", "end of the filing period (YYYY-MM-DD or RFC 3339), exclusive")
+	if err := parse(fs, args, "from", "to"); err != nil {
+		return nil, err
+	}
+	from, err := parseTime(*fromFlag)
+	if err != nil {
+		return nil, usageError{fmt.Errorf("-from: %w", err)}
+	}
+	to, err := parseTime(*toFlag)
+	if err != nil {
+		return nil, usageError{fmt.Errorf("-to: %w", err)}
+	}
+	return c.TaxService.Summary(ctx, from, to)
+}
+
+// parseTime accepts a date or an RFC 3339 timestamp; empty means unbounded
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("expected YYYY-MM-DD or an RFC 3339 timestamp")
+}
@@ -0,0 +1,140 @@
+// Command walletctl is the operator CLI for support tasks on the wallet ledger.
+// It talks to the database directly through the same services as the HTTP API,
+// so every change is validated, posted as a balanced journal and audited.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/auth"
+	"github.com/kodra-pay/wallet-ledger-service/internal/config"
+	"github.com/kodra-pay/wallet-ledger-service/internal/container"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// errDryRun rolls back the command's transaction after it ran successfully
+var errDryRun = errors.New("dry run")
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("walletctl", flag.ContinueOnError)
+	output := fs.String("o", "table", "output format: table or json")
+	dryRun := fs.Bool("dry-run", false, "run the command, print the result, then roll back")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		usage(fs)
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "unknown output format %q\n", *output)
+		return 2
+	}
+	// The operator is the OS account running the command, never a flag, so
+	// one person cannot propose an adjustment and approve it under another name
+	actor, err := operatorName()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot identify the operator: %v\n", err)
+		return 1
+	}
+
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+		usage(fs)
+		return 2
+	}
+
+	cfg := config.Load("walletctl", "")
+	db, err := repositories.InitDB(cfg.PostgresDSN)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	c, err := container.New(cfg, db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build services: %v\n", err)
+		return 1
+	}
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Type:   auth.PrincipalOperator,
+		ID:     actor,
+		Scopes: []string{auth.ScopeAdmin},
+	})
+
+	// The whole command runs in one transaction so a dry run can roll it all back
+	var result interface{}
+	err = c.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = cmd.run(ctx, c, fs.Args()[1:]); err != nil {
+			return err
+		}
+		if *dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	var usageErr usageError
+	switch {
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(os.Stderr, "%s: %v\nusage: walletctl %s\n", name, err, cmd.usage)
+		return 2
+	case errors.Is(err, errDryRun):
+		defer fmt.Fprintln(os.Stderr, "dry run: all changes were rolled back")
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+
+	if err := render(os.Stdout, *output, result); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	if report, ok := result.(*models.ConsistencyReport); ok && !report.OK {
+		return 1
+	}
+	return 0
+}
+
+// operatorName returns the login of the OS user running walletctl. $USER is
+// not consulted since anyone can set it.
+func operatorName() (string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	if u.Username == "" {
+		return "", fmt.Errorf("user %s has no login name", u.Uid)
+	}
+	return u.Username, nil
+}
+
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintf(os.Stderr, "usage: walletctl [flags] <command> [command flags]\n\nflags:\n")
+	fs.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[n].usage)
+	}
+}
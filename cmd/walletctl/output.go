@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// render prints a command result as indented JSON or as an aligned table
+func render(w io.Writer, format string, v interface{}) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	switch v := v.(type) {
+	case *dto.WalletResponse:
+		walletTable(tw, v)
+	case *dto.LedgerEntryResponse:
+		entryTable(tw, []dto.LedgerEntryResponse{*v})
+	case *dto.StatementResponse:
+		walletTable(tw, v.Wallet)
+		fmt.Fprintf(tw, "\nPERIOD\t%s - %s\n", formatBound(v.From), formatBound(v.To))
+		fmt.Fprintf(tw, "OPENING BALANCE\t%d\n", v.OpeningBalance)
+		fmt.Fprintf(tw, "CLOSING BALANCE\t%d\n\n", v.ClosingBalance)
+		entryTable(tw, v.Entries)
+	case *dto.AdjustmentResponse:
+		adjustmentTable(tw, []dto.AdjustmentResponse{*v})
+		if len(v.Decisions) > 0 {
+			fmt.Fprintln(tw, "\nDECISION\tACTOR\tAT\tCOMMENT")
+			for _, d := range v.Decisions {
+				fmt.Fprintf(tw, "%s\t%s:%s\t%s\t%s\n", d.Decision, d.ActorType, d.ActorID, d.CreatedAt.Format(time.RFC3339), d.Comment)
+			}
+		}
+	case []dto.AdjustmentResponse:
+		adjustmentTable(tw, v)
+	case *models.ConsistencyReport:
+		consistencyTable(tw, v)
+	case *models.TaxSummary:
+		taxSummaryTable(tw, v)
+	default:
+		return fmt.Errorf("no table format for %T; use -o json", v)
+	}
+	return tw.Flush()
+}
+
+func walletTable(w io.Writer, wallet *dto.WalletResponse) {
+	fmt.Fprintln(w, "ID\tUSER ID\tTYPE\tCURRENCY\tBALANCE\tSTATUS\tUPDATED AT")
+	fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d\t%s\t%s\n", wallet.ID, wallet.UserID, wallet.Type, wallet.Currency,
+		wallet.Balance, wallet.Status, wallet.UpdatedAt.Format(time.RFC3339))
+}
+
+func entryTable(w io.Writer, entries []dto.LedgerEntryResponse) {
+	fmt.Fprintln(w, "ID\tCREATED AT\tTYPE\tAMOUNT\tBALANCE\tREFERENCE\tREVERSAL OF\tDESCRIPTION")
+	for _, e := range entries {
+		reversalOf := ""
+		if e.ReversalOf != nil {
+			reversalOf = strconv.Itoa(*e.ReversalOf)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%d\t%s\t%s\n", e.ID, e.CreatedAt.Format(time.RFC3339), e.Type,
+			e.Amount, e.Balance, e.Reference, reversalOf, e.Description)
+	}
+}
+
+func adjustmentTable(w io.Writer, adjustments []dto.AdjustmentResponse) {
+	fmt.Fprintln(w, "ID\tWALLET ID\tTYPE\tAMOUNT\tSTATUS\tAPPROVALS\tPROPOSED BY\tEXPIRES AT\tLEDGER ENTRY\tREASON")
+	for _, a := range adjustments {
+		entryID := ""
+		if a.LedgerEntryID != nil {
+			entryID = strconv.Itoa(*a.LedgerEntryID)
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%s\t%d/%d\t%s:%s\t%s\t%s\t%s\n", a.ID, a.WalletID, a.Type, a.Amount, a.Status, a.Approvals, a.RequiredApprovals,
+			a.ProposedByType, a.ProposedByID, a.ExpiresAt.Format(time.RFC3339), entryID, a.Reason)
+	}
+}
+
+func consistencyTable(w io.Writer, report *models.ConsistencyReport) {
+	fmt.Fprintf(w, "REPORT\t%d\n", report.ID)
+	fmt.Fprintf(w, "OK\t%t\n", report.OK)
+	fmt.Fprintf(w, "WALLETS CHECKED\t%d\n", report.WalletsChecked)
+	fmt.Fprintf(w, "ENTRIES CHECKED\t%d\n", report.EntriesChecked)
+	fmt.Fprintf(w, "JOURNALS CHECKED\t%d\n", report.JournalsChecked)
+	fmt.Fprintf(w, "LEGACY ENTRIES\t%d\n", report.LegacyEntries)
+	if len(report.Discrepancies) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nKIND\tWALLET ID\tENTRY ID\tJOURNAL ID\tCURRENCY\tEXPECTED\tACTUAL")
+	for _, d := range report.Discrepancies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\n", d.Kind, optionalID(d.WalletID), optionalID(d.EntryID),
+			d.JournalID, d.Currency, d.Expected, d.Actual)
+	}
+}
+
+func taxSummaryTable(w io.Writer, summary *models.TaxSummary) {
+	fmt.Fprintf(w, "PERIOD\t%s - %s\n\n", summary.From.Format(time.RFC3339), summary.To.Format(time.RFC3339))
+	fmt.Fprintln(w, "JURISDICTION\tTAX\tCURRENCY\tRATE (BPS)\tINCLUSIVE\tPOSTINGS\tBASE\tTAX AMOUNT")
+	for _, l := range summary.Lines {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\t%d\t%d\t%d\n", l.Jurisdiction, l.TaxName, l.Currency, l.RateBPS, l.Inclusive,
+			l.Postings, l.BaseAmount, l.TaxAmount)
+	}
+}
+
+func formatBound(t *time.Time) string {
+	if t == nil {
+		return "*"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func optionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// apiKeyPrefix marks strings that are Kodra API keys, e.g. "kpk_3f9a1c2b.<secret>"
+const apiKeyPrefix = "kpk_"
+
+var (
+	ErrMalformedAPIKey    = errors.New("malformed api key")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// GenerateAPIKey returns a new plaintext API key and its public lookup prefix.
+// Only the hash of the key is ever stored.
+func GenerateAPIKey() (key, prefix string, err error) {
+	idBytes := make([]byte, 4)
+	secretBytes := make([]byte, 24)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(idBytes)
+	return apiKeyPrefix + prefix + "." + hex.EncodeToString(secretBytes), prefix, nil
+}
+
+// ParseAPIKeyPrefix extracts the lookup prefix from a plaintext API key
+func ParseAPIKeyPrefix(key string) (string, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return "", ErrMalformedAPIKey
+	}
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(key, apiKeyPrefix), ".")
+	if !ok || prefix == "" || secret == "" {
+		return "", ErrMalformedAPIKey
+	}
+	return prefix, nil
+}
+
+// HashAPIKey returns the hex SHA-256 digest stored for an API key. API keys are
+// high-entropy random strings, so a fast hash is sufficient.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// clockSkew is the leeway applied to exp and nbf checks
+const clockSkew = 30 * time.Second
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// JWTConfig describes where verification keys come from and which claims are required
+type JWTConfig struct {
+	HS256Secret      string // Shared secret for HS256 tokens
+	JWKSFile         string // JSON Web Key Set file with RSA ("RSA") and/or HMAC ("oct") keys
+	RSAPublicKeyFile string // PEM-encoded RSA public key for RS256 tokens
+	Issuer           string // Required "iss" claim, if set
+	Audience         string // Required "aud" claim, if set
+}
+
+// JWTVerifier validates HS256 and RS256 JSON Web Tokens
+type JWTVerifier struct {
+	hmacKeys map[string][]byte         // kid -> secret ("" for keys without a kid)
+	rsaKeys  map[string]*rsa.PublicKey // kid -> public key
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewJWTVerifier loads verification keys from cfg
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		hmacKeys: map[string][]byte{},
+		rsaKeys:  map[string]*rsa.PublicKey{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		now:      time.Now,
+	}
+	if cfg.HS256Secret != "" {
+		v.hmacKeys[""] = []byte(cfg.HS256Secret)
+	}
+	if cfg.RSAPublicKeyFile != "" {
+		key, err := loadRSAPublicKeyPEM(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load rsa public key: %w", err)
+		}
+		v.rsaKeys[""] = key
+	}
+	if cfg.JWKSFile != "" {
+		if err := v.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, fmt.Errorf("load jwks: %w", err)
+		}
+	}
+	return v, nil
+}
+
+// Enabled reports whether any verification key is configured
+func (v *JWTVerifier) Enabled() bool {
+	return len(v.hmacKeys) > 0 || len(v.rsaKeys) > 0
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+	Name      string          `json:"name"`
+	Scope     string          `json:"scope"`  // Space-delimited (RFC 8693 style)
+	Scopes    []string        `json:"scopes"` // Array form used by some issuers
+}
+
+// Verify checks the token signature and standard claims and returns the caller
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected three segments", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	if err := v.verifySignature(header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := v.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	scopes := claims.Scopes
+	if claims.Scope != "" {
+		scopes = append(scopes, strings.Fields(claims.Scope)...)
+	}
+	return &Principal{Type: PrincipalUser, ID: claims.Subject, Name: claims.Name, Scopes: scopes}, nil
+}
+
+func (v *JWTVerifier) verifySignature(header jwtHeader, signingInput, signature []byte) error {
+	switch header.Alg {
+	case "HS256":
+		for kid, secret := range v.hmacKeys {
+			if header.Kid != "" && kid != "" && kid != header.Kid {
+				continue
+			}
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(signingInput)
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return nil
+			}
+		}
+	case "RS256":
+		digest := sha256.Sum256(signingInput)
+		for kid, key := range v.rsaKeys {
+			if header.Kid != "" && kid != "" && kid != header.Kid {
+				continue
+			}
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, header.Alg)
+	}
+	return fmt.Errorf("%w: signature verification failed", ErrInvalidToken)
+}
+
+func (v *JWTVerifier) validateClaims(claims *jwtClaims) error {
+	now := v.now()
+	if claims.Subject == "" {
+		return fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+	if claims.ExpiresAt == nil || now.After(time.Unix(*claims.ExpiresAt, 0).Add(clockSkew)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if claims.NotBefore != nil && now.Add(clockSkew).Before(time.Unix(*claims.NotBefore, 0)) {
+		return fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.audience != "" && !audienceContains(claims.Audience, v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+// audienceContains handles "aud" as either a string or an array of strings
+func audienceContains(raw json.RawMessage, want string) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == want
+	}
+	var many []string
+	if json.Unmarshal(raw, &many) == nil {
+		for _, aud := range many {
+			if aud == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		K   string `json:"k"`
+	} `json:"keys"`
+}
+
+func (v *JWTVerifier) loadJWKS(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var set jwks
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return err
+	}
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		switch key.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return fmt.Errorf("key %q: modulus: %w", key.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return fmt.Errorf("key %q: exponent: %w", key.Kid, err)
+			}
+			v.rsaKeys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return fmt.Errorf("key %q: secret: %w", key.Kid, err)
+			}
+			v.hmacKeys[key.Kid] = secret
+		}
+	}
+	return nil
+}
+
+func loadRSAPublicKeyPEM(path string) (*rsa.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("PEM block is not an RSA public key")
+	}
+	return key, nil
+}
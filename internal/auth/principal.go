@@ -0,0 +1,31 @@
+package auth
+
+import "context"
+
+// Principal types
+const (
+	PrincipalAPIKey   = "api_key"  // Service-to-service caller authenticated with an API key
+	PrincipalUser     = "user"     // End user authenticated with a JWT
+	PrincipalOperator = "operator" // Support engineer using the walletctl admin CLI
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Type   string   `json:"type"`
+	ID     string   `json:"id"` // API key ID or JWT subject
+	Name   string   `json:"name,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
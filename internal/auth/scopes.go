@@ -0,0 +1,57 @@
+package auth
+
+import "strconv"
+
+// Scopes granted to API keys and JWTs
+const (
+	ScopeWalletsRead    = "wallets:read"        // Read wallets and their ledgers
+	ScopeWalletsWrite   = "wallets:write"       // Create wallets
+	ScopeLedgerPost     = "ledger:post"         // Credit and debit wallets
+	ScopeLedgerReverse  = "ledger:reverse"      // Reverse posted ledger entries
+	ScopeWebhooksManage = "webhooks:manage"     // Manage webhook endpoints and deliveries
+	ScopeAdjustPropose  = "adjustments:propose" // Propose manual credits and debits
+	ScopeAdjustApprove  = "adjustments:approve" // Approve or reject proposed adjustments
+	ScopePayoutsReport  = "payouts:report"      // Report payout outcomes (payout provider callbacks)
+	ScopeAdmin          = "admin"               // Implies every other scope
+)
+
+var knownScopes = map[string]bool{
+	ScopeWalletsRead:    true,
+	ScopeWalletsWrite:   true,
+	ScopeLedgerPost:     true,
+	ScopeLedgerReverse:  true,
+	ScopeWebhooksManage: true,
+	ScopeAdjustPropose:  true,
+	ScopeAdjustApprove:  true,
+	ScopePayoutsReport:  true,
+	ScopeAdmin:          true,
+}
+
+// IsKnownScope reports whether scope is one this service enforces
+func IsKnownScope(scope string) bool {
+	return knownScopes[scope]
+}
+
+// HasScope reports whether the principal was granted scope, directly or via admin
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnerID returns the wallet user ID an end-user principal is restricted to.
+// ok is false for service principals, which may act on any wallet.
+func (p *Principal) OwnerID() (id int, ok bool) {
+	if p.Type != PrincipalUser || p.HasScope(ScopeAdmin) {
+		return 0, false
+	}
+	id, err := strconv.Atoi(p.ID)
+	if err != nil {
+		// A subject that is not a user ID can never own a wallet
+		return -1, true
+	}
+	return id, true
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -10,6 +12,90 @@ type Config struct {
 	Port        string
 	PostgresDSN string
 	RedisAddr   string
+	AutoMigrate bool // Apply pending migrations on startup
+
+	// Webhook delivery settings
+	WebhookPollInterval time.Duration
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookBackoffBase  time.Duration
+	WebhookBackoffMax   time.Duration
+	WebhookAllowPrivate bool // Allow receivers on loopback, link-local and private addresses, for local development
+
+	// Authentication settings
+	AuthBootstrapAPIKey    string
+	AuthJWTHS256Secret     string
+	AuthJWKSFile           string
+	AuthJWTRSAPublicKeyPEM string
+	AuthJWTIssuer          string
+	AuthJWTAudience        string
+
+	// Ledger integrity settings
+	LedgerSigningKeyFile     string
+	LedgerCheckpointInterval time.Duration
+
+	// Fee settings
+	FeeScheduleFile string // JSON fee schedule; empty charges no fees
+	TaxRulesFile    string // JSON tax rules levied on fees; empty levies no tax
+
+	// Limit settings
+	LimitsFile string // JSON limits per KYC tier; empty sets no tier limits
+
+	// Risk settings
+	RiskRulesFile string // JSON risk rules; empty runs no rules
+
+	// Sanctions screening settings
+	ScreeningWatchlistFile string // Watchlist CSV, e.g. the OFAC SDN list; empty disables screening
+	ScreeningThreshold     int    // Minimum fuzzy match score, out of 100
+
+	// Maker-checker adjustment settings
+	AdjustmentApprovalTiers  string        // "min_amount:approvals" pairs, e.g. "0:1,100000:2"
+	AdjustmentTTL            time.Duration // How long a proposal stays open
+	AdjustmentExpiryInterval time.Duration
+
+	// Scheduled transfer settings
+	TransferSchedulePollInterval time.Duration
+	TransferRetryInterval        time.Duration // Wait before retrying an occurrence that lacked funds
+	TransferMaxRetries           int           // Default retries per occurrence
+
+	// Escrow settings
+	EscrowReleaseInterval time.Duration // How often timed escrow releases are processed
+
+	// Merchant settlement settings
+	SettlementDefaultSchedule string        // "T+N", "weekly:<day>" or "manual"
+	SettlementTimezone        string        // Business days are counted in this zone
+	SettlementHolidaysFile    string        // One YYYY-MM-DD per line; empty observes weekends only
+	SettlementInterval        time.Duration // How often due settlements are paid out
+
+	// Rolling reserve settings
+	ReserveReleaseInterval time.Duration // How often matured reserves are released
+
+	// Dispute settings
+	DisputeProvisionalCreditDays int           // Days before an undecided dispute is provisionally credited; 0 never credits automatically
+	DisputeResolutionDays        int           // Days a dispute has to be decided before it is overdue
+	DisputeDeadlineInterval      time.Duration // How often provisional credit deadlines are processed
+
+	// Credit lot settings
+	CreditLotSpendOrder     string        // Comma-separated lot types and "cash", spent first to last
+	CreditLotExpiryInterval time.Duration // How often expired credit lots are written off
+
+	// Currency and loyalty settings
+	CurrenciesFile        string        // JSON list of extra currency units; PTS is built in
+	LoyaltyProgramFile    string        // JSON loyalty program; empty earns no points
+	LoyaltyExpiryInterval time.Duration // How often expired points are written off
+
+	// Interest settings
+	InterestPlansFile       string        // JSON interest rate plans; empty offers none
+	InterestAccrualInterval time.Duration // How often ended days are accrued on savings wallets
+
+	// Payout settings
+	PayoutProvider       string        // Only "fake" is built in
+	PayoutSubmitInterval time.Duration // How often requested payouts are sent to the provider
+	PayoutMaxAttempts    int           // Submissions before a payout fails
+	PayoutRetryInterval  time.Duration // Wait after a temporary provider error
+
+	// Consistency check settings
+	ConsistencyCheckInterval time.Duration // 0 disables the scheduled check
 }
 
 func Load(serviceName, defaultPort string) Config {
@@ -36,6 +122,72 @@ func Load(serviceName, defaultPort string) Config {
 		Port:        getEnv("PORT", defaultPort),
 		PostgresDSN: dsn,
 		RedisAddr:   getEnv("REDIS_ADDR", "redis:6379"),
+		AutoMigrate: getEnvBool("AUTO_MIGRATE", false),
+
+		WebhookPollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+		WebhookTimeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookMaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookBackoffBase:  getEnvDuration("WEBHOOK_BACKOFF_BASE", 30*time.Second),
+		WebhookBackoffMax:   getEnvDuration("WEBHOOK_BACKOFF_MAX", 6*time.Hour),
+		WebhookAllowPrivate: getEnvBool("WEBHOOK_ALLOW_PRIVATE_RECEIVERS", false),
+
+		AuthBootstrapAPIKey:    getEnv("AUTH_BOOTSTRAP_API_KEY", ""),
+		AuthJWTHS256Secret:     getEnv("AUTH_JWT_HS256_SECRET", ""),
+		AuthJWKSFile:           getEnv("AUTH_JWKS_FILE", ""),
+		AuthJWTRSAPublicKeyPEM: getEnv("AUTH_JWT_RSA_PUBLIC_KEY_FILE", ""),
+		AuthJWTIssuer:          getEnv("AUTH_JWT_ISSUER", ""),
+		AuthJWTAudience:        getEnv("AUTH_JWT_AUDIENCE", ""),
+
+		LedgerSigningKeyFile:     getEnv("LEDGER_SIGNING_KEY_FILE", ""),
+		LedgerCheckpointInterval: getEnvDuration("LEDGER_CHECKPOINT_INTERVAL", time.Hour),
+
+		FeeScheduleFile: getEnv("FEE_SCHEDULE_FILE", ""),
+		TaxRulesFile:    getEnv("TAX_RULES_FILE", ""),
+
+		LimitsFile: getEnv("LIMITS_FILE", ""),
+
+		RiskRulesFile: getEnv("RISK_RULES_FILE", ""),
+
+		ScreeningWatchlistFile: getEnv("SCREENING_WATCHLIST_FILE", ""),
+		ScreeningThreshold:     getEnvInt("SCREENING_THRESHOLD", 85),
+
+		AdjustmentApprovalTiers:  getEnv("ADJUSTMENT_APPROVAL_TIERS", "0:1,100000:2"),
+		AdjustmentTTL:            getEnvDuration("ADJUSTMENT_TTL", 72*time.Hour),
+		AdjustmentExpiryInterval: getEnvDuration("ADJUSTMENT_EXPIRY_INTERVAL", 5*time.Minute),
+
+		TransferSchedulePollInterval: getEnvDuration("TRANSFER_SCHEDULE_POLL_INTERVAL", time.Minute),
+		TransferRetryInterval:        getEnvDuration("TRANSFER_RETRY_INTERVAL", time.Hour),
+		TransferMaxRetries:           getEnvInt("TRANSFER_MAX_RETRIES", 3),
+
+		EscrowReleaseInterval: getEnvDuration("ESCROW_RELEASE_INTERVAL", time.Minute),
+
+		SettlementDefaultSchedule: getEnv("SETTLEMENT_DEFAULT_SCHEDULE", "T+1"),
+		SettlementTimezone:        getEnv("SETTLEMENT_TIMEZONE", "UTC"),
+		SettlementHolidaysFile:    getEnv("SETTLEMENT_HOLIDAYS_FILE", ""),
+		SettlementInterval:        getEnvDuration("SETTLEMENT_INTERVAL", 15*time.Minute),
+
+		ReserveReleaseInterval: getEnvDuration("RESERVE_RELEASE_INTERVAL", time.Minute),
+
+		DisputeProvisionalCreditDays: getEnvInt("DISPUTE_PROVISIONAL_CREDIT_DAYS", 10),
+		DisputeResolutionDays:        getEnvInt("DISPUTE_RESOLUTION_DAYS", 45),
+		DisputeDeadlineInterval:      getEnvDuration("DISPUTE_DEADLINE_INTERVAL", 5*time.Minute),
+
+		CreditLotSpendOrder:     getEnv("CREDIT_LOT_SPEND_ORDER", "promo,cashback,cash"),
+		CreditLotExpiryInterval: getEnvDuration("CREDIT_LOT_EXPIRY_INTERVAL", 5*time.Minute),
+
+		CurrenciesFile:        getEnv("CURRENCIES_FILE", ""),
+		LoyaltyProgramFile:    getEnv("LOYALTY_PROGRAM_FILE", ""),
+		LoyaltyExpiryInterval: getEnvDuration("LOYALTY_EXPIRY_INTERVAL", 5*time.Minute),
+
+		InterestPlansFile:       getEnv("INTEREST_PLANS_FILE", ""),
+		InterestAccrualInterval: getEnvDuration("INTEREST_ACCRUAL_INTERVAL", time.Hour),
+
+		PayoutProvider:       getEnv("PAYOUT_PROVIDER", "fake"),
+		PayoutSubmitInterval: getEnvDuration("PAYOUT_SUBMIT_INTERVAL", 30*time.Second),
+		PayoutMaxAttempts:    getEnvInt("PAYOUT_MAX_ATTEMPTS", 5),
+		PayoutRetryInterval:  getEnvDuration("PAYOUT_RETRY_INTERVAL", 5*time.Minute),
+
+		ConsistencyCheckInterval: getEnvDuration("CONSISTENCY_CHECK_INTERVAL", 6*time.Hour),
 	}
 }
 
@@ -45,3 +197,30 @@ func getEnv(key, def string) string {
 	}
 	return def
 }
+
+func getEnvInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+func getEnvBool(key string, def bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
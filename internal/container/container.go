@@ -0,0 +1,270 @@
+package container
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/auth"
+	"github.com/kodra-pay/wallet-ledger-service/internal/config"
+	"github.com/kodra-pay/wallet-ledger-service/internal/metrics"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+	"github.com/kodra-pay/wallet-ledger-service/internal/worker"
+)
+
+// Container wires repositories and services together so that the HTTP server,
+// background workers and CLI commands share a single object graph.
+type Container struct {
+	Config config.Config
+	DB     *sql.DB
+
+	JWTVerifier *auth.JWTVerifier
+
+	Transactor repositories.Transactor
+
+	AuditService   *services.AuditService
+	WalletService  *services.WalletService
+	WebhookService *services.WebhookService
+	APIKeyService  *services.APIKeyService
+	TaxService     *services.TaxService
+	RiskService    *services.RiskService
+
+	AdjustmentService       *services.AdjustmentService
+	TransferScheduleService *services.TransferScheduleService
+	EscrowService           *services.EscrowService
+	SettlementService       *services.SettlementService
+	ReserveService          *services.ReserveService
+	PayoutService           *services.PayoutService
+	DisputeService          *services.DisputeService
+	CreditLotService        *services.CreditLotService
+	LoyaltyService          *services.LoyaltyService
+	InterestService         *services.InterestService
+	PocketService           *services.PocketService
+
+	LedgerIntegrityService *services.LedgerIntegrityService
+	ConsistencyService     *services.ConsistencyService
+
+	Metrics *metrics.Registry
+}
+
+// New builds the service graph on top of an open database
+func New(cfg config.Config, db *sql.DB) (*Container, error) {
+	jwtVerifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		HS256Secret:      cfg.AuthJWTHS256Secret,
+		JWKSFile:         cfg.AuthJWKSFile,
+		RSAPublicKeyFile: cfg.AuthJWTRSAPublicKeyPEM,
+		Issuer:           cfg.AuthJWTIssuer,
+		Audience:         cfg.AuthJWTAudience,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jwt verifier: %w", err)
+	}
+
+	var signingKey ed25519.PrivateKey
+	if cfg.LedgerSigningKeyFile != "" {
+		if signingKey, err = services.LoadSigningKey(cfg.LedgerSigningKeyFile); err != nil {
+			return nil, fmt.Errorf("ledger signing key: %w", err)
+		}
+	}
+
+	approvalTiers, err := services.ParseApprovalTiers(cfg.AdjustmentApprovalTiers)
+	if err != nil {
+		return nil, fmt.Errorf("adjustment approval tiers: %w", err)
+	}
+
+	var feeSchedule *services.FeeSchedule
+	if cfg.FeeScheduleFile != "" {
+		if feeSchedule, err = services.LoadFeeSchedule(cfg.FeeScheduleFile); err != nil {
+			return nil, fmt.Errorf("fee schedule: %w", err)
+		}
+	}
+
+	var taxRules *services.TaxRules
+	if cfg.TaxRulesFile != "" {
+		if taxRules, err = services.LoadTaxRules(cfg.TaxRulesFile); err != nil {
+			return nil, fmt.Errorf("tax rules: %w", err)
+		}
+	}
+
+	var limitConfig *services.LimitConfig
+	if cfg.LimitsFile != "" {
+		if limitConfig, err = services.LoadLimitConfig(cfg.LimitsFile); err != nil {
+			return nil, fmt.Errorf("limits: %w", err)
+		}
+	}
+
+	riskRepo := repositories.NewPostgresRiskRepository(db)
+	var riskRules []services.Rule
+	if cfg.RiskRulesFile != "" {
+		if riskRules, err = services.LoadRiskRules(cfg.RiskRulesFile, riskRepo); err != nil {
+			return nil, fmt.Errorf("risk rules: %w", err)
+		}
+	}
+
+	var screener services.Screener
+	if cfg.ScreeningWatchlistFile != "" {
+		watchlist, err := services.LoadWatchlist(cfg.ScreeningWatchlistFile)
+		if err != nil {
+			return nil, fmt.Errorf("screening watchlist: %w", err)
+		}
+		screener = services.NewWatchlistScreener(watchlist, cfg.ScreeningThreshold)
+	}
+
+	var currencyUnits []models.Currency
+	if cfg.CurrenciesFile != "" {
+		if currencyUnits, err = services.LoadCurrencies(cfg.CurrenciesFile); err != nil {
+			return nil, fmt.Errorf("currencies: %w", err)
+		}
+	}
+	currencies, err := services.NewCurrencyRegistry(currencyUnits)
+	if err != nil {
+		return nil, fmt.Errorf("currencies: %w", err)
+	}
+	var loyaltyProgram *services.LoyaltyProgram
+	if cfg.LoyaltyProgramFile != "" {
+		if loyaltyProgram, err = services.LoadLoyaltyProgram(cfg.LoyaltyProgramFile, currencies); err != nil {
+			return nil, fmt.Errorf("loyalty program: %w", err)
+		}
+	}
+
+	var interestConfig *services.InterestConfig
+	if cfg.InterestPlansFile != "" {
+		if interestConfig, err = services.LoadInterestConfig(cfg.InterestPlansFile); err != nil {
+			return nil, fmt.Errorf("interest plans: %w", err)
+		}
+	}
+
+	lotSpendOrder, err := services.ParseLotSpendOrder(cfg.CreditLotSpendOrder)
+	if err != nil {
+		return nil, fmt.Errorf("credit lot spend order: %w", err)
+	}
+
+	settlementSchedule, err := services.ParseSettlementSchedule(cfg.SettlementDefaultSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("settlement default schedule: %w", err)
+	}
+	settlementLoc, err := time.LoadLocation(cfg.SettlementTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("settlement timezone: %w", err)
+	}
+	var holidays []time.Time
+	if cfg.SettlementHolidaysFile != "" {
+		if holidays, err = services.LoadHolidays(cfg.SettlementHolidaysFile); err != nil {
+			return nil, fmt.Errorf("settlement holidays: %w", err)
+		}
+	}
+
+	var payoutProvider services.PayoutProvider
+	switch cfg.PayoutProvider {
+	case "fake":
+		payoutProvider = services.NewFakePayoutProvider()
+	default:
+		return nil, fmt.Errorf("payout provider: unknown provider %q", cfg.PayoutProvider)
+	}
+
+	walletRepo := repositories.NewPostgresWalletRepository(db)
+	webhookRepo := repositories.NewPostgresWebhookRepository(db)
+	apiKeyRepo := repositories.NewPostgresAPIKeyRepository(db)
+	auditRepo := repositories.NewPostgresAuditRepository(db)
+	creditLotRepo := repositories.NewPostgresCreditLotRepository(db)
+	loyaltyRepo := repositories.NewPostgresLoyaltyRepository(db)
+	pocketRepo := repositories.NewPostgresPocketRepository(db)
+	tx := repositories.NewTransactor(db)
+
+	auditService := services.NewAuditService(auditRepo)
+	webhookService := services.NewWebhookService(webhookRepo, tx, auditService, services.WebhookConfig{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BackoffBase: cfg.WebhookBackoffBase,
+		BackoffMax:  cfg.WebhookBackoffMax,
+		Timeout:     cfg.WebhookTimeout,
+
+		AllowPrivateReceivers: cfg.WebhookAllowPrivate,
+	})
+	taxService := services.NewTaxService(taxRules, repositories.NewPostgresTaxRepository(db))
+	riskService := services.NewRiskService(riskRules, riskRepo, tx, auditService)
+	walletService := services.NewWalletService(walletRepo, tx,
+		services.WithEventPublisher(webhookService),
+		services.WithAuditService(auditService),
+		services.WithFeeSchedule(feeSchedule),
+		services.WithTaxService(taxService),
+		services.WithLimits(services.NewLimitService(limitConfig, repositories.NewPostgresLimitRepository(db))),
+		services.WithRiskService(riskService),
+		services.WithScreening(screener, repositories.NewPostgresScreeningRepository(db)),
+		services.WithCreditLots(creditLotRepo, lotSpendOrder),
+		services.WithCurrencies(currencies),
+		services.WithLoyalty(loyaltyProgram, loyaltyRepo),
+		services.WithPockets(pocketRepo),
+	)
+	reserveService := services.NewReserveService(repositories.NewPostgresReserveRepository(db), walletService, tx, auditService)
+
+	return &Container{
+		Config:         cfg,
+		DB:             db,
+		JWTVerifier:    jwtVerifier,
+		Transactor:     tx,
+		AuditService:   auditService,
+		WalletService:  walletService,
+		WebhookService: webhookService,
+		APIKeyService:  services.NewAPIKeyService(apiKeyRepo, tx, auditService, cfg.AuthBootstrapAPIKey),
+		TaxService:     taxService,
+		RiskService:    riskService,
+
+		AdjustmentService: services.NewAdjustmentService(repositories.NewPostgresAdjustmentRepository(db), walletService, tx, auditService, services.AdjustmentConfig{
+			Tiers: approvalTiers,
+			TTL:   cfg.AdjustmentTTL,
+		}),
+		TransferScheduleService: services.NewTransferScheduleService(repositories.NewPostgresTransferScheduleRepository(db), walletService, tx, auditService, services.TransferScheduleConfig{
+			MaxRetries:    cfg.TransferMaxRetries,
+			RetryInterval: cfg.TransferRetryInterval,
+		}),
+		EscrowService: services.NewEscrowService(repositories.NewPostgresEscrowRepository(db), walletService, tx, auditService),
+		SettlementService: services.NewSettlementService(repositories.NewPostgresSettlementRepository(db), walletService, tx, auditService, services.SettlementConfig{
+			DefaultSchedule: settlementSchedule,
+			Calendar:        services.NewSettlementCalendar(settlementLoc, holidays),
+			Reserves:        reserveService,
+		}),
+		ReserveService: reserveService,
+		PayoutService: services.NewPayoutService(repositories.NewPostgresPayoutRepository(db), walletService, tx, auditService, payoutProvider, services.PayoutConfig{
+			MaxAttempts:   cfg.PayoutMaxAttempts,
+			RetryInterval: cfg.PayoutRetryInterval,
+		}),
+		DisputeService: services.NewDisputeService(repositories.NewPostgresDisputeRepository(db), walletService, tx, auditService, services.DisputeConfig{
+			ProvisionalCreditAfter: time.Duration(cfg.DisputeProvisionalCreditDays) * 24 * time.Hour,
+			ResolutionWindow:       time.Duration(cfg.DisputeResolutionDays) * 24 * time.Hour,
+		}),
+		CreditLotService: services.NewCreditLotService(creditLotRepo, walletService, tx, auditService),
+		LoyaltyService:   services.NewLoyaltyService(loyaltyRepo, walletService, tx, auditService, loyaltyProgram),
+		InterestService:  services.NewInterestService(repositories.NewPostgresInterestRepository(db), walletService, tx, auditService, interestConfig),
+		PocketService:    services.NewPocketService(pocketRepo, walletService, tx, auditService),
+
+		LedgerIntegrityService: services.NewLedgerIntegrityService(walletRepo, repositories.NewPostgresLedgerIntegrityRepository(db), signingKey),
+		ConsistencyService:     services.NewConsistencyService(repositories.NewPostgresConsistencyRepository(db), metrics.Default),
+
+		Metrics: metrics.Default,
+	}, nil
+}
+
+// StartWorkers launches the background jobs; they stop when ctx is cancelled
+func (c *Container) StartWorkers(ctx context.Context) {
+	go worker.Run(ctx, "webhook-delivery", c.Config.WebhookPollInterval, c.WebhookService.ProcessDueDeliveries)
+	go worker.Run(ctx, "adjustment-expiry", c.Config.AdjustmentExpiryInterval, c.AdjustmentService.ExpireDue)
+	go worker.Run(ctx, "transfer-schedules", c.Config.TransferSchedulePollInterval, c.TransferScheduleService.RunDue)
+	go worker.Run(ctx, "escrow-release", c.Config.EscrowReleaseInterval, c.EscrowService.ReleaseDue)
+	go worker.Run(ctx, "settlement", c.Config.SettlementInterval, c.SettlementService.RunDue)
+	go worker.Run(ctx, "reserve-release", c.Config.ReserveReleaseInterval, c.ReserveService.ReleaseDue)
+	go worker.Run(ctx, "payout-submit", c.Config.PayoutSubmitInterval, c.PayoutService.SubmitDue)
+	go worker.Run(ctx, "dispute-deadlines", c.Config.DisputeDeadlineInterval, c.DisputeService.CreditDue)
+	go worker.Run(ctx, "credit-lot-expiry", c.Config.CreditLotExpiryInterval, c.CreditLotService.ExpireDue)
+	go worker.Run(ctx, "points-expiry", c.Config.LoyaltyExpiryInterval, c.LoyaltyService.ExpireDue)
+	go worker.Run(ctx, "interest-accrual", c.Config.InterestAccrualInterval, c.InterestService.AccrueDue)
+	if c.LedgerIntegrityService.SigningEnabled() {
+		go worker.Run(ctx, "ledger-checkpoint", c.Config.LedgerCheckpointInterval, c.LedgerIntegrityService.RunCheckpoint)
+	}
+	if c.Config.ConsistencyCheckInterval > 0 {
+		go worker.Run(ctx, "ledger-consistency", c.Config.ConsistencyCheckInterval, c.ConsistencyService.RunCheck)
+	}
+}
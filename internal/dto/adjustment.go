@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/kodra-pay/wallet-ledger-service/internal/models"
+
+// ProposeAdjustmentRequest DTO for proposing a manual credit or debit
+type ProposeAdjustmentRequest struct {
+	WalletID  int    `json:"wallet_id"`
+	Type      string `json:"type"` // "credit" or "debit"
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason"`
+	Reference int    `json:"reference"` // Optional, e.g. a support ticket number
+}
+
+// AdjustmentDecisionRequest DTO for approving or rejecting a proposed adjustment
+type AdjustmentDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// AdjustmentResponse DTO for returning an adjustment request with its approval chain
+type AdjustmentResponse struct {
+	models.AdjustmentRequest
+	Approvals int                         `json:"approvals"`
+	Decisions []models.AdjustmentDecision `json:"decisions"` // Oldest first
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest DTO for issuing a new API key
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// RotateAPIKeyRequest DTO for rotating an API key. The old key keeps working
+// for GracePeriodSeconds so callers can roll the new key out.
+type RotateAPIKeyRequest struct {
+	GracePeriodSeconds int `json:"grace_period_seconds"`
+}
+
+// APIKeyResponse DTO for returning API key metadata.
+// Key holds the plaintext key and is only populated on create and rotate.
+type APIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RotatedTo  *int       `json:"rotated_to,omitempty"`
+}
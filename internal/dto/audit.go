@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditRecordResponse DTO for returning an audit log record
+type AuditRecordResponse struct {
+	ID         int             `json:"id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    string          `json:"actor_id"`
+	RequestID  string          `json:"request_id,omitempty"`
+	SourceIP   string          `json:"source_ip,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
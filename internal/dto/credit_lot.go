@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// IssueCreditLotRequest DTO for crediting a wallet with a promotional credit lot
+type IssueCreditLotRequest struct {
+	Type         string                       `json:"type"`   // "promo" or "cashback"
+	Source       string                       `json:"source"` // e.g. the campaign issuing the credit
+	Amount       int64                        `json:"amount"`
+	ExpiresAt    *time.Time                   `json:"expires_at"` // Omit for credit that never expires
+	Restrictions models.CreditLotRestrictions `json:"restrictions"`
+	Reference    int                          `json:"reference"`
+	Description  string                       `json:"description"`
+}
+
+// CreditLotResponse DTO for returning a credit lot and its movements
+type CreditLotResponse struct {
+	models.CreditLot
+	Movements []models.CreditLotMovement `json:"movements,omitempty"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/kodra-pay/wallet-ledger-service/internal/models"
+
+// OpenDisputeRequest DTO for disputing a debit
+type OpenDisputeRequest struct {
+	WalletID      int    `json:"wallet_id"`
+	LedgerEntryID int    `json:"ledger_entry_id"`
+	Amount        int64  `json:"amount"` // Defaults to the part of the debit not already disputed
+	Reason        string `json:"reason"`
+}
+
+// DisputeActionRequest DTO for granting a provisional credit on, or withdrawing, a dispute
+type DisputeActionRequest struct {
+	Reference int    `json:"reference"` // Defaults to the dispute ID
+	Reason    string `json:"reason"`
+}
+
+// ResolveDisputeRequest DTO for deciding a dispute
+type ResolveDisputeRequest struct {
+	Outcome   string `json:"outcome"`   // "won" or "lost", from the customer's side
+	Reference int    `json:"reference"` // Defaults to the dispute ID
+	Note      string `json:"note"`
+}
+
+// DisputeResponse DTO for returning a dispute and its postings
+type DisputeResponse struct {
+	models.Dispute
+	Overdue   bool                     `json:"overdue"` // Still open past its resolve-by time
+	Movements []models.DisputeMovement `json:"movements,omitempty"`
+}
@@ -4,8 +4,9 @@ import "time"
 
 // CreateWalletRequest DTO for creating a new wallet
 type CreateWalletRequest struct {
-	UserID   int    `json:"user_id"`
-	Currency string `json:"currency"`
+	UserID     int    `json:"user_id"`
+	Currency   string `json:"currency"`
+	HolderName string `json:"holder_name"` // Screened against sanctions watchlists when screening is enabled
 }
 
 // UpdateBalanceRequest DTO for updating a wallet's balance (credit/debit)
@@ -18,22 +19,115 @@ type UpdateBalanceRequest struct {
 
 // WalletResponse DTO for returning wallet information
 type WalletResponse struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	Currency  string    `json:"currency"`
-	Balance   int64     `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	Type       string    `json:"type"`
+	SystemCode string    `json:"system_code,omitempty"`
+	KYCTier    string    `json:"kyc_tier,omitempty"`
+	Segment    string    `json:"segment,omitempty"`
+	HolderName string    `json:"holder_name,omitempty"`
+	Currency   string    `json:"currency"`
+	AssetType  string    `json:"asset_type"`
+	Balance    int64     `json:"balance"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // LedgerEntryResponse DTO for returning a ledger entry
 type LedgerEntryResponse struct {
 	ID          int       `json:"id"`
 	WalletID    int       `json:"wallet_id"`
+	JournalID   string    `json:"journal_id,omitempty"`
 	Reference   int       `json:"reference"`
 	Type        string    `json:"type"`
 	Amount      int64     `json:"amount"`
 	Balance     int64     `json:"balance"`
 	Description string    `json:"description"`
+	ReversalOf  *int      `json:"reversal_of,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
-}
\ No newline at end of file
+}
+
+// UpdateWalletStatusRequest DTO for freezing, unfreezing or closing a wallet
+type UpdateWalletStatusRequest struct {
+	Status string `json:"status"` // "active", "frozen" or "closed"
+	Reason string `json:"reason"`
+}
+
+// ReverseEntryRequest DTO for reversing a posted ledger entry
+type ReverseEntryRequest struct {
+	Reason    string `json:"reason"`
+	Reference int    `json:"reference"` // Optional; defaults to the original entry's reference
+}
+// AdjustBalanceRequest DTO for a manual operator correction to a wallet balance
+type AdjustBalanceRequest struct {
+	Type      string `json:"type"` // "credit" or "debit"
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason"`
+	Reference int    `json:"reference"`
+}
+
+// StatementResponse DTO for a wallet statement over a period
+type StatementResponse struct {
+	Wallet         *WalletResponse       `json:"wallet"`
+	From           *time.Time            `json:"from,omitempty"`
+	To             *time.Time            `json:"to,omitempty"`
+	OpeningBalance int64                 `json:"opening_balance"`
+	ClosingBalance int64                 `json:"closing_balance"`
+	Entries        []LedgerEntryResponse `json:"entries"` // Oldest first
+}
+
+// TransferRequest DTO for moving money between two wallets
+type TransferRequest struct {
+	FromWalletID int    `json:"from_wallet_id"`
+	ToWalletID   int    `json:"to_wallet_id"`
+	Amount       int64  `json:"amount"`
+	Reference    int    `json:"reference"`
+	Description  string `json:"description"`
+}
+
+// Transfer statuses
+const (
+	TransferPosted        = "posted"
+	TransferPendingReview = "pending_review" // Held by sanctions screening; posted if the review clears it
+)
+
+// TransferResponse DTO for a posted or held transfer
+type TransferResponse struct {
+	Status            string               `json:"status"`
+	JournalID         string               `json:"journal_id,omitempty"`
+	Debit             *LedgerEntryResponse `json:"debit,omitempty"`
+	Credit            *LedgerEntryResponse `json:"credit,omitempty"`
+	Fee               *FeeBreakdown        `json:"fee,omitempty"` // Charged to the sender on top of the amount
+	ScreeningReviewID int                  `json:"screening_review_id,omitempty"`
+}
+
+// BalanceUpdateResponse DTO for a credit or debit, with the fee it was charged
+type BalanceUpdateResponse struct {
+	WalletResponse
+	Fee *FeeBreakdown `json:"fee,omitempty"`
+}
+
+// FeeBreakdown DTO explaining how a fee was calculated
+type FeeBreakdown struct {
+	Rule          string         `json:"rule"`
+	Tier          *int64         `json:"tier_up_to,omitempty"` // Upper bound of the tier applied; 0 for the open-ended tier
+	Flat          int64          `json:"flat"`
+	Percentage    int64          `json:"percentage"`
+	Limit         string         `json:"limit,omitempty"` // "min", "max" or "amount" when the total was clamped
+	Total         int64          `json:"total"`
+	Taxes         []TaxBreakdown `json:"taxes,omitempty"`
+	Charged       int64          `json:"charged"`                   // Total plus exclusive taxes: what the payer was charged
+	LedgerEntryID int            `json:"ledger_entry_id,omitempty"` // The fee debit from the paying wallet
+}
+
+// TaxBreakdown DTO for a tax levied on a fee
+type TaxBreakdown struct {
+	Name          string `json:"name"`
+	Jurisdiction  string `json:"jurisdiction"`
+	RateBPS       int64  `json:"rate_bps"`
+	Inclusive     bool   `json:"inclusive"` // Carved out of the fee rather than added to it
+	Base          int64  `json:"base"`      // Fee net of inclusive taxes
+	Amount        int64  `json:"amount"`
+	LedgerEntryID int    `json:"ledger_entry_id,omitempty"` // The credit to the tax payable account
+}
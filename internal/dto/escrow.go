@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// CreateEscrowRequest DTO for holding a buyer's funds against a contract
+type CreateEscrowRequest struct {
+	ContractID     string     `json:"contract_id"`
+	BuyerWalletID  int        `json:"buyer_wallet_id"`
+	SellerWalletID int        `json:"seller_wallet_id"`
+	Amount         int64      `json:"amount"`
+	Reference      int        `json:"reference"`
+	Description    string     `json:"description"`
+	ReleaseAt      *time.Time `json:"release_at"` // Optional; remaining funds go to the seller at this time
+}
+
+// EscrowReleaseRequest DTO for releasing escrowed funds to the seller
+type EscrowReleaseRequest struct {
+	Amount    int64  `json:"amount"`    // Defaults to everything still held
+	Reference int    `json:"reference"` // Defaults to the escrow ID
+	Reason    string `json:"reason"`
+}
+
+// EscrowCancelRequest DTO for cancelling an escrow and refunding the buyer
+type EscrowCancelRequest struct {
+	Reference int    `json:"reference"` // Defaults to the escrow ID
+	Reason    string `json:"reason"`
+}
+
+// EscrowResponse DTO for returning an escrow and its postings
+type EscrowResponse struct {
+	models.Escrow
+	Remaining int64                   `json:"remaining"`
+	Movements []models.EscrowMovement `json:"movements,omitempty"`
+}
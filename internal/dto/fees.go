@@ -0,0 +1,6 @@
+package dto
+
+// SetSegmentRequest DTO for moving a wallet to another customer segment
+type SetSegmentRequest struct {
+	Segment string `json:"segment"` // Empty leaves the wallet to fee rules that match any segment
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/kodra-pay/wallet-ledger-service/internal/models"
+
+// SetInterestPlanRequest DTO for enrolling a savings wallet in an interest rate plan
+type SetInterestPlanRequest struct {
+	Plan string `json:"plan"`
+}
+
+// InterestAccountResponse DTO for returning a wallet's interest account and
+// its latest capitalisations
+type InterestAccountResponse struct {
+	models.InterestAccount
+	Capitalisations []models.InterestCapitalisation `json:"capitalisations"`
+}
@@ -8,9 +8,19 @@ type LedgerEntryRequest struct {
 	Reference     int     `json:"reference"`
 }
 
+// BalanceResponse DTO for a merchant wallet's settled and unsettled funds.
+// Amounts are in the currency's smallest unit.
 type BalanceResponse struct {
-	MerchantID int     `json:"merchant_id"`
-	Available  float64 `json:"available"`
-	Pending    float64 `json:"pending"`
-	Currency   string  `json:"currency"`
+	WalletID   int    `json:"wallet_id"`
+	MerchantID int    `json:"merchant_id"`
+	Available  int64  `json:"available"` // The wallet balance
+	Pending    int64  `json:"pending"`   // Captured but not yet settled
+	Reserved   int64  `json:"reserved"`  // Settled but held back as a reserve
+	Currency   string `json:"currency"`
+
+	// Available split into the real money outside pockets, the credit lots it
+	// holds, by lot type, and the part set aside in the wallet's pockets
+	Cash     int64            `json:"cash"`
+	Credits  map[string]int64 `json:"credits,omitempty"`
+	Pocketed int64            `json:"pocketed"`
 }
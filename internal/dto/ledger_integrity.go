@@ -0,0 +1,29 @@
+package dto
+
+import "github.com/kodra-pay/wallet-ledger-service/internal/models"
+
+// ChainVerificationReport DTO summarising a hash chain check across all wallets
+type ChainVerificationReport struct {
+	Valid           bool                       `json:"valid"`
+	WalletsChecked  int                        `json:"wallets_checked"`
+	EntriesChecked  int                        `json:"entries_checked"`
+	UnhashedEntries int                        `json:"unhashed_entries"`
+	Broken          []models.ChainVerification `json:"broken"`
+}
+
+// CheckpointVerification DTO for the result of re-checking a signed checkpoint
+type CheckpointVerification struct {
+	CheckpointID      int    `json:"checkpoint_id"`
+	Valid             bool   `json:"valid"`
+	SignatureValid    bool   `json:"signature_valid"`
+	HeadsRootMatches  bool   `json:"heads_root_matches"`
+	ExpectedHeadsRoot string `json:"expected_heads_root"`
+	ActualHeadsRoot   string `json:"actual_heads_root"`
+}
+
+// SigningKeyResponse DTO for publishing the checkpoint verification key
+type SigningKeyResponse struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"` // Base64
+}
@@ -0,0 +1,27 @@
+package dto
+
+// SetKYCTierRequest DTO for moving a wallet to another KYC tier
+type SetKYCTierRequest struct {
+	Tier string `json:"tier"` // Empty uses the default tier
+}
+
+// SetLimitOverrideRequest DTO for overriding some of a wallet's tier limits
+type SetLimitOverrideRequest struct {
+	Limits map[string]int64 `json:"limits"` // Limit name -> amount or count; empty removes the override
+}
+
+// LimitStatus DTO for one limit and how much of it a wallet has used
+type LimitStatus struct {
+	Name     string `json:"name"`
+	Limit    int64  `json:"limit"`
+	Used     int64  `json:"used"` // This period's total, or the balance for max_balance
+	Headroom int64  `json:"headroom"`
+}
+
+// WalletLimitsResponse DTO for the limits in force for a wallet
+type WalletLimitsResponse struct {
+	WalletID int              `json:"wallet_id"`
+	KYCTier  string           `json:"kyc_tier,omitempty"`
+	Override map[string]int64 `json:"override,omitempty"`
+	Limits   []LimitStatus    `json:"limits"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// RedeemPointsRequest DTO for converting loyalty points into money
+type RedeemPointsRequest struct {
+	PointsWalletID int    `json:"points_wallet_id"`
+	WalletID       int    `json:"wallet_id"` // The holder's fiat wallet to credit
+	Points         int64  `json:"points"`
+	Reference      int    `json:"reference"`
+	Description    string `json:"description"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// CreatePayoutRequest DTO for withdrawing wallet funds to a bank account
+type CreatePayoutRequest struct {
+	WalletID    int    `json:"wallet_id"`
+	Amount      int64  `json:"amount"`
+	Reference   int    `json:"reference"` // Unique per wallet
+	Destination string `json:"destination"`
+	Description string `json:"description"`
+}
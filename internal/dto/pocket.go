@@ -0,0 +1,39 @@
+package dto
+
+import "github.com/kodra-pay/wallet-ledger-service/internal/models"
+
+// PocketRequest DTO for creating a pocket or replacing its settings
+type PocketRequest struct {
+	Name         string              `json:"name"`
+	TargetAmount *int64              `json:"target_amount"` // Omit for no savings target
+	TargetDate   string              `json:"target_date"`   // YYYY-MM-DD; omit for no target date
+	Sweep        *models.PocketSweep `json:"sweep"`         // Omit for no auto-sweep
+}
+
+// PocketMoveRequest DTO for moving money between a wallet's main balance and a pocket
+type PocketMoveRequest struct {
+	Amount      int64  `json:"amount"`
+	Description string `json:"description"`
+}
+
+// PocketsResponse DTO for a wallet's balance split into its main balance and pockets
+type PocketsResponse struct {
+	WalletID int             `json:"wallet_id"`
+	Currency string          `json:"currency"`
+	Balance  int64           `json:"balance"` // The wallet balance: main plus the open pockets
+	Main     int64           `json:"main"`    // The cash neither set aside in pockets nor held in credit lots
+	Pockets  []models.Pocket `json:"pockets"`
+}
+
+// PocketResponse DTO for returning a pocket and its latest movements
+type PocketResponse struct {
+	models.Pocket
+	Movements []models.PocketMovement `json:"movements"`
+}
+
+// PocketMoveResponse DTO for returning the outcome of a pocket movement
+type PocketMoveResponse struct {
+	Pocket   models.Pocket         `json:"pocket"`
+	Movement models.PocketMovement `json:"movement"`
+	Main     int64                 `json:"main"` // The wallet's main balance after the movement
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// SetReservePolicyRequest DTO for setting a merchant's rolling reserve
+type SetReservePolicyRequest struct {
+	PercentageBPS int64 `json:"percentage_bps"` // 0 removes the policy
+	HoldDays      int   `json:"hold_days"`
+	Cap           int64 `json:"cap"` // 0 is uncapped
+}
+
+// CreateReserveRequest DTO for holding back merchant funds by hand
+type CreateReserveRequest struct {
+	WalletID  int        `json:"wallet_id"`
+	Amount    int64      `json:"amount"`
+	Reference int        `json:"reference"` // Defaults to the reserve ID
+	Reason    string     `json:"reason"`
+	ReleaseAt *time.Time `json:"release_at"` // Optional; held until released by hand when empty
+}
+
+// AdjustReserveRequest DTO for changing how much a reserve holds or when it is released
+type AdjustReserveRequest struct {
+	Amount    int64      `json:"amount"`     // New amount to hold; 0 keeps it. Lower releases the difference, higher holds more
+	ReleaseAt *time.Time `json:"release_at"` // Optional; replaces the release time
+	Reference int        `json:"reference"`  // Defaults to the reserve ID
+	Reason    string     `json:"reason"`
+}
+
+// ReleaseReserveRequest DTO for releasing reserved funds to the merchant
+type ReleaseReserveRequest struct {
+	Amount    int64  `json:"amount"`    // Defaults to everything still held
+	Reference int    `json:"reference"` // Defaults to the reserve ID
+	Reason    string `json:"reason"`
+}
+
+// ReserveResponse DTO for returning a reserve and its postings
+type ReserveResponse struct {
+	models.Reserve
+	Remaining int64                    `json:"remaining"`
+	Movements []models.ReserveMovement `json:"movements,omitempty"`
+}
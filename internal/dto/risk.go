@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/kodra-pay/wallet-ledger-service/internal/models"
+
+// ResolveRiskCaseRequest DTO for closing a risk case
+type ResolveRiskCaseRequest struct {
+	Status string `json:"status"` // "cleared" or "confirmed"
+	Note   string `json:"note"`
+}
+
+// RiskCaseResponse DTO for returning a risk case and the rule evaluations behind it
+type RiskCaseResponse struct {
+	models.RiskCase
+	Evaluations []models.RiskEvaluation `json:"evaluations"`
+}
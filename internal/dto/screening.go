@@ -0,0 +1,7 @@
+package dto
+
+// ResolveScreeningReviewRequest DTO for clearing or confirming a watchlist hit
+type ResolveScreeningReviewRequest struct {
+	Status string `json:"status"` // "cleared" or "confirmed"
+	Note   string `json:"note"`
+}
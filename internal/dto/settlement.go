@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/kodra-pay/wallet-ledger-service/internal/models"
+
+// CaptureRequest DTO for capturing a merchant payment into pending
+type CaptureRequest struct {
+	WalletID    int    `json:"wallet_id"`
+	Amount      int64  `json:"amount"`
+	Reference   int    `json:"reference"`
+	Description string `json:"description"`
+}
+
+// SetSettlementScheduleRequest DTO for changing a merchant's settlement schedule
+type SetSettlementScheduleRequest struct {
+	Schedule string `json:"schedule"` // "T+N", "weekly:<day>" or "manual"
+}
+
+// SettleRequest DTO for settling a merchant's due funds now
+type SettleRequest struct {
+	WalletID int `json:"wallet_id"`
+}
+
+// SettlementBatchResponse DTO for a settlement batch and its line items
+type SettlementBatchResponse struct {
+	models.SettlementBatch
+	Items []models.SettlementCapture `json:"items,omitempty"`
+}
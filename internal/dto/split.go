@@ -0,0 +1,31 @@
+package dto
+
+// SplitPaymentRequest DTO for splitting one payment across several wallets
+type SplitPaymentRequest struct {
+	SourceWalletID int                `json:"source_wallet_id"` // 0 for an incoming external payment
+	Currency       string             `json:"currency"`         // Required for an external payment
+	Amount         int64              `json:"amount"`
+	Reference      int                `json:"reference"`
+	Description    string             `json:"description"`
+	Destinations   []SplitDestination `json:"destinations"`
+}
+
+// SplitDestination DTO for one share of a split payment. Fixed amounts are
+// allocated first; percentage shares then divide what is left and must add
+// up to 100% (10000 basis points).
+type SplitDestination struct {
+	WalletID      int    `json:"wallet_id"`
+	Amount        int64  `json:"amount"`         // Fixed share
+	PercentageBPS int64  `json:"percentage_bps"` // Percentage share, 150 = 1.5%
+	Description   string `json:"description"`    // Defaults to the payment's description
+}
+
+// SplitPaymentResponse DTO for a posted or held split payment. Status is
+// TransferPosted or TransferPendingReview.
+type SplitPaymentResponse struct {
+	Status            string                `json:"status"`
+	JournalID         string                `json:"journal_id,omitempty"`
+	Source            *LedgerEntryResponse  `json:"source,omitempty"`       // Debit from the source wallet or external clearing
+	Destinations      []LedgerEntryResponse `json:"destinations,omitempty"` // One credit per destination whose share is not zero, in request order
+	ScreeningReviewID int                   `json:"screening_review_id,omitempty"`
+}
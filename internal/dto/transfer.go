@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// CreateTransferScheduleRequest DTO for creating a standing order
+type CreateTransferScheduleRequest struct {
+	FromWalletID int        `json:"from_wallet_id"`
+	ToWalletID   int        `json:"to_wallet_id"`
+	Amount       int64      `json:"amount"`
+	Description  string     `json:"description"`
+	RuleKind     string     `json:"rule_kind"` // "interval" (e.g. "1mo", "2w") or "cron" (e.g. "0 9 1 * *")
+	Rule         string     `json:"rule"`
+	Timezone     string     `json:"timezone"`    // IANA zone, defaults to UTC
+	StartAt      *time.Time `json:"start_at"`    // Defaults to now
+	EndAt        *time.Time `json:"end_at"`      // Optional
+	MaxRetries   *int       `json:"max_retries"` // Retries after insufficient funds; defaults to the service setting
+}
+
+// TransferScheduleResponse DTO for returning a standing order
+type TransferScheduleResponse struct {
+	models.TransferSchedule
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookEndpointRequest DTO for registering a merchant webhook endpoint
+type CreateWebhookEndpointRequest struct {
+	MerchantID int      `json:"merchant_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+// WebhookEndpointResponse DTO for returning a webhook endpoint.
+// Secret is only populated when the endpoint is created.
+type WebhookEndpointResponse struct {
+	ID         int       `json:"id"`
+	MerchantID int       `json:"merchant_id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse DTO for returning a delivery log entry
+type WebhookDeliveryResponse struct {
+	ID             int             `json:"id"`
+	EndpointID     int             `json:"endpoint_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// BalanceChangedEvent is the data payload of balance.credited and balance.debited events
+type BalanceChangedEvent struct {
+	WalletID      int       `json:"wallet_id"`
+	UserID        int       `json:"user_id"`
+	Currency      string    `json:"currency"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	Reference     int       `json:"reference"`
+	Type          string    `json:"type"`
+	Amount        int64     `json:"amount"`
+	Balance       int64     `json:"balance"`
+	Description   string    `json:"description"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type AdjustmentHandler struct {
+	svc *services.AdjustmentService
+}
+
+func NewAdjustmentHandler(svc *services.AdjustmentService) *AdjustmentHandler {
+	return &AdjustmentHandler{svc: svc}
+}
+
+// ProposeAdjustment handles requests to propose a manual credit or debit
+func (h *AdjustmentHandler) ProposeAdjustment(c *fiber.Ctx) error {
+	var req dto.ProposeAdjustmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.WalletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id is required")
+	}
+
+	resp, err := h.svc.Propose(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListAdjustments handles requests to list adjustment proposals
+func (h *AdjustmentHandler) ListAdjustments(c *fiber.Ctx) error {
+	filter := models.AdjustmentFilter{
+		WalletID: c.QueryInt("wallet_id", 0),
+		Status:   c.Query("status"),
+		Limit:    c.QueryInt("limit", 100),
+	}
+
+	resp, err := h.svc.ListAdjustments(c.UserContext(), filter)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetAdjustment handles requests for one adjustment and its approval chain
+func (h *AdjustmentHandler) GetAdjustment(c *fiber.Ctx) error {
+	adjustmentID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid adjustment ID")
+	}
+
+	resp, err := h.svc.GetAdjustment(c.UserContext(), adjustmentID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ApproveAdjustment handles requests to approve a proposed adjustment
+func (h *AdjustmentHandler) ApproveAdjustment(c *fiber.Ctx) error {
+	return h.decide(c, h.svc.Approve)
+}
+
+// RejectAdjustment handles requests to reject a proposed adjustment
+func (h *AdjustmentHandler) RejectAdjustment(c *fiber.Ctx) error {
+	return h.decide(c, h.svc.Reject)
+}
+
+func (h *AdjustmentHandler) decide(c *fiber.Ctx, decide func(ctx context.Context, id int, req dto.AdjustmentDecisionRequest) (*dto.AdjustmentResponse, error)) error {
+	adjustmentID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid adjustment ID")
+	}
+	var req dto.AdjustmentDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	resp, err := decide(c.UserContext(), adjustmentID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
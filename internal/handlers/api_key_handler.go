@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type APIKeyHandler struct {
+	svc *services.APIKeyService
+}
+
+func NewAPIKeyHandler(svc *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{svc: svc}
+}
+
+// CreateAPIKey handles requests to issue a new API key
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req dto.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.CreateAPIKey(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListAPIKeys handles requests to list API key metadata
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	resp, err := h.svc.ListAPIKeys(c.UserContext())
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// RotateAPIKey handles requests to replace an API key with a new one
+func (h *APIKeyHandler) RotateAPIKey(c *fiber.Ctx) error {
+	keyID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid API key ID")
+	}
+
+	var req dto.RotateAPIKeyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	resp, err := h.svc.RotateAPIKey(c.UserContext(), keyID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// RevokeAPIKey handles requests to revoke an API key immediately
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	keyID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid API key ID")
+	}
+
+	if err := h.svc.RevokeAPIKey(c.UserContext(), keyID); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type AuditHandler struct {
+	svc *services.AuditService
+}
+
+func NewAuditHandler(svc *services.AuditService) *AuditHandler {
+	return &AuditHandler{svc: svc}
+}
+
+// ListAuditRecords handles admin queries against the audit trail
+func (h *AuditHandler) ListAuditRecords(c *fiber.Ctx) error {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		ActorType:  c.Query("actor_type"),
+		ActorID:    c.Query("actor_id"),
+		RequestID:  c.Query("request_id"),
+		Limit:      c.QueryInt("limit", 100),
+	}
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return err
+	}
+
+	resp, err := h.svc.ListAuditRecords(c.UserContext(), filter)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// parseTimeQuery reads an optional RFC 3339 timestamp or YYYY-MM-DD date query parameter
+func parseTimeQuery(c *fiber.Ctx, key string) (time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fiber.NewError(fiber.StatusBadRequest, key+" must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+}
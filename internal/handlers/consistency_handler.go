@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type ConsistencyHandler struct {
+	svc *services.ConsistencyService
+}
+
+func NewConsistencyHandler(svc *services.ConsistencyService) *ConsistencyHandler {
+	return &ConsistencyHandler{svc: svc}
+}
+
+// RunCheck handles requests to run a ledger-vs-wallet consistency check now
+func (h *ConsistencyHandler) RunCheck(c *fiber.Ctx) error {
+	report, err := h.svc.Check(c.UserContext())
+	if err != nil {
+		return serviceError(err)
+	}
+	return h.respond(c, fiber.StatusCreated, report)
+}
+
+// GetLatestReport handles requests for the most recent consistency report
+func (h *ConsistencyHandler) GetLatestReport(c *fiber.Ctx) error {
+	report, err := h.svc.GetLatestReport(c.UserContext())
+	if err != nil {
+		return serviceError(err)
+	}
+	return h.respond(c, fiber.StatusOK, report)
+}
+
+// respond writes the report as JSON, or as CSV when ?format=csv
+func (h *ConsistencyHandler) respond(c *fiber.Ctx, status int, report *models.ConsistencyReport) error {
+	switch c.Query("format", "json") {
+	case "json":
+		return c.Status(status).JSON(report)
+	case "csv":
+		var buf bytes.Buffer
+		if err := services.WriteConsistencyCSV(&buf, report); err != nil {
+			return serviceError(err)
+		}
+		c.Set(fiber.HeaderContentType, "text/csv")
+		return c.Status(status).Send(buf.Bytes())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "format must be json or csv")
+	}
+}
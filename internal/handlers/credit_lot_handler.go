@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type CreditLotHandler struct {
+	svc *services.CreditLotService
+}
+
+func NewCreditLotHandler(svc *services.CreditLotService) *CreditLotHandler {
+	return &CreditLotHandler{svc: svc}
+}
+
+// IssueLot handles requests to credit a wallet with a promotional credit lot
+func (h *CreditLotHandler) IssueLot(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.IssueCreditLotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.IssueLot(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListLots handles requests to list a wallet's credit lots
+func (h *CreditLotHandler) ListLots(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.ListLots(c.UserContext(), walletID, c.Query("status"), c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetLot handles requests for a credit lot and its movements
+func (h *CreditLotHandler) GetLot(c *fiber.Ctx) error {
+	lotID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid credit lot ID")
+	}
+
+	resp, err := h.svc.GetLot(c.UserContext(), lotID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
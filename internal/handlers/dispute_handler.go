@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type DisputeHandler struct {
+	svc *services.DisputeService
+}
+
+func NewDisputeHandler(svc *services.DisputeService) *DisputeHandler {
+	return &DisputeHandler{svc: svc}
+}
+
+// OpenDispute handles requests to dispute a debit
+func (h *DisputeHandler) OpenDispute(c *fiber.Ctx) error {
+	var req dto.OpenDisputeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.WalletID == 0 || req.LedgerEntryID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id and ledger_entry_id are required")
+	}
+
+	resp, err := h.svc.OpenDispute(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListDisputes handles requests to list disputes
+func (h *DisputeHandler) ListDisputes(c *fiber.Ctx) error {
+	resp, err := h.svc.ListDisputes(c.UserContext(), c.QueryInt("wallet_id", 0), c.Query("status"), c.QueryBool("overdue", false), c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetDispute handles requests for a dispute and its postings
+func (h *DisputeHandler) GetDispute(c *fiber.Ctx) error {
+	disputeID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid dispute ID")
+	}
+
+	resp, err := h.svc.GetDispute(c.UserContext(), disputeID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GrantProvisionalCredit handles requests to provisionally credit a disputed debit
+func (h *DisputeHandler) GrantProvisionalCredit(c *fiber.Ctx) error {
+	disputeID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid dispute ID")
+	}
+	var req dto.DisputeActionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	resp, err := h.svc.GrantProvisionalCredit(c.UserContext(), disputeID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ResolveDispute handles requests to decide a dispute as won or lost
+func (h *DisputeHandler) ResolveDispute(c *fiber.Ctx) error {
+	disputeID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid dispute ID")
+	}
+	var req dto.ResolveDisputeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.Resolve(c.UserContext(), disputeID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// WithdrawDispute handles requests to withdraw a dispute
+func (h *DisputeHandler) WithdrawDispute(c *fiber.Ctx) error {
+	disputeID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid dispute ID")
+	}
+	var req dto.DisputeActionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	resp, err := h.svc.Withdraw(c.UserContext(), disputeID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
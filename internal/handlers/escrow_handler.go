@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type EscrowHandler struct {
+	svc *services.EscrowService
+}
+
+func NewEscrowHandler(svc *services.EscrowService) *EscrowHandler {
+	return &EscrowHandler{svc: svc}
+}
+
+// CreateEscrow handles requests to hold a buyer's funds in escrow
+func (h *EscrowHandler) CreateEscrow(c *fiber.Ctx) error {
+	var req dto.CreateEscrowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.ContractID == "" || req.BuyerWalletID == 0 || req.SellerWalletID == 0 || req.Amount <= 0 || req.Reference == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "contract_id, buyer_wallet_id, seller_wallet_id, positive amount and reference are required")
+	}
+
+	resp, err := h.svc.CreateEscrow(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListEscrows handles requests to list a wallet's escrows, or to find one by contract
+func (h *EscrowHandler) ListEscrows(c *fiber.Ctx) error {
+	if contractID := c.Query("contract_id"); contractID != "" {
+		resp, err := h.svc.GetEscrowByContractID(c.UserContext(), contractID)
+		if err != nil {
+			return serviceError(err)
+		}
+		return c.Status(fiber.StatusOK).JSON([]dto.EscrowResponse{*resp})
+	}
+
+	walletID := c.QueryInt("wallet_id", 0)
+	if walletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id or contract_id is required")
+	}
+	resp, err := h.svc.ListEscrows(c.UserContext(), walletID, c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetEscrow handles requests for one escrow and its postings
+func (h *EscrowHandler) GetEscrow(c *fiber.Ctx) error {
+	escrowID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid escrow ID")
+	}
+
+	resp, err := h.svc.GetEscrow(c.UserContext(), escrowID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ReleaseEscrow handles requests to release escrowed funds to the seller
+func (h *EscrowHandler) ReleaseEscrow(c *fiber.Ctx) error {
+	escrowID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid escrow ID")
+	}
+	var req dto.EscrowReleaseRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	resp, err := h.svc.Release(c.UserContext(), escrowID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// CancelEscrow handles requests to cancel an escrow and refund the buyer
+func (h *EscrowHandler) CancelEscrow(c *fiber.Ctx) error {
+	escrowID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid escrow ID")
+	}
+	var req dto.EscrowCancelRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	resp, err := h.svc.Cancel(c.UserContext(), escrowID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
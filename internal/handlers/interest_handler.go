@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type InterestHandler struct {
+	svc *services.InterestService
+}
+
+func NewInterestHandler(svc *services.InterestService) *InterestHandler {
+	return &InterestHandler{svc: svc}
+}
+
+// SetPlan handles requests to enrol a savings wallet in an interest rate plan
+func (h *InterestHandler) SetPlan(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.SetInterestPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Plan == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "plan is required")
+	}
+
+	resp, err := h.svc.SetPlan(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetAccount handles requests for a wallet's interest account
+func (h *InterestHandler) GetAccount(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.GetAccount(c.UserContext(), walletID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ListAccruals handles requests for a wallet's daily interest accrual history
+func (h *InterestHandler) ListAccruals(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		return err
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.ListAccruals(c.UserContext(), walletID, from, to, c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
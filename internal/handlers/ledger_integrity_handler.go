@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type LedgerIntegrityHandler struct {
+	svc *services.LedgerIntegrityService
+}
+
+func NewLedgerIntegrityHandler(svc *services.LedgerIntegrityService) *LedgerIntegrityHandler {
+	return &LedgerIntegrityHandler{svc: svc}
+}
+
+// VerifyChain handles requests to verify the ledger hash chain of one wallet or of all wallets
+func (h *LedgerIntegrityHandler) VerifyChain(c *fiber.Ctx) error {
+	if walletID := c.QueryInt("wallet_id", 0); walletID != 0 {
+		resp, err := h.svc.VerifyWallet(c.UserContext(), walletID)
+		if err != nil {
+			return serviceError(err)
+		}
+		return c.Status(fiber.StatusOK).JSON(resp)
+	}
+
+	resp, err := h.svc.VerifyAll(c.UserContext())
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ListCheckpoints handles requests to list signed ledger checkpoints
+func (h *LedgerIntegrityHandler) ListCheckpoints(c *fiber.Ctx) error {
+	resp, err := h.svc.ListCheckpoints(c.UserContext(), c.QueryInt("limit", 50))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// CreateCheckpoint handles requests to sign a checkpoint immediately
+func (h *LedgerIntegrityHandler) CreateCheckpoint(c *fiber.Ctx) error {
+	resp, err := h.svc.CreateCheckpoint(c.UserContext())
+	if err != nil {
+		return serviceError(err)
+	}
+	if resp == nil {
+		return c.SendStatus(fiber.StatusNoContent) // Nothing new to checkpoint
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// VerifyCheckpoint handles requests to re-check a checkpoint against the current ledger
+func (h *LedgerIntegrityHandler) VerifyCheckpoint(c *fiber.Ctx) error {
+	checkpointID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid checkpoint ID")
+	}
+
+	resp, err := h.svc.VerifyCheckpoint(c.UserContext(), checkpointID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetPublicKey handles requests for the checkpoint verification key
+func (h *LedgerIntegrityHandler) GetPublicKey(c *fiber.Ctx) error {
+	resp, err := h.svc.PublicKey()
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type LoyaltyHandler struct {
+	svc *services.LoyaltyService
+}
+
+func NewLoyaltyHandler(svc *services.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{svc: svc}
+}
+
+// Redeem handles requests to convert loyalty points into money
+func (h *LoyaltyHandler) Redeem(c *fiber.Ctx) error {
+	var req dto.RedeemPointsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.PointsWalletID == 0 || req.WalletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "points_wallet_id and wallet_id are required")
+	}
+
+	resp, err := h.svc.Redeem(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListEarnings handles requests to list a points wallet's earnings
+func (h *LoyaltyHandler) ListEarnings(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.ListEarnings(c.UserContext(), walletID, c.Query("status"), c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ListRedemptions handles requests to list a points wallet's redemptions
+func (h *LoyaltyHandler) ListRedemptions(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.ListRedemptions(c.UserContext(), walletID, c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/metrics"
+)
+
+type MetricsHandler struct {
+	registry *metrics.Registry
+}
+
+func NewMetricsHandler(registry *metrics.Registry) *MetricsHandler {
+	return &MetricsHandler{registry: registry}
+}
+
+func (h *MetricsHandler) Register(r fiber.Router) {
+	r.Get("/metrics", h.Metrics)
+}
+
+// Metrics handles Prometheus scrapes
+func (h *MetricsHandler) Metrics(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := h.registry.WriteText(&buf); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4")
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type PayoutHandler struct {
+	svc *services.PayoutService
+}
+
+func NewPayoutHandler(svc *services.PayoutService) *PayoutHandler {
+	return &PayoutHandler{svc: svc}
+}
+
+// CreatePayout handles requests to withdraw wallet funds to a bank account
+func (h *PayoutHandler) CreatePayout(c *fiber.Ctx) error {
+	var req dto.CreatePayoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.WalletID == 0 || req.Amount <= 0 || req.Reference == 0 || req.Destination == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id, positive amount, reference and destination are required")
+	}
+
+	resp, err := h.svc.RequestPayout(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListPayouts handles requests to list a wallet's payouts
+func (h *PayoutHandler) ListPayouts(c *fiber.Ctx) error {
+	walletID := c.QueryInt("wallet_id", 0)
+	if walletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id is required")
+	}
+
+	resp, err := h.svc.ListPayouts(c.UserContext(), walletID, c.Query("status"), c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetPayout handles requests for one payout
+func (h *PayoutHandler) GetPayout(c *fiber.Ctx) error {
+	payoutID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payout ID")
+	}
+
+	resp, err := h.svc.GetPayout(c.UserContext(), payoutID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// Callback handles the payout provider's reports of payout outcomes
+func (h *PayoutHandler) Callback(c *fiber.Ctx) error {
+	resp, err := h.svc.HandleCallback(c.UserContext(), c.Body())
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
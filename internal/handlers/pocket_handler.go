@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type PocketHandler struct {
+	svc *services.PocketService
+}
+
+func NewPocketHandler(svc *services.PocketService) *PocketHandler {
+	return &PocketHandler{svc: svc}
+}
+
+// CreatePocket handles requests to open a pocket in a wallet
+func (h *PocketHandler) CreatePocket(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.PocketRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.CreatePocket(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListPockets handles requests for a wallet's main balance and pockets
+func (h *PocketHandler) ListPockets(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.ListPockets(c.UserContext(), walletID, c.Query("status"))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetPocket handles requests for a pocket and its movements
+func (h *PocketHandler) GetPocket(c *fiber.Ctx) error {
+	pocketID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid pocket ID")
+	}
+
+	resp, err := h.svc.GetPocket(c.UserContext(), pocketID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// UpdatePocket handles requests to change a pocket's name, targets and auto-sweep rule
+func (h *PocketHandler) UpdatePocket(c *fiber.Ctx) error {
+	pocketID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid pocket ID")
+	}
+	var req dto.PocketRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.UpdatePocket(c.UserContext(), pocketID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// Deposit handles requests to move money from the main balance into a pocket
+func (h *PocketHandler) Deposit(c *fiber.Ctx) error {
+	pocketID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid pocket ID")
+	}
+	var req dto.PocketMoveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.Deposit(c.UserContext(), pocketID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// Withdraw handles requests to move money from a pocket back to the main balance
+func (h *PocketHandler) Withdraw(c *fiber.Ctx) error {
+	pocketID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid pocket ID")
+	}
+	var req dto.PocketMoveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.Withdraw(c.UserContext(), pocketID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ClosePocket handles requests to empty a pocket into the main balance and close it
+func (h *PocketHandler) ClosePocket(c *fiber.Ctx) error {
+	pocketID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid pocket ID")
+	}
+
+	resp, err := h.svc.ClosePocket(c.UserContext(), pocketID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type ReserveHandler struct {
+	svc *services.ReserveService
+}
+
+func NewReserveHandler(svc *services.ReserveService) *ReserveHandler {
+	return &ReserveHandler{svc: svc}
+}
+
+// GetPolicy handles requests for a merchant's rolling reserve policy
+func (h *ReserveHandler) GetPolicy(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("walletId")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.GetPolicy(c.UserContext(), walletID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// SetPolicy handles requests to set or remove a merchant's rolling reserve policy
+func (h *ReserveHandler) SetPolicy(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("walletId")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.SetReservePolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.SetPolicy(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// CreateReserve handles requests to hold back a merchant's funds by hand
+func (h *ReserveHandler) CreateReserve(c *fiber.Ctx) error {
+	var req dto.CreateReserveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.WalletID == 0 || req.Amount <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id and positive amount are required")
+	}
+
+	resp, err := h.svc.CreateReserve(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListReserves handles requests to list a wallet's reserves
+func (h *ReserveHandler) ListReserves(c *fiber.Ctx) error {
+	walletID := c.QueryInt("wallet_id", 0)
+	if walletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id is required")
+	}
+
+	resp, err := h.svc.ListReserves(c.UserContext(), walletID, c.QueryBool("open", false), c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetReserve handles requests for one reserve and its postings
+func (h *ReserveHandler) GetReserve(c *fiber.Ctx) error {
+	reserveID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid reserve ID")
+	}
+
+	resp, err := h.svc.GetReserve(c.UserContext(), reserveID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// AdjustReserve handles requests to change a reserve's amount or release time
+func (h *ReserveHandler) AdjustReserve(c *fiber.Ctx) error {
+	reserveID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid reserve ID")
+	}
+	var req dto.AdjustReserveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.Adjust(c.UserContext(), reserveID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ReleaseReserve handles requests to release reserved funds to the merchant early
+func (h *ReserveHandler) ReleaseReserve(c *fiber.Ctx) error {
+	reserveID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid reserve ID")
+	}
+	var req dto.ReleaseReserveRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	resp, err := h.svc.Release(c.UserContext(), reserveID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
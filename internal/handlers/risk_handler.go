@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type RiskHandler struct {
+	svc *services.RiskService
+}
+
+func NewRiskHandler(svc *services.RiskService) *RiskHandler {
+	return &RiskHandler{svc: svc}
+}
+
+// ListCases handles requests to list the risk case queue
+func (h *RiskHandler) ListCases(c *fiber.Ctx) error {
+	resp, err := h.svc.ListCases(c.UserContext(), c.Query("status"), c.QueryInt("wallet_id", 0), c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetCase handles requests for a risk case and its rule evaluations
+func (h *RiskHandler) GetCase(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid risk case ID")
+	}
+
+	resp, err := h.svc.GetCase(c.UserContext(), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ResolveCase handles requests to clear or confirm a risk case
+func (h *RiskHandler) ResolveCase(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid risk case ID")
+	}
+	var req dto.ResolveRiskCaseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.ResolveCase(c.UserContext(), id, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ListEntryEvaluations handles requests for the risk rule evaluations of a ledger entry
+func (h *RiskHandler) ListEntryEvaluations(c *fiber.Ctx) error {
+	entryID, err := c.ParamsInt("entryId")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ledger entry ID")
+	}
+
+	resp, err := h.svc.ListEntryEvaluations(c.UserContext(), entryID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
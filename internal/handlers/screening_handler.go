@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type ScreeningHandler struct {
+	svc *services.WalletService
+}
+
+func NewScreeningHandler(svc *services.WalletService) *ScreeningHandler {
+	return &ScreeningHandler{svc: svc}
+}
+
+// ListReviews handles requests to list sanctions screening reviews
+func (h *ScreeningHandler) ListReviews(c *fiber.Ctx) error {
+	resp, err := h.svc.ListScreeningReviews(c.UserContext(), c.Query("status"), c.QueryInt("wallet_id", 0), c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetReview handles requests for a screening review and its watchlist matches
+func (h *ScreeningHandler) GetReview(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid screening review ID")
+	}
+
+	resp, err := h.svc.GetScreeningReview(c.UserContext(), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ResolveReview handles requests to clear or confirm a watchlist hit
+func (h *ScreeningHandler) ResolveReview(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid screening review ID")
+	}
+	var req dto.ResolveScreeningReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.ResolveScreeningReview(c.UserContext(), id, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type SettlementHandler struct {
+	svc *services.SettlementService
+}
+
+func NewSettlementHandler(svc *services.SettlementService) *SettlementHandler {
+	return &SettlementHandler{svc: svc}
+}
+
+// Capture handles requests to capture a merchant payment into pending funds
+func (h *SettlementHandler) Capture(c *fiber.Ctx) error {
+	var req dto.CaptureRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.WalletID == 0 || req.Amount <= 0 || req.Reference == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id, positive amount and reference are required")
+	}
+
+	resp, err := h.svc.Capture(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// GetBalance handles requests for a wallet's available and pending funds
+func (h *SettlementHandler) GetBalance(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.GetBalance(c.UserContext(), walletID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ListBatches handles requests to list a merchant wallet's settlement batches
+func (h *SettlementHandler) ListBatches(c *fiber.Ctx) error {
+	walletID := c.QueryInt("wallet_id", 0)
+	if walletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id is required")
+	}
+
+	resp, err := h.svc.ListBatches(c.UserContext(), walletID, c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetBatch handles requests for one settlement batch and its line items
+func (h *SettlementHandler) GetBatch(c *fiber.Ctx) error {
+	batchID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid settlement batch ID")
+	}
+
+	resp, err := h.svc.GetBatch(c.UserContext(), batchID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// Settle handles requests to settle a merchant's due funds immediately
+func (h *SettlementHandler) Settle(c *fiber.Ctx) error {
+	var req dto.SettleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.WalletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id is required")
+	}
+
+	resp, err := h.svc.Settle(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// GetSchedule handles requests for a merchant's settlement schedule
+func (h *SettlementHandler) GetSchedule(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("walletId")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.GetSchedule(c.UserContext(), walletID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// SetSchedule handles requests to change a merchant's settlement schedule
+func (h *SettlementHandler) SetSchedule(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("walletId")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.SetSettlementScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Schedule == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "schedule is required")
+	}
+
+	resp, err := h.svc.SetSchedule(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
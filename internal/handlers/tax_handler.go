@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type TaxHandler struct {
+	svc *services.TaxService
+}
+
+func NewTaxHandler(svc *services.TaxService) *TaxHandler {
+	return &TaxHandler{svc: svc}
+}
+
+// GetSummary handles requests for the tax collected over a period
+func (h *TaxHandler) GetSummary(c *fiber.Ctx) error {
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		return err
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.svc.Summary(c.UserContext(), from, to)
+	if err != nil {
+		return serviceError(err)
+	}
+	switch c.Query("format", "json") {
+	case "json":
+		return c.Status(fiber.StatusOK).JSON(summary)
+	case "csv":
+		var buf bytes.Buffer
+		if err := services.WriteTaxSummaryCSV(&buf, summary); err != nil {
+			return serviceError(err)
+		}
+		c.Set(fiber.HeaderContentType, "text/csv")
+		return c.Status(fiber.StatusOK).Send(buf.Bytes())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "format must be json or csv")
+	}
+}
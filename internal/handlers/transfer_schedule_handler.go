@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type TransferScheduleHandler struct {
+	svc *services.TransferScheduleService
+}
+
+func NewTransferScheduleHandler(svc *services.TransferScheduleService) *TransferScheduleHandler {
+	return &TransferScheduleHandler{svc: svc}
+}
+
+// CreateSchedule handles requests to create a scheduled or recurring transfer
+func (h *TransferScheduleHandler) CreateSchedule(c *fiber.Ctx) error {
+	var req dto.CreateTransferScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.FromWalletID == 0 || req.ToWalletID == 0 || req.RuleKind == "" || req.Rule == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "from_wallet_id, to_wallet_id, rule_kind and rule are required")
+	}
+
+	resp, err := h.svc.CreateSchedule(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListSchedules handles requests to list the schedules of a wallet
+func (h *TransferScheduleHandler) ListSchedules(c *fiber.Ctx) error {
+	walletID := c.QueryInt("wallet_id", 0)
+	if walletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "wallet_id is required")
+	}
+
+	resp, err := h.svc.ListSchedules(c.UserContext(), walletID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetSchedule handles requests for a single transfer schedule
+func (h *TransferScheduleHandler) GetSchedule(c *fiber.Ctx) error {
+	scheduleID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid schedule ID")
+	}
+
+	resp, err := h.svc.GetSchedule(c.UserContext(), scheduleID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ListRuns handles requests for a schedule's execution history
+func (h *TransferScheduleHandler) ListRuns(c *fiber.Ctx) error {
+	scheduleID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid schedule ID")
+	}
+
+	resp, err := h.svc.ListRuns(c.UserContext(), scheduleID, c.QueryInt("limit", 100))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// PauseSchedule handles requests to pause a schedule
+func (h *TransferScheduleHandler) PauseSchedule(c *fiber.Ctx) error {
+	return h.setStatus(c, h.svc.PauseSchedule)
+}
+
+// ResumeSchedule handles requests to resume a paused schedule
+func (h *TransferScheduleHandler) ResumeSchedule(c *fiber.Ctx) error {
+	return h.setStatus(c, h.svc.ResumeSchedule)
+}
+
+// CancelSchedule handles requests to cancel a schedule
+func (h *TransferScheduleHandler) CancelSchedule(c *fiber.Ctx) error {
+	return h.setStatus(c, h.svc.CancelSchedule)
+}
+
+func (h *TransferScheduleHandler) setStatus(c *fiber.Ctx, set func(ctx context.Context, id int) (*dto.TransferScheduleResponse, error)) error {
+	scheduleID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid schedule ID")
+	}
+
+	resp, err := set(c.UserContext(), scheduleID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
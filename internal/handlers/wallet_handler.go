@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"errors"
-
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
@@ -27,9 +25,9 @@ func (h *WalletHandler) CreateWallet(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "user_id and currency are required")
 	}
 
-	resp, err := h.svc.CreateWallet(c.Context(), req)
+	resp, err := h.svc.CreateWallet(c.UserContext(), req)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return serviceError(err)
 	}
 	return c.Status(fiber.StatusCreated).JSON(resp)
 }
@@ -41,12 +39,9 @@ func (h *WalletHandler) GetWalletByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
 	}
 
-	resp, err := h.svc.GetWalletByID(c.Context(), walletID)
+	resp, err := h.svc.GetWalletByID(c.UserContext(), walletID)
 	if err != nil {
-		if errors.Is(err, errors.New("wallet not found")) {
-			return fiber.NewError(fiber.StatusNotFound, err.Error())
-		}
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return serviceError(err)
 	}
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
@@ -59,12 +54,9 @@ func (h *WalletHandler) GetWalletByUserIDAndCurrency(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "user_id and currency are required query parameters")
 	}
 
-	resp, err := h.svc.GetWalletByUserIDAndCurrency(c.Context(), userID, currency)
+	resp, err := h.svc.GetWalletByUserIDAndCurrency(c.UserContext(), userID, currency)
 	if err != nil {
-		if errors.Is(err, errors.New("wallet not found for user and currency")) {
-			return fiber.NewError(fiber.StatusNotFound, err.Error())
-		}
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return serviceError(err)
 	}
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
@@ -84,12 +76,77 @@ func (h *WalletHandler) UpdateWalletBalance(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "positive amount, reference, and valid type ('credit'/'debit') are required")
 	}
 
-	resp, err := h.svc.UpdateWalletBalance(c.Context(), walletID, req)
+	resp, err := h.svc.UpdateWalletBalance(c.UserContext(), walletID, req)
 	if err != nil {
-		if errors.Is(err, errors.New("wallet not found")) {
-			return fiber.NewError(fiber.StatusNotFound, err.Error())
-		}
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// Transfer handles requests to move funds from one wallet to another
+func (h *WalletHandler) Transfer(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	var req dto.TransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	req.FromWalletID = walletID
+	if req.Amount <= 0 || req.Reference == 0 || req.ToWalletID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "positive amount, reference and to_wallet_id are required")
+	}
+
+	resp, err := h.svc.Transfer(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	if resp.Status == dto.TransferPendingReview {
+		return c.Status(fiber.StatusAccepted).JSON(resp)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// SplitPayment handles requests to split one payment across several wallets
+func (h *WalletHandler) SplitPayment(c *fiber.Ctx) error {
+	var req dto.SplitPaymentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Amount <= 0 || req.Reference == 0 || len(req.Destinations) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "positive amount, reference and destinations are required")
+	}
+
+	resp, err := h.svc.SplitPayment(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	if resp.Status == dto.TransferPendingReview {
+		return c.Status(fiber.StatusAccepted).JSON(resp)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// UpdateWalletStatus handles requests to freeze, unfreeze or close a wallet
+func (h *WalletHandler) UpdateWalletStatus(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	var req dto.UpdateWalletStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Status == "" || req.Reason == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "status and reason are required")
+	}
+
+	resp, err := h.svc.SetWalletStatus(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
 	}
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
@@ -101,9 +158,103 @@ func (h *WalletHandler) GetWalletLedger(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
 	}
 
-	resp, err := h.svc.GetWalletLedger(c.Context(), walletID)
+	resp, err := h.svc.GetWalletLedger(c.UserContext(), walletID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// ReverseLedgerEntry handles requests to reverse a posted ledger entry
+func (h *WalletHandler) ReverseLedgerEntry(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	entryID, err := c.ParamsInt("entryId")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ledger entry ID")
+	}
+
+	var req dto.ReverseEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Reason == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "reason is required")
+	}
+
+	resp, err := h.svc.ReverseLedgerEntry(c.UserContext(), walletID, entryID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// GetWalletLimits handles requests for a wallet's limits and how much of each is used
+func (h *WalletHandler) GetWalletLimits(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+
+	resp, err := h.svc.GetWalletLimits(c.UserContext(), walletID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// SetLimitOverride handles requests to override a wallet's tier limits
+func (h *WalletHandler) SetLimitOverride(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.SetLimitOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.SetLimitOverride(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// SetKYCTier handles requests to move a wallet to another KYC tier
+func (h *WalletHandler) SetKYCTier(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.SetKYCTierRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.SetKYCTier(c.UserContext(), walletID, req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// SetSegment handles requests to move a wallet to another customer segment
+func (h *WalletHandler) SetSegment(c *fiber.Ctx) error {
+	walletID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid wallet ID")
+	}
+	var req dto.SetSegmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.svc.SetSegment(c.UserContext(), walletID, req)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return serviceError(err)
 	}
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
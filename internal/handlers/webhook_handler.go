@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+)
+
+type WebhookHandler struct {
+	svc *services.WebhookService
+}
+
+func NewWebhookHandler(svc *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+// CreateEndpoint handles requests to register a merchant webhook endpoint
+func (h *WebhookHandler) CreateEndpoint(c *fiber.Ctx) error {
+	var req dto.CreateWebhookEndpointRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.MerchantID == 0 || req.URL == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "merchant_id and url are required")
+	}
+
+	resp, err := h.svc.CreateEndpoint(c.UserContext(), req)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// ListEndpoints handles requests to list a merchant's active webhook endpoints
+func (h *WebhookHandler) ListEndpoints(c *fiber.Ctx) error {
+	merchantID := c.QueryInt("merchant_id", 0)
+	if merchantID == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "merchant_id is a required query parameter")
+	}
+
+	resp, err := h.svc.ListEndpoints(c.UserContext(), merchantID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// DeleteEndpoint handles requests to deactivate a webhook endpoint
+func (h *WebhookHandler) DeleteEndpoint(c *fiber.Ctx) error {
+	endpointID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid endpoint ID")
+	}
+
+	if err := h.svc.DeactivateEndpoint(c.UserContext(), endpointID); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListDeliveries handles requests to read the webhook delivery log
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	filter := models.WebhookDeliveryFilter{
+		EndpointID: c.QueryInt("endpoint_id", 0),
+		MerchantID: c.QueryInt("merchant_id", 0),
+		Status:     c.Query("status"),
+		Limit:      c.QueryInt("limit", 100),
+	}
+
+	resp, err := h.svc.ListDeliveries(c.UserContext(), filter)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// Redeliver handles requests to manually resend a webhook delivery
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	deliveryID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid delivery ID")
+	}
+
+	resp, err := h.svc.Redeliver(c.UserContext(), deliveryID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(resp)
+}
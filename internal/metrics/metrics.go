@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry holds the service's gauges and counters and renders them in the
+// Prometheus text exposition format
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]*metric
+}
+
+type metric struct {
+	name  string
+	help  string
+	kind  string // "gauge" or "counter"
+	value atomic.Uint64
+}
+
+func (m *metric) load() float64 {
+	return math.Float64frombits(m.value.Load())
+}
+
+func (m *metric) store(v float64) {
+	m.value.Store(math.Float64bits(v))
+}
+
+func (m *metric) add(delta float64) {
+	for {
+		old := m.value.Load()
+		if m.value.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]*metric{}}
+}
+
+// Default is the registry exposed on /metrics
+var Default = NewRegistry()
+
+func (r *Registry) register(name, help, kind string) *metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.metrics[name]; ok {
+		return m
+	}
+	m := &metric{name: name, help: help, kind: kind}
+	r.metrics[name] = m
+	return m
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct{ m *metric }
+
+// Gauge returns the gauge with the given name, registering it on first use
+func (r *Registry) Gauge(name, help string) Gauge {
+	return Gauge{m: r.register(name, help, "gauge")}
+}
+
+// Set sets the gauge to v
+func (g Gauge) Set(v float64) { g.m.store(v) }
+
+// Value returns the gauge's current value
+func (g Gauge) Value() float64 { return g.m.load() }
+
+// Counter is a value that only increases
+type Counter struct{ m *metric }
+
+// Counter returns the counter with the given name, registering it on first use
+func (r *Registry) Counter(name, help string) Counter {
+	return Counter{m: r.register(name, help, "counter")}
+}
+
+// Inc adds one to the counter
+func (c Counter) Inc() { c.m.add(1) }
+
+// Value returns the counter's current value
+func (c Counter) Value() float64 { return c.m.load() }
+
+// WriteText writes every metric, sorted by name, in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mu.Lock()
+		m := r.metrics[name]
+		r.mu.Unlock()
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n",
+			m.name, m.help, m.name, m.kind, m.name, strconv.FormatFloat(m.load(), 'g', -1, 64)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/auth"
+)
+
+// principalLocal is the fiber.Ctx Locals key holding the authenticated *auth.Principal
+const principalLocal = "principal"
+
+// APIKeyAuthenticator resolves plaintext API keys to principals
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// Authenticate identifies the caller from an API key (X-API-Key header, or a
+// non-JWT bearer token) or a JWT bearer token, and rejects anonymous requests.
+// The principal is stored in Locals and in the request's user context.
+func Authenticate(keys APIKeyAuthenticator, jwt *auth.JWTVerifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey := c.Get("X-API-Key")
+		bearer := ""
+		if header := c.Get(fiber.HeaderAuthorization); len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+			bearer = strings.TrimSpace(header[7:])
+		}
+
+		var (
+			principal *auth.Principal
+			err       error
+		)
+		switch {
+		case apiKey != "":
+			principal, err = keys.AuthenticateAPIKey(c.UserContext(), apiKey)
+		case bearer != "" && strings.Count(bearer, ".") == 2:
+			if jwt == nil || !jwt.Enabled() {
+				return fiber.NewError(fiber.StatusUnauthorized, "bearer tokens are not accepted")
+			}
+			principal, err = jwt.Verify(bearer)
+		case bearer != "":
+			principal, err = keys.AuthenticateAPIKey(c.UserContext(), bearer)
+		default:
+			return fiber.NewError(fiber.StatusUnauthorized, "missing credentials")
+		}
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrInvalidCredentials) {
+				return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+
+		c.Locals(principalLocal, principal)
+		c.SetUserContext(auth.WithPrincipal(c.UserContext(), principal))
+		return c.Next()
+	}
+}
+
+// Principal returns the caller identified by Authenticate, or nil
+func Principal(c *fiber.Ctx) *auth.Principal {
+	p, _ := c.Locals(principalLocal).(*auth.Principal)
+	return p
+}
+
+// RequireScopes rejects callers that lack any of the given scopes.
+// It must run after Authenticate.
+func RequireScopes(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := Principal(c)
+		if principal == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "missing credentials")
+		}
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				return fiber.NewError(fiber.StatusForbidden, "missing required scope: "+scope)
+			}
+		}
+		return c.Next()
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/requestctx"
 )
 
 func RequestID() fiber.Handler {
@@ -14,6 +16,10 @@ func RequestID() fiber.Handler {
 			requestID = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
 		c.Set("X-Request-ID", requestID)
+
+		// Make the request ID and caller IP available to services (e.g. for the audit log)
+		ctx := requestctx.WithRequestID(c.UserContext(), requestID)
+		c.SetUserContext(requestctx.WithSourceIP(ctx, c.IP()))
 		return c.Next()
 	}
 }
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Adjustment request statuses
+const (
+	AdjustmentPending  = "pending"  // Waiting for approvals
+	AdjustmentApproved = "approved" // Enough approvals; the ledger entry was posted
+	AdjustmentRejected = "rejected" // A checker rejected it; nothing was posted
+	AdjustmentExpired  = "expired"  // Not approved in time; nothing was posted
+)
+
+// Adjustment decisions
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+)
+
+// AdjustmentRequest is a manual credit or debit proposed by one operator (the
+// maker) that only posts once enough other operators (checkers) approve it
+type AdjustmentRequest struct {
+	ID                int        `json:"id"`
+	WalletID          int        `json:"wallet_id"`
+	Type              string     `json:"type"`
+	Amount            int64      `json:"amount"`
+	Reason            string     `json:"reason"`
+	Reference         int        `json:"reference"`
+	Status            string     `json:"status"`
+	RequiredApprovals int        `json:"required_approvals"`
+	ProposedByType    string     `json:"proposed_by_type"`
+	ProposedByID      string     `json:"proposed_by_id"`
+	LedgerEntryID     *int       `json:"ledger_entry_id,omitempty"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// AdjustmentDecision is one checker's approval or rejection
+type AdjustmentDecision struct {
+	ID           int       `json:"id"`
+	AdjustmentID int       `json:"adjustment_id"`
+	Decision     string    `json:"decision"`
+	ActorType    string    `json:"actor_type"`
+	ActorID      string    `json:"actor_id"`
+	Comment      string    `json:"comment,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AdjustmentFilter narrows an adjustment request query; zero values are ignored
+type AdjustmentFilter struct {
+	WalletID int
+	Status   string
+	Limit    int
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// APIKey is a hashed credential used by service-to-service callers
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // Public lookup part of the key
+	KeyHash    string     `json:"-"`      // SHA-256 of the full plaintext key
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // Set when a rotated key is in its grace period
+	RevokedAt  *time.Time `json:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RotatedTo  *int       `json:"rotated_to"` // ID of the key that replaced this one
+}
+
+// Usable reports whether the key may still authenticate at time now
+func (k *APIKey) Usable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
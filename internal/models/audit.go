@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditWalletCreated        = "wallet.created"
+	AuditWalletBalanceUpdated = "wallet.balance_updated"
+	AuditWalletStatusChanged  = "wallet.status_changed"
+	AuditWalletAdjusted       = "wallet.adjusted"
+	AuditLedgerEntryReversed  = "ledger_entry.reversed"
+	AuditAPIKeyCreated        = "api_key.created"
+	AuditAPIKeyRotated        = "api_key.rotated"
+	AuditAPIKeyRevoked        = "api_key.revoked"
+	AuditWebhookCreated       = "webhook_endpoint.created"
+	AuditWebhookDeactivated   = "webhook_endpoint.deactivated"
+	AuditWebhookRedelivered   = "webhook_delivery.redelivered"
+	AuditAdjustmentProposed   = "adjustment.proposed"
+	AuditAdjustmentApproved   = "adjustment.approved"
+	AuditAdjustmentRejected   = "adjustment.rejected"
+	AuditAdjustmentExpired    = "adjustment.expired"
+	AuditWalletTransferred    = "wallet.transferred"
+	AuditScheduleCreated      = "transfer_schedule.created"
+	AuditScheduleStatus       = "transfer_schedule.status_changed"
+	AuditPaymentSplit         = "payment.split"
+	AuditEscrowCreated        = "escrow.created"
+	AuditEscrowReleased       = "escrow.released"
+	AuditEscrowRefunded       = "escrow.refunded"
+	AuditFundsCaptured        = "settlement.captured"
+	AuditSettlementBatch      = "settlement.batch_created"
+	AuditSettlementSchedule   = "settlement.schedule_changed"
+	AuditReserveHeld          = "reserve.held"
+	AuditReserveReleased      = "reserve.released"
+	AuditReserveAdjusted      = "reserve.adjusted"
+	AuditReservePolicy        = "reserve.policy_changed"
+	AuditPayoutRequested      = "payout.requested"
+	AuditPayoutStatusChanged  = "payout.status_changed"
+	AuditWalletKYCTier        = "wallet.kyc_tier_changed"
+	AuditWalletSegment        = "wallet.segment_changed"
+	AuditWalletLimits         = "wallet.limits_changed"
+	AuditRiskCaseOpened       = "risk.case_opened"
+	AuditRiskCaseResolved     = "risk.case_resolved"
+	AuditScreeningOpened      = "screening.review_opened"
+	AuditScreeningResolved    = "screening.review_resolved"
+	AuditDisputeOpened        = "dispute.opened"
+	AuditDisputeStatusChanged = "dispute.status_changed"
+	AuditCreditLotIssued      = "credit_lot.issued"
+	AuditCreditLotExpired     = "credit_lot.expired"
+	AuditPointsRedeemed       = "loyalty.points_redeemed"
+	AuditPointsExpired        = "loyalty.points_expired"
+	AuditInterestPlanSet      = "interest.plan_set"
+	AuditInterestCapitalised  = "interest.capitalised"
+	AuditPocketCreated        = "pocket.created"
+	AuditPocketUpdated        = "pocket.updated"
+	AuditPocketDeposited      = "pocket.deposited"
+	AuditPocketWithdrawn      = "pocket.withdrawn"
+	AuditPocketClosed         = "pocket.closed"
+)
+
+// Audit actor types, in addition to the auth principal types
+const (
+	AuditActorSystem = "system" // Background jobs and CLI commands
+)
+
+// AuditRecord is one immutable row of the audit trail
+type AuditRecord struct {
+	ID         int             `json:"id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    string          `json:"actor_id"`
+	RequestID  string          `json:"request_id"`
+	SourceIP   string          `json:"source_ip"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows an audit log query; zero values are ignored
+type AuditFilter struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	ActorType  string
+	ActorID    string
+	RequestID  string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
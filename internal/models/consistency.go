@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Consistency discrepancy kinds
+const (
+	DiscrepancyWalletBalance     = "wallet_balance"     // wallets.balance differs from the sum of the wallet's entries
+	DiscrepancyRunningBalance    = "running_balance"    // An entry's balance column does not match the running sum
+	DiscrepancyUnbalancedJournal = "unbalanced_journal" // A journal's signed amounts do not sum to zero in a currency
+)
+
+// Discrepancy is one inconsistency found between the ledger and the wallets
+type Discrepancy struct {
+	Kind      string `json:"kind"`
+	WalletID  int    `json:"wallet_id,omitempty"`
+	EntryID   int    `json:"entry_id,omitempty"`
+	JournalID string `json:"journal_id,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+	Expected  int64  `json:"expected"`
+	Actual    int64  `json:"actual"`
+}
+
+// ConsistencyReport is the result of recomputing wallet balances from the ledger
+type ConsistencyReport struct {
+	ID              int           `json:"id"`
+	StartedAt       time.Time     `json:"started_at"`
+	FinishedAt      time.Time     `json:"finished_at"`
+	OK              bool          `json:"ok"`
+	WalletsChecked  int           `json:"wallets_checked"`
+	EntriesChecked  int64         `json:"entries_checked"`
+	JournalsChecked int64         `json:"journals_checked"`
+	LegacyEntries   int64         `json:"legacy_entries"` // Single-sided entries posted before double-entry journals
+	Discrepancies   []Discrepancy `json:"discrepancies"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Credit lot types
+const (
+	CreditLotPromo    = "promo"    // Marketing bonus credit
+	CreditLotCashback = "cashback" // Cashback on earlier spending
+)
+
+// Credit lot statuses
+const (
+	CreditLotOpen    = "open"    // Has a remaining amount that can be spent
+	CreditLotSpent   = "spent"   // Fully spent
+	CreditLotExpired = "expired" // Written off when it expired
+)
+
+// Credit lot movement types
+const (
+	CreditLotMovementIssue  = "issue"  // The lot was credited to the wallet
+	CreditLotMovementSpend  = "spend"  // A debit or transfer drew on the lot
+	CreditLotMovementExpiry = "expiry" // The unspent remainder was written off
+)
+
+// CreditLotRestrictions limit what a lot can be spent on
+type CreditLotRestrictions struct {
+	// TxnTypes are the transaction types ("debit", "transfer") the lot may
+	// pay for; empty allows both
+	TxnTypes []string `json:"txn_types,omitempty"`
+	// MinAmount is the smallest transaction the lot may pay towards
+	MinAmount int64 `json:"min_amount,omitempty"`
+}
+
+// Allows reports whether a transaction of txnType and amount may be paid
+// from the lot
+func (r CreditLotRestrictions) Allows(txnType string, amount int64) bool {
+	if amount < r.MinAmount {
+		return false
+	}
+	if len(r.TxnTypes) == 0 {
+		return true
+	}
+	for _, t := range r.TxnTypes {
+		if t == txnType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreditLot is promotional credit inside a wallet's balance. The wallet's
+// balance includes the remaining amount of its open lots; the rest is cash.
+// Lots are issued from, and written off to, the marketing expense system
+// account.
+type CreditLot struct {
+	ID            int                   `json:"id"`
+	WalletID      int                   `json:"wallet_id"`
+	Type          string                `json:"type"`
+	Source        string                `json:"source"`
+	Currency      string                `json:"currency"`
+	Amount        int64                 `json:"amount"`
+	Remaining     int64                 `json:"remaining"`
+	Restrictions  CreditLotRestrictions `json:"restrictions"`
+	ExpiresAt     *time.Time            `json:"expires_at,omitempty"`
+	Status        string                `json:"status"`
+	ExpiryRetryAt *time.Time            `json:"-"`
+	JournalID     string                `json:"journal_id"`
+	LedgerEntryID int                   `json:"ledger_entry_id"`
+	CreatedByType string                `json:"created_by_type"`
+	CreatedByID   string                `json:"created_by_id"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+// Expired reports whether the lot can no longer be spent at now
+func (l *CreditLot) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// CreditLotMovement is one change to a lot's remaining amount
+type CreditLotMovement struct {
+	ID            int       `json:"id"`
+	LotID         int       `json:"lot_id"`
+	Type          string    `json:"type"`
+	Amount        int64     `json:"amount"`
+	JournalID     string    `json:"journal_id"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
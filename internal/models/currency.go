@@ -0,0 +1,20 @@
+package models
+
+// Asset types a currency can denominate
+const (
+	AssetFiat   = "fiat"   // Money, in the ISO 4217 currency's minor unit
+	AssetPoints = "points" // Non-monetary units such as loyalty points
+)
+
+// Currency describes a unit wallets can hold
+type Currency struct {
+	Code      string `json:"code"`
+	Name      string `json:"name,omitempty"`
+	AssetType string `json:"asset_type"`
+	Exponent  int    `json:"exponent"` // Decimal places of the smallest unit, e.g. 2 for USD
+}
+
+// IsMonetary reports whether the currency is money
+func (c Currency) IsMonetary() bool {
+	return c.AssetType != AssetPoints
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Dispute statuses
+const (
+	DisputeOpened            = "opened"             // Under investigation, no credit yet
+	DisputeProvisionalCredit = "provisional_credit" // Customer credited while the dispute is investigated
+	DisputeWon               = "won"                // Decided for the customer; the credit is final
+	DisputeLost              = "lost"               // Decided against the customer; any provisional credit was reversed
+	DisputeWithdrawn         = "withdrawn"          // Dropped by the customer; any provisional credit was reversed
+)
+
+// Dispute movement types
+const (
+	// DisputeMovementProvisionalCredit credits the customer from the disputes account
+	DisputeMovementProvisionalCredit = "provisional_credit"
+	// DisputeMovementProvisionalReversal takes a provisional credit back
+	DisputeMovementProvisionalReversal = "provisional_reversal"
+	// DisputeMovementRecovery settles the disputes account from external
+	// clearing once a provisionally credited dispute is won
+	DisputeMovementRecovery = "recovery"
+	// DisputeMovementFinalCredit credits the customer from external clearing
+	// when a dispute is won without a provisional credit
+	DisputeMovementFinalCredit = "final_credit"
+)
+
+// Dispute is a customer's challenge of a debit ledger entry. Provisional
+// credits are funded from the disputes system account for the currency.
+type Dispute struct {
+	ID                     int        `json:"id"`
+	WalletID               int        `json:"wallet_id"`
+	LedgerEntryID          int        `json:"ledger_entry_id"`
+	Currency               string     `json:"currency"`
+	Amount                 int64      `json:"amount"`
+	ProvisionalAmount      int64      `json:"provisional_amount"`
+	Status                 string     `json:"status"`
+	Reason                 string     `json:"reason"`
+	ResolutionNote         string     `json:"resolution_note,omitempty"`
+	ProvisionalCreditDueAt *time.Time `json:"provisional_credit_due_at,omitempty"`
+	ResolveBy              time.Time  `json:"resolve_by"`
+	ResolvedAt             *time.Time `json:"resolved_at,omitempty"`
+	CreatedByType          string     `json:"created_by_type"`
+	CreatedByID            string     `json:"created_by_id"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
+
+// IsOpen reports whether the dispute is still undecided
+func (d *Dispute) IsOpen() bool {
+	return d.Status == DisputeOpened || d.Status == DisputeProvisionalCredit
+}
+
+// DisputeMovement is one posting a dispute made
+type DisputeMovement struct {
+	ID            int       `json:"id"`
+	DisputeID     int       `json:"dispute_id"`
+	Type          string    `json:"type"`
+	Amount        int64     `json:"amount"`
+	JournalID     string    `json:"journal_id"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	Reason        string    `json:"reason,omitempty"`
+	ActorType     string    `json:"actor_type"`
+	ActorID       string    `json:"actor_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Escrow statuses
+const (
+	EscrowHeld              = "held"               // All funds held
+	EscrowPartiallyReleased = "partially_released" // Some funds released to the seller
+	EscrowReleased          = "released"           // Everything went to the seller
+	EscrowRefunded          = "refunded"           // Cancelled; whatever was left went back to the buyer
+)
+
+// Escrow movement types
+const (
+	EscrowMovementHold    = "hold"
+	EscrowMovementRelease = "release"
+	EscrowMovementRefund  = "refund"
+)
+
+// Escrow holds a buyer's funds against a contract until they are released to
+// the seller or refunded to the buyer. Held funds sit in the escrow system
+// account for the currency.
+type Escrow struct {
+	ID              int        `json:"id"`
+	ContractID      string     `json:"contract_id"`
+	BuyerWalletID   int        `json:"buyer_wallet_id"`
+	SellerWalletID  int        `json:"seller_wallet_id"`
+	Currency        string     `json:"currency"`
+	Amount          int64      `json:"amount"`
+	ReleasedAmount  int64      `json:"released_amount"`
+	RefundedAmount  int64      `json:"refunded_amount"`
+	Status          string     `json:"status"`
+	Description     string     `json:"description"`
+	ReleaseAt       *time.Time `json:"release_at,omitempty"`
+	ReleaseAttempts int        `json:"release_attempts,omitempty"` // Timed releases refused since the last release
+	ReleaseError    string     `json:"release_error,omitempty"`    // Why the last timed release was refused
+	CreatedByType   string     `json:"created_by_type"`
+	CreatedByID     string     `json:"created_by_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Remaining returns the funds still held
+func (e *Escrow) Remaining() int64 {
+	return e.Amount - e.ReleasedAmount - e.RefundedAmount
+}
+
+// IsOpen reports whether funds are still held
+func (e *Escrow) IsOpen() bool {
+	return e.Status == EscrowHeld || e.Status == EscrowPartiallyReleased
+}
+
+// EscrowMovement is one posting into or out of an escrow
+type EscrowMovement struct {
+	ID            int       `json:"id"`
+	EscrowID      int       `json:"escrow_id"`
+	Type          string    `json:"type"`
+	Amount        int64     `json:"amount"`
+	JournalID     string    `json:"journal_id"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	Reason        string    `json:"reason,omitempty"`
+	ActorType     string    `json:"actor_type"`
+	ActorID       string    `json:"actor_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
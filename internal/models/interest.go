@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// InterestAccount enrols a savings wallet in an interest rate plan. Amounts
+// of interest are decimal strings in minor units, as a day's interest is
+// usually a fraction of one.
+type InterestAccount struct {
+	WalletID       int       `json:"wallet_id"`
+	Plan           string    `json:"plan"`
+	Accrued        string    `json:"accrued"`         // Interest accrued but not yet capitalised
+	AccruedThrough time.Time `json:"accrued_through"` // Last day accrued, as a UTC date
+	CreatedByType  string    `json:"created_by_type"`
+	CreatedByID    string    `json:"created_by_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// InterestAccrual is one day's interest on a wallet's end-of-day balance
+type InterestAccrual struct {
+	ID          int       `json:"id"`
+	WalletID    int       `json:"wallet_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	Plan        string    `json:"plan"`
+	Balance     int64     `json:"balance"` // End-of-day balance
+	Amount      string    `json:"amount"`  // The day's interest
+	Accrued     string    `json:"accrued"` // Uncapitalised interest after the day
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// InterestCapitalisation credits a month's accrued interest to the wallet.
+// Only whole minor units are credited; the fraction is carried forward.
+type InterestCapitalisation struct {
+	ID            int       `json:"id"`
+	WalletID      int       `json:"wallet_id"`
+	Period        time.Time `json:"period"` // First day of the month capitalised
+	Amount        int64     `json:"amount"`
+	Carried       string    `json:"carried"`
+	JournalID     string    `json:"journal_id"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
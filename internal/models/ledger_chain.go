@@ -0,0 +1,105 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// ledgerEntryHashInput is the canonical content covered by a ledger entry hash.
+// Field order and encoding must never change, or existing chains stop verifying.
+// JournalID is left out when empty so entries hashed before journals existed
+// still verify.
+type ledgerEntryHashInput struct {
+	WalletID    int    `json:"wallet_id"`
+	JournalID   string `json:"journal_id,omitempty"`
+	Reference   int    `json:"reference"`
+	Type        string `json:"type"`
+	Amount      int64  `json:"amount"`
+	Balance     int64  `json:"balance"`
+	Description string `json:"description"`
+	ReversalOf  *int   `json:"reversal_of"`
+	CreatedAt   int64  `json:"created_at"` // Unix microseconds, the precision Postgres stores
+	PrevHash    string `json:"prev_hash"`
+}
+
+// ComputeHash returns the hex SHA-256 of the entry's content chained to PrevHash
+func (e *LedgerEntry) ComputeHash() string {
+	raw, _ := json.Marshal(ledgerEntryHashInput{
+		WalletID:    e.WalletID,
+		JournalID:   e.JournalID,
+		Reference:   e.Reference,
+		Type:        e.Type,
+		Amount:      e.Amount,
+		Balance:     e.Balance,
+		Description: e.Description,
+		ReversalOf:  e.ReversalOf,
+		CreatedAt:   e.CreatedAt.UTC().UnixMicro(),
+		PrevHash:    e.PrevHash,
+	})
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainBreak describes the first ledger entry whose hash link does not verify
+type ChainBreak struct {
+	EntryID int    `json:"entry_id"`
+	Reason  string `json:"reason"`
+}
+
+// ChainVerification is the result of walking one wallet's hash chain
+type ChainVerification struct {
+	WalletID        int         `json:"wallet_id"`
+	EntriesChecked  int         `json:"entries_checked"`
+	UnhashedEntries int         `json:"unhashed_entries"` // Legacy entries written before hashing
+	HeadHash        string      `json:"head_hash"`
+	Valid           bool        `json:"valid"`
+	Break           *ChainBreak `json:"break,omitempty"`
+}
+
+// LedgerCheckpoint is a signed snapshot of every wallet's chain head
+type LedgerCheckpoint struct {
+	ID                 int       `json:"id"`
+	MaxEntryID         int       `json:"max_entry_id"`
+	EntryCount         int64     `json:"entry_count"`
+	WalletCount        int64     `json:"wallet_count"`
+	HeadsRoot          string    `json:"heads_root"`
+	PrevCheckpointHash string    `json:"prev_checkpoint_hash"`
+	PayloadHash        string    `json:"payload_hash"`
+	Signature          string    `json:"signature"`
+	KeyID              string    `json:"key_id"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// checkpointPayload is the canonical document an auditor verifies the signature against
+type checkpointPayload struct {
+	MaxEntryID         int    `json:"max_entry_id"`
+	EntryCount         int64  `json:"entry_count"`
+	WalletCount        int64  `json:"wallet_count"`
+	HeadsRoot          string `json:"heads_root"`
+	PrevCheckpointHash string `json:"prev_checkpoint_hash"`
+	CreatedAt          int64  `json:"created_at"` // Unix microseconds
+	KeyID              string `json:"key_id"`
+}
+
+// SigningPayload returns the exact bytes that are hashed and signed for the checkpoint
+func (c *LedgerCheckpoint) SigningPayload() []byte {
+	raw, _ := json.Marshal(checkpointPayload{
+		MaxEntryID:         c.MaxEntryID,
+		EntryCount:         c.EntryCount,
+		WalletCount:        c.WalletCount,
+		HeadsRoot:          c.HeadsRoot,
+		PrevCheckpointHash: c.PrevCheckpointHash,
+		CreatedAt:          c.CreatedAt.UTC().UnixMicro(),
+		KeyID:              c.KeyID,
+	})
+	return raw
+}
+
+// WalletHead is the latest hashed entry of a wallet's chain
+type WalletHead struct {
+	WalletID int
+	EntryID  int
+	Hash     string
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// WalletLimitOverride replaces some of the limits a wallet gets from its KYC
+// tier. Limits are keyed by limit name, e.g. "daily_debit".
+type WalletLimitOverride struct {
+	WalletID      int              `json:"wallet_id"`
+	Limits        map[string]int64 `json:"limits"`
+	UpdatedByType string           `json:"updated_by_type"`
+	UpdatedByID   string           `json:"updated_by_id"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// LimitUsage is what a wallet has posted since the start of a limit period
+type LimitUsage struct {
+	Debit  int64 // Sum of debit entries
+	Credit int64 // Sum of credit entries
+	Count  int64 // Journals touching the wallet
+}
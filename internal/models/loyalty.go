@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Points earning statuses
+const (
+	PointsEarningOpen     = "open"     // Has unredeemed points
+	PointsEarningRedeemed = "redeemed" // Fully redeemed
+	PointsEarningExpired  = "expired"  // Unredeemed points were written off
+)
+
+// PointsEarning is one batch of loyalty points earned on a debit. Points
+// are redeemed oldest first and each batch expires on its own.
+type PointsEarning struct {
+	ID             int        `json:"id"`
+	WalletID       int        `json:"wallet_id"`        // The points wallet
+	SourceWalletID int        `json:"source_wallet_id"` // The wallet whose debit earned the points
+	SourceEntryID  int        `json:"source_entry_id"`
+	Rule           string     `json:"rule"`
+	Points         int64      `json:"points"`
+	Remaining      int64      `json:"remaining"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Status         string     `json:"status"`
+	ExpiryRetryAt  *time.Time `json:"-"`
+	JournalID      string     `json:"journal_id"`
+	LedgerEntryID  int        `json:"ledger_entry_id"` // The points credit
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PointsRedemption converts points into money in a fiat wallet
+type PointsRedemption struct {
+	ID             int       `json:"id"`
+	PointsWalletID int       `json:"points_wallet_id"`
+	WalletID       int       `json:"wallet_id"` // The fiat wallet credited
+	Points         int64     `json:"points"`
+	Amount         int64     `json:"amount"`
+	Currency       string    `json:"currency"`
+	Reference      int       `json:"reference"`
+	JournalID      string    `json:"journal_id"`
+	CreatedByType  string    `json:"created_by_type"`
+	CreatedByID    string    `json:"created_by_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
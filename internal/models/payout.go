@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// Payout statuses
+const (
+	PayoutRequested      = "requested"       // Funds locked; waiting to be sent to the provider
+	PayoutSubmitting     = "submitting"      // Being sent to the provider; resent under the same idempotency key if the worker stops
+	PayoutProcessing     = "processing"      // Accepted by the provider
+	PayoutSucceeded      = "succeeded"       // Paid out; the debit is final
+	PayoutFailed         = "failed"          // Not paid; the funds went back to the wallet
+	PayoutReturned       = "returned"        // Paid, then returned by the bank; the funds went back to the wallet
+	PayoutReleasePending = "release_pending" // Failed or returned, but the wallet could not be credited yet; only the release is retried
+)
+
+// Payout is a withdrawal from a wallet to a bank account. Its funds sit in
+// the payouts in flight system account from request until the provider
+// reports the outcome.
+type Payout struct {
+	ID                int        `json:"id"`
+	WalletID          int        `json:"wallet_id"`
+	Currency          string     `json:"currency"`
+	Amount            int64      `json:"amount"`
+	Reference         int        `json:"reference"`
+	Destination       string     `json:"destination"`
+	Description       string     `json:"description"`
+	Status            string     `json:"status"`
+	Provider          string     `json:"provider"`
+	ProviderReference string     `json:"provider_reference,omitempty"`
+	FailureReason     string     `json:"failure_reason,omitempty"`
+	Attempts          int        `json:"attempts"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at,omitempty"`
+	LockEntryID       int        `json:"lock_entry_id"`
+	FinalEntryID      *int       `json:"final_entry_id,omitempty"`
+	ReleaseEntryID    *int       `json:"release_entry_id,omitempty"`
+	CreatedByType     string     `json:"created_by_type"`
+	CreatedByID       string     `json:"created_by_id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// IdempotencyKey identifies the payout to the provider, which must not pay
+// out twice for the same key
+func (p *Payout) IdempotencyKey() string {
+	return "payout-" + strconv.Itoa(p.ID)
+}
+
+// PendingOutcome returns the status a release pending payout takes once its
+// funds are back in the wallet: returned if it had been paid, failed if not
+func (p *Payout) PendingOutcome() string {
+	if p.FinalEntryID != nil {
+		return PayoutReturned
+	}
+	return PayoutFailed
+}
+
+// IsFinal reports whether the payout can no longer change, apart from a
+// succeeded payout being returned
+func (p *Payout) IsFinal() bool {
+	return p.Status == PayoutSucceeded || p.Status == PayoutFailed || p.Status == PayoutReturned
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Pocket statuses
+const (
+	PocketOpen   = "open"   // Holds money set aside and accepts movements
+	PocketClosed = "closed" // Emptied back into the main balance
+)
+
+// Pocket movement types
+const (
+	PocketMovementDeposit    = "deposit"    // From the main balance into the pocket
+	PocketMovementWithdrawal = "withdrawal" // From the pocket back to the main balance
+	PocketMovementSweep      = "sweep"      // Moved in by the pocket's auto-sweep rule
+)
+
+// Pocket auto-sweep rule kinds
+const (
+	PocketSweepRoundUp       = "round_up"       // Rounds each debit up to a multiple and sets the difference aside
+	PocketSweepCreditPercent = "credit_percent" // Sets a share of each credit aside
+)
+
+// PocketSweep moves money into a pocket as the wallet is credited or debited
+type PocketSweep struct {
+	Kind          string `json:"kind"`
+	Multiple      int64  `json:"multiple,omitempty"`       // round_up: the multiple debits are rounded up to
+	PercentageBPS int64  `json:"percentage_bps,omitempty"` // credit_percent: the share of each credit, in basis points
+}
+
+// Amount returns what the rule sets aside for a ledger entry of entryType
+// and amount
+func (r *PocketSweep) Amount(entryType string, amount int64) int64 {
+	switch {
+	case r == nil:
+		return 0
+	case r.Kind == PocketSweepRoundUp && entryType == "debit" && r.Multiple > 0:
+		return (r.Multiple - amount%r.Multiple) % r.Multiple
+	case r.Kind == PocketSweepCreditPercent && entryType == "credit":
+		return amount * r.PercentageBPS / 10000
+	}
+	return 0
+}
+
+// Pocket is a named sub-balance set aside inside a wallet's balance. The
+// wallet's balance is its main balance plus the balances of its open
+// pockets; moving money between them does not touch the ledger.
+type Pocket struct {
+	ID            int          `json:"id"`
+	WalletID      int          `json:"wallet_id"`
+	Name          string       `json:"name"`
+	TargetAmount  *int64       `json:"target_amount,omitempty"`
+	TargetDate    *time.Time   `json:"target_date,omitempty"`
+	Balance       int64        `json:"balance"`
+	Sweep         *PocketSweep `json:"sweep,omitempty"`
+	Status        string       `json:"status"`
+	CreatedByType string       `json:"created_by_type"`
+	CreatedByID   string       `json:"created_by_id"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	ClosedAt      *time.Time   `json:"closed_at,omitempty"`
+}
+
+// PocketMovement is one internal transfer between a wallet's main balance
+// and one of its pockets
+type PocketMovement struct {
+	ID            int       `json:"id"`
+	PocketID      int       `json:"pocket_id"`
+	Type          string    `json:"type"`
+	Amount        int64     `json:"amount"`
+	Balance       int64     `json:"balance"` // Pocket balance after the movement
+	JournalID     string    `json:"journal_id,omitempty"`
+	LedgerEntryID *int      `json:"ledger_entry_id,omitempty"`
+	Description   string    `json:"description"`
+	CreatedByType string    `json:"created_by_type"`
+	CreatedByID   string    `json:"created_by_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Reserve statuses
+const (
+	ReserveHeld              = "held"               // All funds held
+	ReservePartiallyReleased = "partially_released" // Some funds returned to the merchant
+	ReserveReleased          = "released"           // Everything returned to the merchant
+)
+
+// Reserve sources
+const (
+	ReserveSourceSettlement = "settlement" // Held back from a settlement batch under the wallet's policy
+	ReserveSourceManual     = "manual"     // Held by an operator
+)
+
+// Reserve movement types
+const (
+	ReserveMovementHold    = "hold"
+	ReserveMovementRelease = "release"
+)
+
+// ReservePolicy is a merchant wallet's rolling reserve: PercentageBPS of each
+// settlement is held back for HoldDays, with at most Cap held at once
+type ReservePolicy struct {
+	WalletID      int       `json:"wallet_id"`
+	PercentageBPS int64     `json:"percentage_bps"`
+	HoldDays      int       `json:"hold_days"`
+	Cap           int64     `json:"cap"` // 0 is uncapped
+	UpdatedByType string    `json:"updated_by_type,omitempty"`
+	UpdatedByID   string    `json:"updated_by_id,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Reserve is merchant money held back in the reserve system account until it
+// is released to the merchant's wallet
+type Reserve struct {
+	ID             int        `json:"id"`
+	WalletID       int        `json:"wallet_id"`
+	Currency       string     `json:"currency"`
+	Amount         int64      `json:"amount"`
+	ReleasedAmount int64      `json:"released_amount"`
+	Status         string     `json:"status"`
+	Source         string     `json:"source"`
+	BatchID        *int       `json:"batch_id,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+	ReleaseAt      *time.Time `json:"release_at,omitempty"`
+	CreatedByType  string     `json:"created_by_type"`
+	CreatedByID    string     `json:"created_by_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Remaining returns the funds still held
+func (r *Reserve) Remaining() int64 {
+	return r.Amount - r.ReleasedAmount
+}
+
+// IsOpen reports whether funds are still held
+func (r *Reserve) IsOpen() bool {
+	return r.Status == ReserveHeld || r.Status == ReservePartiallyReleased
+}
+
+// ReserveMovement is one posting into or out of a reserve
+type ReserveMovement struct {
+	ID            int       `json:"id"`
+	ReserveID     int       `json:"reserve_id"`
+	Type          string    `json:"type"`
+	Amount        int64     `json:"amount"`
+	JournalID     string    `json:"journal_id"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	Reason        string    `json:"reason,omitempty"`
+	ActorType     string    `json:"actor_type"`
+	ActorID       string    `json:"actor_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
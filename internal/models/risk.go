@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Risk decisions, from least to most severe
+const (
+	RiskAllow  = "allow"
+	RiskReview = "review" // Posted, and queued for an analyst
+	RiskBlock  = "block"  // Not posted
+)
+
+// Risk case statuses
+const (
+	RiskCaseOpen      = "open"
+	RiskCaseCleared   = "cleared"   // Reviewed and found legitimate
+	RiskCaseConfirmed = "confirmed" // Reviewed and found suspicious
+)
+
+// RiskEvaluation is one rule's decision on a posting to a wallet
+type RiskEvaluation struct {
+	ID            int       `json:"id"`
+	WalletID      int       `json:"wallet_id"`
+	JournalID     string    `json:"journal_id,omitempty"`
+	LedgerEntryID *int      `json:"ledger_entry_id,omitempty"`
+	CaseID        *int      `json:"case_id,omitempty"`
+	Rule          string    `json:"rule"`
+	Decision      string    `json:"decision"`
+	Reason        string    `json:"reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RiskCase is a posting the risk rules flagged for review or blocked, waiting
+// for an analyst's decision
+type RiskCase struct {
+	ID             int        `json:"id"`
+	WalletID       int        `json:"wallet_id"`
+	Decision       string     `json:"decision"`
+	TxnType        string     `json:"txn_type"`
+	Direction      string     `json:"direction"`
+	Amount         int64      `json:"amount"`
+	Currency       string     `json:"currency"`
+	Reference      int        `json:"reference"`
+	JournalID      string     `json:"journal_id,omitempty"`
+	LedgerEntryID  *int       `json:"ledger_entry_id,omitempty"`
+	Status         string     `json:"status"`
+	ResolutionNote string     `json:"resolution_note,omitempty"`
+	ResolvedByType string     `json:"resolved_by_type,omitempty"`
+	ResolvedByID   string     `json:"resolved_by_id,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// RiskActivity is what a wallet has posted since some time
+type RiskActivity struct {
+	Debit  int64
+	Credit int64
+	Count  int64 // Journals touching the wallet
+}
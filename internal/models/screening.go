@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Screening review subjects
+const (
+	ScreeningSubjectWallet   = "wallet"
+	ScreeningSubjectTransfer = "transfer"
+	ScreeningSubjectSplit    = "split"
+)
+
+// Screening review statuses
+const (
+	ScreeningPending   = "pending"
+	ScreeningCleared   = "cleared"   // False positive; the wallet is activated or the payment posted
+	ScreeningConfirmed = "confirmed" // True match; the wallet is frozen or the payment dropped
+)
+
+// ScreeningMatch is a watchlist entry a wallet holder's name matched
+type ScreeningMatch struct {
+	WalletID    int    `json:"wallet_id"`
+	Name        string `json:"name"` // The name screened
+	ListEntryID string `json:"list_entry_id"`
+	ListName    string `json:"list_name"` // The name on the watchlist
+	Program     string `json:"program,omitempty"`
+	Score       int    `json:"score"` // 0-100
+}
+
+// ScreeningReview holds a wallet or transfer back after a watchlist hit until
+// a reviewer clears or confirms it
+type ScreeningReview struct {
+	ID             int              `json:"id"`
+	SubjectType    string           `json:"subject_type"`
+	WalletID       int              `json:"wallet_id"`
+	ToWalletID     *int             `json:"to_wallet_id,omitempty"`
+	Amount         int64            `json:"amount,omitempty"`
+	Reference      int              `json:"reference,omitempty"`
+	Description    string           `json:"description,omitempty"`
+	Split          json.RawMessage  `json:"split,omitempty"` // The held split payment request
+	Matches        []ScreeningMatch `json:"matches"`
+	Status         string           `json:"status"`
+	JournalID      string           `json:"journal_id,omitempty"`
+	ResolutionNote string           `json:"resolution_note,omitempty"`
+	ResolvedByType string           `json:"resolved_by_type,omitempty"`
+	ResolvedByID   string           `json:"resolved_by_id,omitempty"`
+	ResolvedAt     *time.Time       `json:"resolved_at,omitempty"`
+	CreatedByType  string           `json:"created_by_type"`
+	CreatedByID    string           `json:"created_by_id"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Settlement capture statuses
+const (
+	CapturePending = "pending" // In the merchant pending account
+	CaptureSettled = "settled" // Paid to the merchant wallet in a batch
+)
+
+// Settlement batch triggers
+const (
+	SettlementTriggerScheduled = "scheduled" // Created by the settlement job
+	SettlementTriggerManual    = "manual"    // Requested through the API
+)
+
+// MerchantSettlementSchedule is a merchant wallet's settlement schedule,
+// e.g. "T+1", "weekly:friday" or "manual"
+type MerchantSettlementSchedule struct {
+	WalletID      int       `json:"wallet_id"`
+	Schedule      string    `json:"schedule"`
+	UpdatedByType string    `json:"updated_by_type,omitempty"`
+	UpdatedByID   string    `json:"updated_by_id,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SettlementCapture is a captured payment held in the merchant pending
+// account until it is settled. Settled captures are a batch's line items.
+type SettlementCapture struct {
+	ID            int       `json:"id"`
+	WalletID      int       `json:"wallet_id"`
+	Currency      string    `json:"currency"`
+	Amount        int64     `json:"amount"`
+	Reference     int       `json:"reference"`
+	Description   string    `json:"description"`
+	Schedule      string    `json:"schedule"`
+	CapturedAt    time.Time `json:"captured_at"`
+	EligibleOn    time.Time `json:"eligible_on"` // Date only
+	Status        string    `json:"status"`
+	JournalID     string    `json:"journal_id"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	BatchID       *int      `json:"batch_id,omitempty"`
+}
+
+// SettlementBatch moves a set of captures from pending to the merchant wallet
+type SettlementBatch struct {
+	ID             int       `json:"id"`
+	WalletID       int       `json:"wallet_id"`
+	Currency       string    `json:"currency"`
+	Amount         int64     `json:"amount"`
+	ReserveAmount  int64     `json:"reserve_amount"` // Held back from Amount under the wallet's reserve policy
+	ItemCount      int       `json:"item_count"`
+	SettlementDate time.Time `json:"settlement_date"` // Date only
+	Trigger        string    `json:"trigger"`
+	JournalID      string    `json:"journal_id"`
+	LedgerEntryID  int       `json:"ledger_entry_id"`
+	CreatedByType  string    `json:"created_by_type"`
+	CreatedByID    string    `json:"created_by_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
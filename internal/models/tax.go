@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// TaxPosting records tax collected on one fee. Reversing the fee's journal
+// adds a negative posting, so sums over a period give the net tax due.
+type TaxPosting struct {
+	ID            int       `json:"id"`
+	JournalID     string    `json:"journal_id"`
+	LedgerEntryID int       `json:"ledger_entry_id"`
+	Jurisdiction  string    `json:"jurisdiction"`
+	TaxName       string    `json:"tax_name"`
+	Currency      string    `json:"currency"`
+	RateBPS       int64     `json:"rate_bps"`
+	Inclusive     bool      `json:"inclusive"`
+	BaseAmount    int64     `json:"base_amount"`
+	TaxAmount     int64     `json:"tax_amount"`
+	ReversalOf    *int      `json:"reversal_of,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TaxSummaryLine totals one tax in one currency over a period
+type TaxSummaryLine struct {
+	Jurisdiction string `json:"jurisdiction"`
+	TaxName      string `json:"tax_name"`
+	Currency     string `json:"currency"`
+	RateBPS      int64  `json:"rate_bps"`
+	Inclusive    bool   `json:"inclusive"`
+	Postings     int    `json:"postings"`
+	BaseAmount   int64  `json:"base_amount"`
+	TaxAmount    int64  `json:"tax_amount"`
+}
+
+// TaxSummary is the tax collected over [From, To), for filing
+type TaxSummary struct {
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Lines       []TaxSummaryLine `json:"lines"`
+}
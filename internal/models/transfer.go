@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Transfer schedule statuses
+const (
+	TransferScheduleActive    = "active"
+	TransferSchedulePaused    = "paused"
+	TransferScheduleCancelled = "cancelled"
+	TransferScheduleCompleted = "completed" // Past its end date or out of occurrences
+)
+
+// Transfer run statuses
+const (
+	TransferRunRunning   = "running"   // Transient, only visible inside the executing transaction
+	TransferRunSucceeded = "succeeded" // Money moved
+	TransferRunFailed    = "failed"    // Attempt failed; the occurrence will be retried
+	TransferRunAbandoned = "abandoned" // Final attempt failed; the occurrence was skipped
+	TransferRunHeld      = "held"      // Held for sanctions screening review; posted if the review clears it
+)
+
+// TransferSchedule is a standing order that moves money between two wallets
+// on a recurring rule
+type TransferSchedule struct {
+	ID            int        `json:"id"`
+	FromWalletID  int        `json:"from_wallet_id"`
+	ToWalletID    int        `json:"to_wallet_id"`
+	Amount        int64      `json:"amount"`
+	Description   string     `json:"description"`
+	RuleKind      string     `json:"rule_kind"` // "interval" or "cron"
+	Rule          string     `json:"rule"`
+	Timezone      string     `json:"timezone"`
+	StartAt       time.Time  `json:"start_at"`
+	EndAt         *time.Time `json:"end_at,omitempty"`
+	Status        string     `json:"status"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	Attempts      int        `json:"attempts"`
+	MaxRetries    int        `json:"max_retries"`
+	Failures      int        `json:"failures"` // Unexpected errors in a row, which back off the next attempt
+	CreatedByType string     `json:"created_by_type"`
+	CreatedByID   string     `json:"created_by_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TransferRun is one attempt at executing an occurrence of a schedule
+type TransferRun struct {
+	ID            int       `json:"id"`
+	ScheduleID    int       `json:"schedule_id"`
+	OccurrenceAt  time.Time `json:"occurrence_at"`
+	Attempt       int       `json:"attempt"`
+	Status        string    `json:"status"`
+	JournalID     string    `json:"journal_id,omitempty"`
+	LedgerEntryID *int      `json:"ledger_entry_id,omitempty"` // The debit from the source wallet
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -4,14 +4,70 @@ import (
 	"time"
 )
 
+// Wallet statuses
+const (
+	WalletStatusActive        = "active"         // Accepts credits and debits
+	WalletStatusFrozen        = "frozen"         // Temporarily blocked from posting
+	WalletStatusClosed        = "closed"         // Permanently closed; balance must be zero
+	WalletStatusPendingReview = "pending_review" // Blocked from posting until a screening review clears the holder
+)
+
+// Wallet types
+const (
+	WalletTypeStandard = "standard" // Customer wallet
+	WalletTypeSystem   = "system"   // Internal account identified by SystemCode; may go negative
+)
+
+// System account codes. System accounts are created on demand per currency.
+const (
+	// SystemExternalClearing is the counterparty for money entering or leaving
+	// the ledger through plain credits and debits
+	SystemExternalClearing = "external_clearing"
+	// SystemAdjustments is the counterparty for manual operator corrections
+	SystemAdjustments = "adjustments"
+	// SystemFeeRevenue collects the fees charged on postings
+	SystemFeeRevenue = "fee_revenue"
+	// SystemTaxPayable holds tax collected on fees until it is remitted
+	SystemTaxPayable = "tax_payable"
+	// SystemEscrow holds buyer funds for open escrows
+	SystemEscrow = "escrow"
+	// SystemMerchantPending holds captured merchant funds until they are settled
+	SystemMerchantPending = "merchant_pending"
+	// SystemMerchantReserve holds merchant funds kept back as a rolling reserve
+	SystemMerchantReserve = "merchant_reserve"
+	// SystemPayoutsInFlight holds funds locked for payouts until the provider
+	// reports the outcome
+	SystemPayoutsInFlight = "payouts_in_flight"
+	// SystemDisputes funds provisional credits on disputed debits until the
+	// disputes are decided
+	SystemDisputes = "disputes"
+	// SystemMarketingExpense funds promotional credit lots and takes back
+	// what expires unspent
+	SystemMarketingExpense = "marketing_expense"
+	// SystemPointsLiability is the points-denominated counterparty of every
+	// earned point; its negative balance is the points outstanding
+	SystemPointsLiability = "points_liability"
+	// SystemLoyaltyExpense pays out the money points are redeemed for
+	SystemLoyaltyExpense = "loyalty_expense"
+	// SystemInterestExpense funds the interest capitalised into savings wallets
+	SystemInterestExpense = "interest_expense"
+)
+
 // Wallet represents a customer's wallet
 type Wallet struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	Currency  string    `json:"currency"`
-	Balance   int64     `json:"balance"` // Stored in cents/smallest unit
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	Currency   string    `json:"currency"`
+	AssetType  string    `json:"asset_type"` // What Currency denominates; see the Asset constants
+	Balance    int64     `json:"balance"`    // Stored in cents/smallest unit
+	Status     string    `json:"status"`
+	Type       string    `json:"type"`
+	SystemCode string    `json:"system_code,omitempty"`
+	KYCTier    string    `json:"kyc_tier,omitempty"`    // Selects the wallet's limits; empty uses the default tier
+	Segment    string    `json:"segment,omitempty"`     // Customer segment fee rules can match on, e.g. "merchant"
+	HolderName string    `json:"holder_name,omitempty"` // Screened against sanctions watchlists
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // NewWallet creates a new Wallet instance
@@ -21,23 +77,58 @@ func NewWallet(userID int, currency string) *Wallet {
 		UserID:    userID,
 		Currency:  currency,
 		Balance:   0,
+		Status:    WalletStatusActive,
+		Type:      WalletTypeStandard,
+		AssetType: AssetFiat,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 }
 
+// NewSystemWallet creates a system account for the given code and currency
+func NewSystemWallet(code, currency, assetType string) *Wallet {
+	wallet := NewWallet(0, currency)
+	wallet.Type = WalletTypeSystem
+	wallet.AssetType = assetType
+	wallet.SystemCode = code
+	return wallet
+}
+
+// IsSystem reports whether the wallet is an internal system account
+func (w *Wallet) IsSystem() bool {
+	return w.Type == WalletTypeSystem
+}
+
+// IsMonetary reports whether the wallet holds money rather than points
+func (w *Wallet) IsMonetary() bool {
+	return w.AssetType != AssetPoints
+}
+
 // LedgerEntry represents an entry in the transaction ledger for a wallet
 type LedgerEntry struct {
 	ID          int       `json:"id"`
 	WalletID    int       `json:"wallet_id"`
-	Reference   int       `json:"reference"` // Reference to the external transaction
-	Type        string    `json:"type"`      // "credit" or "debit"
-	Amount      int64     `json:"amount"`    // Stored in cents/smallest unit
-	Balance     int64     `json:"balance"`   // Balance of the wallet after this entry
+	JournalID   string    `json:"journal_id"` // Groups the entries of one money movement
+	Reference   int       `json:"reference"`  // Reference to the external transaction
+	Type        string    `json:"type"`       // "credit" or "debit"
+	Amount      int64     `json:"amount"`     // Stored in cents/smallest unit
+	Balance     int64     `json:"balance"`    // Balance of the wallet after this entry
 	Description string    `json:"description"`
+	ReversalOf  *int      `json:"reversal_of"` // ID of the entry this one reverses
+	PrevHash    string    `json:"prev_hash"`   // Hash of the wallet's previous entry
+	Hash        string    `json:"hash"`        // See ComputeHash
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// SignedAmount returns the entry amount as a balance change: positive for
+// credits and negative for debits. A balanced journal's signed amounts sum to zero.
+func (e *LedgerEntry) SignedAmount() int64 {
+	if e.Type == "debit" {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
 // NewLedgerEntry creates a new LedgerEntry instance
 func NewLedgerEntry(walletID, reference int, entryType string, amount, balance int64, description string) *LedgerEntry {
 	return &LedgerEntry{
@@ -48,6 +139,6 @@ func NewLedgerEntry(walletID, reference int, entryType string, amount, balance i
 		Amount:      amount,
 		Balance:     balance,
 		Description: description,
-		CreatedAt:   time.Now(),
+		CreatedAt:   time.Now().UTC().Truncate(time.Microsecond), // Match Postgres precision so hashes verify
 	}
 }
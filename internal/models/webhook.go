@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook event types emitted to merchants
+const (
+	EventBalanceCredited = "balance.credited"
+	EventBalanceDebited  = "balance.debited"
+	EventPayoutUpdated   = "payout.updated"
+
+	// EventWildcard subscribes an endpoint to every event type
+	EventWildcard = "*"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending    = "pending"     // Waiting for its first attempt
+	WebhookDeliveryFailed     = "failed"      // Last attempt failed, a retry is scheduled
+	WebhookDeliverySucceeded  = "succeeded"   // Receiver acknowledged with a 2xx
+	WebhookDeliveryDeadLetter = "dead_letter" // Retries exhausted, needs manual redelivery
+)
+
+// WebhookEndpoint is a merchant-registered URL that receives signed event payloads
+type WebhookEndpoint struct {
+	ID         int       `json:"id"`
+	MerchantID int       `json:"merchant_id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"` // Shared HMAC-SHA256 signing secret
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint wants events of the given type
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	for _, t := range e.EventTypes {
+		if t == eventType || t == EventWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the envelope POSTed to merchant endpoints
+type WebhookEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	MerchantID int             `json:"merchant_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookDelivery tracks the delivery of one event to one endpoint
+type WebhookDelivery struct {
+	ID             int             `json:"id"`
+	EndpointID     int             `json:"endpoint_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// WebhookDeliveryFilter narrows a delivery log query; zero values are ignored
+type WebhookDeliveryFilter struct {
+	EndpointID int
+	MerchantID int
+	Status     string
+	Limit      int
+}
+
+// NewWebhookDelivery creates a pending delivery that is due at now
+func NewWebhookDelivery(endpointID int, event *WebhookEvent, payload []byte, now time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		EndpointID:    endpointID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
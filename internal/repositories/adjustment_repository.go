@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// AdjustmentRepository defines the interface for maker-checker adjustment data operations
+type AdjustmentRepository interface {
+	CreateAdjustment(ctx context.Context, adjustment *models.AdjustmentRequest) error
+	GetAdjustmentByID(ctx context.Context, id int) (*models.AdjustmentRequest, error)
+	GetAdjustmentByIDForUpdate(ctx context.Context, id int) (*models.AdjustmentRequest, error)
+	ListAdjustments(ctx context.Context, filter models.AdjustmentFilter) ([]models.AdjustmentRequest, error)
+	UpdateAdjustment(ctx context.Context, adjustment *models.AdjustmentRequest) error
+	// ExpireAdjustments marks pending requests past their expiry as expired and returns them
+	ExpireAdjustments(ctx context.Context, now time.Time) ([]models.AdjustmentRequest, error)
+	CreateDecision(ctx context.Context, decision *models.AdjustmentDecision) error
+	ListDecisions(ctx context.Context, adjustmentID int) ([]models.AdjustmentDecision, error)
+}
+
+// postgresAdjustmentRepository implements AdjustmentRepository for PostgreSQL
+type postgresAdjustmentRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAdjustmentRepository creates a new PostgreSQL adjustment repository
+func NewPostgresAdjustmentRepository(db *sql.DB) AdjustmentRepository {
+	return &postgresAdjustmentRepository{db: db}
+}
+
+const adjustmentColumns = `id, wallet_id, type, amount, reason, reference, status, required_approvals, proposed_by_type, proposed_by_id, ledger_entry_id, expires_at, decided_at, created_at, updated_at`
+
+func (r *postgresAdjustmentRepository) CreateAdjustment(ctx context.Context, a *models.AdjustmentRequest) error {
+	query := `INSERT INTO adjustment_requests (wallet_id, type, amount, reason, reference, status, required_approvals, proposed_by_type, proposed_by_id, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, a.WalletID, a.Type, a.Amount, a.Reason, a.Reference, a.Status,
+		a.RequiredApprovals, a.ProposedByType, a.ProposedByID, a.ExpiresAt, a.CreatedAt, a.UpdatedAt).Scan(&a.ID)
+}
+
+func (r *postgresAdjustmentRepository) GetAdjustmentByID(ctx context.Context, id int) (*models.AdjustmentRequest, error) {
+	return r.getAdjustment(ctx, `SELECT `+adjustmentColumns+` FROM adjustment_requests WHERE id = $1`, id)
+}
+
+func (r *postgresAdjustmentRepository) GetAdjustmentByIDForUpdate(ctx context.Context, id int) (*models.AdjustmentRequest, error) {
+	return r.getAdjustment(ctx, `SELECT `+adjustmentColumns+` FROM adjustment_requests WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresAdjustmentRepository) getAdjustment(ctx context.Context, query string, id int) (*models.AdjustmentRequest, error) {
+	adjustment, err := scanAdjustment(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Adjustment not found
+	}
+	return adjustment, err
+}
+
+func (r *postgresAdjustmentRepository) ListAdjustments(ctx context.Context, filter models.AdjustmentFilter) ([]models.AdjustmentRequest, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.WalletID != 0 {
+		add("wallet_id = $%d", filter.WalletID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+
+	query := `SELECT ` + adjustmentColumns + ` FROM adjustment_requests`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args))
+	return r.queryAdjustments(ctx, query, args...)
+}
+
+func (r *postgresAdjustmentRepository) UpdateAdjustment(ctx context.Context, a *models.AdjustmentRequest) error {
+	query := `UPDATE adjustment_requests SET status = $1, ledger_entry_id = $2, decided_at = $3, updated_at = $4 WHERE id = $5`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, a.Status, a.LedgerEntryID, a.DecidedAt, a.UpdatedAt, a.ID)
+	return err
+}
+
+func (r *postgresAdjustmentRepository) ExpireAdjustments(ctx context.Context, now time.Time) ([]models.AdjustmentRequest, error) {
+	query := `UPDATE adjustment_requests SET status = $1, decided_at = $2, updated_at = $2
+		WHERE status = $3 AND expires_at <= $2
+		RETURNING ` + adjustmentColumns
+	return r.queryAdjustments(ctx, query, models.AdjustmentExpired, now, models.AdjustmentPending)
+}
+
+func (r *postgresAdjustmentRepository) queryAdjustments(ctx context.Context, query string, args ...interface{}) ([]models.AdjustmentRequest, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []models.AdjustmentRequest
+	for rows.Next() {
+		adjustment, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, *adjustment)
+	}
+	return adjustments, rows.Err()
+}
+
+func (r *postgresAdjustmentRepository) CreateDecision(ctx context.Context, d *models.AdjustmentDecision) error {
+	query := `INSERT INTO adjustment_decisions (adjustment_id, decision, actor_type, actor_id, comment, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, d.AdjustmentID, d.Decision, d.ActorType, d.ActorID, nullString(d.Comment), d.CreatedAt).Scan(&d.ID)
+}
+
+func (r *postgresAdjustmentRepository) ListDecisions(ctx context.Context, adjustmentID int) ([]models.AdjustmentDecision, error) {
+	query := `SELECT id, adjustment_id, decision, actor_type, actor_id, comment, created_at FROM adjustment_decisions WHERE adjustment_id = $1 ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decisions []models.AdjustmentDecision
+	for rows.Next() {
+		var (
+			d       models.AdjustmentDecision
+			comment sql.NullString
+		)
+		if err := rows.Scan(&d.ID, &d.AdjustmentID, &d.Decision, &d.ActorType, &d.ActorID, &comment, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.Comment = comment.String
+		decisions = append(decisions, d)
+	}
+	return decisions, rows.Err()
+}
+
+func scanAdjustment(row rowScanner) (*models.AdjustmentRequest, error) {
+	var (
+		a             models.AdjustmentRequest
+		ledgerEntryID sql.NullInt64
+		decidedAt     sql.NullTime
+	)
+	if err := row.Scan(&a.ID, &a.WalletID, &a.Type, &a.Amount, &a.Reason, &a.Reference, &a.Status, &a.RequiredApprovals,
+		&a.ProposedByType, &a.ProposedByID, &ledgerEntryID, &a.ExpiresAt, &decidedAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if ledgerEntryID.Valid {
+		id := int(ledgerEntryID.Int64)
+		a.LedgerEntryID = &id
+	}
+	if decidedAt.Valid {
+		a.DecidedAt = &decidedAt.Time
+	}
+	return &a, nil
+}
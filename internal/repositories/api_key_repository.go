@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/lib/pq"
+)
+
+// APIKeyRepository defines the interface for API key data operations
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	GetAPIKeyByID(ctx context.Context, id int) (*models.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	MarkRotated(ctx context.Context, id, rotatedTo int, expiresAt time.Time) error
+	RevokeAPIKey(ctx context.Context, id int, revokedAt time.Time) error
+	TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error
+}
+
+// postgresAPIKeyRepository implements APIKeyRepository for PostgreSQL
+type postgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAPIKeyRepository creates a new PostgreSQL API key repository
+func NewPostgresAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &postgresAPIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, created_at, expires_at, revoked_at, last_used_at, rotated_to`
+
+func (r *postgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	query := `INSERT INTO api_keys (name, prefix, key_hash, scopes, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.CreatedAt).Scan(&key.ID)
+}
+
+func (r *postgresAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id int) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+	key, err := scanAPIKey(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Key not found
+	}
+	return key, err
+}
+
+func (r *postgresAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+	key, err := scanAPIKey(executor(ctx, r.db).QueryRowContext(ctx, query, prefix))
+	if err == sql.ErrNoRows {
+		return nil, nil // Key not found
+	}
+	return key, err
+}
+
+func (r *postgresAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *postgresAPIKeyRepository) MarkRotated(ctx context.Context, id, rotatedTo int, expiresAt time.Time) error {
+	query := `UPDATE api_keys SET rotated_to = $1, expires_at = $2 WHERE id = $3`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, rotatedTo, expiresAt, id)
+	return err
+}
+
+func (r *postgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, id int, revokedAt time.Time) error {
+	query := `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, revokedAt, id)
+	return err
+}
+
+func (r *postgresAPIKeyRepository) TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, usedAt, id)
+	return err
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	var rotatedTo sql.NullInt64
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes), &key.CreatedAt, &key.ExpiresAt, &key.RevokedAt, &key.LastUsedAt, &rotatedTo)
+	if err != nil {
+		return nil, err
+	}
+	if rotatedTo.Valid {
+		id := int(rotatedTo.Int64)
+		key.RotatedTo = &id
+	}
+	return key, nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// AuditRepository defines the interface for the append-only audit log
+type AuditRepository interface {
+	CreateAuditRecord(ctx context.Context, record *models.AuditRecord) error
+	ListAuditRecords(ctx context.Context, filter models.AuditFilter) ([]models.AuditRecord, error)
+}
+
+// postgresAuditRepository implements AuditRepository for PostgreSQL
+type postgresAuditRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditRepository creates a new PostgreSQL audit repository
+func NewPostgresAuditRepository(db *sql.DB) AuditRepository {
+	return &postgresAuditRepository{db: db}
+}
+
+func (r *postgresAuditRepository) CreateAuditRecord(ctx context.Context, record *models.AuditRecord) error {
+	query := `INSERT INTO audit_log (action, entity_type, entity_id, actor_type, actor_id, request_id, source_ip, before_state, after_state, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, record.Action, record.EntityType, record.EntityID, record.ActorType, record.ActorID,
+		nullString(record.RequestID), nullString(record.SourceIP), nullJSON(record.Before), nullJSON(record.After), record.CreatedAt).Scan(&record.ID)
+}
+
+func (r *postgresAuditRepository) ListAuditRecords(ctx context.Context, filter models.AuditFilter) ([]models.AuditRecord, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.EntityType != "" {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		add("entity_id = $%d", filter.EntityID)
+	}
+	if filter.ActorType != "" {
+		add("actor_type = $%d", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+
+	query := `SELECT id, action, entity_type, entity_id, actor_type, actor_id, request_id, source_ip, before_state, after_state, created_at FROM audit_log`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args))
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []models.AuditRecord
+	for rows.Next() {
+		var (
+			record              models.AuditRecord
+			requestID, ip       sql.NullString
+			beforeRaw, afterRaw []byte
+		)
+		if err := rows.Scan(&record.ID, &record.Action, &record.EntityType, &record.EntityID, &record.ActorType, &record.ActorID,
+			&requestID, &ip, &beforeRaw, &afterRaw, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+		record.RequestID = requestID.String
+		record.SourceIP = ip.String
+		record.Before = beforeRaw
+		record.After = afterRaw
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// nullJSON stores empty JSON documents as NULL. Documents are sent as text
+// because lib/pq encodes []byte parameters as bytea.
+func nullJSON(raw []byte) sql.NullString {
+	return sql.NullString{String: string(raw), Valid: len(raw) > 0}
+}
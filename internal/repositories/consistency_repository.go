@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// WalletLedgerTotal is a wallet's stored balance alongside the sum of its ledger entries
+type WalletLedgerTotal struct {
+	WalletID    int
+	Currency    string
+	Balance     int64
+	LedgerTotal int64
+	EntryCount  int64
+}
+
+// JournalImbalance is a journal whose legs do not net to zero in one currency
+type JournalImbalance struct {
+	JournalID string
+	Currency  string
+	Net       int64
+}
+
+// JournalStats counts the entries covered by the journal balance check
+type JournalStats struct {
+	Journals      int64
+	LegacyEntries int64
+}
+
+// ConsistencyRepository defines the queries behind ledger-vs-wallet consistency checks
+type ConsistencyRepository interface {
+	// ListWalletLedgerTotals returns every wallet's balance and ledger sum from one snapshot
+	ListWalletLedgerTotals(ctx context.Context) ([]WalletLedgerTotal, error)
+	// ListRunningBalanceBreaks returns, per wallet, the first entry whose balance
+	// column differs from the running sum of amounts, with that expected sum
+	ListRunningBalanceBreaks(ctx context.Context) ([]models.Discrepancy, error)
+	// ListJournalImbalances returns double-entry journals that do not balance
+	ListJournalImbalances(ctx context.Context) ([]JournalImbalance, error)
+	GetJournalStats(ctx context.Context) (JournalStats, error)
+	CreateReport(ctx context.Context, report *models.ConsistencyReport) error
+	GetLatestReport(ctx context.Context) (*models.ConsistencyReport, error)
+}
+
+// postgresConsistencyRepository implements ConsistencyRepository for PostgreSQL
+type postgresConsistencyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresConsistencyRepository creates a new PostgreSQL consistency repository
+func NewPostgresConsistencyRepository(db *sql.DB) ConsistencyRepository {
+	return &postgresConsistencyRepository{db: db}
+}
+
+const signedAmount = `CASE WHEN e.type = 'debit' THEN -e.amount ELSE e.amount END`
+
+// legacyCutoff is the highest entry ID posted before double-entry journals
+const legacyCutoff = `COALESCE((SELECT max_legacy_entry_id FROM double_entry_cutover), 0)`
+
+func (r *postgresConsistencyRepository) ListWalletLedgerTotals(ctx context.Context) ([]WalletLedgerTotal, error) {
+	query := `SELECT w.id, w.currency, w.balance, COALESCE(SUM(` + signedAmount + `), 0), COUNT(e.id)
+		FROM wallets w LEFT JOIN ledger_entries e ON e.wallet_id = w.id
+		GROUP BY w.id, w.currency, w.balance
+		ORDER BY w.id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []WalletLedgerTotal
+	for rows.Next() {
+		var t WalletLedgerTotal
+		if err := rows.Scan(&t.WalletID, &t.Currency, &t.Balance, &t.LedgerTotal, &t.EntryCount); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+func (r *postgresConsistencyRepository) ListRunningBalanceBreaks(ctx context.Context) ([]models.Discrepancy, error) {
+	// Entries on one wallet are posted under the wallet's row lock, so ID order is posting order
+	query := `SELECT DISTINCT ON (wallet_id) wallet_id, id, currency, expected, balance FROM (
+			SELECT e.wallet_id, e.id, w.currency, e.balance,
+				SUM(` + signedAmount + `) OVER (PARTITION BY e.wallet_id ORDER BY e.id) AS expected
+			FROM ledger_entries e JOIN wallets w ON w.id = e.wallet_id
+		) running
+		WHERE balance <> expected
+		ORDER BY wallet_id, id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breaks []models.Discrepancy
+	for rows.Next() {
+		d := models.Discrepancy{Kind: models.DiscrepancyRunningBalance}
+		if err := rows.Scan(&d.WalletID, &d.EntryID, &d.Currency, &d.Expected, &d.Actual); err != nil {
+			return nil, err
+		}
+		breaks = append(breaks, d)
+	}
+	return breaks, rows.Err()
+}
+
+func (r *postgresConsistencyRepository) ListJournalImbalances(ctx context.Context) ([]JournalImbalance, error) {
+	query := `SELECT e.journal_id, w.currency, SUM(` + signedAmount + `)
+		FROM ledger_entries e JOIN wallets w ON w.id = e.wallet_id
+		WHERE e.journal_id IS NOT NULL AND e.id > ` + legacyCutoff + `
+		GROUP BY e.journal_id, w.currency
+		HAVING SUM(` + signedAmount + `) <> 0
+		ORDER BY MIN(e.id)`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var imbalances []JournalImbalance
+	for rows.Next() {
+		var j JournalImbalance
+		if err := rows.Scan(&j.JournalID, &j.Currency, &j.Net); err != nil {
+			return nil, err
+		}
+		imbalances = append(imbalances, j)
+	}
+	return imbalances, rows.Err()
+}
+
+func (r *postgresConsistencyRepository) GetJournalStats(ctx context.Context) (JournalStats, error) {
+	query := `SELECT
+			COUNT(DISTINCT journal_id) FILTER (WHERE id > ` + legacyCutoff + `),
+			COUNT(*) FILTER (WHERE id <= ` + legacyCutoff + `)
+		FROM ledger_entries`
+	var stats JournalStats
+	err := executor(ctx, r.db).QueryRowContext(ctx, query).Scan(&stats.Journals, &stats.LegacyEntries)
+	return stats, err
+}
+
+func (r *postgresConsistencyRepository) CreateReport(ctx context.Context, report *models.ConsistencyReport) error {
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO consistency_reports (started_at, finished_at, ok, wallets_checked, entries_checked, discrepancy_count, report)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, report.StartedAt, report.FinishedAt, report.OK,
+		report.WalletsChecked, report.EntriesChecked, len(report.Discrepancies), string(raw)).Scan(&report.ID)
+}
+
+func (r *postgresConsistencyRepository) GetLatestReport(ctx context.Context) (*models.ConsistencyReport, error) {
+	var (
+		id  int
+		raw []byte
+	)
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT id, report FROM consistency_reports ORDER BY id DESC LIMIT 1`).Scan(&id, &raw)
+	if err == sql.ErrNoRows {
+		return nil, nil // No check has run yet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report models.ConsistencyReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return nil, err
+	}
+	report.ID = id
+	return &report, nil
+}
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// CreditLotRepository defines the interface for credit lot data operations
+type CreditLotRepository interface {
+	CreateLot(ctx context.Context, lot *models.CreditLot) error
+	GetLotByID(ctx context.Context, id int) (*models.CreditLot, error)
+	// ListLots returns a wallet's lots, newest first, optionally narrowed to
+	// a status
+	ListLots(ctx context.Context, walletID int, status string, limit int) ([]models.CreditLot, error)
+	// ListOpenLotsForUpdate locks a wallet's open lots
+	ListOpenLotsForUpdate(ctx context.Context, walletID int) ([]*models.CreditLot, error)
+	// OpenLotTotals sums the remaining amount of a wallet's open lots by type
+	OpenLotTotals(ctx context.Context, walletID int) (map[string]int64, error)
+	// ClaimExpiredLot locks one open lot whose expiry is due together with
+	// its wallet, skipping lots or wallets other transactions hold. It
+	// returns nil when nothing is due.
+	ClaimExpiredLot(ctx context.Context, now time.Time) (*models.CreditLot, error)
+	UpdateLot(ctx context.Context, lot *models.CreditLot) error
+	CreateMovement(ctx context.Context, movement *models.CreditLotMovement) error
+	ListMovements(ctx context.Context, lotID int) ([]models.CreditLotMovement, error)
+}
+
+// postgresCreditLotRepository implements CreditLotRepository for PostgreSQL
+type postgresCreditLotRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCreditLotRepository creates a new PostgreSQL credit lot repository
+func NewPostgresCreditLotRepository(db *sql.DB) CreditLotRepository {
+	return &postgresCreditLotRepository{db: db}
+}
+
+const creditLotColumns = `id, wallet_id, type, source, currency, amount, remaining, restrictions, expires_at, status, expiry_retry_at,
+	journal_id, ledger_entry_id, created_by_type, created_by_id, created_at, updated_at`
+
+func (r *postgresCreditLotRepository) CreateLot(ctx context.Context, lot *models.CreditLot) error {
+	restrictions, err := json.Marshal(lot.Restrictions)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO credit_lots (wallet_id, type, source, currency, amount, remaining, restrictions, expires_at, status, journal_id,
+		ledger_entry_id, created_by_type, created_by_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, lot.WalletID, lot.Type, lot.Source, lot.Currency, lot.Amount, lot.Remaining,
+		restrictions, lot.ExpiresAt, lot.Status, lot.JournalID, lot.LedgerEntryID, lot.CreatedByType, lot.CreatedByID, lot.CreatedAt,
+		lot.UpdatedAt).Scan(&lot.ID)
+}
+
+func (r *postgresCreditLotRepository) GetLotByID(ctx context.Context, id int) (*models.CreditLot, error) {
+	return r.getLot(ctx, `SELECT `+creditLotColumns+` FROM credit_lots WHERE id = $1`, id)
+}
+
+func (r *postgresCreditLotRepository) ClaimExpiredLot(ctx context.Context, now time.Time) (*models.CreditLot, error) {
+	// Locking the wallet here, without waiting, keeps the wallet-then-lots
+	// lock order that spending uses from deadlocking with the claim
+	query := `SELECT ` + creditLotColumns + ` FROM credit_lots WHERE id = (
+		SELECT l.id FROM credit_lots l JOIN wallets w ON w.id = l.wallet_id
+		WHERE l.status = $1 AND COALESCE(l.expiry_retry_at, l.expires_at) <= $2
+		ORDER BY COALESCE(l.expiry_retry_at, l.expires_at)
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED)`
+	return r.getLot(ctx, query, models.CreditLotOpen, now)
+}
+
+func (r *postgresCreditLotRepository) getLot(ctx context.Context, query string, args ...interface{}) (*models.CreditLot, error) {
+	lot, err := scanCreditLot(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Credit lot not found
+	}
+	return lot, err
+}
+
+func (r *postgresCreditLotRepository) ListLots(ctx context.Context, walletID int, status string, limit int) ([]models.CreditLot, error) {
+	query := `SELECT ` + creditLotColumns + ` FROM credit_lots WHERE wallet_id = $1 AND ($2 = '' OR status = $2) ORDER BY id DESC LIMIT $3`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []models.CreditLot
+	for rows.Next() {
+		lot, err := scanCreditLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, *lot)
+	}
+	return lots, rows.Err()
+}
+
+func (r *postgresCreditLotRepository) ListOpenLotsForUpdate(ctx context.Context, walletID int) ([]*models.CreditLot, error) {
+	query := `SELECT ` + creditLotColumns + ` FROM credit_lots WHERE wallet_id = $1 AND status = $2 ORDER BY id FOR UPDATE`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, models.CreditLotOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*models.CreditLot
+	for rows.Next() {
+		lot, err := scanCreditLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	return lots, rows.Err()
+}
+
+func (r *postgresCreditLotRepository) OpenLotTotals(ctx context.Context, walletID int) (map[string]int64, error) {
+	query := `SELECT type, SUM(remaining) FROM credit_lots WHERE wallet_id = $1 AND status = $2 GROUP BY type`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, models.CreditLotOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[string]int64{}
+	for rows.Next() {
+		var (
+			lotType string
+			total   int64
+		)
+		if err := rows.Scan(&lotType, &total); err != nil {
+			return nil, err
+		}
+		totals[lotType] = total
+	}
+	return totals, rows.Err()
+}
+
+func (r *postgresCreditLotRepository) UpdateLot(ctx context.Context, lot *models.CreditLot) error {
+	query := `UPDATE credit_lots SET remaining = $1, status = $2, expiry_retry_at = $3, updated_at = $4 WHERE id = $5`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, lot.Remaining, lot.Status, lot.ExpiryRetryAt, lot.UpdatedAt, lot.ID)
+	return err
+}
+
+func (r *postgresCreditLotRepository) CreateMovement(ctx context.Context, m *models.CreditLotMovement) error {
+	query := `INSERT INTO credit_lot_movements (lot_id, type, amount, journal_id, ledger_entry_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, m.LotID, m.Type, m.Amount, m.JournalID, m.LedgerEntryID, m.CreatedAt).Scan(&m.ID)
+}
+
+func (r *postgresCreditLotRepository) ListMovements(ctx context.Context, lotID int) ([]models.CreditLotMovement, error) {
+	query := `SELECT id, lot_id, type, amount, journal_id, ledger_entry_id, created_at FROM credit_lot_movements WHERE lot_id = $1 ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, lotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []models.CreditLotMovement
+	for rows.Next() {
+		var m models.CreditLotMovement
+		if err := rows.Scan(&m.ID, &m.LotID, &m.Type, &m.Amount, &m.JournalID, &m.LedgerEntryID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+func scanCreditLot(row rowScanner) (*models.CreditLot, error) {
+	var (
+		lot                models.CreditLot
+		restrictions       []byte
+		expiresAt, retryAt sql.NullTime
+	)
+	if err := row.Scan(&lot.ID, &lot.WalletID, &lot.Type, &lot.Source, &lot.Currency, &lot.Amount, &lot.Remaining, &restrictions, &expiresAt,
+		&lot.Status, &retryAt, &lot.JournalID, &lot.LedgerEntryID, &lot.CreatedByType, &lot.CreatedByID, &lot.CreatedAt, &lot.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(restrictions, &lot.Restrictions); err != nil {
+		return nil, err
+	}
+	lot.ExpiresAt, lot.ExpiryRetryAt = nullTimePtr(expiresAt), nullTimePtr(retryAt)
+	return &lot, nil
+}
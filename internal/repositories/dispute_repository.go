@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// DisputeRepository defines the interface for dispute data operations
+type DisputeRepository interface {
+	CreateDispute(ctx context.Context, dispute *models.Dispute) error
+	GetDisputeByID(ctx context.Context, id int) (*models.Dispute, error)
+	GetDisputeByIDForUpdate(ctx context.Context, id int) (*models.Dispute, error)
+	// ListDisputes returns disputes newest first. Zero or empty arguments
+	// are ignored; overdue keeps open disputes past their resolve-by time.
+	ListDisputes(ctx context.Context, walletID int, status string, overdue bool, now time.Time, limit int) ([]models.Dispute, error)
+	// DisputedAmount totals the disputes of a ledger entry that are open or won
+	DisputedAmount(ctx context.Context, entryID int) (int64, error)
+	// ClaimDueProvisionalCredit locks one opened dispute whose provisional
+	// credit is due, skipping disputes other workers hold. It returns nil
+	// when nothing is due.
+	ClaimDueProvisionalCredit(ctx context.Context, now time.Time) (*models.Dispute, error)
+	UpdateDispute(ctx context.Context, dispute *models.Dispute) error
+	CreateMovement(ctx context.Context, movement *models.DisputeMovement) error
+	ListMovements(ctx context.Context, disputeID int) ([]models.DisputeMovement, error)
+}
+
+// postgresDisputeRepository implements DisputeRepository for PostgreSQL
+type postgresDisputeRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresDisputeRepository creates a new PostgreSQL dispute repository
+func NewPostgresDisputeRepository(db *sql.DB) DisputeRepository {
+	return &postgresDisputeRepository{db: db}
+}
+
+const disputeColumns = `id, wallet_id, ledger_entry_id, currency, amount, provisional_amount, status, reason, resolution_note,
+	provisional_credit_due_at, resolve_by, resolved_at, created_by_type, created_by_id, created_at, updated_at`
+
+func (r *postgresDisputeRepository) CreateDispute(ctx context.Context, d *models.Dispute) error {
+	query := `INSERT INTO disputes (wallet_id, ledger_entry_id, currency, amount, provisional_amount, status, reason, provisional_credit_due_at,
+		resolve_by, created_by_type, created_by_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, d.WalletID, d.LedgerEntryID, d.Currency, d.Amount, d.ProvisionalAmount, d.Status, d.Reason,
+		d.ProvisionalCreditDueAt, d.ResolveBy, d.CreatedByType, d.CreatedByID, d.CreatedAt, d.UpdatedAt).Scan(&d.ID)
+}
+
+func (r *postgresDisputeRepository) GetDisputeByID(ctx context.Context, id int) (*models.Dispute, error) {
+	return r.getDispute(ctx, `SELECT `+disputeColumns+` FROM disputes WHERE id = $1`, id)
+}
+
+func (r *postgresDisputeRepository) GetDisputeByIDForUpdate(ctx context.Context, id int) (*models.Dispute, error) {
+	return r.getDispute(ctx, `SELECT `+disputeColumns+` FROM disputes WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresDisputeRepository) ClaimDueProvisionalCredit(ctx context.Context, now time.Time) (*models.Dispute, error) {
+	query := `SELECT ` + disputeColumns + ` FROM disputes
+		WHERE status = $1 AND provisional_credit_due_at <= $2
+		ORDER BY provisional_credit_due_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+	return r.getDispute(ctx, query, models.DisputeOpened, now)
+}
+
+func (r *postgresDisputeRepository) getDispute(ctx context.Context, query string, args ...interface{}) (*models.Dispute, error) {
+	dispute, err := scanDispute(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Dispute not found
+	}
+	return dispute, err
+}
+
+func (r *postgresDisputeRepository) ListDisputes(ctx context.Context, walletID int, status string, overdue bool, now time.Time, limit int) ([]models.Dispute, error) {
+	query := `SELECT ` + disputeColumns + ` FROM disputes
+		WHERE ($1 = 0 OR wallet_id = $1) AND ($2 = '' OR status = $2)
+			AND (NOT $3 OR (status IN ($4, $5) AND resolve_by < $6))
+		ORDER BY id DESC LIMIT $7`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, status, overdue, models.DisputeOpened, models.DisputeProvisionalCredit, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []models.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, *dispute)
+	}
+	return disputes, rows.Err()
+}
+
+func (r *postgresDisputeRepository) DisputedAmount(ctx context.Context, entryID int) (int64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM disputes WHERE ledger_entry_id = $1 AND status IN ($2, $3, $4)`
+	var total int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, entryID, models.DisputeOpened, models.DisputeProvisionalCredit, models.DisputeWon).Scan(&total)
+	return total, err
+}
+
+func (r *postgresDisputeRepository) UpdateDispute(ctx context.Context, d *models.Dispute) error {
+	query := `UPDATE disputes SET provisional_amount = $1, status = $2, resolution_note = $3, provisional_credit_due_at = $4, resolved_at = $5,
+		updated_at = $6 WHERE id = $7`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, d.ProvisionalAmount, d.Status, nullString(d.ResolutionNote), d.ProvisionalCreditDueAt,
+		d.ResolvedAt, d.UpdatedAt, d.ID)
+	return err
+}
+
+func (r *postgresDisputeRepository) CreateMovement(ctx context.Context, m *models.DisputeMovement) error {
+	query := `INSERT INTO dispute_movements (dispute_id, type, amount, journal_id, ledger_entry_id, reason, actor_type, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, m.DisputeID, m.Type, m.Amount, m.JournalID, m.LedgerEntryID, nullString(m.Reason),
+		m.ActorType, m.ActorID, m.CreatedAt).Scan(&m.ID)
+}
+
+func (r *postgresDisputeRepository) ListMovements(ctx context.Context, disputeID int) ([]models.DisputeMovement, error) {
+	query := `SELECT id, dispute_id, type, amount, journal_id, ledger_entry_id, reason, actor_type, actor_id, created_at
+		FROM dispute_movements WHERE dispute_id = $1 ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []models.DisputeMovement
+	for rows.Next() {
+		var (
+			m      models.DisputeMovement
+			reason sql.NullString
+		)
+		if err := rows.Scan(&m.ID, &m.DisputeID, &m.Type, &m.Amount, &m.JournalID, &m.LedgerEntryID, &reason, &m.ActorType, &m.ActorID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Reason = reason.String
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+func scanDispute(row rowScanner) (*models.Dispute, error) {
+	var (
+		d                          models.Dispute
+		note                       sql.NullString
+		provisionalDue, resolvedAt sql.NullTime
+	)
+	if err := row.Scan(&d.ID, &d.WalletID, &d.LedgerEntryID, &d.Currency, &d.Amount, &d.ProvisionalAmount, &d.Status, &d.Reason, &note,
+		&provisionalDue, &d.ResolveBy, &resolvedAt, &d.CreatedByType, &d.CreatedByID, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	d.ResolutionNote = note.String
+	d.ProvisionalCreditDueAt, d.ResolvedAt = nullTimePtr(provisionalDue), nullTimePtr(resolvedAt)
+	return &d, nil
+}
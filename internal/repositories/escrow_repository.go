@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// EscrowRepository defines the interface for escrow data operations
+type EscrowRepository interface {
+	CreateEscrow(ctx context.Context, escrow *models.Escrow) error
+	GetEscrowByID(ctx context.Context, id int) (*models.Escrow, error)
+	GetEscrowByIDForUpdate(ctx context.Context, id int) (*models.Escrow, error)
+	GetEscrowByContractID(ctx context.Context, contractID string) (*models.Escrow, error)
+	ListEscrowsByWalletID(ctx context.Context, walletID, limit int) ([]models.Escrow, error)
+	// ClaimDueEscrow locks one open escrow whose release time has passed,
+	// skipping escrows other workers hold. It returns nil when nothing is due.
+	ClaimDueEscrow(ctx context.Context, now time.Time) (*models.Escrow, error)
+	UpdateEscrow(ctx context.Context, escrow *models.Escrow) error
+	CreateMovement(ctx context.Context, movement *models.EscrowMovement) error
+	ListMovements(ctx context.Context, escrowID int) ([]models.EscrowMovement, error)
+}
+
+// postgresEscrowRepository implements EscrowRepository for PostgreSQL
+type postgresEscrowRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresEscrowRepository creates a new PostgreSQL escrow repository
+func NewPostgresEscrowRepository(db *sql.DB) EscrowRepository {
+	return &postgresEscrowRepository{db: db}
+}
+
+const escrowColumns = `id, contract_id, buyer_wallet_id, seller_wallet_id, currency, amount, released_amount, refunded_amount, status, description, release_at, release_attempts, release_error, created_by_type, created_by_id, created_at, updated_at`
+
+func (r *postgresEscrowRepository) CreateEscrow(ctx context.Context, e *models.Escrow) error {
+	query := `INSERT INTO escrows (contract_id, buyer_wallet_id, seller_wallet_id, currency, amount, released_amount, refunded_amount, status, description, release_at, created_by_type, created_by_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, e.ContractID, e.BuyerWalletID, e.SellerWalletID, e.Currency, e.Amount, e.ReleasedAmount,
+		e.RefundedAmount, e.Status, e.Description, e.ReleaseAt, e.CreatedByType, e.CreatedByID, e.CreatedAt, e.UpdatedAt).Scan(&e.ID)
+}
+
+func (r *postgresEscrowRepository) GetEscrowByID(ctx context.Context, id int) (*models.Escrow, error) {
+	return r.getEscrow(ctx, `SELECT `+escrowColumns+` FROM escrows WHERE id = $1`, id)
+}
+
+func (r *postgresEscrowRepository) GetEscrowByIDForUpdate(ctx context.Context, id int) (*models.Escrow, error) {
+	return r.getEscrow(ctx, `SELECT `+escrowColumns+` FROM escrows WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresEscrowRepository) GetEscrowByContractID(ctx context.Context, contractID string) (*models.Escrow, error) {
+	return r.getEscrow(ctx, `SELECT `+escrowColumns+` FROM escrows WHERE contract_id = $1`, contractID)
+}
+
+func (r *postgresEscrowRepository) ClaimDueEscrow(ctx context.Context, now time.Time) (*models.Escrow, error) {
+	query := `SELECT ` + escrowColumns + ` FROM escrows
+		WHERE status IN ($1, $2) AND release_at <= $3
+		ORDER BY release_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+	return r.getEscrow(ctx, query, models.EscrowHeld, models.EscrowPartiallyReleased, now)
+}
+
+func (r *postgresEscrowRepository) getEscrow(ctx context.Context, query string, args ...interface{}) (*models.Escrow, error) {
+	escrow, err := scanEscrow(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Escrow not found
+	}
+	return escrow, err
+}
+
+func (r *postgresEscrowRepository) ListEscrowsByWalletID(ctx context.Context, walletID, limit int) ([]models.Escrow, error) {
+	query := `SELECT ` + escrowColumns + ` FROM escrows WHERE buyer_wallet_id = $1 OR seller_wallet_id = $1 ORDER BY id DESC LIMIT $2`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var escrows []models.Escrow
+	for rows.Next() {
+		escrow, err := scanEscrow(rows)
+		if err != nil {
+			return nil, err
+		}
+		escrows = append(escrows, *escrow)
+	}
+	return escrows, rows.Err()
+}
+
+func (r *postgresEscrowRepository) UpdateEscrow(ctx context.Context, e *models.Escrow) error {
+	query := `UPDATE escrows SET released_amount = $1, refunded_amount = $2, status = $3, release_at = $4, release_attempts = $5, release_error = $6, updated_at = $7
+		WHERE id = $8`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, e.ReleasedAmount, e.RefundedAmount, e.Status, e.ReleaseAt, e.ReleaseAttempts, e.ReleaseError, e.UpdatedAt, e.ID)
+	return err
+}
+
+func (r *postgresEscrowRepository) CreateMovement(ctx context.Context, m *models.EscrowMovement) error {
+	query := `INSERT INTO escrow_movements (escrow_id, type, amount, journal_id, ledger_entry_id, reason, actor_type, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, m.EscrowID, m.Type, m.Amount, m.JournalID, m.LedgerEntryID, nullString(m.Reason),
+		m.ActorType, m.ActorID, m.CreatedAt).Scan(&m.ID)
+}
+
+func (r *postgresEscrowRepository) ListMovements(ctx context.Context, escrowID int) ([]models.EscrowMovement, error) {
+	query := `SELECT id, escrow_id, type, amount, journal_id, ledger_entry_id, reason, actor_type, actor_id, created_at
+		FROM escrow_movements WHERE escrow_id = $1 ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []models.EscrowMovement
+	for rows.Next() {
+		var (
+			m      models.EscrowMovement
+			reason sql.NullString
+		)
+		if err := rows.Scan(&m.ID, &m.EscrowID, &m.Type, &m.Amount, &m.JournalID, &m.LedgerEntryID, &reason, &m.ActorType, &m.ActorID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Reason = reason.String
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+func scanEscrow(row rowScanner) (*models.Escrow, error) {
+	var (
+		e         models.Escrow
+		releaseAt sql.NullTime
+	)
+	if err := row.Scan(&e.ID, &e.ContractID, &e.BuyerWalletID, &e.SellerWalletID, &e.Currency, &e.Amount, &e.ReleasedAmount, &e.RefundedAmount,
+		&e.Status, &e.Description, &releaseAt, &e.ReleaseAttempts, &e.ReleaseError, &e.CreatedByType, &e.CreatedByID, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	e.ReleaseAt = nullTimePtr(releaseAt)
+	return &e, nil
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// InterestRepository defines the interface for savings interest data operations
+type InterestRepository interface {
+	// GetAccount returns a wallet's interest account, or nil when the wallet
+	// is not enrolled
+	GetAccount(ctx context.Context, walletID int) (*models.InterestAccount, error)
+	CreateAccount(ctx context.Context, account *models.InterestAccount) error
+	UpdateAccount(ctx context.Context, account *models.InterestAccount) error
+	// ClaimDueAccount locks one account on one of plans not yet accrued
+	// through the given day together with its wallet, skipping rows other
+	// transactions hold. It returns nil when every account is up to date.
+	ClaimDueAccount(ctx context.Context, through time.Time, plans []string) (*models.InterestAccount, error)
+	// EndOfDayBalance returns a wallet's balance after its last entry created
+	// before the given time, or zero when there is none
+	EndOfDayBalance(ctx context.Context, walletID int, before time.Time) (int64, error)
+	CreateAccrual(ctx context.Context, accrual *models.InterestAccrual) error
+	// ListAccruals returns a wallet's accruals between two dates inclusive,
+	// latest first; zero dates are ignored
+	ListAccruals(ctx context.Context, walletID int, from, to time.Time, limit int) ([]models.InterestAccrual, error)
+	CreateCapitalisation(ctx context.Context, capitalisation *models.InterestCapitalisation) error
+	ListCapitalisations(ctx context.Context, walletID, limit int) ([]models.InterestCapitalisation, error)
+}
+
+// postgresInterestRepository implements InterestRepository for PostgreSQL
+type postgresInterestRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresInterestRepository creates a new PostgreSQL interest repository
+func NewPostgresInterestRepository(db *sql.DB) InterestRepository {
+	return &postgresInterestRepository{db: db}
+}
+
+const interestAccountColumns = `wallet_id, plan, accrued, accrued_through, created_by_type, created_by_id, created_at, updated_at`
+
+func (r *postgresInterestRepository) GetAccount(ctx context.Context, walletID int) (*models.InterestAccount, error) {
+	query := `SELECT ` + interestAccountColumns + ` FROM interest_accounts WHERE wallet_id = $1`
+	account, err := scanInterestAccount(executor(ctx, r.db).QueryRowContext(ctx, query, walletID))
+	if err == sql.ErrNoRows {
+		return nil, nil // Not enrolled
+	}
+	return account, err
+}
+
+func (r *postgresInterestRepository) CreateAccount(ctx context.Context, a *models.InterestAccount) error {
+	query := `INSERT INTO interest_accounts (wallet_id, plan, accrued, accrued_through, created_by_type, created_by_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, a.WalletID, a.Plan, a.Accrued, a.AccruedThrough, a.CreatedByType, a.CreatedByID,
+		a.CreatedAt, a.UpdatedAt)
+	return err
+}
+
+func (r *postgresInterestRepository) UpdateAccount(ctx context.Context, a *models.InterestAccount) error {
+	query := `UPDATE interest_accounts SET plan = $1, accrued = $2, accrued_through = $3, updated_at = $4 WHERE wallet_id = $5`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, a.Plan, a.Accrued, a.AccruedThrough, a.UpdatedAt, a.WalletID)
+	return err
+}
+
+func (r *postgresInterestRepository) ClaimDueAccount(ctx context.Context, through time.Time, plans []string) (*models.InterestAccount, error) {
+	// Locking the wallet here, without waiting, keeps the capitalisation's
+	// posting from deadlocking with postings that lock the wallet first
+	query := `SELECT ` + interestAccountColumns + ` FROM interest_accounts WHERE wallet_id = (
+		SELECT a.wallet_id FROM interest_accounts a JOIN wallets w ON w.id = a.wallet_id
+		WHERE a.accrued_through < $1 AND a.plan = ANY($2)
+		ORDER BY a.accrued_through
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED)`
+	account, err := scanInterestAccount(executor(ctx, r.db).QueryRowContext(ctx, query, through, pq.Array(plans)))
+	if err == sql.ErrNoRows {
+		return nil, nil // Nothing due
+	}
+	return account, err
+}
+
+func (r *postgresInterestRepository) EndOfDayBalance(ctx context.Context, walletID int, before time.Time) (int64, error) {
+	query := `SELECT balance FROM ledger_entries WHERE wallet_id = $1 AND created_at < $2 ORDER BY created_at DESC, id DESC LIMIT 1`
+	var balance int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID, before).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, nil // No entries yet
+	}
+	return balance, err
+}
+
+func (r *postgresInterestRepository) CreateAccrual(ctx context.Context, a *models.InterestAccrual) error {
+	query := `INSERT INTO interest_accruals (wallet_id, accrual_date, plan, balance, amount, accrued, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, a.WalletID, a.AccrualDate, a.Plan, a.Balance, a.Amount, a.Accrued,
+		a.CreatedAt).Scan(&a.ID)
+}
+
+func (r *postgresInterestRepository) ListAccruals(ctx context.Context, walletID int, from, to time.Time, limit int) ([]models.InterestAccrual, error) {
+	query := `SELECT id, wallet_id, accrual_date, plan, balance, amount, accrued, created_at FROM interest_accruals WHERE wallet_id = $1`
+	args := []interface{}{walletID}
+	if !from.IsZero() {
+		args = append(args, from)
+		query += fmt.Sprintf(` AND accrual_date >= $%d`, len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		query += fmt.Sprintf(` AND accrual_date <= $%d`, len(args))
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(` ORDER BY accrual_date DESC LIMIT $%d`, len(args))
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accruals []models.InterestAccrual
+	for rows.Next() {
+		var a models.InterestAccrual
+		if err := rows.Scan(&a.ID, &a.WalletID, &a.AccrualDate, &a.Plan, &a.Balance, &a.Amount, &a.Accrued, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		accruals = append(accruals, a)
+	}
+	return accruals, rows.Err()
+}
+
+func (r *postgresInterestRepository) CreateCapitalisation(ctx context.Context, c *models.InterestCapitalisation) error {
+	query := `INSERT INTO interest_capitalisations (wallet_id, period, amount, carried, journal_id, ledger_entry_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, c.WalletID, c.Period, c.Amount, c.Carried, c.JournalID, c.LedgerEntryID,
+		c.CreatedAt).Scan(&c.ID)
+}
+
+func (r *postgresInterestRepository) ListCapitalisations(ctx context.Context, walletID, limit int) ([]models.InterestCapitalisation, error) {
+	query := `SELECT id, wallet_id, period, amount, carried, journal_id, ledger_entry_id, created_at FROM interest_capitalisations
+		WHERE wallet_id = $1 ORDER BY period DESC LIMIT $2`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var capitalisations []models.InterestCapitalisation
+	for rows.Next() {
+		var c models.InterestCapitalisation
+		if err := rows.Scan(&c.ID, &c.WalletID, &c.Period, &c.Amount, &c.Carried, &c.JournalID, &c.LedgerEntryID, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		capitalisations = append(capitalisations, c)
+	}
+	return capitalisations, rows.Err()
+}
+
+func scanInterestAccount(row rowScanner) (*models.InterestAccount, error) {
+	var a models.InterestAccount
+	if err := row.Scan(&a.WalletID, &a.Plan, &a.Accrued, &a.AccruedThrough, &a.CreatedByType, &a.CreatedByID, &a.CreatedAt,
+		&a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// LedgerIntegrityRepository defines data operations for ledger hash chain checkpoints
+type LedgerIntegrityRepository interface {
+	// MaxSettledEntryID returns the highest entry ID created at or before cutoff
+	MaxSettledEntryID(ctx context.Context, cutoff time.Time) (int, error)
+	// ListWalletHeads returns each wallet's latest hashed entry with ID <= maxEntryID
+	ListWalletHeads(ctx context.Context, maxEntryID int) ([]models.WalletHead, error)
+	CountEntries(ctx context.Context, maxEntryID int) (int64, error)
+	CreateCheckpoint(ctx context.Context, checkpoint *models.LedgerCheckpoint) error
+	GetCheckpointByID(ctx context.Context, id int) (*models.LedgerCheckpoint, error)
+	GetLatestCheckpoint(ctx context.Context) (*models.LedgerCheckpoint, error)
+	ListCheckpoints(ctx context.Context, limit int) ([]models.LedgerCheckpoint, error)
+}
+
+// postgresLedgerIntegrityRepository implements LedgerIntegrityRepository for PostgreSQL
+type postgresLedgerIntegrityRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresLedgerIntegrityRepository creates a new PostgreSQL ledger integrity repository
+func NewPostgresLedgerIntegrityRepository(db *sql.DB) LedgerIntegrityRepository {
+	return &postgresLedgerIntegrityRepository{db: db}
+}
+
+const ledgerCheckpointColumns = `id, max_entry_id, entry_count, wallet_count, heads_root, prev_checkpoint_hash, payload_hash, signature, key_id, created_at`
+
+func (r *postgresLedgerIntegrityRepository) MaxSettledEntryID(ctx context.Context, cutoff time.Time) (int, error) {
+	var id sql.NullInt64
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT MAX(id) FROM ledger_entries WHERE created_at <= $1`, cutoff).Scan(&id)
+	return int(id.Int64), err
+}
+
+func (r *postgresLedgerIntegrityRepository) ListWalletHeads(ctx context.Context, maxEntryID int) ([]models.WalletHead, error) {
+	query := `SELECT DISTINCT ON (wallet_id) wallet_id, id, hash FROM ledger_entries
+		WHERE id <= $1 AND hash IS NOT NULL
+		ORDER BY wallet_id, id DESC`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, maxEntryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var heads []models.WalletHead
+	for rows.Next() {
+		var head models.WalletHead
+		if err := rows.Scan(&head.WalletID, &head.EntryID, &head.Hash); err != nil {
+			return nil, err
+		}
+		heads = append(heads, head)
+	}
+	return heads, rows.Err()
+}
+
+func (r *postgresLedgerIntegrityRepository) CountEntries(ctx context.Context, maxEntryID int) (int64, error) {
+	var count int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM ledger_entries WHERE id <= $1`, maxEntryID).Scan(&count)
+	return count, err
+}
+
+func (r *postgresLedgerIntegrityRepository) CreateCheckpoint(ctx context.Context, checkpoint *models.LedgerCheckpoint) error {
+	query := `INSERT INTO ledger_checkpoints (max_entry_id, entry_count, wallet_count, heads_root, prev_checkpoint_hash, payload_hash, signature, key_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, checkpoint.MaxEntryID, checkpoint.EntryCount, checkpoint.WalletCount, checkpoint.HeadsRoot,
+		nullString(checkpoint.PrevCheckpointHash), checkpoint.PayloadHash, checkpoint.Signature, checkpoint.KeyID, checkpoint.CreatedAt).Scan(&checkpoint.ID)
+}
+
+func (r *postgresLedgerIntegrityRepository) GetCheckpointByID(ctx context.Context, id int) (*models.LedgerCheckpoint, error) {
+	query := `SELECT ` + ledgerCheckpointColumns + ` FROM ledger_checkpoints WHERE id = $1`
+	checkpoint, err := scanLedgerCheckpoint(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Checkpoint not found
+	}
+	return checkpoint, err
+}
+
+func (r *postgresLedgerIntegrityRepository) GetLatestCheckpoint(ctx context.Context) (*models.LedgerCheckpoint, error) {
+	query := `SELECT ` + ledgerCheckpointColumns + ` FROM ledger_checkpoints ORDER BY id DESC LIMIT 1`
+	checkpoint, err := scanLedgerCheckpoint(executor(ctx, r.db).QueryRowContext(ctx, query))
+	if err == sql.ErrNoRows {
+		return nil, nil // No checkpoints yet
+	}
+	return checkpoint, err
+}
+
+func (r *postgresLedgerIntegrityRepository) ListCheckpoints(ctx context.Context, limit int) ([]models.LedgerCheckpoint, error) {
+	query := `SELECT ` + ledgerCheckpointColumns + ` FROM ledger_checkpoints ORDER BY id DESC LIMIT $1`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checkpoints []models.LedgerCheckpoint
+	for rows.Next() {
+		checkpoint, err := scanLedgerCheckpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, *checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+func scanLedgerCheckpoint(row rowScanner) (*models.LedgerCheckpoint, error) {
+	checkpoint := &models.LedgerCheckpoint{}
+	var prev sql.NullString
+	err := row.Scan(&checkpoint.ID, &checkpoint.MaxEntryID, &checkpoint.EntryCount, &checkpoint.WalletCount, &checkpoint.HeadsRoot,
+		&prev, &checkpoint.PayloadHash, &checkpoint.Signature, &checkpoint.KeyID, &checkpoint.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint.PrevCheckpointHash = prev.String
+	return checkpoint, nil
+}
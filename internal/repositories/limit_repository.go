@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// LimitRepository defines the interface for wallet limit data operations
+type LimitRepository interface {
+	// GetOverride returns a wallet's limit override, or nil when it has none
+	GetOverride(ctx context.Context, walletID int) (*models.WalletLimitOverride, error)
+	UpsertOverride(ctx context.Context, override *models.WalletLimitOverride) error
+	DeleteOverride(ctx context.Context, walletID int) error
+	// Usage totals the wallet's ledger entries created at or after since
+	Usage(ctx context.Context, walletID int, since time.Time) (*models.LimitUsage, error)
+}
+
+// postgresLimitRepository implements LimitRepository for PostgreSQL
+type postgresLimitRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresLimitRepository creates a new PostgreSQL limit repository
+func NewPostgresLimitRepository(db *sql.DB) LimitRepository {
+	return &postgresLimitRepository{db: db}
+}
+
+func (r *postgresLimitRepository) GetOverride(ctx context.Context, walletID int) (*models.WalletLimitOverride, error) {
+	query := `SELECT wallet_id, limits, updated_by_type, updated_by_id, updated_at FROM wallet_limit_overrides WHERE wallet_id = $1`
+	var (
+		o      models.WalletLimitOverride
+		limits []byte
+	)
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID).Scan(&o.WalletID, &limits, &o.UpdatedByType, &o.UpdatedByID, &o.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil // No override; the tier's limits apply
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(limits, &o.Limits); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *postgresLimitRepository) UpsertOverride(ctx context.Context, o *models.WalletLimitOverride) error {
+	limits, err := json.Marshal(o.Limits)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO wallet_limit_overrides (wallet_id, limits, updated_by_type, updated_by_id, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wallet_id) DO UPDATE SET limits = EXCLUDED.limits, updated_by_type = EXCLUDED.updated_by_type,
+			updated_by_id = EXCLUDED.updated_by_id, updated_at = EXCLUDED.updated_at`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, o.WalletID, limits, o.UpdatedByType, o.UpdatedByID, o.UpdatedAt)
+	return err
+}
+
+func (r *postgresLimitRepository) DeleteOverride(ctx context.Context, walletID int) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM wallet_limit_overrides WHERE wallet_id = $1`, walletID)
+	return err
+}
+
+func (r *postgresLimitRepository) Usage(ctx context.Context, walletID int, since time.Time) (*models.LimitUsage, error) {
+	query := `SELECT COALESCE(SUM(amount) FILTER (WHERE type = 'debit'), 0), COALESCE(SUM(amount) FILTER (WHERE type = 'credit'), 0), COUNT(DISTINCT journal_id)
+		FROM ledger_entries WHERE wallet_id = $1 AND created_at >= $2`
+	var u models.LimitUsage
+	if err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID, since).Scan(&u.Debit, &u.Credit, &u.Count); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// LoyaltyRepository defines the interface for loyalty points data operations
+type LoyaltyRepository interface {
+	CreateEarning(ctx context.Context, earning *models.PointsEarning) error
+	// ListEarnings returns a points wallet's earnings, newest first,
+	// optionally narrowed to a status
+	ListEarnings(ctx context.Context, walletID int, status string, limit int) ([]models.PointsEarning, error)
+	// ListOpenEarningsForUpdate locks a points wallet's open earnings,
+	// earliest expiry first
+	ListOpenEarningsForUpdate(ctx context.Context, walletID int) ([]*models.PointsEarning, error)
+	// ClaimExpiredEarning locks one open earning whose expiry is due together
+	// with its wallet, skipping rows other transactions hold. It returns nil
+	// when nothing is due.
+	ClaimExpiredEarning(ctx context.Context, now time.Time) (*models.PointsEarning, error)
+	UpdateEarning(ctx context.Context, earning *models.PointsEarning) error
+	CreateRedemption(ctx context.Context, redemption *models.PointsRedemption) error
+	ListRedemptions(ctx context.Context, pointsWalletID, limit int) ([]models.PointsRedemption, error)
+}
+
+// postgresLoyaltyRepository implements LoyaltyRepository for PostgreSQL
+type postgresLoyaltyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresLoyaltyRepository creates a new PostgreSQL loyalty repository
+func NewPostgresLoyaltyRepository(db *sql.DB) LoyaltyRepository {
+	return &postgresLoyaltyRepository{db: db}
+}
+
+const pointsEarningColumns = `id, wallet_id, source_wallet_id, source_entry_id, rule, points, remaining, expires_at, status, expiry_retry_at,
+	journal_id, ledger_entry_id, created_at, updated_at`
+
+func (r *postgresLoyaltyRepository) CreateEarning(ctx context.Context, e *models.PointsEarning) error {
+	query := `INSERT INTO points_earnings (wallet_id, source_wallet_id, source_entry_id, rule, points, remaining, expires_at, status, journal_id,
+		ledger_entry_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, e.WalletID, e.SourceWalletID, e.SourceEntryID, e.Rule, e.Points, e.Remaining,
+		e.ExpiresAt, e.Status, e.JournalID, e.LedgerEntryID, e.CreatedAt, e.UpdatedAt).Scan(&e.ID)
+}
+
+func (r *postgresLoyaltyRepository) ListEarnings(ctx context.Context, walletID int, status string, limit int) ([]models.PointsEarning, error) {
+	query := `SELECT ` + pointsEarningColumns + ` FROM points_earnings WHERE wallet_id = $1 AND ($2 = '' OR status = $2) ORDER BY id DESC LIMIT $3`
+	earnings, err := r.listEarnings(ctx, query, walletID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]models.PointsEarning, 0, len(earnings))
+	for _, earning := range earnings {
+		list = append(list, *earning)
+	}
+	return list, nil
+}
+
+func (r *postgresLoyaltyRepository) ListOpenEarningsForUpdate(ctx context.Context, walletID int) ([]*models.PointsEarning, error) {
+	query := `SELECT ` + pointsEarningColumns + ` FROM points_earnings WHERE wallet_id = $1 AND status = $2
+		ORDER BY expires_at NULLS LAST, id FOR UPDATE`
+	return r.listEarnings(ctx, query, walletID, models.PointsEarningOpen)
+}
+
+func (r *postgresLoyaltyRepository) listEarnings(ctx context.Context, query string, args ...interface{}) ([]*models.PointsEarning, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var earnings []*models.PointsEarning
+	for rows.Next() {
+		earning, err := scanPointsEarning(rows)
+		if err != nil {
+			return nil, err
+		}
+		earnings = append(earnings, earning)
+	}
+	return earnings, rows.Err()
+}
+
+func (r *postgresLoyaltyRepository) ClaimExpiredEarning(ctx context.Context, now time.Time) (*models.PointsEarning, error) {
+	// Locking the wallet here, without waiting, keeps the wallet-then-earnings
+	// lock order that redemption uses from deadlocking with the claim
+	query := `SELECT ` + pointsEarningColumns + ` FROM points_earnings WHERE id = (
+		SELECT e.id FROM points_earnings e JOIN wallets w ON w.id = e.wallet_id
+		WHERE e.status = $1 AND COALESCE(e.expiry_retry_at, e.expires_at) <= $2
+		ORDER BY COALESCE(e.expiry_retry_at, e.expires_at)
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED)`
+	earning, err := scanPointsEarning(executor(ctx, r.db).QueryRowContext(ctx, query, models.PointsEarningOpen, now))
+	if err == sql.ErrNoRows {
+		return nil, nil // Nothing due
+	}
+	return earning, err
+}
+
+func (r *postgresLoyaltyRepository) UpdateEarning(ctx context.Context, e *models.PointsEarning) error {
+	query := `UPDATE points_earnings SET remaining = $1, status = $2, expiry_retry_at = $3, updated_at = $4 WHERE id = $5`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, e.Remaining, e.Status, e.ExpiryRetryAt, e.UpdatedAt, e.ID)
+	return err
+}
+
+func (r *postgresLoyaltyRepository) CreateRedemption(ctx context.Context, m *models.PointsRedemption) error {
+	query := `INSERT INTO points_redemptions (points_wallet_id, wallet_id, points, amount, currency, reference, journal_id, created_by_type,
+		created_by_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, m.PointsWalletID, m.WalletID, m.Points, m.Amount, m.Currency, m.Reference, m.JournalID,
+		m.CreatedByType, m.CreatedByID, m.CreatedAt).Scan(&m.ID)
+}
+
+func (r *postgresLoyaltyRepository) ListRedemptions(ctx context.Context, pointsWalletID, limit int) ([]models.PointsRedemption, error) {
+	query := `SELECT id, points_wallet_id, wallet_id, points, amount, currency, reference, journal_id, created_by_type, created_by_id, created_at
+		FROM points_redemptions WHERE points_wallet_id = $1 ORDER BY id DESC LIMIT $2`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, pointsWalletID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redemptions []models.PointsRedemption
+	for rows.Next() {
+		var m models.PointsRedemption
+		if err := rows.Scan(&m.ID, &m.PointsWalletID, &m.WalletID, &m.Points, &m.Amount, &m.Currency, &m.Reference, &m.JournalID,
+			&m.CreatedByType, &m.CreatedByID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, m)
+	}
+	return redemptions, rows.Err()
+}
+
+func scanPointsEarning(row rowScanner) (*models.PointsEarning, error) {
+	var (
+		e                  models.PointsEarning
+		expiresAt, retryAt sql.NullTime
+	)
+	if err := row.Scan(&e.ID, &e.WalletID, &e.SourceWalletID, &e.SourceEntryID, &e.Rule, &e.Points, &e.Remaining, &expiresAt, &e.Status,
+		&retryAt, &e.JournalID, &e.LedgerEntryID, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	e.ExpiresAt, e.ExpiryRetryAt = nullTimePtr(expiresAt), nullTimePtr(retryAt)
+	return &e, nil
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// PayoutRepository defines the interface for payout data operations
+type PayoutRepository interface {
+	CreatePayout(ctx context.Context, payout *models.Payout) error
+	GetPayoutByID(ctx context.Context, id int) (*models.Payout, error)
+	GetPayoutByIDForUpdate(ctx context.Context, id int) (*models.Payout, error)
+	GetPayoutByReference(ctx context.Context, walletID, reference int) (*models.Payout, error)
+	GetPayoutByProviderReferenceForUpdate(ctx context.Context, provider, providerReference string) (*models.Payout, error)
+	ListPayoutsByWalletID(ctx context.Context, walletID int, status string, limit int) ([]models.Payout, error)
+	// ClaimDuePayout locks one payout the worker has to act on, skipping
+	// payouts other workers hold: a requested payout due for submission, a
+	// submission whose worker stopped before recording the result, or a
+	// release to retry. It returns nil when nothing is due.
+	ClaimDuePayout(ctx context.Context, now time.Time) (*models.Payout, error)
+	UpdatePayout(ctx context.Context, payout *models.Payout) error
+}
+
+// postgresPayoutRepository implements PayoutRepository for PostgreSQL
+type postgresPayoutRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPayoutRepository creates a new PostgreSQL payout repository
+func NewPostgresPayoutRepository(db *sql.DB) PayoutRepository {
+	return &postgresPayoutRepository{db: db}
+}
+
+const payoutColumns = `id, wallet_id, currency, amount, reference, destination, description, status, provider, provider_reference, failure_reason,
+	attempts, next_attempt_at, lock_entry_id, final_entry_id, release_entry_id, created_by_type, created_by_id, created_at, updated_at`
+
+func (r *postgresPayoutRepository) CreatePayout(ctx context.Context, p *models.Payout) error {
+	query := `INSERT INTO payouts (wallet_id, currency, amount, reference, destination, description, status, provider, attempts, next_attempt_at,
+			lock_entry_id, created_by_type, created_by_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, p.WalletID, p.Currency, p.Amount, p.Reference, p.Destination, p.Description, p.Status,
+		p.Provider, p.Attempts, p.NextAttemptAt, p.LockEntryID, p.CreatedByType, p.CreatedByID, p.CreatedAt, p.UpdatedAt).Scan(&p.ID)
+}
+
+func (r *postgresPayoutRepository) GetPayoutByID(ctx context.Context, id int) (*models.Payout, error) {
+	return r.getPayout(ctx, `SELECT `+payoutColumns+` FROM payouts WHERE id = $1`, id)
+}
+
+func (r *postgresPayoutRepository) GetPayoutByIDForUpdate(ctx context.Context, id int) (*models.Payout, error) {
+	return r.getPayout(ctx, `SELECT `+payoutColumns+` FROM payouts WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresPayoutRepository) GetPayoutByReference(ctx context.Context, walletID, reference int) (*models.Payout, error) {
+	return r.getPayout(ctx, `SELECT `+payoutColumns+` FROM payouts WHERE wallet_id = $1 AND reference = $2`, walletID, reference)
+}
+
+func (r *postgresPayoutRepository) GetPayoutByProviderReferenceForUpdate(ctx context.Context, provider, providerReference string) (*models.Payout, error) {
+	query := `SELECT ` + payoutColumns + ` FROM payouts WHERE provider = $1 AND provider_reference = $2 FOR UPDATE`
+	return r.getPayout(ctx, query, provider, providerReference)
+}
+
+func (r *postgresPayoutRepository) ClaimDuePayout(ctx context.Context, now time.Time) (*models.Payout, error) {
+	query := `SELECT ` + payoutColumns + ` FROM payouts
+		WHERE status IN ($1, $2, $3) AND next_attempt_at <= $4
+		ORDER BY next_attempt_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+	return r.getPayout(ctx, query, models.PayoutRequested, models.PayoutSubmitting, models.PayoutReleasePending, now)
+}
+
+func (r *postgresPayoutRepository) getPayout(ctx context.Context, query string, args ...interface{}) (*models.Payout, error) {
+	payout, err := scanPayout(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Payout not found
+	}
+	return payout, err
+}
+
+func (r *postgresPayoutRepository) ListPayoutsByWalletID(ctx context.Context, walletID int, status string, limit int) ([]models.Payout, error) {
+	query := `SELECT ` + payoutColumns + ` FROM payouts WHERE wallet_id = $1 AND ($2 = '' OR status = $2) ORDER BY id DESC LIMIT $3`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []models.Payout
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, *payout)
+	}
+	return payouts, rows.Err()
+}
+
+func (r *postgresPayoutRepository) UpdatePayout(ctx context.Context, p *models.Payout) error {
+	query := `UPDATE payouts SET status = $1, provider_reference = $2, failure_reason = $3, attempts = $4, next_attempt_at = $5,
+		final_entry_id = $6, release_entry_id = $7, updated_at = $8 WHERE id = $9`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, p.Status, nullString(p.ProviderReference), nullString(p.FailureReason), p.Attempts,
+		p.NextAttemptAt, p.FinalEntryID, p.ReleaseEntryID, p.UpdatedAt, p.ID)
+	return err
+}
+
+func scanPayout(row rowScanner) (*models.Payout, error) {
+	var (
+		p                            models.Payout
+		providerRef, failure         sql.NullString
+		nextAttemptAt                sql.NullTime
+		finalEntryID, releaseEntryID sql.NullInt64
+	)
+	if err := row.Scan(&p.ID, &p.WalletID, &p.Currency, &p.Amount, &p.Reference, &p.Destination, &p.Description, &p.Status, &p.Provider,
+		&providerRef, &failure, &p.Attempts, &nextAttemptAt, &p.LockEntryID, &finalEntryID, &releaseEntryID,
+		&p.CreatedByType, &p.CreatedByID, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	p.ProviderReference, p.FailureReason = providerRef.String, failure.String
+	p.NextAttemptAt = nullTimePtr(nextAttemptAt)
+	p.FinalEntryID, p.ReleaseEntryID = nullIntPtr(finalEntryID), nullIntPtr(releaseEntryID)
+	return &p, nil
+}
+
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	id := int(n.Int64)
+	return &id
+}
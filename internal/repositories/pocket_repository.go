@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// PocketRepository defines the interface for wallet pocket data operations
+type PocketRepository interface {
+	CreatePocket(ctx context.Context, pocket *models.Pocket) error
+	GetPocketByID(ctx context.Context, id int) (*models.Pocket, error)
+	// GetPocketByIDForUpdate locks the pocket row until the surrounding
+	// transaction ends
+	GetPocketByIDForUpdate(ctx context.Context, id int) (*models.Pocket, error)
+	// ListPockets returns a wallet's pockets, oldest first, optionally
+	// narrowed to a status
+	ListPockets(ctx context.Context, walletID int, status string) ([]models.Pocket, error)
+	// ListOpenPocketsForUpdate locks a wallet's open pockets, oldest first
+	ListOpenPocketsForUpdate(ctx context.Context, walletID int) ([]*models.Pocket, error)
+	// PocketedTotal sums the balances of a wallet's open pockets
+	PocketedTotal(ctx context.Context, walletID int) (int64, error)
+	UpdatePocket(ctx context.Context, pocket *models.Pocket) error
+	CreateMovement(ctx context.Context, movement *models.PocketMovement) error
+	// ListMovements returns a pocket's movements, newest first
+	ListMovements(ctx context.Context, pocketID, limit int) ([]models.PocketMovement, error)
+}
+
+// postgresPocketRepository implements PocketRepository for PostgreSQL
+type postgresPocketRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPocketRepository creates a new PostgreSQL pocket repository
+func NewPostgresPocketRepository(db *sql.DB) PocketRepository {
+	return &postgresPocketRepository{db: db}
+}
+
+const pocketColumns = `id, wallet_id, name, target_amount, target_date, balance, sweep, status, created_by_type, created_by_id, created_at,
+	updated_at, closed_at`
+
+func (r *postgresPocketRepository) CreatePocket(ctx context.Context, p *models.Pocket) error {
+	sweep, err := marshalSweep(p.Sweep)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO pockets (wallet_id, name, target_amount, target_date, balance, sweep, status, created_by_type, created_by_id,
+		created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, p.WalletID, p.Name, p.TargetAmount, p.TargetDate, p.Balance, sweep, p.Status,
+		p.CreatedByType, p.CreatedByID, p.CreatedAt, p.UpdatedAt).Scan(&p.ID)
+}
+
+func (r *postgresPocketRepository) GetPocketByID(ctx context.Context, id int) (*models.Pocket, error) {
+	return r.getPocket(ctx, `SELECT `+pocketColumns+` FROM pockets WHERE id = $1`, id)
+}
+
+func (r *postgresPocketRepository) GetPocketByIDForUpdate(ctx context.Context, id int) (*models.Pocket, error) {
+	return r.getPocket(ctx, `SELECT `+pocketColumns+` FROM pockets WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresPocketRepository) getPocket(ctx context.Context, query string, id int) (*models.Pocket, error) {
+	pocket, err := scanPocket(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Pocket not found
+	}
+	return pocket, err
+}
+
+func (r *postgresPocketRepository) ListPockets(ctx context.Context, walletID int, status string) ([]models.Pocket, error) {
+	query := `SELECT ` + pocketColumns + ` FROM pockets WHERE wallet_id = $1 AND ($2 = '' OR status = $2) ORDER BY id`
+	pockets, err := r.listPockets(ctx, query, walletID, status)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]models.Pocket, 0, len(pockets))
+	for _, pocket := range pockets {
+		list = append(list, *pocket)
+	}
+	return list, nil
+}
+
+func (r *postgresPocketRepository) ListOpenPocketsForUpdate(ctx context.Context, walletID int) ([]*models.Pocket, error) {
+	query := `SELECT ` + pocketColumns + ` FROM pockets WHERE wallet_id = $1 AND status = $2 ORDER BY id FOR UPDATE`
+	return r.listPockets(ctx, query, walletID, models.PocketOpen)
+}
+
+func (r *postgresPocketRepository) listPockets(ctx context.Context, query string, args ...interface{}) ([]*models.Pocket, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pockets []*models.Pocket
+	for rows.Next() {
+		pocket, err := scanPocket(rows)
+		if err != nil {
+			return nil, err
+		}
+		pockets = append(pockets, pocket)
+	}
+	return pockets, rows.Err()
+}
+
+func (r *postgresPocketRepository) PocketedTotal(ctx context.Context, walletID int) (int64, error) {
+	query := `SELECT COALESCE(SUM(balance), 0) FROM pockets WHERE wallet_id = $1 AND status = $2`
+	var total int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID, models.PocketOpen).Scan(&total)
+	return total, err
+}
+
+func (r *postgresPocketRepository) UpdatePocket(ctx context.Context, p *models.Pocket) error {
+	sweep, err := marshalSweep(p.Sweep)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE pockets SET name = $1, target_amount = $2, target_date = $3, balance = $4, sweep = $5, status = $6, updated_at = $7,
+		closed_at = $8 WHERE id = $9`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, p.Name, p.TargetAmount, p.TargetDate, p.Balance, sweep, p.Status, p.UpdatedAt,
+		p.ClosedAt, p.ID)
+	return err
+}
+
+func (r *postgresPocketRepository) CreateMovement(ctx context.Context, m *models.PocketMovement) error {
+	query := `INSERT INTO pocket_movements (pocket_id, type, amount, balance, journal_id, ledger_entry_id, description, created_by_type,
+		created_by_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, m.PocketID, m.Type, m.Amount, m.Balance, nullString(m.JournalID), m.LedgerEntryID,
+		m.Description, m.CreatedByType, m.CreatedByID, m.CreatedAt).Scan(&m.ID)
+}
+
+func (r *postgresPocketRepository) ListMovements(ctx context.Context, pocketID, limit int) ([]models.PocketMovement, error) {
+	query := `SELECT id, pocket_id, type, amount, balance, journal_id, ledger_entry_id, description, created_by_type, created_by_id, created_at
+		FROM pocket_movements WHERE pocket_id = $1 ORDER BY id DESC LIMIT $2`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, pocketID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []models.PocketMovement{}
+	for rows.Next() {
+		var (
+			m             models.PocketMovement
+			journalID     sql.NullString
+			ledgerEntryID sql.NullInt64
+		)
+		if err := rows.Scan(&m.ID, &m.PocketID, &m.Type, &m.Amount, &m.Balance, &journalID, &ledgerEntryID, &m.Description,
+			&m.CreatedByType, &m.CreatedByID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.JournalID, m.LedgerEntryID = journalID.String, nullIntPtr(ledgerEntryID)
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+func marshalSweep(sweep *models.PocketSweep) (sql.NullString, error) {
+	if sweep == nil {
+		return sql.NullString{}, nil
+	}
+	raw, err := json.Marshal(sweep)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return nullJSON(raw), nil
+}
+
+func scanPocket(row rowScanner) (*models.Pocket, error) {
+	var (
+		p                    models.Pocket
+		targetAmount         sql.NullInt64
+		targetDate, closedAt sql.NullTime
+		sweep                []byte
+	)
+	if err := row.Scan(&p.ID, &p.WalletID, &p.Name, &targetAmount, &targetDate, &p.Balance, &sweep, &p.Status, &p.CreatedByType,
+		&p.CreatedByID, &p.CreatedAt, &p.UpdatedAt, &closedAt); err != nil {
+		return nil, err
+	}
+	if targetAmount.Valid {
+		p.TargetAmount = &targetAmount.Int64
+	}
+	p.TargetDate, p.ClosedAt = nullTimePtr(targetDate), nullTimePtr(closedAt)
+	if len(sweep) > 0 {
+		if err := json.Unmarshal(sweep, &p.Sweep); err != nil {
+			return nil, err
+		}
+	}
+	return &p, nil
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// ReserveRepository defines the interface for rolling reserve data operations
+type ReserveRepository interface {
+	// GetPolicy returns a wallet's reserve policy, or nil when it has none
+	GetPolicy(ctx context.Context, walletID int) (*models.ReservePolicy, error)
+	UpsertPolicy(ctx context.Context, policy *models.ReservePolicy) error
+	DeletePolicy(ctx context.Context, walletID int) error
+	CreateReserve(ctx context.Context, reserve *models.Reserve) error
+	GetReserveByID(ctx context.Context, id int) (*models.Reserve, error)
+	GetReserveByIDForUpdate(ctx context.Context, id int) (*models.Reserve, error)
+	ListReservesByWalletID(ctx context.Context, walletID int, openOnly bool, limit int) ([]models.Reserve, error)
+	// HeldTotal returns the funds still held for a wallet. When source is
+	// not empty only reserves from that source are counted.
+	HeldTotal(ctx context.Context, walletID int, source string) (int64, error)
+	// ClaimDueReserve locks one open reserve whose release time has passed,
+	// skipping reserves other workers hold. It returns nil when nothing is due.
+	ClaimDueReserve(ctx context.Context, now time.Time) (*models.Reserve, error)
+	UpdateReserve(ctx context.Context, reserve *models.Reserve) error
+	CreateMovement(ctx context.Context, movement *models.ReserveMovement) error
+	ListMovements(ctx context.Context, reserveID int) ([]models.ReserveMovement, error)
+}
+
+// postgresReserveRepository implements ReserveRepository for PostgreSQL
+type postgresReserveRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresReserveRepository creates a new PostgreSQL reserve repository
+func NewPostgresReserveRepository(db *sql.DB) ReserveRepository {
+	return &postgresReserveRepository{db: db}
+}
+
+const reserveColumns = `id, wallet_id, currency, amount, released_amount, status, source, batch_id, reason, release_at, created_by_type, created_by_id, created_at, updated_at`
+
+func (r *postgresReserveRepository) GetPolicy(ctx context.Context, walletID int) (*models.ReservePolicy, error) {
+	query := `SELECT wallet_id, percentage_bps, hold_days, cap, updated_by_type, updated_by_id, updated_at FROM reserve_policies WHERE wallet_id = $1`
+	var p models.ReservePolicy
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID).Scan(&p.WalletID, &p.PercentageBPS, &p.HoldDays, &p.Cap,
+		&p.UpdatedByType, &p.UpdatedByID, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil // No policy; nothing is held back
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *postgresReserveRepository) UpsertPolicy(ctx context.Context, p *models.ReservePolicy) error {
+	query := `INSERT INTO reserve_policies (wallet_id, percentage_bps, hold_days, cap, updated_by_type, updated_by_id, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (wallet_id) DO UPDATE SET percentage_bps = EXCLUDED.percentage_bps, hold_days = EXCLUDED.hold_days, cap = EXCLUDED.cap,
+			updated_by_type = EXCLUDED.updated_by_type, updated_by_id = EXCLUDED.updated_by_id, updated_at = EXCLUDED.updated_at`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, p.WalletID, p.PercentageBPS, p.HoldDays, p.Cap, p.UpdatedByType, p.UpdatedByID, p.UpdatedAt)
+	return err
+}
+
+func (r *postgresReserveRepository) DeletePolicy(ctx context.Context, walletID int) error {
+	_, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM reserve_policies WHERE wallet_id = $1`, walletID)
+	return err
+}
+
+func (r *postgresReserveRepository) CreateReserve(ctx context.Context, res *models.Reserve) error {
+	query := `INSERT INTO reserves (wallet_id, currency, amount, released_amount, status, source, batch_id, reason, release_at, created_by_type, created_by_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, res.WalletID, res.Currency, res.Amount, res.ReleasedAmount, res.Status, res.Source,
+		res.BatchID, res.Reason, res.ReleaseAt, res.CreatedByType, res.CreatedByID, res.CreatedAt, res.UpdatedAt).Scan(&res.ID)
+}
+
+func (r *postgresReserveRepository) GetReserveByID(ctx context.Context, id int) (*models.Reserve, error) {
+	return r.getReserve(ctx, `SELECT `+reserveColumns+` FROM reserves WHERE id = $1`, id)
+}
+
+func (r *postgresReserveRepository) GetReserveByIDForUpdate(ctx context.Context, id int) (*models.Reserve, error) {
+	return r.getReserve(ctx, `SELECT `+reserveColumns+` FROM reserves WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresReserveRepository) ClaimDueReserve(ctx context.Context, now time.Time) (*models.Reserve, error) {
+	query := `SELECT ` + reserveColumns + ` FROM reserves
+		WHERE status IN ($1, $2) AND release_at <= $3
+		ORDER BY release_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+	return r.getReserve(ctx, query, models.ReserveHeld, models.ReservePartiallyReleased, now)
+}
+
+func (r *postgresReserveRepository) getReserve(ctx context.Context, query string, args ...interface{}) (*models.Reserve, error) {
+	reserve, err := scanReserve(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Reserve not found
+	}
+	return reserve, err
+}
+
+func (r *postgresReserveRepository) ListReservesByWalletID(ctx context.Context, walletID int, openOnly bool, limit int) ([]models.Reserve, error) {
+	query := `SELECT ` + reserveColumns + ` FROM reserves WHERE wallet_id = $1 AND (NOT $2 OR status IN ($3, $4)) ORDER BY id DESC LIMIT $5`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, openOnly, models.ReserveHeld, models.ReservePartiallyReleased, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reserves []models.Reserve
+	for rows.Next() {
+		reserve, err := scanReserve(rows)
+		if err != nil {
+			return nil, err
+		}
+		reserves = append(reserves, *reserve)
+	}
+	return reserves, rows.Err()
+}
+
+func (r *postgresReserveRepository) HeldTotal(ctx context.Context, walletID int, source string) (int64, error) {
+	query := `SELECT COALESCE(SUM(amount - released_amount), 0) FROM reserves
+		WHERE wallet_id = $1 AND status IN ($2, $3) AND ($4 = '' OR source = $4)`
+	var total int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID, models.ReserveHeld, models.ReservePartiallyReleased, source).Scan(&total)
+	return total, err
+}
+
+func (r *postgresReserveRepository) UpdateReserve(ctx context.Context, res *models.Reserve) error {
+	query := `UPDATE reserves SET released_amount = $1, status = $2, release_at = $3, updated_at = $4 WHERE id = $5`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, res.ReleasedAmount, res.Status, res.ReleaseAt, res.UpdatedAt, res.ID)
+	return err
+}
+
+func (r *postgresReserveRepository) CreateMovement(ctx context.Context, m *models.ReserveMovement) error {
+	query := `INSERT INTO reserve_movements (reserve_id, type, amount, journal_id, ledger_entry_id, reason, actor_type, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, m.ReserveID, m.Type, m.Amount, m.JournalID, m.LedgerEntryID, nullString(m.Reason),
+		m.ActorType, m.ActorID, m.CreatedAt).Scan(&m.ID)
+}
+
+func (r *postgresReserveRepository) ListMovements(ctx context.Context, reserveID int) ([]models.ReserveMovement, error) {
+	query := `SELECT id, reserve_id, type, amount, journal_id, ledger_entry_id, reason, actor_type, actor_id, created_at
+		FROM reserve_movements WHERE reserve_id = $1 ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, reserveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []models.ReserveMovement
+	for rows.Next() {
+		var (
+			m      models.ReserveMovement
+			reason sql.NullString
+		)
+		if err := rows.Scan(&m.ID, &m.ReserveID, &m.Type, &m.Amount, &m.JournalID, &m.LedgerEntryID, &reason, &m.ActorType, &m.ActorID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Reason = reason.String
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+func scanReserve(row rowScanner) (*models.Reserve, error) {
+	var (
+		res       models.Reserve
+		batchID   sql.NullInt64
+		releaseAt sql.NullTime
+	)
+	if err := row.Scan(&res.ID, &res.WalletID, &res.Currency, &res.Amount, &res.ReleasedAmount, &res.Status, &res.Source, &batchID,
+		&res.Reason, &releaseAt, &res.CreatedByType, &res.CreatedByID, &res.CreatedAt, &res.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if batchID.Valid {
+		id := int(batchID.Int64)
+		res.BatchID = &id
+	}
+	res.ReleaseAt = nullTimePtr(releaseAt)
+	return &res, nil
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// RiskRepository defines the interface for risk evaluation and case data operations
+type RiskRepository interface {
+	// Activity totals the wallet's ledger entries created at or after since
+	Activity(ctx context.Context, walletID int, since time.Time) (*models.RiskActivity, error)
+	// CountRoundEntries counts the wallet's entries of the given type created
+	// at or after since whose amount is at least minAmount and a multiple of multiple
+	CountRoundEntries(ctx context.Context, walletID int, entryType string, since time.Time, minAmount, multiple int64) (int64, error)
+	CreateEvaluation(ctx context.Context, evaluation *models.RiskEvaluation) error
+	ListEvaluationsByCaseID(ctx context.Context, caseID int) ([]models.RiskEvaluation, error)
+	ListEvaluationsByLedgerEntryID(ctx context.Context, entryID int) ([]models.RiskEvaluation, error)
+	CreateCase(ctx context.Context, c *models.RiskCase) error
+	GetCaseByID(ctx context.Context, id int) (*models.RiskCase, error)
+	GetCaseByIDForUpdate(ctx context.Context, id int) (*models.RiskCase, error)
+	ListCases(ctx context.Context, status string, walletID, limit int) ([]models.RiskCase, error)
+	UpdateCase(ctx context.Context, c *models.RiskCase) error
+}
+
+// postgresRiskRepository implements RiskRepository for PostgreSQL
+type postgresRiskRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRiskRepository creates a new PostgreSQL risk repository
+func NewPostgresRiskRepository(db *sql.DB) RiskRepository {
+	return &postgresRiskRepository{db: db}
+}
+
+const (
+	riskEvaluationColumns = `id, wallet_id, journal_id, ledger_entry_id, case_id, rule, decision, reason, created_at`
+	riskCaseColumns       = `id, wallet_id, decision, txn_type, direction, amount, currency, reference, journal_id, ledger_entry_id, status,
+	resolution_note, resolved_by_type, resolved_by_id, resolved_at, created_at`
+)
+
+func (r *postgresRiskRepository) Activity(ctx context.Context, walletID int, since time.Time) (*models.RiskActivity, error) {
+	query := `SELECT COALESCE(SUM(amount) FILTER (WHERE type = 'debit'), 0), COALESCE(SUM(amount) FILTER (WHERE type = 'credit'), 0), COUNT(DISTINCT journal_id)
+		FROM ledger_entries WHERE wallet_id = $1 AND created_at >= $2`
+	var a models.RiskActivity
+	if err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID, since).Scan(&a.Debit, &a.Credit, &a.Count); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresRiskRepository) CountRoundEntries(ctx context.Context, walletID int, entryType string, since time.Time, minAmount, multiple int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM ledger_entries WHERE wallet_id = $1 AND type = $2 AND created_at >= $3 AND amount >= $4 AND amount % $5 = 0`
+	var count int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID, entryType, since, minAmount, multiple).Scan(&count)
+	return count, err
+}
+
+func (r *postgresRiskRepository) CreateEvaluation(ctx context.Context, e *models.RiskEvaluation) error {
+	query := `INSERT INTO risk_evaluations (wallet_id, journal_id, ledger_entry_id, case_id, rule, decision, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, e.WalletID, nullString(e.JournalID), e.LedgerEntryID, e.CaseID, e.Rule, e.Decision,
+		e.Reason, e.CreatedAt).Scan(&e.ID)
+}
+
+func (r *postgresRiskRepository) ListEvaluationsByCaseID(ctx context.Context, caseID int) ([]models.RiskEvaluation, error) {
+	return r.listEvaluations(ctx, `SELECT `+riskEvaluationColumns+` FROM risk_evaluations WHERE case_id = $1 ORDER BY id`, caseID)
+}
+
+func (r *postgresRiskRepository) ListEvaluationsByLedgerEntryID(ctx context.Context, entryID int) ([]models.RiskEvaluation, error) {
+	return r.listEvaluations(ctx, `SELECT `+riskEvaluationColumns+` FROM risk_evaluations WHERE ledger_entry_id = $1 ORDER BY id`, entryID)
+}
+
+func (r *postgresRiskRepository) listEvaluations(ctx context.Context, query string, args ...interface{}) ([]models.RiskEvaluation, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var evaluations []models.RiskEvaluation
+	for rows.Next() {
+		var (
+			e                     models.RiskEvaluation
+			journalID             sql.NullString
+			ledgerEntryID, caseID sql.NullInt64
+		)
+		if err := rows.Scan(&e.ID, &e.WalletID, &journalID, &ledgerEntryID, &caseID, &e.Rule, &e.Decision, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.JournalID = journalID.String
+		e.LedgerEntryID, e.CaseID = nullIntPtr(ledgerEntryID), nullIntPtr(caseID)
+		evaluations = append(evaluations, e)
+	}
+	return evaluations, rows.Err()
+}
+
+func (r *postgresRiskRepository) CreateCase(ctx context.Context, c *models.RiskCase) error {
+	query := `INSERT INTO risk_cases (wallet_id, decision, txn_type, direction, amount, currency, reference, journal_id, ledger_entry_id, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, c.WalletID, c.Decision, c.TxnType, c.Direction, c.Amount, c.Currency, c.Reference,
+		nullString(c.JournalID), c.LedgerEntryID, c.Status, c.CreatedAt).Scan(&c.ID)
+}
+
+func (r *postgresRiskRepository) GetCaseByID(ctx context.Context, id int) (*models.RiskCase, error) {
+	return r.getCase(ctx, `SELECT `+riskCaseColumns+` FROM risk_cases WHERE id = $1`, id)
+}
+
+func (r *postgresRiskRepository) GetCaseByIDForUpdate(ctx context.Context, id int) (*models.RiskCase, error) {
+	return r.getCase(ctx, `SELECT `+riskCaseColumns+` FROM risk_cases WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresRiskRepository) getCase(ctx context.Context, query string, args ...interface{}) (*models.RiskCase, error) {
+	c, err := scanRiskCase(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Case not found
+	}
+	return c, err
+}
+
+func (r *postgresRiskRepository) ListCases(ctx context.Context, status string, walletID, limit int) ([]models.RiskCase, error) {
+	query := `SELECT ` + riskCaseColumns + ` FROM risk_cases WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR wallet_id = $2) ORDER BY id DESC LIMIT $3`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, status, walletID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []models.RiskCase
+	for rows.Next() {
+		c, err := scanRiskCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, *c)
+	}
+	return cases, rows.Err()
+}
+
+func (r *postgresRiskRepository) UpdateCase(ctx context.Context, c *models.RiskCase) error {
+	query := `UPDATE risk_cases SET status = $1, resolution_note = $2, resolved_by_type = $3, resolved_by_id = $4, resolved_at = $5 WHERE id = $6`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, c.Status, nullString(c.ResolutionNote), nullString(c.ResolvedByType), nullString(c.ResolvedByID),
+		c.ResolvedAt, c.ID)
+	return err
+}
+
+func scanRiskCase(row rowScanner) (*models.RiskCase, error) {
+	var (
+		c                             models.RiskCase
+		journalID, note, byType, byID sql.NullString
+		ledgerEntryID                 sql.NullInt64
+		resolvedAt                    sql.NullTime
+	)
+	if err := row.Scan(&c.ID, &c.WalletID, &c.Decision, &c.TxnType, &c.Direction, &c.Amount, &c.Currency, &c.Reference, &journalID, &ledgerEntryID,
+		&c.Status, &note, &byType, &byID, &resolvedAt, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	c.JournalID, c.ResolutionNote, c.ResolvedByType, c.ResolvedByID = journalID.String, note.String, byType.String, byID.String
+	c.LedgerEntryID = nullIntPtr(ledgerEntryID)
+	c.ResolvedAt = nullTimePtr(resolvedAt)
+	return &c, nil
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// ScreeningRepository defines the interface for screening review data operations
+type ScreeningRepository interface {
+	CreateReview(ctx context.Context, review *models.ScreeningReview) error
+	GetReviewByID(ctx context.Context, id int) (*models.ScreeningReview, error)
+	GetReviewByIDForUpdate(ctx context.Context, id int) (*models.ScreeningReview, error)
+	ListReviews(ctx context.Context, status string, walletID, limit int) ([]models.ScreeningReview, error)
+	UpdateReview(ctx context.Context, review *models.ScreeningReview) error
+}
+
+// postgresScreeningRepository implements ScreeningRepository for PostgreSQL
+type postgresScreeningRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresScreeningRepository creates a new PostgreSQL screening repository
+func NewPostgresScreeningRepository(db *sql.DB) ScreeningRepository {
+	return &postgresScreeningRepository{db: db}
+}
+
+const screeningReviewColumns = `id, subject_type, wallet_id, to_wallet_id, amount, reference, description, split, matches, status, journal_id,
+	resolution_note, resolved_by_type, resolved_by_id, resolved_at, created_by_type, created_by_id, created_at`
+
+func (r *postgresScreeningRepository) CreateReview(ctx context.Context, review *models.ScreeningReview) error {
+	matches, err := json.Marshal(review.Matches)
+	if err != nil {
+		return err
+	}
+	var amount, reference, split interface{}
+	if review.SubjectType == models.ScreeningSubjectTransfer || review.SubjectType == models.ScreeningSubjectSplit {
+		amount, reference = review.Amount, review.Reference
+	}
+	if len(review.Split) > 0 {
+		split = []byte(review.Split)
+	}
+	query := `INSERT INTO screening_reviews (subject_type, wallet_id, to_wallet_id, amount, reference, description, split, matches, status,
+		created_by_type, created_by_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, review.SubjectType, review.WalletID, review.ToWalletID, amount, reference,
+		nullString(review.Description), split, matches, review.Status, review.CreatedByType, review.CreatedByID, review.CreatedAt).Scan(&review.ID)
+}
+
+func (r *postgresScreeningRepository) GetReviewByID(ctx context.Context, id int) (*models.ScreeningReview, error) {
+	return r.getReview(ctx, `SELECT `+screeningReviewColumns+` FROM screening_reviews WHERE id = $1`, id)
+}
+
+func (r *postgresScreeningRepository) GetReviewByIDForUpdate(ctx context.Context, id int) (*models.ScreeningReview, error) {
+	return r.getReview(ctx, `SELECT `+screeningReviewColumns+` FROM screening_reviews WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresScreeningRepository) getReview(ctx context.Context, query string, args ...interface{}) (*models.ScreeningReview, error) {
+	review, err := scanScreeningReview(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Review not found
+	}
+	return review, err
+}
+
+func (r *postgresScreeningRepository) ListReviews(ctx context.Context, status string, walletID, limit int) ([]models.ScreeningReview, error) {
+	query := `SELECT ` + screeningReviewColumns + ` FROM screening_reviews
+		WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR wallet_id = $2 OR to_wallet_id = $2) ORDER BY id DESC LIMIT $3`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, status, walletID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.ScreeningReview
+	for rows.Next() {
+		review, err := scanScreeningReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *postgresScreeningRepository) UpdateReview(ctx context.Context, review *models.ScreeningReview) error {
+	query := `UPDATE screening_reviews SET status = $1, journal_id = $2, resolution_note = $3, resolved_by_type = $4, resolved_by_id = $5,
+		resolved_at = $6 WHERE id = $7`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, review.Status, nullString(review.JournalID), nullString(review.ResolutionNote),
+		nullString(review.ResolvedByType), nullString(review.ResolvedByID), review.ResolvedAt, review.ID)
+	return err
+}
+
+func scanScreeningReview(row rowScanner) (*models.ScreeningReview, error) {
+	var (
+		review                                                     models.ScreeningReview
+		toWalletID, amount, reference                              sql.NullInt64
+		description, journalID, note, resolvedByType, resolvedByID sql.NullString
+		resolvedAt                                                 sql.NullTime
+		split, matches                                             []byte
+	)
+	if err := row.Scan(&review.ID, &review.SubjectType, &review.WalletID, &toWalletID, &amount, &reference, &description, &split, &matches,
+		&review.Status, &journalID, &note, &resolvedByType, &resolvedByID, &resolvedAt, &review.CreatedByType, &review.CreatedByID,
+		&review.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(matches, &review.Matches); err != nil {
+		return nil, err
+	}
+	review.ToWalletID, review.Split = nullIntPtr(toWalletID), split
+	review.Amount, review.Reference = amount.Int64, int(reference.Int64)
+	review.Description, review.JournalID, review.ResolutionNote = description.String, journalID.String, note.String
+	review.ResolvedByType, review.ResolvedByID = resolvedByType.String, resolvedByID.String
+	review.ResolvedAt = nullTimePtr(resolvedAt)
+	return &review, nil
+}
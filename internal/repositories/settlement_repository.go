@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// SettlementRepository defines the interface for merchant settlement data operations
+type SettlementRepository interface {
+	GetSchedule(ctx context.Context, walletID int) (*models.MerchantSettlementSchedule, error)
+	UpsertSchedule(ctx context.Context, schedule *models.MerchantSettlementSchedule) error
+	CreateCapture(ctx context.Context, capture *models.SettlementCapture) error
+	// PendingTotal returns the unsettled captured amount of a wallet
+	PendingTotal(ctx context.Context, walletID int) (int64, error)
+	// ListDueWalletIDs returns wallets with captures eligible on or before day,
+	// excluding captures on a manual schedule
+	ListDueWalletIDs(ctx context.Context, day time.Time) ([]int, error)
+	// LockDueCaptures locks a wallet's pending captures eligible on or before
+	// day, skipping captures another settlement holds
+	LockDueCaptures(ctx context.Context, walletID int, day time.Time) ([]models.SettlementCapture, error)
+	CreateBatch(ctx context.Context, batch *models.SettlementBatch) error
+	SetBatchPosting(ctx context.Context, batch *models.SettlementBatch) error
+	MarkCapturesSettled(ctx context.Context, batchID int, captureIDs []int) error
+	GetBatchByID(ctx context.Context, id int) (*models.SettlementBatch, error)
+	ListBatchesByWalletID(ctx context.Context, walletID, limit int) ([]models.SettlementBatch, error)
+	ListBatchCaptures(ctx context.Context, batchID int) ([]models.SettlementCapture, error)
+}
+
+// postgresSettlementRepository implements SettlementRepository for PostgreSQL
+type postgresSettlementRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSettlementRepository creates a new PostgreSQL settlement repository
+func NewPostgresSettlementRepository(db *sql.DB) SettlementRepository {
+	return &postgresSettlementRepository{db: db}
+}
+
+const (
+	settlementCaptureColumns = `id, wallet_id, currency, amount, reference, description, schedule, captured_at, eligible_on, status, journal_id, ledger_entry_id, batch_id`
+	settlementBatchColumns   = `id, wallet_id, currency, amount, reserve_amount, item_count, settlement_date, trigger, journal_id, ledger_entry_id, created_by_type, created_by_id, created_at`
+)
+
+func (r *postgresSettlementRepository) GetSchedule(ctx context.Context, walletID int) (*models.MerchantSettlementSchedule, error) {
+	query := `SELECT wallet_id, schedule, updated_by_type, updated_by_id, updated_at FROM settlement_schedules WHERE wallet_id = $1`
+	var s models.MerchantSettlementSchedule
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID).Scan(&s.WalletID, &s.Schedule, &s.UpdatedByType, &s.UpdatedByID, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil // No schedule set; the default applies
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *postgresSettlementRepository) UpsertSchedule(ctx context.Context, s *models.MerchantSettlementSchedule) error {
+	query := `INSERT INTO settlement_schedules (wallet_id, schedule, updated_by_type, updated_by_id, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wallet_id) DO UPDATE SET schedule = EXCLUDED.schedule, updated_by_type = EXCLUDED.updated_by_type,
+			updated_by_id = EXCLUDED.updated_by_id, updated_at = EXCLUDED.updated_at`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, s.WalletID, s.Schedule, s.UpdatedByType, s.UpdatedByID, s.UpdatedAt)
+	return err
+}
+
+func (r *postgresSettlementRepository) CreateCapture(ctx context.Context, c *models.SettlementCapture) error {
+	query := `INSERT INTO settlement_captures (wallet_id, currency, amount, reference, description, schedule, captured_at, eligible_on, status, journal_id, ledger_entry_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, c.WalletID, c.Currency, c.Amount, c.Reference, c.Description, c.Schedule,
+		c.CapturedAt, c.EligibleOn, c.Status, c.JournalID, c.LedgerEntryID).Scan(&c.ID)
+}
+
+func (r *postgresSettlementRepository) PendingTotal(ctx context.Context, walletID int) (int64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM settlement_captures WHERE wallet_id = $1 AND status = $2`
+	var total int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, walletID, models.CapturePending).Scan(&total)
+	return total, err
+}
+
+func (r *postgresSettlementRepository) ListDueWalletIDs(ctx context.Context, day time.Time) ([]int, error) {
+	query := `SELECT DISTINCT wallet_id FROM settlement_captures
+		WHERE status = $1 AND eligible_on <= $2 AND schedule <> 'manual'
+		ORDER BY wallet_id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, models.CapturePending, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *postgresSettlementRepository) LockDueCaptures(ctx context.Context, walletID int, day time.Time) ([]models.SettlementCapture, error) {
+	query := `SELECT ` + settlementCaptureColumns + ` FROM settlement_captures
+		WHERE wallet_id = $1 AND status = $2 AND eligible_on <= $3
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED`
+	return r.listCaptures(ctx, query, walletID, models.CapturePending, day)
+}
+
+func (r *postgresSettlementRepository) CreateBatch(ctx context.Context, b *models.SettlementBatch) error {
+	query := `INSERT INTO settlement_batches (wallet_id, currency, amount, item_count, settlement_date, trigger, created_by_type, created_by_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, b.WalletID, b.Currency, b.Amount, b.ItemCount, b.SettlementDate, b.Trigger,
+		b.CreatedByType, b.CreatedByID, b.CreatedAt).Scan(&b.ID)
+}
+
+func (r *postgresSettlementRepository) SetBatchPosting(ctx context.Context, b *models.SettlementBatch) error {
+	query := `UPDATE settlement_batches SET journal_id = $1, ledger_entry_id = $2, reserve_amount = $3 WHERE id = $4`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, b.JournalID, b.LedgerEntryID, b.ReserveAmount, b.ID)
+	return err
+}
+
+func (r *postgresSettlementRepository) MarkCapturesSettled(ctx context.Context, batchID int, captureIDs []int) error {
+	query := `UPDATE settlement_captures SET status = $1, batch_id = $2 WHERE id = ANY($3)`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, models.CaptureSettled, batchID, pq.Array(captureIDs))
+	return err
+}
+
+func (r *postgresSettlementRepository) GetBatchByID(ctx context.Context, id int) (*models.SettlementBatch, error) {
+	query := `SELECT ` + settlementBatchColumns + ` FROM settlement_batches WHERE id = $1`
+	batch, err := scanSettlementBatch(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Batch not found
+	}
+	return batch, err
+}
+
+func (r *postgresSettlementRepository) ListBatchesByWalletID(ctx context.Context, walletID, limit int) ([]models.SettlementBatch, error) {
+	query := `SELECT ` + settlementBatchColumns + ` FROM settlement_batches WHERE wallet_id = $1 ORDER BY id DESC LIMIT $2`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []models.SettlementBatch
+	for rows.Next() {
+		batch, err := scanSettlementBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, *batch)
+	}
+	return batches, rows.Err()
+}
+
+func (r *postgresSettlementRepository) ListBatchCaptures(ctx context.Context, batchID int) ([]models.SettlementCapture, error) {
+	query := `SELECT ` + settlementCaptureColumns + ` FROM settlement_captures WHERE batch_id = $1 ORDER BY id`
+	return r.listCaptures(ctx, query, batchID)
+}
+
+func (r *postgresSettlementRepository) listCaptures(ctx context.Context, query string, args ...interface{}) ([]models.SettlementCapture, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var captures []models.SettlementCapture
+	for rows.Next() {
+		var (
+			c       models.SettlementCapture
+			batchID sql.NullInt64
+		)
+		if err := rows.Scan(&c.ID, &c.WalletID, &c.Currency, &c.Amount, &c.Reference, &c.Description, &c.Schedule, &c.CapturedAt,
+			&c.EligibleOn, &c.Status, &c.JournalID, &c.LedgerEntryID, &batchID); err != nil {
+			return nil, err
+		}
+		if batchID.Valid {
+			id := int(batchID.Int64)
+			c.BatchID = &id
+		}
+		captures = append(captures, c)
+	}
+	return captures, rows.Err()
+}
+
+func scanSettlementBatch(row rowScanner) (*models.SettlementBatch, error) {
+	var b models.SettlementBatch
+	if err := row.Scan(&b.ID, &b.WalletID, &b.Currency, &b.Amount, &b.ReserveAmount, &b.ItemCount, &b.SettlementDate, &b.Trigger, &b.JournalID,
+		&b.LedgerEntryID, &b.CreatedByType, &b.CreatedByID, &b.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
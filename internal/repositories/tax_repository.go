@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// TaxRepository defines the interface for tax posting data operations
+type TaxRepository interface {
+	CreateTaxPosting(ctx context.Context, posting *models.TaxPosting) error
+	// ReverseTaxPostings records negative postings for the tax collected in
+	// journalID, pointing them at the entries of reversalJournalID that reverse
+	// the original tax entries. It returns the number of postings reversed.
+	ReverseTaxPostings(ctx context.Context, journalID, reversalJournalID string, at time.Time) (int64, error)
+	SummarizeTaxPostings(ctx context.Context, from, to time.Time) ([]models.TaxSummaryLine, error)
+}
+
+// postgresTaxRepository implements TaxRepository for PostgreSQL
+type postgresTaxRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTaxRepository creates a new PostgreSQL tax repository
+func NewPostgresTaxRepository(db *sql.DB) TaxRepository {
+	return &postgresTaxRepository{db: db}
+}
+
+func (r *postgresTaxRepository) CreateTaxPosting(ctx context.Context, p *models.TaxPosting) error {
+	query := `INSERT INTO tax_postings (journal_id, ledger_entry_id, jurisdiction, tax_name, currency, rate_bps, inclusive, base_amount, tax_amount, reversal_of, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	var reversalOf sql.NullInt64
+	if p.ReversalOf != nil {
+		reversalOf = nullInt(*p.ReversalOf)
+	}
+	return executor(ctx, r.db).QueryRowContext(ctx, query, p.JournalID, p.LedgerEntryID, p.Jurisdiction, p.TaxName, p.Currency,
+		p.RateBPS, p.Inclusive, p.BaseAmount, p.TaxAmount, reversalOf, p.CreatedAt).Scan(&p.ID)
+}
+
+func (r *postgresTaxRepository) ReverseTaxPostings(ctx context.Context, journalID, reversalJournalID string, at time.Time) (int64, error) {
+	query := `INSERT INTO tax_postings (journal_id, ledger_entry_id, jurisdiction, tax_name, currency, rate_bps, inclusive, base_amount, tax_amount, reversal_of, created_at)
+		SELECT $2, le.id, t.jurisdiction, t.tax_name, t.currency, t.rate_bps, t.inclusive, -t.base_amount, -t.tax_amount, t.id, $3
+		FROM tax_postings t
+		JOIN ledger_entries le ON le.reversal_of = t.ledger_entry_id AND le.journal_id = $2
+		WHERE t.journal_id = $1 AND t.reversal_of IS NULL`
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, journalID, reversalJournalID, at)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *postgresTaxRepository) SummarizeTaxPostings(ctx context.Context, from, to time.Time) ([]models.TaxSummaryLine, error) {
+	query := `SELECT jurisdiction, tax_name, currency, rate_bps, inclusive, COUNT(*), SUM(base_amount), SUM(tax_amount)
+		FROM tax_postings
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY jurisdiction, tax_name, currency, rate_bps, inclusive
+		ORDER BY jurisdiction, tax_name, currency, rate_bps`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.TaxSummaryLine
+	for rows.Next() {
+		var l models.TaxSummaryLine
+		if err := rows.Scan(&l.Jurisdiction, &l.TaxName, &l.Currency, &l.RateBPS, &l.Inclusive, &l.Postings, &l.BaseAmount, &l.TaxAmount); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
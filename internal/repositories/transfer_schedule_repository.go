@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// TransferScheduleRepository defines the interface for standing order data operations
+type TransferScheduleRepository interface {
+	CreateSchedule(ctx context.Context, schedule *models.TransferSchedule) error
+	GetScheduleByID(ctx context.Context, id int) (*models.TransferSchedule, error)
+	GetScheduleByIDForUpdate(ctx context.Context, id int) (*models.TransferSchedule, error)
+	ListSchedulesByWalletID(ctx context.Context, walletID int) ([]models.TransferSchedule, error)
+	// ClaimDueSchedule locks one active schedule due at now, skipping schedules
+	// other workers hold. It returns nil when nothing is due.
+	ClaimDueSchedule(ctx context.Context, now time.Time) (*models.TransferSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *models.TransferSchedule) error
+	CreateRun(ctx context.Context, run *models.TransferRun) error
+	UpdateRun(ctx context.Context, run *models.TransferRun) error
+	ListRuns(ctx context.Context, scheduleID, limit int) ([]models.TransferRun, error)
+}
+
+// postgresTransferScheduleRepository implements TransferScheduleRepository for PostgreSQL
+type postgresTransferScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTransferScheduleRepository creates a new PostgreSQL transfer schedule repository
+func NewPostgresTransferScheduleRepository(db *sql.DB) TransferScheduleRepository {
+	return &postgresTransferScheduleRepository{db: db}
+}
+
+const transferScheduleColumns = `id, from_wallet_id, to_wallet_id, amount, description, rule_kind, rule, timezone, start_at, end_at, status, next_run_at, next_attempt_at, attempts, max_retries, failures, created_by_type, created_by_id, created_at, updated_at`
+
+func (r *postgresTransferScheduleRepository) CreateSchedule(ctx context.Context, s *models.TransferSchedule) error {
+	query := `INSERT INTO transfer_schedules (from_wallet_id, to_wallet_id, amount, description, rule_kind, rule, timezone, start_at, end_at, status, next_run_at, next_attempt_at, attempts, max_retries, created_by_type, created_by_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, s.FromWalletID, s.ToWalletID, s.Amount, s.Description, s.RuleKind, s.Rule, s.Timezone,
+		s.StartAt, s.EndAt, s.Status, s.NextRunAt, s.NextAttemptAt, s.Attempts, s.MaxRetries, s.CreatedByType, s.CreatedByID, s.CreatedAt, s.UpdatedAt).Scan(&s.ID)
+}
+
+func (r *postgresTransferScheduleRepository) GetScheduleByID(ctx context.Context, id int) (*models.TransferSchedule, error) {
+	return r.getSchedule(ctx, `SELECT `+transferScheduleColumns+` FROM transfer_schedules WHERE id = $1`, id)
+}
+
+func (r *postgresTransferScheduleRepository) GetScheduleByIDForUpdate(ctx context.Context, id int) (*models.TransferSchedule, error) {
+	return r.getSchedule(ctx, `SELECT `+transferScheduleColumns+` FROM transfer_schedules WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *postgresTransferScheduleRepository) ClaimDueSchedule(ctx context.Context, now time.Time) (*models.TransferSchedule, error) {
+	query := `SELECT ` + transferScheduleColumns + ` FROM transfer_schedules
+		WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+	return r.getSchedule(ctx, query, models.TransferScheduleActive, now)
+}
+
+func (r *postgresTransferScheduleRepository) getSchedule(ctx context.Context, query string, args ...interface{}) (*models.TransferSchedule, error) {
+	schedule, err := scanTransferSchedule(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil // Schedule not found
+	}
+	return schedule, err
+}
+
+func (r *postgresTransferScheduleRepository) ListSchedulesByWalletID(ctx context.Context, walletID int) ([]models.TransferSchedule, error) {
+	query := `SELECT ` + transferScheduleColumns + ` FROM transfer_schedules WHERE from_wallet_id = $1 OR to_wallet_id = $1 ORDER BY id DESC`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []models.TransferSchedule
+	for rows.Next() {
+		schedule, err := scanTransferSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (r *postgresTransferScheduleRepository) UpdateSchedule(ctx context.Context, s *models.TransferSchedule) error {
+	query := `UPDATE transfer_schedules SET status = $1, next_run_at = $2, next_attempt_at = $3, attempts = $4, failures = $5, updated_at = $6 WHERE id = $7`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, s.Status, s.NextRunAt, s.NextAttemptAt, s.Attempts, s.Failures, s.UpdatedAt, s.ID)
+	return err
+}
+
+func (r *postgresTransferScheduleRepository) CreateRun(ctx context.Context, run *models.TransferRun) error {
+	query := `INSERT INTO transfer_runs (schedule_id, occurrence_at, attempt, status, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, run.ScheduleID, run.OccurrenceAt, run.Attempt, run.Status, run.CreatedAt).Scan(&run.ID)
+}
+
+func (r *postgresTransferScheduleRepository) UpdateRun(ctx context.Context, run *models.TransferRun) error {
+	query := `UPDATE transfer_runs SET status = $1, journal_id = $2, ledger_entry_id = $3, error = $4 WHERE id = $5`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, run.Status, nullString(run.JournalID), run.LedgerEntryID, nullString(run.Error), run.ID)
+	return err
+}
+
+func (r *postgresTransferScheduleRepository) ListRuns(ctx context.Context, scheduleID, limit int) ([]models.TransferRun, error) {
+	query := `SELECT id, schedule_id, occurrence_at, attempt, status, journal_id, ledger_entry_id, error, created_at FROM transfer_runs
+		WHERE schedule_id = $1 ORDER BY id DESC LIMIT $2`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, scheduleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.TransferRun
+	for rows.Next() {
+		var (
+			run               models.TransferRun
+			journalID, runErr sql.NullString
+			ledgerEntryID     sql.NullInt64
+		)
+		if err := rows.Scan(&run.ID, &run.ScheduleID, &run.OccurrenceAt, &run.Attempt, &run.Status, &journalID, &ledgerEntryID, &runErr, &run.CreatedAt); err != nil {
+			return nil, err
+		}
+		run.JournalID = journalID.String
+		run.Error = runErr.String
+		if ledgerEntryID.Valid {
+			id := int(ledgerEntryID.Int64)
+			run.LedgerEntryID = &id
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func scanTransferSchedule(row rowScanner) (*models.TransferSchedule, error) {
+	var (
+		s                             models.TransferSchedule
+		endAt, nextRunAt, nextAttempt sql.NullTime
+	)
+	if err := row.Scan(&s.ID, &s.FromWalletID, &s.ToWalletID, &s.Amount, &s.Description, &s.RuleKind, &s.Rule, &s.Timezone, &s.StartAt, &endAt,
+		&s.Status, &nextRunAt, &nextAttempt, &s.Attempts, &s.MaxRetries, &s.Failures, &s.CreatedByType, &s.CreatedByID, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	s.EndAt = nullTimePtr(endAt)
+	s.NextRunAt = nullTimePtr(nextRunAt)
+	s.NextAttemptAt = nullTimePtr(nextAttempt)
+	return &s, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
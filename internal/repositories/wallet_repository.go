@@ -14,9 +14,24 @@ type WalletRepository interface {
 	CreateWallet(ctx context.Context, wallet *models.Wallet) error
 	GetWalletByUserIDAndCurrency(ctx context.Context, userID int, currency string) (*models.Wallet, error)
 	GetWalletByID(ctx context.Context, id int) (*models.Wallet, error)
+	// GetOrCreateSystemWallet returns the system account for code and currency, creating it if needed
+	GetOrCreateSystemWallet(ctx context.Context, code, currency, assetType string) (*models.Wallet, error)
+	// GetWalletByIDForUpdate locks the wallet row until the surrounding transaction ends
+	GetWalletByIDForUpdate(ctx context.Context, id int) (*models.Wallet, error)
 	UpdateWalletBalance(ctx context.Context, walletID int, amount int64) error
+	UpdateWalletStatus(ctx context.Context, walletID int, status string) error
+	UpdateWalletKYCTier(ctx context.Context, walletID int, tier string) error
+	UpdateWalletSegment(ctx context.Context, walletID int, segment string) error
 	CreateLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error
+	GetLedgerEntryByID(ctx context.Context, id int) (*models.LedgerEntry, error)
+	GetLedgerEntriesByJournalID(ctx context.Context, journalID string) ([]models.LedgerEntry, error)
+	GetReversalOf(ctx context.Context, entryID int) (*models.LedgerEntry, error)
 	GetLedgerEntriesByWalletID(ctx context.Context, walletID int) ([]models.LedgerEntry, error)
+	// GetLatestLedgerEntry returns the wallet's most recent entry (its hash chain head)
+	GetLatestLedgerEntry(ctx context.Context, walletID int) (*models.LedgerEntry, error)
+	// WalkLedgerEntries calls fn for each of the wallet's entries in posting order
+	WalkLedgerEntries(ctx context.Context, walletID int, fn func(*models.LedgerEntry) error) error
+	ListWalletIDs(ctx context.Context) ([]int, error)
 }
 
 // postgresWalletRepository implements WalletRepository for PostgreSQL
@@ -29,10 +44,12 @@ func NewPostgresWalletRepository(db *sql.DB) WalletRepository {
 	return &postgresWalletRepository{db: db}
 }
 
+const walletColumns = `id, user_id, currency, asset_type, balance, status, type, system_code, kyc_tier, segment, holder_name, created_at, updated_at`
+
 func (r *postgresWalletRepository) CreateWallet(ctx context.Context, wallet *models.Wallet) error {
-	query := `INSERT INTO wallets (user_id, currency, balance, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `INSERT INTO wallets (user_id, currency, asset_type, balance, status, type, system_code, holder_name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
 	var id int
-	err := r.db.QueryRowContext(ctx, query, wallet.UserID, wallet.Currency, wallet.Balance, wallet.CreatedAt, wallet.UpdatedAt).Scan(&id)
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, wallet.UserID, wallet.Currency, wallet.AssetType, wallet.Balance, wallet.Status, wallet.Type, nullString(wallet.SystemCode), wallet.HolderName, wallet.CreatedAt, wallet.UpdatedAt).Scan(&id)
 	if err == nil {
 		wallet.ID = id
 	}
@@ -40,9 +57,8 @@ func (r *postgresWalletRepository) CreateWallet(ctx context.Context, wallet *mod
 }
 
 func (r *postgresWalletRepository) GetWalletByUserIDAndCurrency(ctx context.Context, userID int, currency string) (*models.Wallet, error) {
-	wallet := &models.Wallet{}
-	query := `SELECT id, user_id, currency, balance, created_at, updated_at FROM wallets WHERE user_id = $1 AND currency = $2`
-	err := r.db.QueryRowContext(ctx, query, userID, currency).Scan(&wallet.ID, &wallet.UserID, &wallet.Currency, &wallet.Balance, &wallet.CreatedAt, &wallet.UpdatedAt)
+	query := `SELECT ` + walletColumns + ` FROM wallets WHERE user_id = $1 AND currency = $2 AND type <> 'system'`
+	wallet, err := scanWallet(executor(ctx, r.db).QueryRowContext(ctx, query, userID, currency))
 	if err == sql.ErrNoRows {
 		return nil, nil // Wallet not found
 	}
@@ -50,9 +66,28 @@ func (r *postgresWalletRepository) GetWalletByUserIDAndCurrency(ctx context.Cont
 }
 
 func (r *postgresWalletRepository) GetWalletByID(ctx context.Context, id int) (*models.Wallet, error) {
-	wallet := &models.Wallet{}
-	query := `SELECT id, user_id, currency, balance, created_at, updated_at FROM wallets WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&wallet.ID, &wallet.UserID, &wallet.Currency, &wallet.Balance, &wallet.CreatedAt, &wallet.UpdatedAt)
+	query := `SELECT ` + walletColumns + ` FROM wallets WHERE id = $1`
+	wallet, err := scanWallet(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Wallet not found
+	}
+	return wallet, err
+}
+
+func (r *postgresWalletRepository) GetOrCreateSystemWallet(ctx context.Context, code, currency, assetType string) (*models.Wallet, error) {
+	wallet := models.NewSystemWallet(code, currency, assetType)
+	insert := `INSERT INTO wallets (user_id, currency, asset_type, balance, status, type, system_code, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (system_code, currency) WHERE type = 'system' DO NOTHING`
+	if _, err := executor(ctx, r.db).ExecContext(ctx, insert, wallet.UserID, wallet.Currency, wallet.AssetType, wallet.Balance, wallet.Status, wallet.Type, wallet.SystemCode, wallet.CreatedAt, wallet.UpdatedAt); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + walletColumns + ` FROM wallets WHERE type = 'system' AND system_code = $1 AND currency = $2`
+	return scanWallet(executor(ctx, r.db).QueryRowContext(ctx, query, code, currency))
+}
+
+func (r *postgresWalletRepository) GetWalletByIDForUpdate(ctx context.Context, id int) (*models.Wallet, error) {
+	query := `SELECT ` + walletColumns + ` FROM wallets WHERE id = $1 FOR UPDATE`
+	wallet, err := scanWallet(executor(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil // Wallet not found
 	}
@@ -61,35 +96,173 @@ func (r *postgresWalletRepository) GetWalletByID(ctx context.Context, id int) (*
 
 func (r *postgresWalletRepository) UpdateWalletBalance(ctx context.Context, walletID int, amount int64) error {
 	query := `UPDATE wallets SET balance = balance + $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.ExecContext(ctx, query, amount, time.Now(), walletID)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, amount, time.Now(), walletID)
+	return err
+}
+
+func (r *postgresWalletRepository) UpdateWalletStatus(ctx context.Context, walletID int, status string) error {
+	query := `UPDATE wallets SET status = $1, updated_at = $2 WHERE id = $3`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, status, time.Now(), walletID)
+	return err
+}
+
+func (r *postgresWalletRepository) UpdateWalletKYCTier(ctx context.Context, walletID int, tier string) error {
+	query := `UPDATE wallets SET kyc_tier = $1, updated_at = $2 WHERE id = $3`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, tier, time.Now(), walletID)
+	return err
+}
+
+func (r *postgresWalletRepository) UpdateWalletSegment(ctx context.Context, walletID int, segment string) error {
+	query := `UPDATE wallets SET segment = $1, updated_at = $2 WHERE id = $3`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, segment, time.Now(), walletID)
 	return err
 }
 
 func (r *postgresWalletRepository) CreateLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error {
-	query := `INSERT INTO ledger_entries (wallet_id, reference, type, amount, balance, description, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	query := `INSERT INTO ledger_entries (wallet_id, journal_id, reference, type, amount, balance, description, reversal_of, prev_hash, hash, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
 	var id int
-	err := r.db.QueryRowContext(ctx, query, entry.WalletID, entry.Reference, entry.Type, entry.Amount, entry.Balance, entry.Description, entry.CreatedAt).Scan(&id)
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, entry.WalletID, nullString(entry.JournalID), entry.Reference, entry.Type, entry.Amount, entry.Balance, entry.Description, entry.ReversalOf,
+		nullString(entry.PrevHash), nullString(entry.Hash), entry.CreatedAt).Scan(&id)
 	if err == nil {
 		entry.ID = id
 	}
 	return err
 }
 
+func scanWallet(row rowScanner) (*models.Wallet, error) {
+	wallet := &models.Wallet{}
+	var systemCode sql.NullString
+	if err := row.Scan(&wallet.ID, &wallet.UserID, &wallet.Currency, &wallet.AssetType, &wallet.Balance, &wallet.Status, &wallet.Type, &systemCode, &wallet.KYCTier, &wallet.Segment, &wallet.HolderName, &wallet.CreatedAt, &wallet.UpdatedAt); err != nil {
+		return nil, err
+	}
+	wallet.SystemCode = systemCode.String
+	return wallet, nil
+}
+
+func (r *postgresWalletRepository) GetLedgerEntryByID(ctx context.Context, id int) (*models.LedgerEntry, error) {
+	query := `SELECT ` + ledgerEntryColumns + ` FROM ledger_entries WHERE id = $1`
+	entry, err := scanLedgerEntry(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Entry not found
+	}
+	return entry, err
+}
+
+func (r *postgresWalletRepository) GetLedgerEntriesByJournalID(ctx context.Context, journalID string) ([]models.LedgerEntry, error) {
+	query := `SELECT ` + ledgerEntryColumns + ` FROM ledger_entries WHERE journal_id = $1 ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, journalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.LedgerEntry
+	for rows.Next() {
+		entry, err := scanLedgerEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *postgresWalletRepository) GetReversalOf(ctx context.Context, entryID int) (*models.LedgerEntry, error) {
+	query := `SELECT ` + ledgerEntryColumns + ` FROM ledger_entries WHERE reversal_of = $1`
+	entry, err := scanLedgerEntry(executor(ctx, r.db).QueryRowContext(ctx, query, entryID))
+	if err == sql.ErrNoRows {
+		return nil, nil // Entry has not been reversed
+	}
+	return entry, err
+}
+
 func (r *postgresWalletRepository) GetLedgerEntriesByWalletID(ctx context.Context, walletID int) ([]models.LedgerEntry, error) {
 	var entries []models.LedgerEntry
-	query := `SELECT id, wallet_id, reference, type, amount, balance, description, created_at FROM ledger_entries WHERE wallet_id = $1 ORDER BY created_at DESC`
-	rows, err := r.db.QueryContext(ctx, query, walletID)
+	query := `SELECT ` + ledgerEntryColumns + ` FROM ledger_entries WHERE wallet_id = $1 ORDER BY created_at DESC`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		entry := models.LedgerEntry{}
-		if err := rows.Scan(&entry.ID, &entry.WalletID, &entry.Reference, &entry.Type, &entry.Amount, &entry.Balance, &entry.Description, &entry.CreatedAt); err != nil {
+		entry, err := scanLedgerEntry(rows)
+		if err != nil {
 			return nil, err
 		}
-		entries = append(entries, entry)
+		entries = append(entries, *entry)
 	}
 	return entries, nil
 }
+
+func (r *postgresWalletRepository) GetLatestLedgerEntry(ctx context.Context, walletID int) (*models.LedgerEntry, error) {
+	query := `SELECT ` + ledgerEntryColumns + ` FROM ledger_entries WHERE wallet_id = $1 ORDER BY id DESC LIMIT 1`
+	entry, err := scanLedgerEntry(executor(ctx, r.db).QueryRowContext(ctx, query, walletID))
+	if err == sql.ErrNoRows {
+		return nil, nil // No entries yet
+	}
+	return entry, err
+}
+
+func (r *postgresWalletRepository) WalkLedgerEntries(ctx context.Context, walletID int, fn func(*models.LedgerEntry) error) error {
+	query := `SELECT ` + ledgerEntryColumns + ` FROM ledger_entries WHERE wallet_id = $1 ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, walletID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanLedgerEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *postgresWalletRepository) ListWalletIDs(ctx context.Context) ([]int, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, `SELECT id FROM wallets ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+const ledgerEntryColumns = `id, wallet_id, journal_id, reference, type, amount, balance, description, reversal_of, prev_hash, hash, created_at`
+
+func scanLedgerEntry(row rowScanner) (*models.LedgerEntry, error) {
+	entry := &models.LedgerEntry{}
+	var (
+		journalID   sql.NullString
+		description sql.NullString
+		reversalOf  sql.NullInt64
+		prevHash    sql.NullString
+		hash        sql.NullString
+	)
+	if err := row.Scan(&entry.ID, &entry.WalletID, &journalID, &entry.Reference, &entry.Type, &entry.Amount, &entry.Balance, &description, &reversalOf, &prevHash, &hash, &entry.CreatedAt); err != nil {
+		return nil, err
+	}
+	entry.PrevHash = prevHash.String
+	entry.Hash = hash.String
+	entry.JournalID = journalID.String
+	entry.Description = description.String
+	if reversalOf.Valid {
+		id := int(reversalOf.Int64)
+		entry.ReversalOf = &id
+	}
+	return entry, nil
+}
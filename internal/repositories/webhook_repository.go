@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/lib/pq"
+)
+
+// WebhookRepository defines the interface for webhook endpoint and delivery data operations
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	GetEndpointByID(ctx context.Context, id int) (*models.WebhookEndpoint, error)
+	ListEndpointsByMerchantID(ctx context.Context, merchantID int) ([]models.WebhookEndpoint, error)
+	DeactivateEndpoint(ctx context.Context, id int) error
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	// ClaimDueDeliveries leases up to limit due deliveries until leaseUntil so
+	// that concurrent workers never send the same delivery twice.
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// postgresWebhookRepository implements WebhookRepository for PostgreSQL
+type postgresWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresWebhookRepository creates a new PostgreSQL webhook repository
+func NewPostgresWebhookRepository(db *sql.DB) WebhookRepository {
+	return &postgresWebhookRepository{db: db}
+}
+
+const webhookEndpointColumns = `id, merchant_id, url, secret, event_types, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at`
+
+func (r *postgresWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	query := `INSERT INTO webhook_endpoints (merchant_id, url, secret, event_types, active, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, endpoint.MerchantID, endpoint.URL, endpoint.Secret, pq.Array(endpoint.EventTypes), endpoint.Active, endpoint.CreatedAt, endpoint.UpdatedAt).Scan(&endpoint.ID)
+}
+
+func (r *postgresWebhookRepository) GetEndpointByID(ctx context.Context, id int) (*models.WebhookEndpoint, error) {
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoints WHERE id = $1`
+	endpoint, err := scanWebhookEndpoint(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Endpoint not found
+	}
+	return endpoint, err
+}
+
+func (r *postgresWebhookRepository) ListEndpointsByMerchantID(ctx context.Context, merchantID int) ([]models.WebhookEndpoint, error) {
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoints WHERE merchant_id = $1 AND active ORDER BY id`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []models.WebhookEndpoint
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, *endpoint)
+	}
+	return endpoints, rows.Err()
+}
+
+func (r *postgresWebhookRepository) DeactivateEndpoint(ctx context.Context, id int) error {
+	query := `UPDATE webhook_endpoints SET active = FALSE, updated_at = $1 WHERE id = $2`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
+	return err
+}
+
+func (r *postgresWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	return executor(ctx, r.db).QueryRowContext(ctx, query, delivery.EndpointID, delivery.EventID, delivery.EventType, string(delivery.Payload), delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt).Scan(&delivery.ID)
+}
+
+func (r *postgresWebhookRepository) GetDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+	delivery, err := scanWebhookDelivery(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Delivery not found
+	}
+	return delivery, err
+}
+
+func (r *postgresWebhookRepository) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.EndpointID != 0 {
+		args = append(args, filter.EndpointID)
+		conditions = append(conditions, fmt.Sprintf("endpoint_id = $%d", len(args)))
+	}
+	if filter.MerchantID != 0 {
+		args = append(args, filter.MerchantID)
+		conditions = append(conditions, fmt.Sprintf("endpoint_id IN (SELECT id FROM webhook_endpoints WHERE merchant_id = $%d)", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d`, len(args))
+
+	return r.queryDeliveries(ctx, query, args...)
+}
+
+func (r *postgresWebhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status IN ('pending', 'failed') AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	return r.queryDeliveries(ctx, query, leaseUntil, now, limit)
+}
+
+func (r *postgresWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6, updated_at = $7 WHERE id = $8`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, nullInt(delivery.LastStatusCode), nullString(delivery.LastError), delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID)
+	return err
+}
+
+func (r *postgresWebhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanWebhookEndpoint(row rowScanner) (*models.WebhookEndpoint, error) {
+	endpoint := &models.WebhookEndpoint{}
+	err := row.Scan(&endpoint.ID, &endpoint.MerchantID, &endpoint.URL, &endpoint.Secret, pq.Array(&endpoint.EventTypes), &endpoint.Active, &endpoint.CreatedAt, &endpoint.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func scanWebhookDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	var (
+		payload    []byte
+		statusCode sql.NullInt64
+		lastError  sql.NullString
+	)
+	err := row.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventID, &delivery.EventType, &payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &statusCode, &lastError, &delivery.DeliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	delivery.LastStatusCode = int(statusCode.Int64)
+	delivery.LastError = lastError.String
+	return delivery, nil
+}
@@ -0,0 +1,29 @@
+package requestctx
+
+import "context"
+
+type requestIDKey struct{}
+
+type sourceIPKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the X-Request-ID of the request
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithSourceIP returns a copy of ctx carrying the caller's IP address
+func WithSourceIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, sourceIPKey{}, ip)
+}
+
+// SourceIP returns the caller IP stored in ctx, or ""
+func SourceIP(ctx context.Context) string {
+	ip, _ := ctx.Value(sourceIPKey{}).(string)
+	return ip
+}
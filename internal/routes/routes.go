@@ -1,26 +1,197 @@
 package routes
 
 import (
-	"database/sql"
 	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/wallet-ledger-service/internal/auth"
+	"github.com/kodra-pay/wallet-ledger-service/internal/container"
 	"github.com/kodra-pay/wallet-ledger-service/internal/handlers"
-	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
-	"github.com/kodra-pay/wallet-ledger-service/internal/services"
+	"github.com/kodra-pay/wallet-ledger-service/internal/middleware"
 )
 
-func Register(app *fiber.App, serviceName string, db *sql.DB) {
+func Register(app *fiber.App, serviceName string, c *container.Container) {
 	health := handlers.NewHealthHandler(serviceName)
 	health.Register(app)
+	handlers.NewMetricsHandler(c.Metrics).Register(app)
 
-	walletRepo := repositories.NewPostgresWalletRepository(db)
-	walletService := services.NewWalletService(walletRepo)
-	walletHandler := handlers.NewWalletHandler(walletService)
+	walletHandler := handlers.NewWalletHandler(c.WalletService)
+	webhookHandler := handlers.NewWebhookHandler(c.WebhookService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(c.APIKeyService)
+	auditHandler := handlers.NewAuditHandler(c.AuditService)
+	integrityHandler := handlers.NewLedgerIntegrityHandler(c.LedgerIntegrityService)
+	consistencyHandler := handlers.NewConsistencyHandler(c.ConsistencyService)
+	adjustmentHandler := handlers.NewAdjustmentHandler(c.AdjustmentService)
+	taxHandler := handlers.NewTaxHandler(c.TaxService)
+	escrowHandler := handlers.NewEscrowHandler(c.EscrowService)
+	scheduleHandler := handlers.NewTransferScheduleHandler(c.TransferScheduleService)
+	settlementHandler := handlers.NewSettlementHandler(c.SettlementService)
+	reserveHandler := handlers.NewReserveHandler(c.ReserveService)
+	payoutHandler := handlers.NewPayoutHandler(c.PayoutService)
+	riskHandler := handlers.NewRiskHandler(c.RiskService)
+	screeningHandler := handlers.NewScreeningHandler(c.WalletService)
+	disputeHandler := handlers.NewDisputeHandler(c.DisputeService)
+	creditLotHandler := handlers.NewCreditLotHandler(c.CreditLotService)
+	loyaltyHandler := handlers.NewLoyaltyHandler(c.LoyaltyService)
+	interestHandler := handlers.NewInterestHandler(c.InterestService)
+	pocketHandler := handlers.NewPocketHandler(c.PocketService)
+
+	// Everything under /api/v1 requires an API key or a JWT
+	api := app.Group("/api/v1", middleware.Authenticate(c.APIKeyService, c.JWTVerifier))
+
+	read := middleware.RequireScopes(auth.ScopeWalletsRead)
+	write := middleware.RequireScopes(auth.ScopeWalletsWrite)
+	post := middleware.RequireScopes(auth.ScopeLedgerPost)
+	reverse := middleware.RequireScopes(auth.ScopeLedgerReverse)
+	webhooks := middleware.RequireScopes(auth.ScopeWebhooksManage)
+	admin := middleware.RequireScopes(auth.ScopeAdmin)
+	propose := middleware.RequireScopes(auth.ScopeAdjustPropose)
+	approve := middleware.RequireScopes(auth.ScopeAdjustApprove)
+	payoutReport := middleware.RequireScopes(auth.ScopePayoutsReport)
 
 	// API Group for wallets
-	walletGroup := app.Group("/api/v1/wallets")
-	walletGroup.Post("/", walletHandler.CreateWallet)
-	walletGroup.Get("/:id", walletHandler.GetWalletByID)
-	walletGroup.Get("/", walletHandler.GetWalletByUserIDAndCurrency) // Query params: user_id, currency
-	walletGroup.Post("/:id/update-balance", walletHandler.UpdateWalletBalance)
-	walletGroup.Get("/:id/ledger", walletHandler.GetWalletLedger)
-}
\ No newline at end of file
+	walletGroup := api.Group("/wallets")
+	walletGroup.Post("/", write, walletHandler.CreateWallet)
+	walletGroup.Get("/:id", read, walletHandler.GetWalletByID)
+	walletGroup.Get("/", read, walletHandler.GetWalletByUserIDAndCurrency) // Query params: user_id, currency
+	walletGroup.Post("/:id/update-balance", post, walletHandler.UpdateWalletBalance)
+	walletGroup.Post("/:id/transfer", post, walletHandler.Transfer)
+	walletGroup.Post("/:id/status", admin, walletHandler.UpdateWalletStatus)
+	walletGroup.Get("/:id/balance", read, settlementHandler.GetBalance)
+	walletGroup.Get("/:id/limits", read, walletHandler.GetWalletLimits)
+	walletGroup.Put("/:id/limits", admin, walletHandler.SetLimitOverride)
+	walletGroup.Put("/:id/kyc-tier", admin, walletHandler.SetKYCTier)
+	walletGroup.Put("/:id/segment", admin, walletHandler.SetSegment)
+	walletGroup.Get("/:id/credit-lots", read, creditLotHandler.ListLots) // Query params: status, limit
+	walletGroup.Post("/:id/credit-lots", admin, creditLotHandler.IssueLot)
+	walletGroup.Get("/:id/interest", read, interestHandler.GetAccount)
+	walletGroup.Put("/:id/interest", admin, interestHandler.SetPlan)
+	walletGroup.Get("/:id/interest/accruals", read, interestHandler.ListAccruals) // Query params: from, to, limit
+	walletGroup.Get("/:id/pockets", read, pocketHandler.ListPockets)              // Query params: status
+	walletGroup.Post("/:id/pockets", write, pocketHandler.CreatePocket)
+	walletGroup.Get("/:id/ledger", read, walletHandler.GetWalletLedger)
+	walletGroup.Post("/:id/ledger/:entryId/reverse", reverse, walletHandler.ReverseLedgerEntry)
+
+	// API Group for merchant webhooks
+	webhookGroup := api.Group("/webhooks", webhooks)
+	webhookGroup.Post("/endpoints", webhookHandler.CreateEndpoint)
+	webhookGroup.Get("/endpoints", webhookHandler.ListEndpoints) // Query params: merchant_id
+	webhookGroup.Delete("/endpoints/:id", webhookHandler.DeleteEndpoint)
+	webhookGroup.Get("/deliveries", webhookHandler.ListDeliveries) // Query params: endpoint_id, merchant_id, status, limit
+	webhookGroup.Post("/deliveries/:id/redeliver", webhookHandler.Redeliver)
+
+	// Marketplace split payments
+	api.Post("/splits", post, walletHandler.SplitPayment)
+
+	// API Group for escrows
+	escrowGroup := api.Group("/escrows")
+	escrowGroup.Post("/", post, escrowHandler.CreateEscrow)
+	escrowGroup.Get("/", read, escrowHandler.ListEscrows) // Query params: wallet_id or contract_id, limit
+	escrowGroup.Get("/:id", read, escrowHandler.GetEscrow)
+	escrowGroup.Post("/:id/release", post, escrowHandler.ReleaseEscrow)
+	escrowGroup.Post("/:id/cancel", post, escrowHandler.CancelEscrow)
+
+	// API Group for merchant settlement
+	settlementGroup := api.Group("/settlements")
+	settlementGroup.Post("/captures", post, settlementHandler.Capture)
+	settlementGroup.Get("/batches", read, settlementHandler.ListBatches) // Query params: wallet_id, limit
+	settlementGroup.Get("/batches/:id", read, settlementHandler.GetBatch)
+	settlementGroup.Post("/batches", admin, settlementHandler.Settle)
+	settlementGroup.Get("/schedules/:walletId", read, settlementHandler.GetSchedule)
+	settlementGroup.Put("/schedules/:walletId", admin, settlementHandler.SetSchedule)
+
+	// API Group for merchant rolling reserves
+	reserveGroup := api.Group("/reserves")
+	reserveGroup.Get("/policies/:walletId", read, reserveHandler.GetPolicy)
+	reserveGroup.Put("/policies/:walletId", admin, reserveHandler.SetPolicy)
+	reserveGroup.Post("/", admin, reserveHandler.CreateReserve)
+	reserveGroup.Get("/", read, reserveHandler.ListReserves) // Query params: wallet_id, open, limit
+	reserveGroup.Get("/:id", read, reserveHandler.GetReserve)
+	reserveGroup.Post("/:id/adjust", admin, reserveHandler.AdjustReserve)
+	reserveGroup.Post("/:id/release", admin, reserveHandler.ReleaseReserve)
+
+	// API Group for bank payouts
+	payoutGroup := api.Group("/payouts")
+	payoutGroup.Post("/", post, payoutHandler.CreatePayout)
+	payoutGroup.Get("/", read, payoutHandler.ListPayouts) // Query params: wallet_id, status, limit
+	payoutGroup.Post("/callback", payoutReport, payoutHandler.Callback)
+	payoutGroup.Get("/:id", read, payoutHandler.GetPayout)
+
+	// API Group for disputes of debits
+	disputeGroup := api.Group("/disputes")
+	disputeGroup.Post("/", post, disputeHandler.OpenDispute)
+	disputeGroup.Get("/", read, disputeHandler.ListDisputes) // Query params: wallet_id, status, overdue, limit
+	disputeGroup.Get("/:id", read, disputeHandler.GetDispute)
+	disputeGroup.Post("/:id/provisional-credit", admin, disputeHandler.GrantProvisionalCredit)
+	disputeGroup.Post("/:id/resolve", admin, disputeHandler.ResolveDispute)
+	disputeGroup.Post("/:id/withdraw", post, disputeHandler.WithdrawDispute)
+
+	// Promotional credit lots
+	api.Get("/credit-lots/:id", read, creditLotHandler.GetLot)
+
+	// API Group for pockets inside wallets
+	pocketGroup := api.Group("/pockets")
+	pocketGroup.Get("/:id", read, pocketHandler.GetPocket)
+	pocketGroup.Put("/:id", write, pocketHandler.UpdatePocket)
+	pocketGroup.Post("/:id/deposit", post, pocketHandler.Deposit)
+	pocketGroup.Post("/:id/withdraw", post, pocketHandler.Withdraw)
+	pocketGroup.Post("/:id/close", write, pocketHandler.ClosePocket)
+
+	// API Group for loyalty points
+	loyaltyGroup := api.Group("/loyalty")
+	loyaltyGroup.Post("/redemptions", post, loyaltyHandler.Redeem)
+	loyaltyGroup.Get("/wallets/:id/earnings", read, loyaltyHandler.ListEarnings)       // Query params: status, limit
+	loyaltyGroup.Get("/wallets/:id/redemptions", read, loyaltyHandler.ListRedemptions) // Query params: limit
+
+	// API Group for the risk case queue (admin only)
+	riskGroup := api.Group("/risk", admin)
+	riskGroup.Get("/cases", riskHandler.ListCases) // Query params: status, wallet_id, limit
+	riskGroup.Get("/cases/:id", riskHandler.GetCase)
+	riskGroup.Post("/cases/:id/resolve", riskHandler.ResolveCase)
+	riskGroup.Get("/entries/:entryId/evaluations", riskHandler.ListEntryEvaluations)
+
+	// API Group for sanctions screening reviews (admin only)
+	screeningGroup := api.Group("/screening", admin)
+	screeningGroup.Get("/reviews", screeningHandler.ListReviews) // Query params: status, wallet_id, limit
+	screeningGroup.Get("/reviews/:id", screeningHandler.GetReview)
+	screeningGroup.Post("/reviews/:id/resolve", screeningHandler.ResolveReview)
+
+	// API Group for scheduled and recurring transfers
+	scheduleGroup := api.Group("/transfer-schedules")
+	scheduleGroup.Post("/", post, scheduleHandler.CreateSchedule)
+	scheduleGroup.Get("/", read, scheduleHandler.ListSchedules) // Query params: wallet_id
+	scheduleGroup.Get("/:id", read, scheduleHandler.GetSchedule)
+	scheduleGroup.Get("/:id/runs", read, scheduleHandler.ListRuns) // Query params: limit
+	scheduleGroup.Post("/:id/pause", post, scheduleHandler.PauseSchedule)
+	scheduleGroup.Post("/:id/resume", post, scheduleHandler.ResumeSchedule)
+	scheduleGroup.Delete("/:id", post, scheduleHandler.CancelSchedule)
+
+	// API Group for maker-checker manual adjustments
+	adjustmentGroup := api.Group("/adjustments")
+	adjustmentGroup.Post("/", propose, adjustmentHandler.ProposeAdjustment)
+	adjustmentGroup.Get("/", read, adjustmentHandler.ListAdjustments) // Query params: wallet_id, status, limit
+	adjustmentGroup.Get("/:id", read, adjustmentHandler.GetAdjustment)
+	adjustmentGroup.Post("/:id/approve", approve, adjustmentHandler.ApproveAdjustment)
+	adjustmentGroup.Post("/:id/reject", approve, adjustmentHandler.RejectAdjustment)
+
+	// API Group for service API keys
+	apiKeyGroup := api.Group("/api-keys", admin)
+	apiKeyGroup.Post("/", apiKeyHandler.CreateAPIKey)
+	apiKeyGroup.Get("/", apiKeyHandler.ListAPIKeys)
+	apiKeyGroup.Post("/:id/rotate", apiKeyHandler.RotateAPIKey)
+	apiKeyGroup.Delete("/:id", apiKeyHandler.RevokeAPIKey)
+
+	// API Group for ledger integrity (admin only)
+	ledgerGroup := api.Group("/ledger", admin)
+	ledgerGroup.Get("/verify", integrityHandler.VerifyChain) // Query params: wallet_id (optional)
+	ledgerGroup.Get("/checkpoints", integrityHandler.ListCheckpoints)
+	ledgerGroup.Post("/checkpoints", integrityHandler.CreateCheckpoint)
+	ledgerGroup.Get("/checkpoints/public-key", integrityHandler.GetPublicKey)
+	ledgerGroup.Get("/checkpoints/:id/verify", integrityHandler.VerifyCheckpoint)
+	ledgerGroup.Post("/consistency", consistencyHandler.RunCheck)              // Query params: format (json, csv)
+	ledgerGroup.Get("/consistency/latest", consistencyHandler.GetLatestReport) // Query params: format (json, csv)
+
+	// Tax reporting (admin only)
+	api.Get("/taxes/summary", admin, taxHandler.GetSummary) // Query params: from, to, format (json, csv)
+
+	// Audit trail (admin only)
+	api.Get("/audit", admin, auditHandler.ListAuditRecords) // Query params: action, entity_type, entity_id, actor_type, actor_id, request_id, from, to, limit
+}
@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// intervalRule repeats every n units from an anchor. Hours and minutes are
+// fixed durations; days, weeks, months and years follow the calendar in the
+// anchor's time zone.
+type intervalRule struct {
+	anchor time.Time
+	n      int
+	unit   string
+}
+
+// interval units, longest suffix first so "mo" is not read as "m"
+var intervalUnits = []string{"mo", "m", "h", "d", "w", "y"}
+
+// parseInterval parses "<n><unit>" where unit is m (minutes), h, d, w, mo or y
+func parseInterval(expr string, anchor time.Time) (*intervalRule, error) {
+	expr = strings.TrimSpace(expr)
+	for _, unit := range intervalUnits {
+		if !strings.HasSuffix(expr, unit) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(expr, unit))
+		if err != nil || n < 1 {
+			break
+		}
+		if unit == "m" && n < 5 {
+			return nil, fmt.Errorf("interval %q is too short; the minimum is 5m", expr)
+		}
+		return &intervalRule{anchor: anchor, n: n, unit: unit}, nil
+	}
+	return nil, fmt.Errorf("invalid interval %q, expected a count and a unit (m, h, d, w, mo, y), e.g. 1mo", expr)
+}
+
+// occurrence returns the k-th occurrence, counting the anchor as 0
+func (r *intervalRule) occurrence(k int) time.Time {
+	a := r.anchor
+	switch r.unit {
+	case "m":
+		return a.Add(time.Duration(k*r.n) * time.Minute)
+	case "h":
+		return a.Add(time.Duration(k*r.n) * time.Hour)
+	case "d":
+		return a.AddDate(0, 0, k*r.n)
+	case "w":
+		return a.AddDate(0, 0, 7*k*r.n)
+	case "mo":
+		return addMonths(a, k*r.n)
+	default: // "y"
+		return addMonths(a, 12*k*r.n)
+	}
+}
+
+// maxPeriod is an upper bound on one interval, used to estimate how many
+// occurrences to skip without overshooting
+func (r *intervalRule) maxPeriod() time.Duration {
+	day := 25 * time.Hour // Longest possible day, across a DST change
+	switch r.unit {
+	case "m":
+		return time.Duration(r.n) * time.Minute
+	case "h":
+		return time.Duration(r.n) * time.Hour
+	case "d":
+		return time.Duration(r.n) * day
+	case "w":
+		return time.Duration(7*r.n) * day
+	case "mo":
+		return time.Duration(31*r.n) * day
+	default:
+		return time.Duration(366*r.n) * day
+	}
+}
+
+func (r *intervalRule) Next(t time.Time) time.Time {
+	if t.Before(r.anchor) {
+		return r.anchor
+	}
+	k := int(t.Sub(r.anchor)/r.maxPeriod()) - 1
+	if k < 0 {
+		k = 0
+	}
+	for !r.occurrence(k).After(t) {
+		k++
+	}
+	return r.occurrence(k)
+}
+
+// addMonths moves t by n calendar months, clamping the day to the end of
+// shorter months so the 31st becomes the 30th or 28th rather than spilling over
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := daysIn(first.Year(), first.Month()); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
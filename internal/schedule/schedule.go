@@ -0,0 +1,57 @@
+// Package schedule computes the occurrences of recurring rules: fixed
+// intervals such as "1mo" or "2w", and five-field cron expressions. Times are
+// evaluated in the schedule's own time zone so that "every month on the 1st
+// at 09:00" stays at 09:00 local time across DST changes.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rule kinds
+const (
+	KindInterval = "interval"
+	KindCron     = "cron"
+)
+
+// Rule yields the occurrences of a recurring schedule
+type Rule interface {
+	// Next returns the first occurrence strictly after t, or the zero time if
+	// there is none
+	Next(t time.Time) time.Time
+}
+
+// Parse builds the rule of the given kind. Occurrences never fall before start.
+func Parse(kind, expr string, start time.Time, loc *time.Location) (Rule, error) {
+	if loc == nil {
+		return nil, errors.New("time zone is required")
+	}
+	start = start.In(loc)
+	switch kind {
+	case KindInterval:
+		return parseInterval(expr, start)
+	case KindCron:
+		c, err := parseCron(expr, loc)
+		if err != nil {
+			return nil, err
+		}
+		return startBounded{rule: c, start: start}, nil
+	default:
+		return nil, fmt.Errorf("unknown rule kind %q, must be %q or %q", kind, KindInterval, KindCron)
+	}
+}
+
+// startBounded keeps a rule's occurrences at or after start
+type startBounded struct {
+	rule  Rule
+	start time.Time
+}
+
+func (b startBounded) Next(t time.Time) time.Time {
+	if t.Before(b.start) {
+		t = b.start.Add(-time.Nanosecond)
+	}
+	return b.rule.Next(t)
+}
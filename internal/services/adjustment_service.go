@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/auth"
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// ApprovalTier requires Approvals distinct checkers for adjustments of at
+// least MinAmount (in the wallet currency's smallest unit)
+type ApprovalTier struct {
+	MinAmount int64
+	Approvals int
+}
+
+// ParseApprovalTiers parses comma-separated "min_amount:approvals" pairs,
+// e.g. "0:1,100000:2,1000000:3"
+func ParseApprovalTiers(value string) ([]ApprovalTier, error) {
+	var tiers []ApprovalTier
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		minAmount, approvals, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("approval tier %q must look like min_amount:approvals", pair)
+		}
+		tier := ApprovalTier{}
+		var err error
+		if tier.MinAmount, err = strconv.ParseInt(minAmount, 10, 64); err != nil || tier.MinAmount < 0 {
+			return nil, fmt.Errorf("approval tier %q: invalid amount", pair)
+		}
+		if tier.Approvals, err = strconv.Atoi(approvals); err != nil || tier.Approvals < 1 {
+			return nil, fmt.Errorf("approval tier %q: approvals must be at least 1", pair)
+		}
+		tiers = append(tiers, tier)
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinAmount < tiers[j].MinAmount })
+	return tiers, nil
+}
+
+// AdjustmentConfig controls the maker-checker workflow
+type AdjustmentConfig struct {
+	Tiers []ApprovalTier
+	TTL   time.Duration // How long a proposal stays open for approval
+}
+
+// AdjustmentService runs the maker-checker workflow for manual adjustments:
+// one operator proposes, other operators approve or reject, and only the
+// final approval posts the ledger entry
+type AdjustmentService struct {
+	repo    repositories.AdjustmentRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	cfg     AdjustmentConfig
+	now     func() time.Time
+}
+
+// NewAdjustmentService creates a new adjustment service
+func NewAdjustmentService(repo repositories.AdjustmentRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService, cfg AdjustmentConfig) *AdjustmentService {
+	return &AdjustmentService{repo: repo, wallets: wallets, tx: tx, audit: audit, cfg: cfg, now: time.Now}
+}
+
+// requiredApprovals returns the approvals needed for an adjustment of amount
+func (s *AdjustmentService) requiredApprovals(amount int64) int {
+	required := 1
+	for _, tier := range s.cfg.Tiers {
+		if amount >= tier.MinAmount && tier.Approvals > required {
+			required = tier.Approvals
+		}
+	}
+	return required
+}
+
+// operator returns the identified caller; adjustments are never anonymous
+func operator(ctx context.Context) (*auth.Principal, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: adjustments require an identified operator", ErrForbidden)
+	}
+	if _, restricted := principal.OwnerID(); restricted {
+		return nil, ErrForbidden
+	}
+	return principal, nil
+}
+
+// Propose records a pending adjustment; nothing is posted until it is approved
+func (s *AdjustmentService) Propose(ctx context.Context, req dto.ProposeAdjustmentRequest) (*dto.AdjustmentResponse, error) {
+	maker, err := operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Type != "credit" && req.Type != "debit" {
+		return nil, fmt.Errorf("%w: invalid adjustment type, must be 'credit' or 'debit'", ErrInvalidRequest)
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidRequest)
+	}
+
+	wallet, err := s.wallets.GetWalletByID(ctx, req.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.Type == models.WalletTypeSystem {
+		return nil, fmt.Errorf("%w: system accounts cannot be adjusted directly", ErrInvalidRequest)
+	}
+
+	now := s.now().UTC()
+	adjustment := &models.AdjustmentRequest{
+		WalletID:          req.WalletID,
+		Type:              req.Type,
+		Amount:            req.Amount,
+		Reason:            req.Reason,
+		Reference:         req.Reference,
+		Status:            models.AdjustmentPending,
+		RequiredApprovals: s.requiredApprovals(req.Amount),
+		ProposedByType:    maker.Type,
+		ProposedByID:      maker.ID,
+		ExpiresAt:         now.Add(s.cfg.TTL),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateAdjustment(ctx, adjustment); err != nil {
+			return fmt.Errorf("failed to create adjustment: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditAdjustmentProposed, "adjustment", adjustment.ID, nil, adjustment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toAdjustmentResponse(adjustment, nil), nil
+}
+
+// Approve records the caller's approval and posts the adjustment once it has
+// enough. The maker cannot approve their own proposal, and each checker counts once.
+func (s *AdjustmentService) Approve(ctx context.Context, adjustmentID int, req dto.AdjustmentDecisionRequest) (*dto.AdjustmentResponse, error) {
+	return s.decide(ctx, adjustmentID, models.DecisionApprove, req.Comment)
+}
+
+// Reject records the caller's rejection, which closes the proposal
+func (s *AdjustmentService) Reject(ctx context.Context, adjustmentID int, req dto.AdjustmentDecisionRequest) (*dto.AdjustmentResponse, error) {
+	return s.decide(ctx, adjustmentID, models.DecisionReject, req.Comment)
+}
+
+// errAdjustmentExpired is returned for decisions that arrive after the proposal expired
+var errAdjustmentExpired = fmt.Errorf("%w: adjustment has expired", ErrConflict)
+
+func (s *AdjustmentService) decide(ctx context.Context, adjustmentID int, decision, comment string) (*dto.AdjustmentResponse, error) {
+	checker, err := operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired bool
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		adjustment, err := s.repo.GetAdjustmentByIDForUpdate(ctx, adjustmentID)
+		if err != nil {
+			return fmt.Errorf("failed to lock adjustment: %w", err)
+		}
+		if adjustment == nil {
+			return ErrAdjustmentNotFound
+		}
+		if adjustment.Status != models.AdjustmentPending {
+			return fmt.Errorf("%w: adjustment is %s", ErrConflict, adjustment.Status)
+		}
+		now := s.now().UTC()
+		if !now.Before(adjustment.ExpiresAt) {
+			expired = true
+			return s.expire(ctx, adjustment, now)
+		}
+		if adjustment.ProposedByType == checker.Type && adjustment.ProposedByID == checker.ID {
+			return fmt.Errorf("%w: the proposer of an adjustment cannot approve or reject it", ErrForbidden)
+		}
+
+		decisions, err := s.repo.ListDecisions(ctx, adjustment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get adjustment decisions: %w", err)
+		}
+		approvals := 0
+		for _, d := range decisions {
+			if d.ActorType == checker.Type && d.ActorID == checker.ID {
+				return fmt.Errorf("%w: you already decided on this adjustment", ErrConflict)
+			}
+			if d.Decision == models.DecisionApprove {
+				approvals++
+			}
+		}
+
+		before := *adjustment
+		record := &models.AdjustmentDecision{
+			AdjustmentID: adjustment.ID,
+			Decision:     decision,
+			ActorType:    checker.Type,
+			ActorID:      checker.ID,
+			Comment:      comment,
+			CreatedAt:    now,
+		}
+		if err := s.repo.CreateDecision(ctx, record); err != nil {
+			return fmt.Errorf("failed to record decision: %w", err)
+		}
+
+		action := models.AuditAdjustmentApproved
+		switch {
+		case decision == models.DecisionReject:
+			action = models.AuditAdjustmentRejected
+			adjustment.Status = models.AdjustmentRejected
+			adjustment.DecidedAt = &now
+		case approvals+1 >= adjustment.RequiredApprovals:
+			entry, err := s.wallets.AdjustBalance(ctx, adjustment.WalletID, dto.AdjustBalanceRequest{
+				Type:      adjustment.Type,
+				Amount:    adjustment.Amount,
+				Reason:    adjustment.Reason,
+				Reference: adjustment.Reference,
+			})
+			if err != nil {
+				return err
+			}
+			adjustment.Status = models.AdjustmentApproved
+			adjustment.LedgerEntryID = &entry.ID
+			adjustment.DecidedAt = &now
+		}
+		adjustment.UpdatedAt = now
+		if err := s.repo.UpdateAdjustment(ctx, adjustment); err != nil {
+			return fmt.Errorf("failed to update adjustment: %w", err)
+		}
+		return s.audit.Record(ctx, action, "adjustment", adjustment.ID, before, adjustmentDecisionAuditState{Adjustment: adjustment, Decision: record})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, errAdjustmentExpired
+	}
+	return s.GetAdjustment(ctx, adjustmentID)
+}
+
+// adjustmentDecisionAuditState is the after-state recorded for approvals and rejections
+type adjustmentDecisionAuditState struct {
+	Adjustment *models.AdjustmentRequest  `json:"adjustment"`
+	Decision   *models.AdjustmentDecision `json:"decision"`
+}
+
+func (s *AdjustmentService) expire(ctx context.Context, adjustment *models.AdjustmentRequest, now time.Time) error {
+	before := *adjustment
+	adjustment.Status = models.AdjustmentExpired
+	adjustment.DecidedAt = &now
+	adjustment.UpdatedAt = now
+	if err := s.repo.UpdateAdjustment(ctx, adjustment); err != nil {
+		return fmt.Errorf("failed to expire adjustment: %w", err)
+	}
+	return s.audit.Record(ctx, models.AuditAdjustmentExpired, "adjustment", adjustment.ID, before, adjustment)
+}
+
+// ExpireDue closes every pending proposal past its expiry; run by a background worker
+func (s *AdjustmentService) ExpireDue(ctx context.Context) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		expired, err := s.repo.ExpireAdjustments(ctx, s.now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to expire adjustments: %w", err)
+		}
+		for i := range expired {
+			before := expired[i]
+			before.Status, before.DecidedAt = models.AdjustmentPending, nil
+			if err := s.audit.Record(ctx, models.AuditAdjustmentExpired, "adjustment", expired[i].ID, before, expired[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetAdjustment returns an adjustment with its full approval chain
+func (s *AdjustmentService) GetAdjustment(ctx context.Context, adjustmentID int) (*dto.AdjustmentResponse, error) {
+	if _, restricted := callerOwnerID(ctx); restricted {
+		return nil, ErrForbidden
+	}
+	adjustment, err := s.repo.GetAdjustmentByID(ctx, adjustmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adjustment: %w", err)
+	}
+	if adjustment == nil {
+		return nil, ErrAdjustmentNotFound
+	}
+	decisions, err := s.repo.ListDecisions(ctx, adjustmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adjustment decisions: %w", err)
+	}
+	return toAdjustmentResponse(adjustment, decisions), nil
+}
+
+// ListAdjustments returns adjustments newest first with their approval chains
+func (s *AdjustmentService) ListAdjustments(ctx context.Context, filter models.AdjustmentFilter) ([]dto.AdjustmentResponse, error) {
+	if _, restricted := callerOwnerID(ctx); restricted {
+		return nil, ErrForbidden
+	}
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+	adjustments, err := s.repo.ListAdjustments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list adjustments: %w", err)
+	}
+
+	resp := make([]dto.AdjustmentResponse, 0, len(adjustments))
+	for i := range adjustments {
+		decisions, err := s.repo.ListDecisions(ctx, adjustments[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get adjustment decisions: %w", err)
+		}
+		resp = append(resp, *toAdjustmentResponse(&adjustments[i], decisions))
+	}
+	return resp, nil
+}
+
+func toAdjustmentResponse(adjustment *models.AdjustmentRequest, decisions []models.AdjustmentDecision) *dto.AdjustmentResponse {
+	resp := &dto.AdjustmentResponse{AdjustmentRequest: *adjustment, Decisions: []models.AdjustmentDecision{}}
+	for _, d := range decisions {
+		if d.Decision == models.DecisionApprove {
+			resp.Approvals++
+		}
+		resp.Decisions = append(resp.Decisions, d)
+	}
+	return resp
+}
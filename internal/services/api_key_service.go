@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/auth"
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// apiKeyTouchInterval limits how often last_used_at is written for a busy key
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyService issues, rotates, revokes and authenticates hashed API keys
+type APIKeyService struct {
+	repo         repositories.APIKeyRepository
+	tx           repositories.Transactor
+	audit        *AuditService
+	bootstrapKey string
+	now          func() time.Time
+}
+
+// NewAPIKeyService creates a new API key service. bootstrapKey, when set, is a
+// static admin key used to issue the first real keys; leave it empty afterwards.
+func NewAPIKeyService(repo repositories.APIKeyRepository, tx repositories.Transactor, audit *AuditService, bootstrapKey string) *APIKeyService {
+	return &APIKeyService{repo: repo, tx: tx, audit: audit, bootstrapKey: bootstrapKey, now: time.Now}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidRequest)
+	}
+	for _, scope := range req.Scopes {
+		if !auth.IsKnownScope(scope) {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidRequest, scope)
+		}
+	}
+	var (
+		key       *models.APIKey
+		plaintext string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if key, plaintext, err = s.issue(ctx, req.Name, req.Scopes); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, models.AuditAPIKeyCreated, "api_key", key.ID, nil, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toAPIKeyResponse(key)
+	resp.Key = plaintext
+	return resp, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	keys, err := s.repo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	resp := make([]dto.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, *toAPIKeyResponse(&keys[i]))
+	}
+	return resp, nil
+}
+
+// RotateAPIKey issues a replacement key with the same name and scopes. The old
+// key stays valid until the grace period ends.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, keyID int, req dto.RotateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	if req.GracePeriodSeconds < 0 {
+		return nil, fmt.Errorf("%w: grace_period_seconds must not be negative", ErrInvalidRequest)
+	}
+	old, err := s.repo.GetAPIKeyByID(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if old == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	if !old.Usable(s.now()) || old.RotatedTo != nil {
+		return nil, fmt.Errorf("%w: api key is revoked or already rotated", ErrInvalidRequest)
+	}
+
+	var (
+		key       *models.APIKey
+		plaintext string
+	)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if key, plaintext, err = s.issue(ctx, old.Name, old.Scopes); err != nil {
+			return err
+		}
+		expiresAt := s.now().Add(time.Duration(req.GracePeriodSeconds) * time.Second)
+		if err := s.repo.MarkRotated(ctx, old.ID, key.ID, expiresAt); err != nil {
+			return fmt.Errorf("failed to expire rotated api key: %w", err)
+		}
+		after := *old
+		after.ExpiresAt = &expiresAt
+		after.RotatedTo = &key.ID
+		return s.audit.Record(ctx, models.AuditAPIKeyRotated, "api_key", old.ID, old, map[string]*models.APIKey{"old": &after, "new": key})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toAPIKeyResponse(key)
+	resp.Key = plaintext
+	return resp, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, keyID int) error {
+	key, err := s.repo.GetAPIKeyByID(ctx, keyID)
+	if err != nil {
+		return fmt.Errorf("failed to get api key: %w", err)
+	}
+	if key == nil {
+		return ErrAPIKeyNotFound
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		revokedAt := s.now()
+		if err := s.repo.RevokeAPIKey(ctx, keyID, revokedAt); err != nil {
+			return fmt.Errorf("failed to revoke api key: %w", err)
+		}
+		after := *key
+		after.RevokedAt = &revokedAt
+		return s.audit.Record(ctx, models.AuditAPIKeyRevoked, "api_key", key.ID, key, &after)
+	})
+}
+
+// AuthenticateAPIKey resolves a plaintext API key to the calling principal
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, plaintext string) (*auth.Principal, error) {
+	if s.bootstrapKey != "" && subtle.ConstantTimeCompare([]byte(plaintext), []byte(s.bootstrapKey)) == 1 {
+		return &auth.Principal{Type: auth.PrincipalAPIKey, ID: "bootstrap", Name: "bootstrap", Scopes: []string{"admin"}}, nil
+	}
+
+	prefix, err := auth.ParseAPIKeyPrefix(plaintext)
+	if err != nil {
+		return nil, auth.ErrInvalidCredentials
+	}
+	key, err := s.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+	now := s.now()
+	if key == nil || subtle.ConstantTimeCompare([]byte(auth.HashAPIKey(plaintext)), []byte(key.KeyHash)) != 1 || !key.Usable(now) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.repo.TouchAPIKey(ctx, key.ID, now); err != nil {
+			log.Printf("failed to record use of api key %d: %v", key.ID, err)
+		}
+	}
+	return &auth.Principal{Type: auth.PrincipalAPIKey, ID: strconv.Itoa(key.ID), Name: key.Name, Scopes: key.Scopes}, nil
+}
+
+func (s *APIKeyService) issue(ctx context.Context, name string, scopes []string) (*models.APIKey, string, error) {
+	plaintext, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	if scopes == nil {
+		scopes = []string{}
+	}
+	key := &models.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashAPIKey(plaintext),
+		Scopes:    scopes,
+		CreatedAt: s.now(),
+	}
+	if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+	return key, plaintext, nil
+}
+
+func toAPIKeyResponse(key *models.APIKey) *dto.APIKeyResponse {
+	return &dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+		RotatedTo:  key.RotatedTo,
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+	"github.com/kodra-pay/wallet-ledger-service/internal/requestctx"
+)
+
+// AuditService writes and queries the append-only audit trail
+type AuditService struct {
+	repo repositories.AuditRepository
+	now  func() time.Time
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo repositories.AuditRepository) *AuditService {
+	return &AuditService{repo: repo, now: time.Now}
+}
+
+// Record appends an audit record attributed to the caller in ctx. Pass the
+// context of the transaction making the change so that the record commits or
+// rolls back together with it. A nil *AuditService records nothing.
+func (s *AuditService) Record(ctx context.Context, action, entityType string, entityID int, before, after interface{}) error {
+	if s == nil {
+		return nil
+	}
+
+	beforeJSON, err := marshalAuditState(before)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit before state: %w", err)
+	}
+	afterJSON, err := marshalAuditState(after)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit after state: %w", err)
+	}
+
+	actorType, actorID := actor(ctx)
+	record := &models.AuditRecord{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   strconv.Itoa(entityID),
+		ActorType:  actorType,
+		ActorID:    actorID,
+		RequestID:  requestctx.RequestID(ctx),
+		SourceIP:   requestctx.SourceIP(ctx),
+		Before:     beforeJSON,
+		After:      afterJSON,
+		CreatedAt:  s.now(),
+	}
+	if err := s.repo.CreateAuditRecord(ctx, record); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+func (s *AuditService) ListAuditRecords(ctx context.Context, filter models.AuditFilter) ([]dto.AuditRecordResponse, error) {
+	if filter.Limit <= 0 || filter.Limit > 1000 {
+		filter.Limit = 100
+	}
+	records, err := s.repo.ListAuditRecords(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+
+	resp := make([]dto.AuditRecordResponse, 0, len(records))
+	for _, record := range records {
+		resp = append(resp, dto.AuditRecordResponse{
+			ID:         record.ID,
+			Action:     record.Action,
+			EntityType: record.EntityType,
+			EntityID:   record.EntityID,
+			ActorType:  record.ActorType,
+			ActorID:    record.ActorID,
+			RequestID:  record.RequestID,
+			SourceIP:   record.SourceIP,
+			Before:     record.Before,
+			After:      record.After,
+			CreatedAt:  record.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func marshalAuditState(state interface{}) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
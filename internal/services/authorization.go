@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/auth"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// authorizeOwner returns ErrForbidden when the caller is an end user acting on
+// another user's resources. Service principals, and internal callers without
+// a principal (workers, CLI), are not restricted.
+func authorizeOwner(ctx context.Context, userID int) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if ownerID, restricted := principal.OwnerID(); restricted && ownerID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// callerOwnerID returns the user ID the caller is restricted to, if any
+func callerOwnerID(ctx context.Context) (int, bool) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return principal.OwnerID()
+}
+
+// actor identifies the caller for records that store who acted; internal
+// callers are recorded as the system
+func actor(ctx context.Context) (actorType, actorID string) {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return principal.Type, principal.ID
+	}
+	return models.AuditActorSystem, models.AuditActorSystem
+}
+
+// requireIntegration returns ErrForbidden unless the caller is a service
+// authenticated with an API key or an internal caller. People, end users and
+// staff alike, have to go through the adjustment approval flow instead.
+func requireIntegration(ctx context.Context) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.Type == auth.PrincipalAPIKey {
+		return nil
+	}
+	return fmt.Errorf("%w: manual credits must be proposed as adjustments", ErrForbidden)
+}
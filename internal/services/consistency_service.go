@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/metrics"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// ConsistencyService recomputes wallet balances from the ledger and reports
+// any drift between the two, along with journals that do not balance
+type ConsistencyService struct {
+	repo repositories.ConsistencyRepository
+	now  func() time.Time
+
+	runs          metrics.Counter
+	lastRun       metrics.Gauge
+	drift         metrics.Gauge
+	discrepancies metrics.Gauge
+}
+
+// NewConsistencyService creates a new consistency service reporting to registry
+func NewConsistencyService(repo repositories.ConsistencyRepository, registry *metrics.Registry) *ConsistencyService {
+	return &ConsistencyService{
+		repo: repo,
+		now:  time.Now,
+
+		runs:          registry.Counter("ledger_consistency_runs_total", "Number of completed ledger consistency checks."),
+		lastRun:       registry.Gauge("ledger_consistency_last_run_timestamp_seconds", "Unix time the last consistency check finished."),
+		drift:         registry.Gauge("ledger_consistency_drift", "1 when the last consistency check found discrepancies, otherwise 0. Alert on 1."),
+		discrepancies: registry.Gauge("ledger_consistency_discrepancies", "Number of discrepancies found by the last consistency check."),
+	}
+}
+
+// Check runs every consistency check, stores the report and updates the metrics
+func (s *ConsistencyService) Check(ctx context.Context) (*models.ConsistencyReport, error) {
+	report := &models.ConsistencyReport{
+		StartedAt:     s.now().UTC(),
+		Discrepancies: []models.Discrepancy{},
+	}
+
+	totals, err := s.repo.ListWalletLedgerTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recompute wallet balances: %w", err)
+	}
+	for _, t := range totals {
+		report.WalletsChecked++
+		report.EntriesChecked += t.EntryCount
+		if t.Balance != t.LedgerTotal {
+			report.Discrepancies = append(report.Discrepancies, models.Discrepancy{
+				Kind:     models.DiscrepancyWalletBalance,
+				WalletID: t.WalletID,
+				Currency: t.Currency,
+				Expected: t.LedgerTotal,
+				Actual:   t.Balance,
+			})
+		}
+	}
+
+	breaks, err := s.repo.ListRunningBalanceBreaks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check running balances: %w", err)
+	}
+	report.Discrepancies = append(report.Discrepancies, breaks...)
+
+	imbalances, err := s.repo.ListJournalImbalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check journal balances: %w", err)
+	}
+	for _, j := range imbalances {
+		report.Discrepancies = append(report.Discrepancies, models.Discrepancy{
+			Kind:      models.DiscrepancyUnbalancedJournal,
+			JournalID: j.JournalID,
+			Currency:  j.Currency,
+			Expected:  0,
+			Actual:    j.Net,
+		})
+	}
+
+	stats, err := s.repo.GetJournalStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count journals: %w", err)
+	}
+	report.JournalsChecked = stats.Journals
+	report.LegacyEntries = stats.LegacyEntries
+
+	report.OK = len(report.Discrepancies) == 0
+	report.FinishedAt = s.now().UTC()
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to store consistency report: %w", err)
+	}
+
+	s.runs.Inc()
+	s.lastRun.Set(float64(report.FinishedAt.Unix()))
+	s.discrepancies.Set(float64(len(report.Discrepancies)))
+	if report.OK {
+		s.drift.Set(0)
+	} else {
+		s.drift.Set(1)
+	}
+	return report, nil
+}
+
+// RunCheck is the scheduled job: it runs a check and logs an alert on drift
+func (s *ConsistencyService) RunCheck(ctx context.Context) error {
+	report, err := s.Check(ctx)
+	if err != nil {
+		return err
+	}
+	if !report.OK {
+		log.Printf("ALERT: ledger consistency check %d found %d discrepancies", report.ID, len(report.Discrepancies))
+	}
+	return nil
+}
+
+// GetLatestReport returns the most recently stored report
+func (s *ConsistencyService) GetLatestReport(ctx context.Context) (*models.ConsistencyReport, error) {
+	report, err := s.repo.GetLatestReport(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consistency report: %w", err)
+	}
+	if report == nil {
+		return nil, ErrConsistencyReportNotFound
+	}
+	return report, nil
+}
+
+// WriteConsistencyCSV writes a report's discrepancies as CSV, one row each
+func WriteConsistencyCSV(w io.Writer, report *models.ConsistencyReport) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"kind", "wallet_id", "entry_id", "journal_id", "currency", "expected", "actual"}); err != nil {
+		return err
+	}
+	for _, d := range report.Discrepancies {
+		row := []string{d.Kind, optionalID(d.WalletID), optionalID(d.EntryID), d.JournalID, d.Currency,
+			strconv.FormatInt(d.Expected, 10), strconv.FormatInt(d.Actual, 10)}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func optionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+const (
+	// maxCreditLotsPerTick bounds the work one worker tick does
+	maxCreditLotsPerTick = 100
+	// creditLotExpiryRetryDelay postpones a write-off the ledger refused,
+	// e.g. because the wallet is frozen
+	creditLotExpiryRetryDelay = time.Hour
+)
+
+// CreditLotService issues promotional credit lots into wallets and writes
+// them off when they expire. Spending them is part of every WalletService
+// posting that debits a wallet.
+type CreditLotService struct {
+	repo    repositories.CreditLotRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	now     func() time.Time
+}
+
+// NewCreditLotService creates a new credit lot service
+func NewCreditLotService(repo repositories.CreditLotRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService) *CreditLotService {
+	return &CreditLotService{repo: repo, wallets: wallets, tx: tx, audit: audit, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *CreditLotService) WithClock(now func() time.Time) *CreditLotService {
+	s.now = now
+	return s
+}
+
+// IssueLot credits a wallet with a credit lot funded from the marketing
+// expense account
+func (s *CreditLotService) IssueLot(ctx context.Context, walletID int, req dto.IssueCreditLotRequest) (*dto.CreditLotResponse, error) {
+	if req.Type != models.CreditLotPromo && req.Type != models.CreditLotCashback {
+		return nil, fmt.Errorf("%w: type must be 'promo' or 'cashback'", ErrInvalidRequest)
+	}
+	if req.Source == "" {
+		return nil, fmt.Errorf("%w: source is required", ErrInvalidRequest)
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	now := s.now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidRequest)
+	}
+	if req.Restrictions.MinAmount < 0 {
+		return nil, fmt.Errorf("%w: restrictions.min_amount cannot be negative", ErrInvalidRequest)
+	}
+	for _, txnType := range req.Restrictions.TxnTypes {
+		if txnType != FeeTxnDebit && txnType != FeeTxnTransfer {
+			return nil, fmt.Errorf("%w: restrictions.txn_types may only hold 'debit' and 'transfer'", ErrInvalidRequest)
+		}
+	}
+
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot hold credit lots", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+
+	lot := &models.CreditLot{
+		WalletID:     wallet.ID,
+		Type:         req.Type,
+		Source:       req.Source,
+		Currency:     wallet.Currency,
+		Amount:       req.Amount,
+		Remaining:    req.Amount,
+		Restrictions: req.Restrictions,
+		Status:       models.CreditLotOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.UTC()
+		lot.ExpiresAt = &expiresAt
+	}
+	lot.CreatedByType, lot.CreatedByID = actor(ctx)
+	description := req.Description
+	if description == "" {
+		description = fmt.Sprintf("%s credit: %s", req.Type, req.Source)
+	}
+
+	var movements []models.CreditLotMovement
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		expense, err := s.wallets.systemWallet(ctx, models.SystemMarketingExpense, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		entry := models.NewLedgerEntry(wallet.ID, req.Reference, "credit", req.Amount, 0, description)
+		posted, err := s.wallets.postJournal(ctx, []*models.LedgerEntry{entry, counterLeg(entry, expense.ID)})
+		if err != nil {
+			return err
+		}
+		lot.JournalID, lot.LedgerEntryID = posted.ID, entry.ID
+		if err := s.repo.CreateLot(ctx, lot); err != nil {
+			return fmt.Errorf("failed to create credit lot: %w", err)
+		}
+		movement, err := s.recordMovement(ctx, lot, models.CreditLotMovementIssue, req.Amount, entry)
+		if err != nil {
+			return err
+		}
+		movements = append(movements, *movement)
+		return s.audit.Record(ctx, models.AuditCreditLotIssued, "credit_lot", lot.ID, nil, lot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CreditLotResponse{CreditLot: *lot, Movements: movements}, nil
+}
+
+// GetLot returns a credit lot and its movements
+func (s *CreditLotService) GetLot(ctx context.Context, lotID int) (*dto.CreditLotResponse, error) {
+	lot, err := s.repo.GetLotByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit lot: %w", err)
+	}
+	if lot == nil {
+		return nil, ErrCreditLotNotFound
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, lot.WalletID); err != nil {
+		return nil, err
+	}
+	movements, err := s.repo.ListMovements(ctx, lot.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit lot movements: %w", err)
+	}
+	return &dto.CreditLotResponse{CreditLot: *lot, Movements: movements}, nil
+}
+
+// ListLots returns a wallet's credit lots, newest first, optionally narrowed
+// to a status
+func (s *CreditLotService) ListLots(ctx context.Context, walletID int, status string, limit int) ([]models.CreditLot, error) {
+	switch status {
+	case "", models.CreditLotOpen, models.CreditLotSpent, models.CreditLotExpired:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidRequest, status)
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	lots, err := s.repo.ListLots(ctx, walletID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit lots: %w", err)
+	}
+	if lots == nil {
+		lots = []models.CreditLot{}
+	}
+	return lots, nil
+}
+
+// ExpireDue writes off the unspent remainder of expired lots to the
+// marketing expense account; run by a background worker
+func (s *CreditLotService) ExpireDue(ctx context.Context) error {
+	for i := 0; i < maxCreditLotsPerTick; i++ {
+		var claimed bool
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := s.now().UTC()
+			lot, err := s.repo.ClaimExpiredLot(ctx, now)
+			if err != nil {
+				return fmt.Errorf("failed to claim credit lot: %w", err)
+			}
+			if lot == nil {
+				return nil
+			}
+			claimed = true
+
+			err = s.expire(ctx, lot)
+			if isRejection(err) {
+				// Refused before anything was posted; keep the lot open and try again later
+				log.Printf("credit lot %d: write-off postponed: %v", lot.ID, err)
+				retryAt := now.Add(creditLotExpiryRetryDelay)
+				lot.ExpiryRetryAt, lot.UpdatedAt = &retryAt, now
+				if err := s.repo.UpdateLot(ctx, lot); err != nil {
+					return fmt.Errorf("failed to postpone credit lot write-off: %w", err)
+				}
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// expire writes off a claimed lot. The lot is closed first, so that its
+// share of the balance counts as main balance, and the write-off is sized
+// from the locked wallet by writeOff.
+func (s *CreditLotService) expire(ctx context.Context, lot *models.CreditLot) error {
+	before := *lot
+	lot.Remaining, lot.Status, lot.ExpiryRetryAt, lot.UpdatedAt = 0, models.CreditLotExpired, nil, s.now().UTC()
+	if err := s.repo.UpdateLot(ctx, lot); err != nil {
+		return fmt.Errorf("failed to update credit lot: %w", err)
+	}
+	expense, err := s.wallets.systemWallet(ctx, models.SystemMarketingExpense, lot.Currency)
+	if err != nil {
+		return err
+	}
+	entry := models.NewLedgerEntry(lot.WalletID, lot.ID, "debit", before.Remaining, 0, fmt.Sprintf("Expired %s credit: %s", lot.Type, lot.Source))
+	counter := counterLeg(entry, expense.ID)
+	_, err = s.wallets.postJournalAs(ctx, spendCashOnly, []*models.LedgerEntry{entry, counter}, s.writeOff(before.Remaining, entry, counter))
+	switch {
+	case errors.Is(err, errNothingToWriteOff):
+	case err != nil:
+		// Refused before anything was posted; the caller may keep the lot open
+		*lot = before
+		return err
+	default:
+		if _, err := s.recordMovement(ctx, lot, models.CreditLotMovementExpiry, entry.Amount, entry); err != nil {
+			return err
+		}
+	}
+	return s.audit.Record(ctx, models.AuditCreditLotExpired, "credit_lot", lot.ID, &before, lot)
+}
+
+// errNothingToWriteOff is writeOff finding nothing left of a lot to write off
+var errNothingToWriteOff = errors.New("nothing to write off")
+
+// writeOff sizes the legs writing off an expired lot once its wallet is
+// locked: the lot's remaining amount, but never more than the wallet's main
+// balance, in case other postings have already drawn on the lot's share.
+func (s *CreditLotService) writeOff(remaining int64, legs ...*models.LedgerEntry) journalGuard {
+	return func(ctx context.Context, locked map[int]*models.Wallet) error {
+		main, _, _, err := s.wallets.balanceBreakdown(ctx, locked[legs[0].WalletID])
+		if err != nil {
+			return err
+		}
+		amount := min(remaining, main)
+		if amount <= 0 {
+			return errNothingToWriteOff
+		}
+		for _, leg := range legs {
+			leg.Amount = amount
+		}
+		return nil
+	}
+}
+
+func (s *CreditLotService) recordMovement(ctx context.Context, lot *models.CreditLot, movementType string, amount int64, entry *models.LedgerEntry) (*models.CreditLotMovement, error) {
+	movement := &models.CreditLotMovement{
+		LotID:         lot.ID,
+		Type:          movementType,
+		Amount:        amount,
+		JournalID:     entry.JournalID,
+		LedgerEntryID: entry.ID,
+		CreatedAt:     s.now().UTC(),
+	}
+	if err := s.repo.CreateMovement(ctx, movement); err != nil {
+		return nil, fmt.Errorf("failed to record credit lot movement: %w", err)
+	}
+	return movement, nil
+}
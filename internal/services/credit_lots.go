@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// LotSpendCash stands for a wallet's real money in a lot spend order
+const LotSpendCash = "cash"
+
+// DefaultLotSpendOrder spends promotional credit before cashback, and both
+// before real money
+var DefaultLotSpendOrder = []string{models.CreditLotPromo, models.CreditLotCashback, LotSpendCash}
+
+// ParseLotSpendOrder parses a comma-separated spend order of lot types and
+// "cash", e.g. "promo,cash,cashback". Debits draw on the listed kinds of
+// money from first to last; lots of the same type are spent earliest expiry
+// first. An empty order is DefaultLotSpendOrder.
+func ParseLotSpendOrder(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultLotSpendOrder, nil
+	}
+	var order []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		kind := strings.TrimSpace(part)
+		switch kind {
+		case LotSpendCash, models.CreditLotPromo, models.CreditLotCashback:
+		default:
+			return nil, fmt.Errorf("unknown spend order entry %q", kind)
+		}
+		if seen[kind] {
+			return nil, fmt.Errorf("%q appears twice in the spend order", kind)
+		}
+		seen[kind] = true
+		order = append(order, kind)
+	}
+	if !seen[LotSpendCash] {
+		return nil, fmt.Errorf("spend order must include %q", LotSpendCash)
+	}
+	return order, nil
+}
+
+// spendCashOnly as a journal's spend type pays its debits from cash alone,
+// never from credit lots: for money leaving the ledger, clawbacks and
+// corrections
+const spendCashOnly = ""
+
+// walletDebit is what a journal takes out of one wallet, and the parts of it
+// paid from credit lots
+type walletDebit struct {
+	entry *models.LedgerEntry // The wallet's first debit leg; lot movements point at it
+	total int64
+	takes []lotTake
+}
+
+// lotTake is the part of a debit paid from one lot
+type lotTake struct {
+	lot    *models.CreditLot
+	amount int64
+}
+
+// planDebit decides how a journal's debit from a locked customer wallet is
+// paid: from its credit lots in spend order, as a spend transaction, and from
+// its main balance, the cash outside its lots and pockets. A spendCashOnly
+// debit draws on the main balance alone. It fails with ErrInsufficientFunds
+// when the debit would dip into the wallet's pockets, or when the wallet
+// holds enough but too much of it is in lots that cannot pay for this
+// transaction. Otherwise whatever is left overdraws the wallet, as debits
+// without requireFunds may.
+func (s *WalletService) planDebit(ctx context.Context, wallet *models.Wallet, debit *walletDebit, spend string) error {
+	pocketed, err := s.pocketedTotal(ctx, wallet.ID)
+	if err != nil {
+		return err
+	}
+	var lots []*models.CreditLot
+	if s.lots != nil && wallet.IsMonetary() {
+		if lots, err = s.lots.ListOpenLotsForUpdate(ctx, wallet.ID); err != nil {
+			return fmt.Errorf("failed to lock credit lots: %w", err)
+		}
+	}
+	main := wallet.Balance - pocketed
+	for _, lot := range lots {
+		main -= lot.Remaining
+	}
+	main = max(main, 0)
+
+	left := debit.total
+	if spend == spendCashOnly {
+		left -= min(main, left)
+	} else {
+		debit.takes, left = planLotSpend(lots, s.lotOrder, main, debit.entry.Amount, debit.total, spend, s.now().UTC())
+	}
+	switch {
+	case left == 0:
+		return nil
+	case pocketed > 0:
+		return fmt.Errorf("%w: wallet %d has %d outside its pockets, needs %d",
+			ErrInsufficientFunds, wallet.ID, debit.total-left, debit.total)
+	case spend != spendCashOnly && wallet.Balance >= debit.total:
+		return fmt.Errorf("%w: wallet %d has only %d that can be spent on a %s, needs %d",
+			ErrInsufficientFunds, wallet.ID, debit.total-left, spend, debit.total)
+	}
+	return nil
+}
+
+// recordLotTakes applies a posted debit's takes to its lots
+func (s *WalletService) recordLotTakes(ctx context.Context, debit *walletDebit) error {
+	now := s.now().UTC()
+	for _, take := range debit.takes {
+		lot := take.lot
+		lot.Remaining -= take.amount
+		if lot.Remaining == 0 {
+			lot.Status = models.CreditLotSpent
+		}
+		lot.UpdatedAt = now
+		if err := s.lots.UpdateLot(ctx, lot); err != nil {
+			return fmt.Errorf("failed to update credit lot: %w", err)
+		}
+		movement := &models.CreditLotMovement{
+			LotID:         lot.ID,
+			Type:          models.CreditLotMovementSpend,
+			Amount:        take.amount,
+			JournalID:     debit.entry.JournalID,
+			LedgerEntryID: debit.entry.ID,
+			CreatedAt:     now,
+		}
+		if err := s.lots.CreateMovement(ctx, movement); err != nil {
+			return fmt.Errorf("failed to record credit lot movement: %w", err)
+		}
+	}
+	return nil
+}
+
+// planLotSpend decides how a debit of total is paid from a wallet's open
+// lots and its cash, following order; lots of the same type go earliest
+// expiry first, and lots of types missing from order go last. Expired lots,
+// and lots whose restrictions rule out a txnType transaction of amount, are
+// skipped. left is the part of total neither lots nor cash cover.
+func planLotSpend(lots []*models.CreditLot, order []string, cash, amount, total int64, txnType string, now time.Time) (takes []lotTake, left int64) {
+	rank := func(kind string) int {
+		for i, o := range order {
+			if o == kind {
+				return i
+			}
+		}
+		return len(order)
+	}
+	sorted := append([]*models.CreditLot(nil), lots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if rank(a.Type) != rank(b.Type) {
+			return rank(a.Type) < rank(b.Type)
+		}
+		switch {
+		case a.ExpiresAt == nil || b.ExpiresAt == nil:
+			return a.ExpiresAt != nil && b.ExpiresAt == nil
+		case !a.ExpiresAt.Equal(*b.ExpiresAt):
+			return a.ExpiresAt.Before(*b.ExpiresAt)
+		}
+		return a.ID < b.ID
+	})
+
+	left = total
+	cashRank, cashSpent := rank(LotSpendCash), false
+	spendCash := func() {
+		left -= min(cash, left)
+		cashSpent = true
+	}
+	for _, lot := range sorted {
+		if left == 0 {
+			break
+		}
+		if !cashSpent && rank(lot.Type) > cashRank {
+			spendCash()
+			if left == 0 {
+				break
+			}
+		}
+		if lot.Remaining <= 0 || lot.Expired(now) || !lot.Restrictions.Allows(txnType, amount) {
+			continue
+		}
+		take := min(lot.Remaining, left)
+		takes = append(takes, lotTake{lot: lot, amount: take})
+		left -= take
+	}
+	if !cashSpent {
+		spendCash()
+	}
+	return takes, left
+}
+
+// requireCash rejects the journal when walletID holds less than amount in
+// its main balance, i.e. outside its credit lots and pockets. Money leaving
+// the ledger, such as a payout, cannot be paid from promotional credit.
+func (s *WalletService) requireCash(walletID int, amount int64) journalGuard {
+	return func(ctx context.Context, locked map[int]*models.Wallet) error {
+		wallet := locked[walletID]
+		main, _, _, err := s.balanceBreakdown(ctx, wallet)
+		if err != nil {
+			return err
+		}
+		if main < amount {
+			return fmt.Errorf("%w: wallet %d has %d in cash outside its pockets, needs %d", ErrInsufficientFunds, walletID, main, amount)
+		}
+		return nil
+	}
+}
+
+// balanceBreakdown splits a wallet's balance into its main balance, the
+// real money outside its credit lots and pockets, the remaining amount of
+// its open credit lots by lot type, and the total of its open pockets
+func (s *WalletService) balanceBreakdown(ctx context.Context, wallet *models.Wallet) (main int64, credits map[string]int64, pocketed int64, err error) {
+	if pocketed, err = s.pocketedTotal(ctx, wallet.ID); err != nil {
+		return 0, nil, 0, err
+	}
+	main = wallet.Balance - pocketed
+	if s.lots != nil {
+		if credits, err = s.lots.OpenLotTotals(ctx, wallet.ID); err != nil {
+			return 0, nil, 0, fmt.Errorf("failed to get credit lot totals: %w", err)
+		}
+	}
+	for _, total := range credits {
+		main -= total
+	}
+	return max(main, 0), credits, pocketed, nil
+}
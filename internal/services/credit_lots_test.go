@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+func TestParseLotSpendOrder(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "", want: DefaultLotSpendOrder},
+		{value: " cashback, cash ,promo", want: []string{"cashback", "cash", "promo"}},
+		{value: "cash", want: []string{"cash"}},
+		{value: "promo,cashback", wantErr: true},
+		{value: "promo,cash,promo", wantErr: true},
+		{value: "promo,cash,points", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLotSpendOrder(tt.value)
+		if (err != nil) != tt.wantErr || !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLotSpendOrder(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestPlanLotSpend(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	in := func(d time.Duration) *time.Time {
+		at := now.Add(d)
+		return &at
+	}
+	lots := []*models.CreditLot{
+		{ID: 1, Type: models.CreditLotCashback, Remaining: 300},
+		{ID: 2, Type: models.CreditLotPromo, Remaining: 200, ExpiresAt: in(48 * time.Hour)},
+		{ID: 3, Type: models.CreditLotPromo, Remaining: 100, ExpiresAt: in(24 * time.Hour)},
+		{ID: 4, Type: models.CreditLotPromo, Remaining: 500},
+		{ID: 5, Type: models.CreditLotPromo, Remaining: 700, ExpiresAt: in(-time.Minute)},
+		{ID: 6, Type: models.CreditLotPromo, Remaining: 400, Restrictions: models.CreditLotRestrictions{TxnTypes: []string{FeeTxnTransfer}}},
+		{ID: 7, Type: models.CreditLotPromo, Remaining: 600, Restrictions: models.CreditLotRestrictions{MinAmount: 5000}},
+	}
+	tests := []struct {
+		name  string
+		order []string
+		cash  int64
+		total int64
+
+		wantIDs  []int         // Lots in the order they are drawn on
+		want     map[int]int64 // Lot ID to amount taken
+		wantLeft int64
+	}{
+		{
+			name:    "promo by earliest expiry, then cashback, then cash",
+			order:   DefaultLotSpendOrder,
+			cash:    1000,
+			total:   1500,
+			wantIDs: []int{3, 2, 4, 1},
+			want:    map[int]int64{3: 100, 2: 200, 4: 500, 1: 300},
+		},
+		{
+			name:    "stops once paid",
+			order:   DefaultLotSpendOrder,
+			cash:    1000,
+			total:   250,
+			wantIDs: []int{3, 2},
+			want:    map[int]int64{3: 100, 2: 150},
+		},
+		{
+			name:    "cash before lots ranked after it",
+			order:   []string{models.CreditLotPromo, LotSpendCash, models.CreditLotCashback},
+			cash:    400,
+			total:   1250,
+			wantIDs: []int{3, 2, 4, 1},
+			want:    map[int]int64{3: 100, 2: 200, 4: 500, 1: 50},
+		},
+		{
+			name:    "cash first leaves the lots alone",
+			order:   []string{LotSpendCash, models.CreditLotPromo, models.CreditLotCashback},
+			cash:    1000,
+			total:   1000,
+			wantIDs: nil,
+			want:    map[int]int64{},
+		},
+		{
+			name:     "short of funds",
+			order:    DefaultLotSpendOrder,
+			cash:     100,
+			total:    2000,
+			wantIDs:  []int{3, 2, 4, 1},
+			want:     map[int]int64{3: 100, 2: 200, 4: 500, 1: 300},
+			wantLeft: 800,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			takes, left := planLotSpend(lots, tt.order, tt.cash, 1000, tt.total, FeeTxnDebit, now)
+			var ids []int
+			got := map[int]int64{}
+			for _, take := range takes {
+				ids = append(ids, take.lot.ID)
+				got[take.lot.ID] = take.amount
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || !reflect.DeepEqual(got, tt.want) || left != tt.wantLeft {
+				t.Errorf("took %v from lots %v leaving %d, want %v from %v leaving %d", got, ids, left, tt.want, tt.wantIDs, tt.wantLeft)
+			}
+		})
+	}
+
+	// Restrictions decide which lots a transaction may use
+	takes, _ := planLotSpend(lots[5:], DefaultLotSpendOrder, 0, 5000, 1000, FeeTxnTransfer, now)
+	if len(takes) != 2 || takes[0].lot.ID != 6 || takes[1].lot.ID != 7 {
+		t.Errorf("transfer of 5000 took %+v, want lots 6 and 7", takes)
+	}
+	if takes, _ := planLotSpend(lots[5:], DefaultLotSpendOrder, 0, 4999, 1000, FeeTxnDebit, now); len(takes) != 0 {
+		t.Errorf("debit of 4999 took %+v, want none", takes)
+	}
+}
+
+func TestCreditLotExpireDue(t *testing.T) {
+	tests := []struct {
+		name      string
+		balance   int64
+		remaining int64
+		frozen    bool
+
+		wantStatus  string
+		wantBalance int64
+		wantExpense int64
+	}{
+		{name: "unspent remainder written off", balance: 5000, remaining: 2000, wantStatus: models.CreditLotExpired, wantBalance: 3000, wantExpense: 2000},
+		{name: "capped at what the wallet holds", balance: 800, remaining: 2000, wantStatus: models.CreditLotExpired, wantBalance: 0, wantExpense: 800},
+		{name: "nothing left to write off", balance: 0, remaining: 2000, wantStatus: models.CreditLotExpired},
+		{name: "frozen wallet postpones the write-off", balance: 5000, remaining: 2000, frozen: true, wantStatus: models.CreditLotOpen, wantBalance: 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+			expired := now.Add(-time.Minute)
+			wallets := newFakeWalletRepository()
+			repo := &fakeCreditLotRepository{}
+			walletSvc := NewWalletService(wallets, fakeTransactor{}, WithCreditLots(repo, DefaultLotSpendOrder)).WithClock(func() time.Time { return now })
+			svc := NewCreditLotService(repo, walletSvc, fakeTransactor{}, nil).WithClock(func() time.Time { return now })
+			wallet := wallets.addWallet(1, "GBP", tt.balance)
+			if tt.frozen {
+				setWalletStatus(wallets, wallet.ID, models.WalletStatusFrozen)
+			}
+			repo.CreateLot(context.Background(), &models.CreditLot{
+				WalletID: wallet.ID, Type: models.CreditLotPromo, Currency: "GBP", Amount: 3000, Remaining: tt.remaining, ExpiresAt: &expired, Status: models.CreditLotOpen,
+			})
+
+			if err := svc.ExpireDue(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			lot, _ := repo.GetLotByID(context.Background(), 1)
+			if lot.Status != tt.wantStatus || tt.frozen != (lot.ExpiryRetryAt != nil) {
+				t.Errorf("lot = %s, retry at %v, want %s", lot.Status, lot.ExpiryRetryAt, tt.wantStatus)
+			}
+			if got := wallets.balance(wallet.ID); got != tt.wantBalance {
+				t.Errorf("balance = %d, want %d", got, tt.wantBalance)
+			}
+			if got := wallets.systemBalance(models.SystemMarketingExpense, "GBP"); got != tt.wantExpense {
+				t.Errorf("marketing expense = %d, want %d", got, tt.wantExpense)
+			}
+			var written []int64
+			movements, _ := repo.ListMovements(context.Background(), lot.ID)
+			for _, movement := range movements {
+				written = append(written, movement.Amount)
+			}
+			if want := []int64{tt.wantExpense}; tt.wantExpense > 0 && !reflect.DeepEqual(written, want) || tt.wantExpense == 0 && written != nil {
+				t.Errorf("expiry movements = %v, want one of %d", written, tt.wantExpense)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// PointsCurrency is the built-in loyalty points unit
+var PointsCurrency = models.Currency{Code: "PTS", Name: "Loyalty points", AssetType: models.AssetPoints}
+
+// iso4217Exponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth
+var iso4217Exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyRegistry knows the units wallets can hold: any ISO 4217 style code
+// (three upper-case letters) as fiat money, plus registered units such as
+// loyalty points. Registered units take precedence.
+type CurrencyRegistry struct {
+	units map[string]models.Currency
+}
+
+// NewCurrencyRegistry creates a registry of the built-in points unit and
+// units, which may override it
+func NewCurrencyRegistry(units []models.Currency) (*CurrencyRegistry, error) {
+	r := &CurrencyRegistry{units: map[string]models.Currency{PointsCurrency.Code: PointsCurrency}}
+	for _, unit := range units {
+		if unit.Code == "" || len(unit.Code) > 10 {
+			return nil, fmt.Errorf("currency code %q must be 1 to 10 characters", unit.Code)
+		}
+		switch unit.AssetType {
+		case "":
+			unit.AssetType = models.AssetFiat
+		case models.AssetFiat, models.AssetPoints:
+		default:
+			return nil, fmt.Errorf("currency %s: unknown asset type %q", unit.Code, unit.AssetType)
+		}
+		if unit.Exponent < 0 || unit.Exponent > 8 {
+			return nil, fmt.Errorf("currency %s: exponent must be between 0 and 8", unit.Code)
+		}
+		r.units[unit.Code] = unit
+	}
+	return r, nil
+}
+
+// LoadCurrencies reads extra currency units from a JSON list of
+// {"code", "name", "asset_type", "exponent"} objects
+func LoadCurrencies(path string) ([]models.Currency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var units []models.Currency
+	if err := json.Unmarshal(data, &units); err != nil {
+		return nil, fmt.Errorf("invalid currencies: %w", err)
+	}
+	return units, nil
+}
+
+// Lookup returns the currency for code. A nil registry treats every code as
+// fiat.
+func (r *CurrencyRegistry) Lookup(code string) (models.Currency, error) {
+	if r == nil {
+		return models.Currency{Code: code, AssetType: models.AssetFiat, Exponent: 2}, nil
+	}
+	if unit, ok := r.units[code]; ok {
+		return unit, nil
+	}
+	if !isISOCode(code) {
+		return models.Currency{}, fmt.Errorf("%w: unknown currency %q", ErrInvalidRequest, code)
+	}
+	exponent, ok := iso4217Exponents[code]
+	if !ok {
+		exponent = 2
+	}
+	return models.Currency{Code: code, AssetType: models.AssetFiat, Exponent: exponent}, nil
+}
+
+func isISOCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// requireMonetary rejects wallets holding points rather than money
+func requireMonetary(wallets ...*models.Wallet) error {
+	for _, wallet := range wallets {
+		if !wallet.IsMonetary() {
+			return fmt.Errorf("%w: wallet %d holds %s %s, not money", ErrInvalidRequest, wallet.ID, wallet.AssetType, wallet.Currency)
+		}
+	}
+	return nil
+}
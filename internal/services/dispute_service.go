@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+const (
+	// maxDisputesPerTick bounds the work one worker tick does
+	maxDisputesPerTick = 100
+	// disputeCreditRetryDelay postpones an automatic provisional credit the
+	// ledger refused, e.g. because the customer's wallet is frozen
+	disputeCreditRetryDelay = time.Hour
+)
+
+// DisputeConfig holds the dispute deadlines
+type DisputeConfig struct {
+	// ProvisionalCreditAfter is how long after opening a dispute the customer
+	// is provisionally credited if nobody has decided by then; 0 never
+	// credits automatically
+	ProvisionalCreditAfter time.Duration
+	// ResolutionWindow is how long after opening a dispute it should be
+	// decided; later it is reported as overdue
+	ResolutionWindow time.Duration
+}
+
+// DisputeService tracks customer disputes of debits from opening to
+// resolution, funding provisional credits from the disputes system account
+type DisputeService struct {
+	repo    repositories.DisputeRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	cfg     DisputeConfig
+	now     func() time.Time
+}
+
+// NewDisputeService creates a new dispute service
+func NewDisputeService(repo repositories.DisputeRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService, cfg DisputeConfig) *DisputeService {
+	return &DisputeService{repo: repo, wallets: wallets, tx: tx, audit: audit, cfg: cfg, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *DisputeService) WithClock(now func() time.Time) *DisputeService {
+	s.now = now
+	return s
+}
+
+// OpenDispute disputes some or all of a debit on the caller's wallet. A debit
+// can be disputed more than once, but never for more than its amount across
+// disputes that are open or won.
+func (s *DisputeService) OpenDispute(ctx context.Context, req dto.OpenDisputeRequest) (*dto.DisputeResponse, error) {
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidRequest)
+	}
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("%w: amount cannot be negative", ErrInvalidRequest)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, req.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot open disputes", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+	entry, err := s.wallets.repo.GetLedgerEntryByID(ctx, req.LedgerEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entry: %w", err)
+	}
+	if entry == nil || entry.WalletID != wallet.ID {
+		return nil, ErrLedgerEntryNotFound
+	}
+	if entry.Type != "debit" || entry.ReversalOf != nil {
+		return nil, fmt.Errorf("%w: only debits can be disputed", ErrInvalidRequest)
+	}
+
+	now := s.now().UTC()
+	dispute := &models.Dispute{
+		WalletID:      wallet.ID,
+		LedgerEntryID: entry.ID,
+		Currency:      wallet.Currency,
+		Amount:        req.Amount,
+		Status:        models.DisputeOpened,
+		Reason:        req.Reason,
+		ResolveBy:     now.Add(s.cfg.ResolutionWindow),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if s.cfg.ProvisionalCreditAfter > 0 {
+		due := now.Add(s.cfg.ProvisionalCreditAfter)
+		dispute.ProvisionalCreditDueAt = &due
+	}
+	dispute.CreatedByType, dispute.CreatedByID = actor(ctx)
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Locking the wallet serializes disputes of the same entry
+		if _, err := s.wallets.repo.GetWalletByIDForUpdate(ctx, wallet.ID); err != nil {
+			return fmt.Errorf("failed to lock wallet: %w", err)
+		}
+		disputed, err := s.repo.DisputedAmount(ctx, entry.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get disputed amount: %w", err)
+		}
+		undisputed := entry.Amount - disputed
+		if undisputed <= 0 {
+			return fmt.Errorf("%w: ledger entry %d is already disputed in full", ErrConflict, entry.ID)
+		}
+		if dispute.Amount == 0 {
+			dispute.Amount = undisputed
+		}
+		if dispute.Amount > undisputed {
+			return fmt.Errorf("%w: only %d of the debit is undisputed", ErrInvalidRequest, undisputed)
+		}
+		if err := s.repo.CreateDispute(ctx, dispute); err != nil {
+			return fmt.Errorf("failed to create dispute: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditDisputeOpened, "dispute", dispute.ID, nil, dispute)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(dispute, nil), nil
+}
+
+// GetDispute returns a dispute and its postings
+func (s *DisputeService) GetDispute(ctx context.Context, disputeID int) (*dto.DisputeResponse, error) {
+	dispute, err := s.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+	if dispute == nil {
+		return nil, ErrDisputeNotFound
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, dispute.WalletID); err != nil {
+		return nil, err
+	}
+	movements, err := s.repo.ListMovements(ctx, dispute.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dispute movements: %w", err)
+	}
+	return s.toResponse(dispute, movements), nil
+}
+
+// ListDisputes returns disputes newest first, narrowed to a wallet, a status
+// and to overdue disputes as asked. Only unrestricted callers may list across
+// wallets.
+func (s *DisputeService) ListDisputes(ctx context.Context, walletID int, status string, overdue bool, limit int) ([]dto.DisputeResponse, error) {
+	if walletID != 0 {
+		if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+			return nil, err
+		}
+	} else if _, restricted := callerOwnerID(ctx); restricted {
+		return nil, fmt.Errorf("%w: wallet_id is required", ErrInvalidRequest)
+	}
+	switch status {
+	case "", models.DisputeOpened, models.DisputeProvisionalCredit, models.DisputeWon, models.DisputeLost, models.DisputeWithdrawn:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidRequest, status)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	disputes, err := s.repo.ListDisputes(ctx, walletID, status, overdue, s.now().UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list disputes: %w", err)
+	}
+
+	resp := make([]dto.DisputeResponse, 0, len(disputes))
+	for i := range disputes {
+		resp = append(resp, *s.toResponse(&disputes[i], nil))
+	}
+	return resp, nil
+}
+
+// GrantProvisionalCredit credits the customer the disputed amount while the
+// dispute is investigated
+func (s *DisputeService) GrantProvisionalCredit(ctx context.Context, disputeID int, req dto.DisputeActionRequest) (*dto.DisputeResponse, error) {
+	return s.transition(ctx, disputeID, false, func(ctx context.Context, dispute *models.Dispute) error {
+		if dispute.Status != models.DisputeOpened {
+			return fmt.Errorf("%w: dispute is %s", ErrConflict, dispute.Status)
+		}
+		return s.grant(ctx, dispute, req.Reference, req.Reason)
+	})
+}
+
+// Resolve decides an open dispute. Won makes the customer's credit final,
+// crediting them now if they had no provisional credit. Lost reverses any
+// provisional credit; like any reversal this may overdraw the wallet.
+func (s *DisputeService) Resolve(ctx context.Context, disputeID int, req dto.ResolveDisputeRequest) (*dto.DisputeResponse, error) {
+	if req.Outcome != models.DisputeWon && req.Outcome != models.DisputeLost {
+		return nil, fmt.Errorf("%w: outcome must be 'won' or 'lost'", ErrInvalidRequest)
+	}
+	return s.transition(ctx, disputeID, false, func(ctx context.Context, dispute *models.Dispute) error {
+		return s.decide(ctx, dispute, req.Outcome, req.Reference, req.Note)
+	})
+}
+
+// Withdraw drops an open dispute at the customer's request, reversing any
+// provisional credit
+func (s *DisputeService) Withdraw(ctx context.Context, disputeID int, req dto.DisputeActionRequest) (*dto.DisputeResponse, error) {
+	return s.transition(ctx, disputeID, true, func(ctx context.Context, dispute *models.Dispute) error {
+		return s.decide(ctx, dispute, models.DisputeWithdrawn, req.Reference, req.Reason)
+	})
+}
+
+// transition locks a dispute and applies fn. Customer actions check the
+// caller may act on the disputed wallet; the rest are admin routes.
+func (s *DisputeService) transition(ctx context.Context, disputeID int, customer bool, fn func(ctx context.Context, dispute *models.Dispute) error) (*dto.DisputeResponse, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		dispute, err := s.repo.GetDisputeByIDForUpdate(ctx, disputeID)
+		if err != nil {
+			return fmt.Errorf("failed to lock dispute: %w", err)
+		}
+		if dispute == nil {
+			return ErrDisputeNotFound
+		}
+		if customer {
+			if _, err := s.wallets.getAuthorizedWallet(ctx, dispute.WalletID); err != nil {
+				return err
+			}
+		}
+		if !dispute.IsOpen() {
+			return fmt.Errorf("%w: dispute is %s", ErrConflict, dispute.Status)
+		}
+		return fn(ctx, dispute)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDispute(ctx, disputeID)
+}
+
+// grant posts the provisional credit and moves the dispute to provisional_credit
+func (s *DisputeService) grant(ctx context.Context, dispute *models.Dispute, reference int, reason string) error {
+	if _, err := s.post(ctx, dispute, models.DisputeMovementProvisionalCredit, dispute.Amount, reference, reason); err != nil {
+		return err
+	}
+	before := *dispute
+	dispute.ProvisionalAmount = dispute.Amount
+	dispute.Status = models.DisputeProvisionalCredit
+	dispute.ProvisionalCreditDueAt = nil
+	return s.update(ctx, &before, dispute)
+}
+
+// decide closes a dispute as won, lost or withdrawn and makes its postings
+func (s *DisputeService) decide(ctx context.Context, dispute *models.Dispute, status string, reference int, note string) error {
+	var err error
+	switch {
+	case status == models.DisputeWon && dispute.ProvisionalAmount > 0:
+		_, err = s.post(ctx, dispute, models.DisputeMovementRecovery, dispute.ProvisionalAmount, reference, note)
+	case status == models.DisputeWon:
+		_, err = s.post(ctx, dispute, models.DisputeMovementFinalCredit, dispute.Amount, reference, note)
+	case dispute.ProvisionalAmount > 0:
+		_, err = s.post(ctx, dispute, models.DisputeMovementProvisionalReversal, dispute.ProvisionalAmount, reference, note)
+	}
+	if err != nil {
+		return err
+	}
+
+	before := *dispute
+	now := s.now().UTC()
+	if status != models.DisputeWon {
+		dispute.ProvisionalAmount = 0
+	}
+	dispute.Status, dispute.ResolutionNote, dispute.ResolvedAt = status, note, &now
+	dispute.ProvisionalCreditDueAt = nil
+	return s.update(ctx, &before, dispute)
+}
+
+func (s *DisputeService) update(ctx context.Context, before, dispute *models.Dispute) error {
+	dispute.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateDispute(ctx, dispute); err != nil {
+		return fmt.Errorf("failed to update dispute: %w", err)
+	}
+	return s.audit.Record(ctx, models.AuditDisputeStatusChanged, "dispute", dispute.ID, before, dispute)
+}
+
+// CreditDue provisionally credits disputes still undecided at their
+// provisional credit deadline; run by a background worker
+func (s *DisputeService) CreditDue(ctx context.Context) error {
+	for i := 0; i < maxDisputesPerTick; i++ {
+		var claimed bool
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := s.now().UTC()
+			dispute, err := s.repo.ClaimDueProvisionalCredit(ctx, now)
+			if err != nil {
+				return fmt.Errorf("failed to claim dispute: %w", err)
+			}
+			if dispute == nil {
+				return nil
+			}
+			claimed = true
+
+			err = s.grant(ctx, dispute, 0, "provisional credit deadline reached")
+			if errors.Is(err, ErrWalletNotActive) {
+				// Refused before anything was written; try again later
+				log.Printf("dispute %d: provisional credit postponed: %v", dispute.ID, err)
+				retryAt := now.Add(disputeCreditRetryDelay)
+				dispute.ProvisionalCreditDueAt = &retryAt
+				dispute.UpdatedAt = now
+				if err := s.repo.UpdateDispute(ctx, dispute); err != nil {
+					return fmt.Errorf("failed to postpone provisional credit: %w", err)
+				}
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// post makes one dispute posting as a journal and records the movement.
+// Provisional credits and their reversals move funds between the customer
+// and the disputes account; a recovery settles the disputes account from
+// external clearing; a final credit pays the customer from external
+// clearing.
+func (s *DisputeService) post(ctx context.Context, dispute *models.Dispute, movementType string, amount int64, reference int, reason string) (*models.DisputeMovement, error) {
+	account, err := s.wallets.systemWallet(ctx, models.SystemDisputes, dispute.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if reference == 0 {
+		reference = dispute.ID
+	}
+
+	var entry, counter *models.LedgerEntry
+	switch movementType {
+	case models.DisputeMovementProvisionalCredit:
+		entry = models.NewLedgerEntry(dispute.WalletID, reference, "credit", amount, 0, fmt.Sprintf("Provisional credit for dispute %d", dispute.ID))
+		counter = counterLeg(entry, account.ID)
+	case models.DisputeMovementProvisionalReversal:
+		entry = models.NewLedgerEntry(dispute.WalletID, reference, "debit", amount, 0, fmt.Sprintf("Reversal of provisional credit for dispute %d", dispute.ID))
+		counter = counterLeg(entry, account.ID)
+	case models.DisputeMovementRecovery, models.DisputeMovementFinalCredit:
+		clearing, err := s.wallets.systemWallet(ctx, models.SystemExternalClearing, dispute.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if movementType == models.DisputeMovementRecovery {
+			entry = models.NewLedgerEntry(account.ID, reference, "credit", amount, 0, fmt.Sprintf("Recovery for dispute %d", dispute.ID))
+		} else {
+			entry = models.NewLedgerEntry(dispute.WalletID, reference, "credit", amount, 0, fmt.Sprintf("Credit for won dispute %d", dispute.ID))
+		}
+		counter = counterLeg(entry, clearing.ID)
+	default:
+		return nil, fmt.Errorf("unknown dispute movement %q", movementType)
+	}
+
+	// Taking back a provisional credit claws back money, not promotional credit
+	posted, err := s.wallets.postJournalAs(ctx, spendCashOnly, []*models.LedgerEntry{entry, counter})
+	if err != nil {
+		return nil, err
+	}
+	movement := &models.DisputeMovement{
+		DisputeID:     dispute.ID,
+		Type:          movementType,
+		Amount:        amount,
+		JournalID:     posted.ID,
+		LedgerEntryID: entry.ID,
+		Reason:        reason,
+		CreatedAt:     s.now().UTC(),
+	}
+	movement.ActorType, movement.ActorID = actor(ctx)
+	if err := s.repo.CreateMovement(ctx, movement); err != nil {
+		return nil, fmt.Errorf("failed to record dispute movement: %w", err)
+	}
+	return movement, nil
+}
+
+func (s *DisputeService) toResponse(dispute *models.Dispute, movements []models.DisputeMovement) *dto.DisputeResponse {
+	return &dto.DisputeResponse{
+		Dispute:   *dispute,
+		Overdue:   dispute.IsOpen() && s.now().After(dispute.ResolveBy),
+		Movements: movements,
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by services. Handlers map them to HTTP status codes
+// with errors.Is, so wrap them with %w when adding detail.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrNotFound       = errors.New("not found")
+	ErrForbidden      = errors.New("forbidden")
+	ErrConflict       = errors.New("conflict")
+
+	ErrWalletNotFound          = fmt.Errorf("wallet %w", ErrNotFound)
+	ErrWalletNotActive         = fmt.Errorf("%w: wallet is not active", ErrConflict)
+	ErrInsufficientFunds       = fmt.Errorf("%w: insufficient funds", ErrConflict)
+	ErrLedgerEntryNotFound     = fmt.Errorf("ledger entry %w", ErrNotFound)
+	ErrWebhookEndpointNotFound = fmt.Errorf("webhook endpoint %w", ErrNotFound)
+	ErrWebhookDeliveryNotFound = fmt.Errorf("webhook delivery %w", ErrNotFound)
+	ErrAPIKeyNotFound          = fmt.Errorf("api key %w", ErrNotFound)
+	ErrCheckpointNotFound      = fmt.Errorf("ledger checkpoint %w", ErrNotFound)
+
+	ErrConsistencyReportNotFound = fmt.Errorf("consistency report %w", ErrNotFound)
+	ErrAdjustmentNotFound        = fmt.Errorf("adjustment %w", ErrNotFound)
+	ErrTransferScheduleNotFound  = fmt.Errorf("transfer schedule %w", ErrNotFound)
+	ErrEscrowNotFound            = fmt.Errorf("escrow %w", ErrNotFound)
+	ErrSettlementBatchNotFound   = fmt.Errorf("settlement batch %w", ErrNotFound)
+	ErrReserveNotFound           = fmt.Errorf("reserve %w", ErrNotFound)
+	ErrPayoutNotFound            = fmt.Errorf("payout %w", ErrNotFound)
+	ErrRiskCaseNotFound          = fmt.Errorf("risk case %w", ErrNotFound)
+	ErrScreeningReviewNotFound   = fmt.Errorf("screening review %w", ErrNotFound)
+	ErrDisputeNotFound           = fmt.Errorf("dispute %w", ErrNotFound)
+	ErrCreditLotNotFound         = fmt.Errorf("credit lot %w", ErrNotFound)
+	ErrInterestAccountNotFound   = fmt.Errorf("interest account %w", ErrNotFound)
+	ErrPocketNotFound            = fmt.Errorf("pocket %w", ErrNotFound)
+)
+
+// isRejection reports whether err is a service refusing a request, as
+// opposed to a failure of the database or another dependency. Rejections are
+// returned before anything is written, so the transaction is still usable.
+func isRejection(err error) bool {
+	return errors.Is(err, ErrInvalidRequest) || errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrForbidden) || errors.Is(err, ErrConflict)
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+const (
+	// maxEscrowsPerTick bounds the work one worker tick does
+	maxEscrowsPerTick = 100
+	// escrowReleaseRetryDelay postpones a timed release the ledger refused,
+	// e.g. because the seller's wallet is frozen. It doubles with every
+	// refusal in a row, up to maxEscrowReleaseRetryDelay.
+	escrowReleaseRetryDelay    = time.Hour
+	maxEscrowReleaseRetryDelay = 24 * time.Hour
+)
+
+// EscrowService holds buyer funds against a contract in the escrow system
+// account and releases them to the seller or refunds them to the buyer
+type EscrowService struct {
+	repo    repositories.EscrowRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	now     func() time.Time
+}
+
+// NewEscrowService creates a new escrow service
+func NewEscrowService(repo repositories.EscrowRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService) *EscrowService {
+	return &EscrowService{repo: repo, wallets: wallets, tx: tx, audit: audit, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *EscrowService) WithClock(now func() time.Time) *EscrowService {
+	s.now = now
+	return s
+}
+
+// CreateEscrow moves funds from the buyer's wallet into escrow
+func (s *EscrowService) CreateEscrow(ctx context.Context, req dto.CreateEscrowRequest) (*dto.EscrowResponse, error) {
+	if req.ContractID == "" {
+		return nil, fmt.Errorf("%w: contract_id is required", ErrInvalidRequest)
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	if req.BuyerWalletID == req.SellerWalletID {
+		return nil, fmt.Errorf("%w: buyer and seller wallets must differ", ErrInvalidRequest)
+	}
+
+	buyer, err := s.wallets.getAuthorizedWallet(ctx, req.BuyerWalletID)
+	if err != nil {
+		return nil, err
+	}
+	seller, err := s.wallets.repo.GetWalletByID(ctx, req.SellerWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if seller == nil {
+		return nil, fmt.Errorf("%w: seller %d", ErrWalletNotFound, req.SellerWalletID)
+	}
+	if buyer.IsSystem() || seller.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot take part in escrows", ErrInvalidRequest)
+	}
+	if err := requireMonetary(buyer, seller); err != nil {
+		return nil, err
+	}
+	if buyer.Currency != seller.Currency {
+		return nil, fmt.Errorf("%w: wallets have different currencies", ErrInvalidRequest)
+	}
+
+	now := s.now().UTC()
+	escrow := &models.Escrow{
+		ContractID:     req.ContractID,
+		BuyerWalletID:  buyer.ID,
+		SellerWalletID: seller.ID,
+		Currency:       buyer.Currency,
+		Amount:         req.Amount,
+		Status:         models.EscrowHeld,
+		Description:    req.Description,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if req.ReleaseAt != nil {
+		if !req.ReleaseAt.After(now) {
+			return nil, fmt.Errorf("%w: release_at must be in the future", ErrInvalidRequest)
+		}
+		releaseAt := req.ReleaseAt.UTC()
+		escrow.ReleaseAt = &releaseAt
+	}
+	escrow.CreatedByType, escrow.CreatedByID = actor(ctx)
+
+	var movement *models.EscrowMovement
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetEscrowByContractID(ctx, req.ContractID)
+		if err != nil {
+			return fmt.Errorf("failed to check for existing escrow: %w", err)
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: contract %s already has escrow %d", ErrConflict, req.ContractID, existing.ID)
+		}
+		if err := s.repo.CreateEscrow(ctx, escrow); err != nil {
+			return fmt.Errorf("failed to create escrow: %w", err)
+		}
+		movement, err = s.post(ctx, escrow, models.EscrowMovementHold, req.Amount, req.Reference, "")
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, models.AuditEscrowCreated, "escrow", escrow.ID, nil, escrow)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toEscrowResponse(escrow, []models.EscrowMovement{*movement}), nil
+}
+
+// GetEscrow returns an escrow and its postings to its buyer or seller
+func (s *EscrowService) GetEscrow(ctx context.Context, escrowID int) (*dto.EscrowResponse, error) {
+	escrow, err := s.repo.GetEscrowByID(ctx, escrowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escrow: %w", err)
+	}
+	if escrow == nil {
+		return nil, ErrEscrowNotFound
+	}
+	if err := s.authorize(ctx, escrow.BuyerWalletID, escrow.SellerWalletID); err != nil {
+		return nil, err
+	}
+	movements, err := s.repo.ListMovements(ctx, escrow.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escrow movements: %w", err)
+	}
+	return toEscrowResponse(escrow, movements), nil
+}
+
+// GetEscrowByContractID returns the escrow for a contract
+func (s *EscrowService) GetEscrowByContractID(ctx context.Context, contractID string) (*dto.EscrowResponse, error) {
+	escrow, err := s.repo.GetEscrowByContractID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escrow: %w", err)
+	}
+	if escrow == nil {
+		return nil, ErrEscrowNotFound
+	}
+	return s.GetEscrow(ctx, escrow.ID)
+}
+
+// ListEscrows returns the escrows a wallet is the buyer or seller in, newest first
+func (s *EscrowService) ListEscrows(ctx context.Context, walletID, limit int) ([]dto.EscrowResponse, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	escrows, err := s.repo.ListEscrowsByWalletID(ctx, walletID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escrows: %w", err)
+	}
+
+	resp := make([]dto.EscrowResponse, 0, len(escrows))
+	for i := range escrows {
+		resp = append(resp, *toEscrowResponse(&escrows[i], nil))
+	}
+	return resp, nil
+}
+
+// Release pays some or all of the held funds to the seller. Only the buyer
+// (or a service) may release.
+func (s *EscrowService) Release(ctx context.Context, escrowID int, req dto.EscrowReleaseRequest) (*dto.EscrowResponse, error) {
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("%w: amount cannot be negative", ErrInvalidRequest)
+	}
+	return s.settle(ctx, escrowID, func(escrow *models.Escrow) int { return escrow.BuyerWalletID }, func(ctx context.Context, escrow *models.Escrow) (*models.EscrowMovement, error) {
+		amount := req.Amount
+		if amount == 0 {
+			amount = escrow.Remaining()
+		}
+		if amount > escrow.Remaining() {
+			return nil, fmt.Errorf("%w: only %d is held", ErrInvalidRequest, escrow.Remaining())
+		}
+		return s.release(ctx, escrow, amount, req.Reference, req.Reason)
+	})
+}
+
+// Cancel refunds everything still held to the buyer. Only the seller (or a
+// service) may cancel, since it gives up the seller's claim.
+func (s *EscrowService) Cancel(ctx context.Context, escrowID int, req dto.EscrowCancelRequest) (*dto.EscrowResponse, error) {
+	return s.settle(ctx, escrowID, func(escrow *models.Escrow) int { return escrow.SellerWalletID }, func(ctx context.Context, escrow *models.Escrow) (*models.EscrowMovement, error) {
+		movement, err := s.post(ctx, escrow, models.EscrowMovementRefund, escrow.Remaining(), req.Reference, req.Reason)
+		if err != nil {
+			return nil, err
+		}
+		before := *escrow
+		escrow.RefundedAmount += movement.Amount
+		escrow.Status = models.EscrowRefunded
+		escrow.ReleaseAt = nil
+		escrow.UpdatedAt = s.now().UTC()
+		if err := s.repo.UpdateEscrow(ctx, escrow); err != nil {
+			return nil, fmt.Errorf("failed to update escrow: %w", err)
+		}
+		return movement, s.audit.Record(ctx, models.AuditEscrowRefunded, "escrow", escrow.ID, before, escrow)
+	})
+}
+
+// settle locks an open escrow the caller may act on as party and applies fn
+func (s *EscrowService) settle(ctx context.Context, escrowID int, party func(*models.Escrow) int,
+	fn func(ctx context.Context, escrow *models.Escrow) (*models.EscrowMovement, error)) (*dto.EscrowResponse, error) {
+	var escrow *models.Escrow
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if escrow, err = s.repo.GetEscrowByIDForUpdate(ctx, escrowID); err != nil {
+			return fmt.Errorf("failed to lock escrow: %w", err)
+		}
+		if escrow == nil {
+			return ErrEscrowNotFound
+		}
+		if err := s.authorize(ctx, party(escrow)); err != nil {
+			return err
+		}
+		if !escrow.IsOpen() {
+			return fmt.Errorf("%w: escrow is %s", ErrConflict, escrow.Status)
+		}
+		_, err = fn(ctx, escrow)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetEscrow(ctx, escrow.ID)
+}
+
+// release pays amount to the seller and updates the escrow's state
+func (s *EscrowService) release(ctx context.Context, escrow *models.Escrow, amount int64, reference int, reason string) (*models.EscrowMovement, error) {
+	movement, err := s.post(ctx, escrow, models.EscrowMovementRelease, amount, reference, reason)
+	if err != nil {
+		return nil, err
+	}
+	before := *escrow
+	escrow.ReleasedAmount += amount
+	escrow.ReleaseAttempts, escrow.ReleaseError = 0, ""
+	escrow.Status = models.EscrowPartiallyReleased
+	if escrow.Remaining() == 0 {
+		escrow.Status = models.EscrowReleased
+		escrow.ReleaseAt = nil
+	}
+	escrow.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateEscrow(ctx, escrow); err != nil {
+		return nil, fmt.Errorf("failed to update escrow: %w", err)
+	}
+	return movement, s.audit.Record(ctx, models.AuditEscrowReleased, "escrow", escrow.ID, before, escrow)
+}
+
+// ReleaseDue pays out escrows whose release time has passed; run by a
+// background worker
+func (s *EscrowService) ReleaseDue(ctx context.Context) error {
+	for i := 0; i < maxEscrowsPerTick; i++ {
+		var claimed bool
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := s.now().UTC()
+			escrow, err := s.repo.ClaimDueEscrow(ctx, now)
+			if err != nil {
+				return fmt.Errorf("failed to claim escrow: %w", err)
+			}
+			if escrow == nil {
+				return nil
+			}
+			claimed = true
+
+			_, err = s.release(ctx, escrow, escrow.Remaining(), 0, "release time reached")
+			if isRejection(err) {
+				// Refused before anything was written; record why and back off
+				// so the escrow does not hold up the ones due after it
+				escrow.ReleaseAttempts++
+				escrow.ReleaseError = err.Error()
+				retryAt := now.Add(escrowReleaseBackoff(escrow.ReleaseAttempts))
+				escrow.ReleaseAt = &retryAt
+				escrow.UpdatedAt = now
+				log.Printf("escrow %d: timed release attempt %d postponed to %s: %v", escrow.ID, escrow.ReleaseAttempts, retryAt.Format(time.RFC3339), err)
+				if err := s.repo.UpdateEscrow(ctx, escrow); err != nil {
+					return fmt.Errorf("failed to postpone escrow release: %w", err)
+				}
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// escrowReleaseBackoff returns how long to wait after the given number of
+// refused timed releases in a row
+func escrowReleaseBackoff(attempts int) time.Duration {
+	delay := escrowReleaseRetryDelay
+	for i := 1; i < attempts && delay < maxEscrowReleaseRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxEscrowReleaseRetryDelay)
+}
+
+// post moves amount into or out of the escrow account as one journal and
+// records the movement. Holds debit the buyer, releases credit the seller
+// and refunds credit the buyer.
+func (s *EscrowService) post(ctx context.Context, escrow *models.Escrow, movementType string, amount int64, reference int, reason string) (*models.EscrowMovement, error) {
+	account, err := s.wallets.systemWallet(ctx, models.SystemEscrow, escrow.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if reference == 0 {
+		reference = escrow.ID
+	}
+
+	var (
+		customer *models.LedgerEntry
+		guards   []journalGuard
+	)
+	switch movementType {
+	case models.EscrowMovementHold:
+		customer = models.NewLedgerEntry(escrow.BuyerWalletID, reference, "debit", amount, 0, fmt.Sprintf("Escrow hold for contract %s", escrow.ContractID))
+		guards = append(guards, requireFunds(escrow.BuyerWalletID, amount))
+	case models.EscrowMovementRelease:
+		customer = models.NewLedgerEntry(escrow.SellerWalletID, reference, "credit", amount, 0, fmt.Sprintf("Escrow release for contract %s", escrow.ContractID))
+	case models.EscrowMovementRefund:
+		customer = models.NewLedgerEntry(escrow.BuyerWalletID, reference, "credit", amount, 0, fmt.Sprintf("Escrow refund for contract %s", escrow.ContractID))
+	default:
+		return nil, fmt.Errorf("unknown escrow movement %q", movementType)
+	}
+
+	posted, err := s.wallets.postJournal(ctx, []*models.LedgerEntry{customer, counterLeg(customer, account.ID)}, guards...)
+	if err != nil {
+		return nil, err
+	}
+	movement := &models.EscrowMovement{
+		EscrowID:      escrow.ID,
+		Type:          movementType,
+		Amount:        amount,
+		JournalID:     posted.ID,
+		LedgerEntryID: customer.ID,
+		Reason:        reason,
+		CreatedAt:     s.now().UTC(),
+	}
+	movement.ActorType, movement.ActorID = actor(ctx)
+	if err := s.repo.CreateMovement(ctx, movement); err != nil {
+		return nil, fmt.Errorf("failed to record escrow movement: %w", err)
+	}
+	return movement, nil
+}
+
+// authorize checks the caller may act on at least one of the wallets
+func (s *EscrowService) authorize(ctx context.Context, walletIDs ...int) error {
+	if _, restricted := callerOwnerID(ctx); !restricted {
+		return nil
+	}
+	for _, walletID := range walletIDs {
+		_, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrForbidden) {
+			return err
+		}
+	}
+	return ErrForbidden
+}
+
+func toEscrowResponse(escrow *models.Escrow, movements []models.EscrowMovement) *dto.EscrowResponse {
+	return &dto.EscrowResponse{Escrow: *escrow, Remaining: escrow.Remaining(), Movements: movements}
+}
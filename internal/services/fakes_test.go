@@ -0,0 +1,784 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// fakeClock is a settable clock for the services' WithClock
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock(t time.Time) *fakeClock {
+	return &fakeClock{t: t}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// fakeTransactor runs fn without a transaction; the fakes apply writes at once
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeWebhookRepository keeps webhook endpoints and deliveries in memory
+type fakeWebhookRepository struct {
+	mu         sync.Mutex
+	endpoints  map[int]*models.WebhookEndpoint
+	deliveries map[int]*models.WebhookDelivery
+}
+
+func newFakeWebhookRepository() *fakeWebhookRepository {
+	return &fakeWebhookRepository{endpoints: map[int]*models.WebhookEndpoint{}, deliveries: map[int]*models.WebhookDelivery{}}
+}
+
+func (r *fakeWebhookRepository) CreateEndpoint(_ context.Context, endpoint *models.WebhookEndpoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	endpoint.ID = len(r.endpoints) + 1
+	copied := *endpoint
+	r.endpoints[endpoint.ID] = &copied
+	return nil
+}
+
+func (r *fakeWebhookRepository) GetEndpointByID(_ context.Context, id int) (*models.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	endpoint, ok := r.endpoints[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *endpoint
+	return &copied, nil
+}
+
+func (r *fakeWebhookRepository) ListEndpointsByMerchantID(_ context.Context, merchantID int) ([]models.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var endpoints []models.WebhookEndpoint
+	for _, endpoint := range r.endpoints {
+		if endpoint.MerchantID == merchantID && endpoint.Active {
+			endpoints = append(endpoints, *endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ID < endpoints[j].ID })
+	return endpoints, nil
+}
+
+func (r *fakeWebhookRepository) DeactivateEndpoint(_ context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if endpoint, ok := r.endpoints[id]; ok {
+		endpoint.Active = false
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepository) CreateDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.ID = len(r.deliveries) + 1
+	copied := *delivery
+	r.deliveries[delivery.ID] = &copied
+	return nil
+}
+
+func (r *fakeWebhookRepository) GetDeliveryByID(_ context.Context, id int) (*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *delivery
+	return &copied, nil
+}
+
+func (r *fakeWebhookRepository) ListDeliveries(_ context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if (filter.EndpointID == 0 || delivery.EndpointID == filter.EndpointID) && (filter.Status == "" || delivery.Status == filter.Status) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries leases due deliveries by pushing their next attempt
+// out to leaseUntil, as the PostgreSQL repository does
+func (r *fakeWebhookRepository) ClaimDueDeliveries(_ context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if (delivery.Status == models.WebhookDeliveryPending || delivery.Status == models.WebhookDeliveryFailed) && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	claimed := make([]models.WebhookDelivery, 0, len(due))
+	for _, delivery := range due {
+		delivery.NextAttemptAt = leaseUntil
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepository) UpdateDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *delivery
+	r.deliveries[delivery.ID] = &copied
+	return nil
+}
+
+// fakeWalletRepository keeps wallets and ledger entries in memory. Methods the
+// tests do not reach panic through the nil embedded interface.
+type fakeWalletRepository struct {
+	repositories.WalletRepository
+
+	mu      sync.Mutex
+	wallets map[int]*models.Wallet
+	entries []models.LedgerEntry
+	nextID  int
+}
+
+func newFakeWalletRepository() *fakeWalletRepository {
+	return &fakeWalletRepository{wallets: map[int]*models.Wallet{}}
+}
+
+// addWallet stores an active customer wallet with the given balance
+func (r *fakeWalletRepository) addWallet(userID int, currency string, balance int64) *models.Wallet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	wallet := &models.Wallet{
+		ID:        r.nextID,
+		UserID:    userID,
+		Currency:  currency,
+		AssetType: models.AssetFiat,
+		Balance:   balance,
+		Status:    models.WalletStatusActive,
+		Type:      models.WalletTypeStandard,
+	}
+	r.wallets[wallet.ID] = wallet
+	copied := *wallet
+	return &copied
+}
+
+// balance returns a wallet's current balance
+func (r *fakeWalletRepository) balance(walletID int) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.wallets[walletID].Balance
+}
+
+// systemBalance returns the balance of a system account, or 0 when it was never created
+func (r *fakeWalletRepository) systemBalance(code, currency string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, wallet := range r.wallets {
+		if wallet.IsSystem() && wallet.SystemCode == code && wallet.Currency == currency {
+			return wallet.Balance
+		}
+	}
+	return 0
+}
+
+func (r *fakeWalletRepository) GetWalletByID(_ context.Context, id int) (*models.Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wallet, ok := r.wallets[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *wallet
+	return &copied, nil
+}
+
+func (r *fakeWalletRepository) GetWalletByIDForUpdate(ctx context.Context, id int) (*models.Wallet, error) {
+	return r.GetWalletByID(ctx, id)
+}
+
+func (r *fakeWalletRepository) GetOrCreateSystemWallet(_ context.Context, code, currency, assetType string) (*models.Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, wallet := range r.wallets {
+		if wallet.IsSystem() && wallet.SystemCode == code && wallet.Currency == currency {
+			copied := *wallet
+			return &copied, nil
+		}
+	}
+	r.nextID++
+	wallet := &models.Wallet{
+		ID:         r.nextID,
+		Currency:   currency,
+		AssetType:  assetType,
+		Status:     models.WalletStatusActive,
+		Type:       models.WalletTypeSystem,
+		SystemCode: code,
+	}
+	r.wallets[wallet.ID] = wallet
+	copied := *wallet
+	return &copied, nil
+}
+
+func (r *fakeWalletRepository) UpdateWalletBalance(_ context.Context, walletID int, amount int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wallet, ok := r.wallets[walletID]
+	if !ok {
+		return fmt.Errorf("wallet %d not found", walletID)
+	}
+	wallet.Balance += amount
+	return nil
+}
+
+func (r *fakeWalletRepository) CreateLedgerEntry(_ context.Context, entry *models.LedgerEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = len(r.entries) + 1
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *fakeWalletRepository) GetLatestLedgerEntry(_ context.Context, walletID int) (*models.LedgerEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if r.entries[i].WalletID == walletID {
+			entry := r.entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeWalletRepository) GetLedgerEntryByID(_ context.Context, id int) (*models.LedgerEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id < 1 || id > len(r.entries) {
+		return nil, nil
+	}
+	entry := r.entries[id-1]
+	return &entry, nil
+}
+
+func (r *fakeWalletRepository) GetLedgerEntriesByJournalID(_ context.Context, journalID string) ([]models.LedgerEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var entries []models.LedgerEntry
+	for _, entry := range r.entries {
+		if entry.JournalID == journalID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeWalletRepository) GetReversalOf(_ context.Context, entryID int) (*models.LedgerEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.entries {
+		if entry.ReversalOf != nil && *entry.ReversalOf == entryID {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeTransferScheduleRepository keeps schedules and runs in memory and,
+// like the unique index on succeeded runs, refuses a second success for the
+// same occurrence
+type fakeTransferScheduleRepository struct {
+	mu        sync.Mutex
+	schedules map[int]*models.TransferSchedule
+	runs      []*models.TransferRun
+}
+
+func newFakeTransferScheduleRepository() *fakeTransferScheduleRepository {
+	return &fakeTransferScheduleRepository{schedules: map[int]*models.TransferSchedule{}}
+}
+
+func (r *fakeTransferScheduleRepository) CreateSchedule(_ context.Context, sched *models.TransferSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sched.ID = len(r.schedules) + 1
+	copied := *sched
+	r.schedules[sched.ID] = &copied
+	return nil
+}
+
+func (r *fakeTransferScheduleRepository) GetScheduleByID(_ context.Context, id int) (*models.TransferSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sched, ok := r.schedules[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *sched
+	return &copied, nil
+}
+
+func (r *fakeTransferScheduleRepository) GetScheduleByIDForUpdate(ctx context.Context, id int) (*models.TransferSchedule, error) {
+	return r.GetScheduleByID(ctx, id)
+}
+
+func (r *fakeTransferScheduleRepository) ListSchedulesByWalletID(_ context.Context, walletID int) ([]models.TransferSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var schedules []models.TransferSchedule
+	for _, sched := range r.schedules {
+		if sched.FromWalletID == walletID || sched.ToWalletID == walletID {
+			schedules = append(schedules, *sched)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID > schedules[j].ID })
+	return schedules, nil
+}
+
+func (r *fakeTransferScheduleRepository) ClaimDueSchedule(_ context.Context, now time.Time) (*models.TransferSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due *models.TransferSchedule
+	for _, sched := range r.schedules {
+		if sched.Status != models.TransferScheduleActive || sched.NextAttemptAt == nil || sched.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || sched.ID < due.ID {
+			due = sched
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	copied := *due
+	return &copied, nil
+}
+
+func (r *fakeTransferScheduleRepository) UpdateSchedule(_ context.Context, sched *models.TransferSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *sched
+	r.schedules[sched.ID] = &copied
+	return nil
+}
+
+func (r *fakeTransferScheduleRepository) CreateRun(_ context.Context, run *models.TransferRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run.ID = len(r.runs) + 1
+	copied := *run
+	r.runs = append(r.runs, &copied)
+	return nil
+}
+
+func (r *fakeTransferScheduleRepository) UpdateRun(_ context.Context, run *models.TransferRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if run.Status == models.TransferRunSucceeded {
+		for _, other := range r.runs {
+			if other.ID != run.ID && other.ScheduleID == run.ScheduleID && other.OccurrenceAt.Equal(run.OccurrenceAt) && other.Status == models.TransferRunSucceeded {
+				return fmt.Errorf("occurrence %s of schedule %d already succeeded", run.OccurrenceAt, run.ScheduleID)
+			}
+		}
+	}
+	copied := *run
+	r.runs[run.ID-1] = &copied
+	return nil
+}
+
+func (r *fakeTransferScheduleRepository) ListRuns(_ context.Context, scheduleID, limit int) ([]models.TransferRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var runs []models.TransferRun
+	for i := len(r.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if r.runs[i].ScheduleID == scheduleID {
+			runs = append(runs, *r.runs[i])
+		}
+	}
+	return runs, nil
+}
+
+// fakePayoutRepository keeps payouts in memory
+type fakePayoutRepository struct {
+	mu      sync.Mutex
+	payouts map[int]*models.Payout
+}
+
+func newFakePayoutRepository() *fakePayoutRepository {
+	return &fakePayoutRepository{payouts: map[int]*models.Payout{}}
+}
+
+func (r *fakePayoutRepository) CreatePayout(_ context.Context, payout *models.Payout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payout.ID = len(r.payouts) + 1
+	copied := *payout
+	r.payouts[payout.ID] = &copied
+	return nil
+}
+
+// find returns a copy of the first payout matching match, or nil
+func (r *fakePayoutRepository) find(match func(*models.Payout) bool) *models.Payout {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := 1; id <= len(r.payouts); id++ {
+		if payout := r.payouts[id]; match(payout) {
+			copied := *payout
+			return &copied
+		}
+	}
+	return nil
+}
+
+func (r *fakePayoutRepository) GetPayoutByID(_ context.Context, id int) (*models.Payout, error) {
+	return r.find(func(p *models.Payout) bool { return p.ID == id }), nil
+}
+
+func (r *fakePayoutRepository) GetPayoutByIDForUpdate(ctx context.Context, id int) (*models.Payout, error) {
+	return r.GetPayoutByID(ctx, id)
+}
+
+func (r *fakePayoutRepository) GetPayoutByReference(_ context.Context, walletID, reference int) (*models.Payout, error) {
+	return r.find(func(p *models.Payout) bool { return p.WalletID == walletID && p.Reference == reference }), nil
+}
+
+func (r *fakePayoutRepository) GetPayoutByProviderReferenceForUpdate(_ context.Context, provider, providerReference string) (*models.Payout, error) {
+	return r.find(func(p *models.Payout) bool { return p.Provider == provider && p.ProviderReference == providerReference }), nil
+}
+
+func (r *fakePayoutRepository) ListPayoutsByWalletID(_ context.Context, walletID int, status string, limit int) ([]models.Payout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var payouts []models.Payout
+	for id := len(r.payouts); id >= 1 && len(payouts) < limit; id-- {
+		if payout := r.payouts[id]; payout.WalletID == walletID && (status == "" || payout.Status == status) {
+			payouts = append(payouts, *payout)
+		}
+	}
+	return payouts, nil
+}
+
+func (r *fakePayoutRepository) ClaimDuePayout(_ context.Context, now time.Time) (*models.Payout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due *models.Payout
+	for _, payout := range r.payouts {
+		switch payout.Status {
+		case models.PayoutRequested, models.PayoutSubmitting, models.PayoutReleasePending:
+		default:
+			continue
+		}
+		if payout.NextAttemptAt == nil || payout.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || payout.NextAttemptAt.Before(*due.NextAttemptAt) {
+			due = payout
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	copied := *due
+	return &copied, nil
+}
+
+func (r *fakePayoutRepository) UpdatePayout(_ context.Context, payout *models.Payout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *payout
+	r.payouts[payout.ID] = &copied
+	return nil
+}
+
+// fakeLimitRepository keeps limit overrides in memory and reports the usage
+// set for each period start
+type fakeLimitRepository struct {
+	mu        sync.Mutex
+	overrides map[int]*models.WalletLimitOverride
+	usage     map[time.Time]models.LimitUsage
+}
+
+func newFakeLimitRepository() *fakeLimitRepository {
+	return &fakeLimitRepository{overrides: map[int]*models.WalletLimitOverride{}, usage: map[time.Time]models.LimitUsage{}}
+}
+
+func (r *fakeLimitRepository) GetOverride(_ context.Context, walletID int) (*models.WalletLimitOverride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	override, ok := r.overrides[walletID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *override
+	return &copied, nil
+}
+
+func (r *fakeLimitRepository) UpsertOverride(_ context.Context, override *models.WalletLimitOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *override
+	r.overrides[override.WalletID] = &copied
+	return nil
+}
+
+func (r *fakeLimitRepository) DeleteOverride(_ context.Context, walletID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.overrides, walletID)
+	return nil
+}
+
+func (r *fakeLimitRepository) Usage(_ context.Context, _ int, since time.Time) (*models.LimitUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := r.usage[since]
+	return &usage, nil
+}
+
+// fakeRiskRepository reports fixed activity and round entry counts to the
+// risk rules; the case methods are not implemented
+type fakeRiskRepository struct {
+	repositories.RiskRepository
+
+	activity   models.RiskActivity
+	roundCount int64
+	since      time.Time // Start of the last window asked about
+}
+
+func (r *fakeRiskRepository) Activity(_ context.Context, _ int, since time.Time) (*models.RiskActivity, error) {
+	r.since = since
+	activity := r.activity
+	return &activity, nil
+}
+
+func (r *fakeRiskRepository) CountRoundEntries(_ context.Context, _ int, _ string, since time.Time, _, _ int64) (int64, error) {
+	r.since = since
+	return r.roundCount, nil
+}
+
+// fakeCreditLotRepository keeps credit lots and their movements in memory
+type fakeCreditLotRepository struct {
+	mu        sync.Mutex
+	lots      []*models.CreditLot
+	movements []models.CreditLotMovement
+}
+
+func (r *fakeCreditLotRepository) CreateLot(_ context.Context, lot *models.CreditLot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lot.ID = len(r.lots) + 1
+	copied := *lot
+	r.lots = append(r.lots, &copied)
+	return nil
+}
+
+func (r *fakeCreditLotRepository) GetLotByID(_ context.Context, id int) (*models.CreditLot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id < 1 || id > len(r.lots) {
+		return nil, nil
+	}
+	copied := *r.lots[id-1]
+	return &copied, nil
+}
+
+func (r *fakeCreditLotRepository) ListLots(_ context.Context, walletID int, status string, limit int) ([]models.CreditLot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var lots []models.CreditLot
+	for i := len(r.lots) - 1; i >= 0 && len(lots) < limit; i-- {
+		if lot := r.lots[i]; lot.WalletID == walletID && (status == "" || lot.Status == status) {
+			lots = append(lots, *lot)
+		}
+	}
+	return lots, nil
+}
+
+func (r *fakeCreditLotRepository) ListOpenLotsForUpdate(_ context.Context, walletID int) ([]*models.CreditLot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var lots []*models.CreditLot
+	for _, lot := range r.lots {
+		if lot.WalletID == walletID && lot.Status == models.CreditLotOpen {
+			copied := *lot
+			lots = append(lots, &copied)
+		}
+	}
+	return lots, nil
+}
+
+func (r *fakeCreditLotRepository) OpenLotTotals(_ context.Context, walletID int) (map[string]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	totals := map[string]int64{}
+	for _, lot := range r.lots {
+		if lot.WalletID == walletID && lot.Status == models.CreditLotOpen {
+			totals[lot.Type] += lot.Remaining
+		}
+	}
+	return totals, nil
+}
+
+func (r *fakeCreditLotRepository) ClaimExpiredLot(_ context.Context, now time.Time) (*models.CreditLot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, lot := range r.lots {
+		if lot.Status != models.CreditLotOpen || !lot.Expired(now) || lot.ExpiryRetryAt != nil && lot.ExpiryRetryAt.After(now) {
+			continue
+		}
+		copied := *lot
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeCreditLotRepository) UpdateLot(_ context.Context, lot *models.CreditLot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *lot
+	r.lots[lot.ID-1] = &copied
+	return nil
+}
+
+func (r *fakeCreditLotRepository) CreateMovement(_ context.Context, movement *models.CreditLotMovement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	movement.ID = len(r.movements) + 1
+	r.movements = append(r.movements, *movement)
+	return nil
+}
+
+func (r *fakeCreditLotRepository) ListMovements(_ context.Context, lotID int) ([]models.CreditLotMovement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var movements []models.CreditLotMovement
+	for _, movement := range r.movements {
+		if movement.LotID == lotID {
+			movements = append(movements, movement)
+		}
+	}
+	return movements, nil
+}
+
+// fakeInterestRepository holds one wallet's interest account and reports a
+// fixed end-of-day balance for every day
+type fakeInterestRepository struct {
+	mu              sync.Mutex
+	account         *models.InterestAccount
+	balance         int64
+	accruals        []models.InterestAccrual
+	capitalisations []models.InterestCapitalisation
+}
+
+func (r *fakeInterestRepository) GetAccount(_ context.Context, walletID int) (*models.InterestAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.account == nil || r.account.WalletID != walletID {
+		return nil, nil
+	}
+	copied := *r.account
+	return &copied, nil
+}
+
+func (r *fakeInterestRepository) CreateAccount(_ context.Context, account *models.InterestAccount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *account
+	r.account = &copied
+	return nil
+}
+
+func (r *fakeInterestRepository) UpdateAccount(ctx context.Context, account *models.InterestAccount) error {
+	return r.CreateAccount(ctx, account)
+}
+
+func (r *fakeInterestRepository) ClaimDueAccount(_ context.Context, through time.Time, plans []string) (*models.InterestAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.account == nil || !r.account.AccruedThrough.Before(through) {
+		return nil, nil
+	}
+	for _, plan := range plans {
+		if plan == r.account.Plan {
+			copied := *r.account
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeInterestRepository) EndOfDayBalance(context.Context, int, time.Time) (int64, error) {
+	return r.balance, nil
+}
+
+func (r *fakeInterestRepository) CreateAccrual(_ context.Context, accrual *models.InterestAccrual) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accrual.ID = len(r.accruals) + 1
+	r.accruals = append(r.accruals, *accrual)
+	return nil
+}
+
+func (r *fakeInterestRepository) ListAccruals(_ context.Context, walletID int, from, to time.Time, limit int) ([]models.InterestAccrual, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var accruals []models.InterestAccrual
+	for i := len(r.accruals) - 1; i >= 0 && len(accruals) < limit; i-- {
+		accrual := r.accruals[i]
+		if accrual.WalletID == walletID && (from.IsZero() || !accrual.AccrualDate.Before(from)) && (to.IsZero() || !accrual.AccrualDate.After(to)) {
+			accruals = append(accruals, accrual)
+		}
+	}
+	return accruals, nil
+}
+
+func (r *fakeInterestRepository) CreateCapitalisation(_ context.Context, capitalisation *models.InterestCapitalisation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	capitalisation.ID = len(r.capitalisations) + 1
+	r.capitalisations = append(r.capitalisations, *capitalisation)
+	return nil
+}
+
+func (r *fakeInterestRepository) ListCapitalisations(_ context.Context, walletID, limit int) ([]models.InterestCapitalisation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var capitalisations []models.InterestCapitalisation
+	for i := len(r.capitalisations) - 1; i >= 0 && len(capitalisations) < limit; i-- {
+		if r.capitalisations[i].WalletID == walletID {
+			capitalisations = append(capitalisations, r.capitalisations[i])
+		}
+	}
+	return capitalisations, nil
+}
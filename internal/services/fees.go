@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// Transaction types fee rules can match on
+const (
+	FeeTxnCredit   = "credit"   // Money paid into a wallet through UpdateWalletBalance
+	FeeTxnDebit    = "debit"    // Money paid out of a wallet through UpdateWalletBalance
+	FeeTxnTransfer = "transfer" // Wallet to wallet transfer, charged to the sender
+)
+
+const (
+	// maxFeeBPS is a 100% fee
+	maxFeeBPS = 10000
+
+	// maxFeeAmount is the largest amount a fee can be computed on, and the
+	// largest fixed fee, without amount*bps overflowing
+	maxFeeAmount = math.MaxInt64 / maxFeeBPS
+)
+
+// FeeSchedule is an ordered set of fee rules. A transaction is charged by the
+// most specific matching rule; ties go to the rule listed first. Transactions
+// no rule matches are free.
+type FeeSchedule struct {
+	Rules []FeeRule `json:"rules"`
+}
+
+// FeeRule prices one class of transaction. Empty match fields match anything;
+// Segment matches the customer segment operators assign wallets to.
+// Amounts are in the currency's smallest unit and percentages in basis points
+// (150 = 1.5%). When Tiers is set, the tier covering the amount replaces
+// Flat and PercentageBPS. The result is then held between Min and Max.
+type FeeRule struct {
+	Name            string    `json:"name"`
+	Segment         string    `json:"segment"`
+	Currency        string    `json:"currency"`
+	TransactionType string    `json:"transaction_type"`
+	Flat            int64     `json:"flat"`
+	PercentageBPS   int64     `json:"percentage_bps"`
+	Tiers           []FeeTier `json:"tiers"`
+	Min             int64     `json:"min"`
+	Max             int64     `json:"max"` // 0 means no cap
+}
+
+// FeeTier prices amounts up to and including UpTo; 0 means no upper bound
+type FeeTier struct {
+	UpTo          int64 `json:"up_to"`
+	Flat          int64 `json:"flat"`
+	PercentageBPS int64 `json:"percentage_bps"`
+}
+
+// LoadFeeSchedule reads a fee schedule from a JSON file
+func LoadFeeSchedule(path string) (*FeeSchedule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFeeSchedule(raw)
+}
+
+// ParseFeeSchedule decodes and validates a JSON fee schedule
+func ParseFeeSchedule(raw []byte) (*FeeSchedule, error) {
+	var schedule FeeSchedule
+	if err := json.Unmarshal(raw, &schedule); err != nil {
+		return nil, fmt.Errorf("invalid fee schedule: %w", err)
+	}
+	for i, rule := range schedule.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("fee rule %d (%s): %w", i, rule.Name, err)
+		}
+	}
+	return &schedule, nil
+}
+
+func (r FeeRule) validate() error {
+	switch r.TransactionType {
+	case "", FeeTxnCredit, FeeTxnDebit, FeeTxnTransfer:
+	default:
+		return fmt.Errorf("unknown transaction type %q", r.TransactionType)
+	}
+	if r.Flat < 0 || r.PercentageBPS < 0 || r.Min < 0 || r.Max < 0 {
+		return errors.New("amounts and percentages cannot be negative")
+	}
+	if r.PercentageBPS > maxFeeBPS {
+		return fmt.Errorf("percentage cannot exceed %d bps", maxFeeBPS)
+	}
+	if r.Flat > maxFeeAmount || r.Min > maxFeeAmount || r.Max > maxFeeAmount {
+		return fmt.Errorf("amounts cannot exceed %d", int64(maxFeeAmount))
+	}
+	if r.Max > 0 && r.Max < r.Min {
+		return errors.New("max is below min")
+	}
+	for i, tier := range r.Tiers {
+		if tier.Flat < 0 || tier.PercentageBPS < 0 {
+			return errors.New("tier amounts and percentages cannot be negative")
+		}
+		if tier.PercentageBPS > maxFeeBPS {
+			return fmt.Errorf("tier percentage cannot exceed %d bps", maxFeeBPS)
+		}
+		if tier.Flat > maxFeeAmount {
+			return fmt.Errorf("tier amounts cannot exceed %d", int64(maxFeeAmount))
+		}
+		last := i == len(r.Tiers)-1
+		if tier.UpTo == 0 && !last {
+			return errors.New("only the last tier may be unbounded")
+		}
+		if i > 0 && tier.UpTo != 0 && tier.UpTo <= r.Tiers[i-1].UpTo {
+			return errors.New("tiers must be in ascending up_to order")
+		}
+	}
+	return nil
+}
+
+// Quote prices a transaction. It returns nil when no rule applies or the fee
+// comes to zero. A nil schedule charges nothing.
+func (s *FeeSchedule) Quote(segment, currency, txnType string, amount int64) (*dto.FeeBreakdown, error) {
+	if s == nil {
+		return nil, nil
+	}
+	rule := s.match(segment, currency, txnType)
+	if rule == nil {
+		return nil, nil
+	}
+	if amount > maxFeeAmount {
+		return nil, fmt.Errorf("%w: amount exceeds %d, the most a fee can be charged on", ErrInvalidRequest, int64(maxFeeAmount))
+	}
+
+	fee := &dto.FeeBreakdown{Rule: rule.Name, Flat: rule.Flat}
+	bps := rule.PercentageBPS
+	for i, tier := range rule.Tiers {
+		if tier.UpTo == 0 || amount <= tier.UpTo {
+			fee.Tier = &rule.Tiers[i].UpTo
+			fee.Flat, bps = tier.Flat, tier.PercentageBPS
+			break
+		}
+	}
+	// Round the percentage half up to the smallest unit
+	fee.Percentage = (amount*bps + 5000) / 10000
+	fee.Total = fee.Flat + fee.Percentage
+
+	switch {
+	case fee.Total < rule.Min:
+		fee.Total, fee.Limit = rule.Min, "min"
+	case rule.Max > 0 && fee.Total > rule.Max:
+		fee.Total, fee.Limit = rule.Max, "max"
+	}
+	if fee.Total == 0 {
+		return nil, nil
+	}
+	return fee, nil
+}
+
+// match returns the most specific rule for the transaction
+func (s *FeeSchedule) match(segment, currency, txnType string) *FeeRule {
+	var best *FeeRule
+	bestScore := -1
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		score := 0
+		for _, field := range []struct{ want, got string }{
+			{rule.Segment, segment},
+			{rule.Currency, currency},
+			{rule.TransactionType, txnType},
+		} {
+			if field.want == "" {
+				continue
+			}
+			if field.want != field.got {
+				score = -1
+				break
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// quoteFee prices a posting from wallet, including the taxes on the fee. It
+// returns nil when the posting is free. A credit is never charged more than
+// was paid in.
+func (s *WalletService) quoteFee(wallet *models.Wallet, txnType string, amount int64) (*dto.FeeBreakdown, error) {
+	fee, err := s.fees.Quote(wallet.Segment, wallet.Currency, txnType, amount)
+	if fee == nil || err != nil {
+		return nil, err
+	}
+	if err := s.taxes.Apply(fee, wallet.Currency); err != nil {
+		return nil, err
+	}
+	if txnType == FeeTxnCredit && fee.Charged > amount {
+		// The charge grows with the fee, so bisect for the largest fee whose
+		// charge fits in the amount: a zero fee always fits, the quoted one
+		// does not. Smaller fees than the quoted one always tax cleanly.
+		fits, over := int64(0), fee.Total
+		for over-fits > 1 {
+			fee.Total = fits + (over-fits)/2
+			_ = s.taxes.Apply(fee, wallet.Currency)
+			if fee.Charged <= amount {
+				fits = fee.Total
+			} else {
+				over = fee.Total
+			}
+		}
+		if fits == 0 {
+			return nil, nil
+		}
+		fee.Total, fee.Limit = fits, "amount"
+		return fee, s.taxes.Apply(fee, wallet.Currency)
+	}
+	return fee, nil
+}
+
+// SetSegment moves a wallet to another customer segment, changing the fee
+// rules that match it
+func (s *WalletService) SetSegment(ctx context.Context, walletID int, req dto.SetSegmentRequest) (*dto.WalletResponse, error) {
+	segment := strings.TrimSpace(req.Segment)
+	if len(segment) > 30 {
+		return nil, fmt.Errorf("%w: segment must be at most 30 characters", ErrInvalidRequest)
+	}
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts have no segment", ErrInvalidRequest)
+	}
+
+	var updated *models.Wallet
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateWalletSegment(ctx, walletID, segment); err != nil {
+			return fmt.Errorf("failed to update segment: %w", err)
+		}
+		if updated, err = s.repo.GetWalletByID(ctx, walletID); err != nil {
+			return fmt.Errorf("failed to get updated wallet: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditWalletSegment, "wallet", walletID, wallet, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toWalletResponse(updated), nil
+}
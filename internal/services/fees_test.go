@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+func TestParseFeeSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{name: "flat and percentage", rule: `{"flat":30,"percentage_bps":150,"min":50,"max":1000}`},
+		{name: "full percentage", rule: `{"percentage_bps":10000}`},
+		{name: "percentage over 100%", rule: `{"percentage_bps":10001}`, wantErr: true},
+		{name: "tier percentage over 100%", rule: `{"tiers":[{"percentage_bps":20000}]}`, wantErr: true},
+		{name: "negative flat", rule: `{"flat":-1}`, wantErr: true},
+		{name: "max below min", rule: `{"min":100,"max":50}`, wantErr: true},
+		{name: "flat too large", rule: `{"flat":922337203685478}`, wantErr: true},
+		{name: "unknown transaction type", rule: `{"transaction_type":"refund"}`, wantErr: true},
+		{name: "unbounded tier not last", rule: `{"tiers":[{"flat":10},{"up_to":100,"flat":20}]}`, wantErr: true},
+		{name: "tiers out of order", rule: `{"tiers":[{"up_to":100},{"up_to":50},{}]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFeeSchedule([]byte(`{"rules":[` + tt.rule + `]}`))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeeScheduleQuote(t *testing.T) {
+	schedule, err := ParseFeeSchedule([]byte(`{"rules":[
+		{"name":"default","flat":25},
+		{"name":"gbp","currency":"GBP","percentage_bps":100,"min":50,"max":500},
+		{"name":"gbp transfers","currency":"GBP","transaction_type":"transfer","flat":10,"percentage_bps":150},
+		{"name":"gbp transfers again","currency":"GBP","transaction_type":"transfer","flat":99},
+		{"name":"business","segment":"business","currency":"GBP","transaction_type":"transfer","tiers":[
+			{"up_to":10000,"flat":20},
+			{"up_to":100000,"percentage_bps":50},
+			{"flat":100,"percentage_bps":25}
+		]},
+		{"name":"free eur credits","currency":"EUR","transaction_type":"credit"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		segment  string
+		currency string
+		txnType  string
+		amount   int64
+
+		wantRule  string // Empty when the posting is free
+		wantTotal int64
+		wantLimit string
+		wantTier  int64 // -1 when no tier applies
+	}{
+		{name: "catch-all", currency: "USD", txnType: FeeTxnDebit, amount: 10000, wantRule: "default", wantTotal: 25, wantTier: -1},
+		{name: "currency beats catch-all", currency: "GBP", txnType: FeeTxnDebit, amount: 20000, wantRule: "gbp", wantTotal: 200, wantTier: -1},
+		{name: "clamped to min", currency: "GBP", txnType: FeeTxnDebit, amount: 1000, wantRule: "gbp", wantTotal: 50, wantLimit: "min", wantTier: -1},
+		{name: "clamped to max", currency: "GBP", txnType: FeeTxnCredit, amount: 100000, wantRule: "gbp", wantTotal: 500, wantLimit: "max", wantTier: -1},
+		{name: "most specific wins, ties to the first listed", currency: "GBP", txnType: FeeTxnTransfer, amount: 10000, wantRule: "gbp transfers", wantTotal: 160, wantTier: -1},
+		{name: "percentage rounds to nearest", currency: "GBP", txnType: FeeTxnTransfer, amount: 1033, wantRule: "gbp transfers", wantTotal: 25, wantTier: -1}, // 15.495 -> 15
+		{name: "percentage rounds half up", currency: "GBP", txnType: FeeTxnTransfer, amount: 1100, wantRule: "gbp transfers", wantTotal: 27, wantTier: -1},    // 16.5 -> 17
+		{name: "segment tier bound is inclusive", segment: "business", currency: "GBP", txnType: FeeTxnTransfer, amount: 10000, wantRule: "business", wantTotal: 20, wantTier: 10000},
+		{name: "segment middle tier", segment: "business", currency: "GBP", txnType: FeeTxnTransfer, amount: 10001, wantRule: "business", wantTotal: 50, wantTier: 100000},
+		{name: "segment open tier", segment: "business", currency: "GBP", txnType: FeeTxnTransfer, amount: 200000, wantRule: "business", wantTotal: 600, wantTier: 0},
+		{name: "other segment falls back", segment: "retail", currency: "GBP", txnType: FeeTxnTransfer, amount: 10000, wantRule: "gbp transfers", wantTotal: 160, wantTier: -1},
+		{name: "zero fee is free", currency: "EUR", txnType: FeeTxnCredit, amount: 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, err := schedule.Quote(tt.segment, tt.currency, tt.txnType, tt.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantRule == "" {
+				if fee != nil {
+					t.Errorf("fee = %+v, want none", fee)
+				}
+				return
+			}
+			if fee == nil {
+				t.Fatalf("fee = nil, want %s", tt.wantRule)
+			}
+			if fee.Rule != tt.wantRule || fee.Total != tt.wantTotal || fee.Limit != tt.wantLimit {
+				t.Errorf("fee = %s %d limited by %q, want %s %d limited by %q", fee.Rule, fee.Total, fee.Limit, tt.wantRule, tt.wantTotal, tt.wantLimit)
+			}
+			tier := int64(-1)
+			if fee.Tier != nil {
+				tier = *fee.Tier
+			}
+			if tier != tt.wantTier {
+				t.Errorf("tier = %d, want %d", tier, tt.wantTier)
+			}
+		})
+	}
+}
+
+func TestFeeScheduleQuoteRejectsOverflowingAmount(t *testing.T) {
+	schedule := &FeeSchedule{Rules: []FeeRule{{Name: "all", PercentageBPS: maxFeeBPS}}}
+	if fee, err := schedule.Quote("", "GBP", FeeTxnTransfer, maxFeeAmount); err != nil || fee.Total != maxFeeAmount {
+		t.Errorf("Quote(max) = %+v, %v, want the whole amount", fee, err)
+	}
+	if _, err := schedule.Quote("", "GBP", FeeTxnTransfer, maxFeeAmount+1); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Quote(max+1): err = %v, want ErrInvalidRequest", err)
+	}
+	if fee, err := (*FeeSchedule)(nil).Quote("", "GBP", FeeTxnTransfer, maxFeeAmount+1); fee != nil || err != nil {
+		t.Errorf("nil schedule quoted %+v, %v, want nothing", fee, err)
+	}
+}
+
+func TestQuoteFeeCapsCreditAtAmount(t *testing.T) {
+	schedule := &FeeSchedule{Rules: []FeeRule{{Name: "credits", TransactionType: FeeTxnCredit, Flat: 75}}}
+	svc := NewWalletService(nil, nil, WithFeeSchedule(schedule))
+	wallet := &models.Wallet{Currency: "GBP"}
+
+	fee, err := svc.quoteFee(wallet, FeeTxnCredit, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.Total != 40 || fee.Charged != 40 || fee.Limit != "amount" {
+		t.Errorf("fee = %d charged %d limited by %q, want 40 limited by the amount", fee.Total, fee.Charged, fee.Limit)
+	}
+	// Debits are not capped; the wallet pays the fee on top
+	if fee, _ := svc.quoteFee(wallet, FeeTxnDebit, 40); fee != nil {
+		t.Errorf("debit fee = %+v, want none from a credits-only rule", fee)
+	}
+}
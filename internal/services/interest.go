@@ -0,0 +1,199 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+// Interest methods
+const (
+	// InterestSimple accrues interest on the end-of-day balance alone
+	InterestSimple = "simple"
+	// InterestCompound compounds daily: interest also accrues on interest
+	// accrued but not yet capitalised
+	InterestCompound = "compound"
+)
+
+// Day-count conventions, which set the fraction of a year one day is
+const (
+	DayCountACT365 = "ACT/365" // Every day is 1/365 of a year, leap years included
+	DayCount30360  = "30/360"  // Every month is 30 days of a 360-day year (US bond basis)
+)
+
+// interestScale is the number of decimal places of a minor unit that
+// accrued interest is kept to
+const interestScale = 12
+
+// InterestConfig lists the interest rate plans savings wallets can be
+// enrolled in. It is loaded from JSON, e.g.
+//
+//	{
+//	  "plans": {
+//	    "easy-saver": {
+//	      "currency": "GBP",
+//	      "method": "compound",
+//	      "day_count": "ACT/365",
+//	      "tiers": [{"from": 0, "rate": "0.015"}, {"from": 1000000, "rate": "0.025"}]
+//	    }
+//	  }
+//	}
+//
+// which pays 1.5% a year on the first £10,000 and 2.5% on the rest.
+type InterestConfig struct {
+	Plans map[string]*InterestPlan `json:"plans"`
+}
+
+// InterestPlan sets how a savings wallet earns interest
+type InterestPlan struct {
+	Currency string         `json:"currency"` // Restricts the plan to one currency; empty allows any
+	Method   string         `json:"method"`   // "simple" or "compound"
+	DayCount string         `json:"day_count"`
+	Tiers    []InterestTier `json:"tiers"` // Balance bands, lowest first; the first starts at 0
+}
+
+// InterestTier pays Rate a year on the part of the balance from From up to
+// the next tier's From
+type InterestTier struct {
+	From int64  `json:"from"`
+	Rate string `json:"rate"` // Decimal annual rate, e.g. "0.0425" for 4.25%
+
+	rate *big.Rat
+}
+
+// LoadInterestConfig reads interest rate plans from a JSON file
+func LoadInterestConfig(path string) (*InterestConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseInterestConfig(raw)
+}
+
+// ParseInterestConfig decodes and validates JSON interest rate plans
+func ParseInterestConfig(raw []byte) (*InterestConfig, error) {
+	var cfg InterestConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid interest config: %w", err)
+	}
+	for name, plan := range cfg.Plans {
+		if name == "" || len(name) > 50 {
+			return nil, errors.New("plan names must be 1 to 50 characters")
+		}
+		if plan == nil {
+			return nil, fmt.Errorf("plan %s: missing definition", name)
+		}
+		if err := plan.validate(); err != nil {
+			return nil, fmt.Errorf("plan %s: %w", name, err)
+		}
+	}
+	return &cfg, nil
+}
+
+func (p *InterestPlan) validate() error {
+	if p.Method != InterestSimple && p.Method != InterestCompound {
+		return fmt.Errorf("method must be %q or %q", InterestSimple, InterestCompound)
+	}
+	if p.DayCount != DayCountACT365 && p.DayCount != DayCount30360 {
+		return fmt.Errorf("day_count must be %q or %q", DayCountACT365, DayCount30360)
+	}
+	if len(p.Tiers) == 0 || p.Tiers[0].From != 0 {
+		return errors.New("tiers must start with a tier from 0")
+	}
+	for i := range p.Tiers {
+		tier := &p.Tiers[i]
+		if i > 0 && tier.From <= p.Tiers[i-1].From {
+			return errors.New("tiers must be in increasing order of from")
+		}
+		rate, ok := new(big.Rat).SetString(tier.Rate)
+		if !ok || rate.Sign() < 0 {
+			return fmt.Errorf("tier from %d: rate must be a non-negative decimal", tier.From)
+		}
+		tier.rate = rate
+	}
+	return nil
+}
+
+// plan returns the named plan, or nil when it is not configured
+func (c *InterestConfig) plan(name string) *InterestPlan {
+	if c == nil {
+		return nil
+	}
+	return c.Plans[name]
+}
+
+// planNames returns the names of the configured plans
+func (c *InterestConfig) planNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.Plans))
+	for name := range c.Plans {
+		names = append(names, name)
+	}
+	return names
+}
+
+// dailyInterest returns the interest base earns over day, in minor units,
+// with each tier's rate applied to its band of base
+func (p *InterestPlan) dailyInterest(base *big.Rat, day time.Time) *big.Rat {
+	interest := new(big.Rat)
+	for i, tier := range p.Tiers {
+		lower := new(big.Rat).SetInt64(tier.From)
+		if base.Cmp(lower) <= 0 {
+			break
+		}
+		band := new(big.Rat).Sub(base, lower)
+		if i+1 < len(p.Tiers) {
+			if upper := new(big.Rat).SetInt64(p.Tiers[i+1].From); base.Cmp(upper) > 0 {
+				band.Sub(upper, lower)
+			}
+		}
+		interest.Add(interest, band.Mul(band, tier.rate))
+	}
+	return interest.Mul(interest, dayFraction(p.DayCount, day))
+}
+
+// dayFraction returns the fraction of a year day counts as
+func dayFraction(convention string, day time.Time) *big.Rat {
+	if convention == DayCount30360 {
+		return big.NewRat(days30360(day, day.AddDate(0, 0, 1)), 360)
+	}
+	return big.NewRat(1, 365)
+}
+
+// days30360 counts the days from start to end under US 30/360, where the
+// 31st counts as the 30th. Summed over a month's days it always gives 30.
+func days30360(start, end time.Time) int64 {
+	d1, d2 := start.Day(), end.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return int64(360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1)
+}
+
+// parseInterest reads an amount of interest stored as a decimal string
+func parseInterest(s string) (*big.Rat, error) {
+	amount, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid interest amount %q", s)
+	}
+	return amount, nil
+}
+
+// roundInterest rounds an amount of interest to the precision it is stored in
+func roundInterest(amount *big.Rat) *big.Rat {
+	rounded, _ := new(big.Rat).SetString(amount.FloatString(interestScale))
+	return rounded
+}
+
+// formatInterest formats an amount of interest for storage
+func formatInterest(amount *big.Rat) string {
+	return amount.FloatString(interestScale)
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+const (
+	// maxInterestDaysPerTick bounds the work one worker tick does
+	maxInterestDaysPerTick = 500
+	// interestCapitalisationsShown is how many capitalisations an interest
+	// account response lists
+	interestCapitalisationsShown = 12
+)
+
+// InterestService accrues interest on savings wallets and capitalises it.
+// Each day's interest is worked out on the wallet's end-of-day balance in
+// UTC, once the day is over, and kept to a fraction of a minor unit. At the
+// end of each month the whole minor units accrued are credited to the
+// wallet from the interest expense account; the fraction is carried forward.
+type InterestService struct {
+	repo    repositories.InterestRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	cfg     *InterestConfig
+	now     func() time.Time
+}
+
+// NewInterestService creates a new interest service; a nil config offers no
+// plans
+func NewInterestService(repo repositories.InterestRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService, cfg *InterestConfig) *InterestService {
+	return &InterestService{repo: repo, wallets: wallets, tx: tx, audit: audit, cfg: cfg, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *InterestService) WithClock(now func() time.Time) *InterestService {
+	s.now = now
+	return s
+}
+
+// today returns the current UTC date
+func (s *InterestService) today() time.Time {
+	now := s.now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// SetPlan enrols a wallet in an interest rate plan, or moves it to another
+// plan from the next day accrued. A new enrolment accrues from today.
+func (s *InterestService) SetPlan(ctx context.Context, walletID int, req dto.SetInterestPlanRequest) (*models.InterestAccount, error) {
+	plan := s.cfg.plan(req.Plan)
+	if plan == nil {
+		return nil, fmt.Errorf("%w: unknown interest plan %q", ErrInvalidRequest, req.Plan)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot earn interest", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+	if plan.Currency != "" && plan.Currency != wallet.Currency {
+		return nil, fmt.Errorf("%w: plan %s is for %s wallets", ErrInvalidRequest, req.Plan, plan.Currency)
+	}
+
+	var account *models.InterestAccount
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetAccount(ctx, wallet.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get interest account: %w", err)
+		}
+		now := s.now().UTC()
+		if existing == nil {
+			account = &models.InterestAccount{
+				WalletID:       wallet.ID,
+				Plan:           req.Plan,
+				Accrued:        formatInterest(new(big.Rat)),
+				AccruedThrough: s.today().AddDate(0, 0, -1),
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			account.CreatedByType, account.CreatedByID = actor(ctx)
+			if err := s.repo.CreateAccount(ctx, account); err != nil {
+				return fmt.Errorf("failed to create interest account: %w", err)
+			}
+			return s.audit.Record(ctx, models.AuditInterestPlanSet, "interest_account", account.WalletID, nil, account)
+		}
+		before := *existing
+		account = existing
+		account.Plan, account.UpdatedAt = req.Plan, now
+		if err := s.repo.UpdateAccount(ctx, account); err != nil {
+			return fmt.Errorf("failed to update interest account: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditInterestPlanSet, "interest_account", account.WalletID, &before, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// GetAccount returns a wallet's interest account and its latest
+// capitalisations
+func (s *InterestService) GetAccount(ctx context.Context, walletID int) (*dto.InterestAccountResponse, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	account, err := s.repo.GetAccount(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interest account: %w", err)
+	}
+	if account == nil {
+		return nil, ErrInterestAccountNotFound
+	}
+	capitalisations, err := s.repo.ListCapitalisations(ctx, walletID, interestCapitalisationsShown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interest capitalisations: %w", err)
+	}
+	if capitalisations == nil {
+		capitalisations = []models.InterestCapitalisation{}
+	}
+	return &dto.InterestAccountResponse{InterestAccount: *account, Capitalisations: capitalisations}, nil
+}
+
+// ListAccruals returns a wallet's daily accruals between two dates
+// inclusive, latest first
+func (s *InterestService) ListAccruals(ctx context.Context, walletID int, from, to time.Time, limit int) ([]models.InterestAccrual, error) {
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidRequest)
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	accruals, err := s.repo.ListAccruals(ctx, walletID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interest accruals: %w", err)
+	}
+	if accruals == nil {
+		accruals = []models.InterestAccrual{}
+	}
+	return accruals, nil
+}
+
+// AccrueDue accrues interest for every day that has ended since each
+// account was last accrued, capitalising at month ends; run by a background
+// worker. Accounts on plans that are no longer configured wait until their
+// plan is back.
+func (s *InterestService) AccrueDue(ctx context.Context) error {
+	plans := s.cfg.planNames()
+	if len(plans) == 0 {
+		return nil
+	}
+	for i := 0; i < maxInterestDaysPerTick; i++ {
+		var claimed bool
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			account, err := s.repo.ClaimDueAccount(ctx, s.today().AddDate(0, 0, -1), plans)
+			if err != nil {
+				return fmt.Errorf("failed to claim interest account: %w", err)
+			}
+			if account == nil {
+				return nil
+			}
+			claimed = true
+			return s.accrue(ctx, account)
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// accrue accrues a claimed account's next day of interest and, when the day
+// ends a month, capitalises what the month accrued
+func (s *InterestService) accrue(ctx context.Context, account *models.InterestAccount) error {
+	plan := s.cfg.plan(account.Plan)
+	day := account.AccruedThrough.AddDate(0, 0, 1)
+	balance, err := s.repo.EndOfDayBalance(ctx, account.WalletID, day.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("failed to get end-of-day balance: %w", err)
+	}
+	accrued, err := parseInterest(account.Accrued)
+	if err != nil {
+		return err
+	}
+
+	base := new(big.Rat).SetInt64(max(balance, 0))
+	if plan.Method == InterestCompound {
+		base.Add(base, accrued)
+	}
+	amount := roundInterest(plan.dailyInterest(base, day))
+	accrued.Add(accrued, amount)
+	accrual := &models.InterestAccrual{
+		WalletID:    account.WalletID,
+		AccrualDate: day,
+		Plan:        account.Plan,
+		Balance:     balance,
+		Amount:      formatInterest(amount),
+		Accrued:     formatInterest(accrued),
+		CreatedAt:   s.now().UTC(),
+	}
+	if err := s.repo.CreateAccrual(ctx, accrual); err != nil {
+		return fmt.Errorf("failed to record interest accrual: %w", err)
+	}
+
+	if day.AddDate(0, 0, 1).Day() == 1 {
+		if err := s.capitalise(ctx, account.WalletID, time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC), accrued); err != nil {
+			return err
+		}
+	}
+	account.Accrued, account.AccruedThrough, account.UpdatedAt = formatInterest(accrued), day, s.now().UTC()
+	if err := s.repo.UpdateAccount(ctx, account); err != nil {
+		return fmt.Errorf("failed to update interest account: %w", err)
+	}
+	return nil
+}
+
+// capitalise credits the whole minor units of accrued to the wallet for the
+// month starting at period and takes them off accrued. A wallet that cannot
+// take the credit keeps the interest accrued until a later month end.
+func (s *InterestService) capitalise(ctx context.Context, walletID int, period time.Time, accrued *big.Rat) error {
+	whole := new(big.Int).Quo(accrued.Num(), accrued.Denom())
+	if whole.Sign() <= 0 {
+		return nil
+	}
+	wallet, err := s.wallets.repo.GetWalletByID(ctx, walletID)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if wallet == nil {
+		return ErrWalletNotFound
+	}
+	expense, err := s.wallets.systemWallet(ctx, models.SystemInterestExpense, wallet.Currency)
+	if err != nil {
+		return err
+	}
+
+	amount := whole.Int64()
+	reference := period.Year()*100 + int(period.Month())
+	entry := models.NewLedgerEntry(wallet.ID, reference, "credit", amount, 0, "Interest for "+period.Format("January 2006"))
+	posted, err := s.wallets.postJournal(ctx, []*models.LedgerEntry{entry, counterLeg(entry, expense.ID)})
+	if errors.Is(err, ErrWalletNotActive) {
+		// Refused before anything was written; the interest stays accrued
+		log.Printf("wallet %d: interest for %s not capitalised: %v", wallet.ID, period.Format("2006-01"), err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	accrued.Sub(accrued, new(big.Rat).SetInt(whole))
+	capitalisation := &models.InterestCapitalisation{
+		WalletID:      wallet.ID,
+		Period:        period,
+		Amount:        amount,
+		Carried:       formatInterest(accrued),
+		JournalID:     posted.ID,
+		LedgerEntryID: entry.ID,
+		CreatedAt:     s.now().UTC(),
+	}
+	if err := s.repo.CreateCapitalisation(ctx, capitalisation); err != nil {
+		return fmt.Errorf("failed to record interest capitalisation: %w", err)
+	}
+	return s.audit.Record(ctx, models.AuditInterestCapitalised, "interest_capitalisation", capitalisation.ID, nil, capitalisation)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// interestPlan parses a single plan named "plan"
+func interestPlan(t *testing.T, plan string) *InterestConfig {
+	t.Helper()
+	cfg, err := ParseInterestConfig([]byte(`{"plans":{"plan":` + plan + `}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestDays30360(t *testing.T) {
+	tests := []struct {
+		start, end time.Time
+		want       int64
+	}{
+		{start: utcDate(2026, 1, 1), end: utcDate(2026, 1, 2), want: 1},
+		{start: utcDate(2026, 1, 30), end: utcDate(2026, 1, 31), want: 0}, // The 31st counts as the 30th
+		{start: utcDate(2026, 1, 31), end: utcDate(2026, 2, 1), want: 1},
+		{start: utcDate(2026, 2, 28), end: utcDate(2026, 3, 1), want: 3},
+		{start: utcDate(2024, 2, 28), end: utcDate(2024, 2, 29), want: 1},
+		{start: utcDate(2024, 2, 29), end: utcDate(2024, 3, 1), want: 2},
+		{start: utcDate(2026, 12, 31), end: utcDate(2027, 1, 1), want: 1},
+		{start: utcDate(2026, 1, 15), end: utcDate(2027, 1, 15), want: 360},
+	}
+	for _, tt := range tests {
+		if got := days30360(tt.start, tt.end); got != tt.want {
+			t.Errorf("days30360(%s, %s) = %d, want %d", tt.start.Format("2006-01-02"), tt.end.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	// Whatever the month's length, its days add up to 30
+	for _, month := range []time.Time{utcDate(2026, 1, 1), utcDate(2026, 2, 1), utcDate(2024, 2, 1), utcDate(2026, 4, 1), utcDate(2026, 12, 1)} {
+		var total int64
+		for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+			total += days30360(day, day.AddDate(0, 0, 1))
+		}
+		if total != 30 {
+			t.Errorf("days of %s add up to %d, want 30", month.Format("January 2006"), total)
+		}
+	}
+}
+
+func TestDailyInterest(t *testing.T) {
+	const tiered = `"tiers":[{"from":0,"rate":"0.01"},{"from":100000,"rate":"0.02"}]`
+	tests := []struct {
+		name     string
+		dayCount string
+		base     int64
+		day      time.Time
+		want     *big.Rat
+	}{
+		{name: "ACT/365 lower tier", dayCount: DayCountACT365, base: 36500, day: utcDate(2026, 1, 5), want: big.NewRat(1, 1)},
+		{name: "ACT/365 leap day", dayCount: DayCountACT365, base: 36500, day: utcDate(2024, 2, 29), want: big.NewRat(1, 1)},
+		{name: "ACT/365 across tiers", dayCount: DayCountACT365, base: 136500, day: utcDate(2026, 1, 5), want: big.NewRat(1000+730, 365)},
+		{name: "ACT/365 tier boundary", dayCount: DayCountACT365, base: 100000, day: utcDate(2026, 1, 5), want: big.NewRat(1000, 365)},
+		{name: "30/360 ordinary day", dayCount: DayCount30360, base: 36000, day: utcDate(2026, 1, 5), want: big.NewRat(1, 1)},
+		{name: "30/360 the 31st earns nothing", dayCount: DayCount30360, base: 36000, day: utcDate(2026, 1, 30), want: new(big.Rat)},
+		{name: "30/360 end of February", dayCount: DayCount30360, base: 36000, day: utcDate(2026, 2, 28), want: big.NewRat(3, 1)},
+		{name: "nothing on nothing", dayCount: DayCountACT365, base: 0, day: utcDate(2026, 1, 5), want: new(big.Rat)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := interestPlan(t, `{"method":"simple","day_count":"`+tt.dayCount+`",`+tiered+`}`).plan("plan")
+			if got := plan.dailyInterest(new(big.Rat).SetInt64(tt.base), tt.day); got.Cmp(tt.want) != 0 {
+				t.Errorf("dailyInterest(%d, %s) = %s, want %s", tt.base, tt.day.Format("2006-01-02"), got.RatString(), tt.want.RatString())
+			}
+		})
+	}
+}
+
+func TestAccrueDueCarriesFractions(t *testing.T) {
+	type capitalised struct {
+		amount  int64
+		carried string
+	}
+	tests := []struct {
+		name     string
+		dayCount string
+		want     []capitalised
+	}{
+		{
+			// 1000/365 a day rounds to 2.739726027397; January's 31 days
+			// come to 84.931506849307 and February's 28 to 76.712328767116
+			name:     "ACT/365",
+			dayCount: DayCountACT365,
+			want:     []capitalised{{84, "0.931506849307"}, {77, "0.643835616423"}},
+		},
+		{
+			// 1000/360 a day rounds to 2.777777777778. The 31st of January
+			// earns nothing and the 28th of February earns three days.
+			name:     "30/360",
+			dayCount: DayCount30360,
+			want:     []capitalised{{83, "0.333333333340"}, {83, "0.666666666679"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock(time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC))
+			wallets := newFakeWalletRepository()
+			wallet := wallets.addWallet(1, "GBP", 100000)
+			repo := &fakeInterestRepository{
+				balance: 100000,
+				account: &models.InterestAccount{WalletID: wallet.ID, Plan: "plan", Accrued: "0", AccruedThrough: utcDate(2025, 12, 31)},
+			}
+			cfg := interestPlan(t, `{"method":"simple","day_count":"`+tt.dayCount+`","tiers":[{"from":0,"rate":"0.01"}]}`)
+			svc := NewInterestService(repo, NewWalletService(wallets, fakeTransactor{}).WithClock(clock.Now), fakeTransactor{}, nil, cfg).WithClock(clock.Now)
+
+			if err := svc.AccrueDue(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			var got []capitalised
+			for _, c := range repo.capitalisations {
+				got = append(got, capitalised{c.Amount, c.Carried})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("capitalised %v, want %v", got, tt.want)
+			}
+			if repo.account.Accrued != tt.want[1].carried || !repo.account.AccruedThrough.Equal(utcDate(2026, 2, 28)) || len(repo.accruals) != 59 {
+				t.Errorf("account accrued %s through %s after %d accruals, want %s through 2026-02-28 after 59", repo.account.Accrued, repo.account.AccruedThrough.Format("2006-01-02"), len(repo.accruals), tt.want[1].carried)
+			}
+			if got, want := wallets.balance(wallet.ID), 100000+tt.want[0].amount+tt.want[1].amount; got != want {
+				t.Errorf("balance = %d, want %d", got, want)
+			}
+		})
+	}
+}
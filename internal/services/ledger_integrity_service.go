@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// checkpointSettleLag keeps checkpoints clear of entries whose transactions may
+// still be in flight, so a later commit cannot land below a checkpoint's max ID.
+const checkpointSettleLag = time.Minute
+
+// errStopWalk ends a ledger walk early once a break has been found
+var errStopWalk = errors.New("stop walk")
+
+// LedgerIntegrityService verifies the per-wallet ledger hash chains and issues
+// Ed25519-signed checkpoints of every chain head
+type LedgerIntegrityService struct {
+	wallets repositories.WalletRepository
+	repo    repositories.LedgerIntegrityRepository
+	key     ed25519.PrivateKey // nil disables checkpoint signing
+	now     func() time.Time
+}
+
+// NewLedgerIntegrityService creates a new ledger integrity service. key may be nil
+// when no signing key is configured; verification still works.
+func NewLedgerIntegrityService(wallets repositories.WalletRepository, repo repositories.LedgerIntegrityRepository, key ed25519.PrivateKey) *LedgerIntegrityService {
+	return &LedgerIntegrityService{wallets: wallets, repo: repo, key: key, now: time.Now}
+}
+
+// LoadSigningKey reads an Ed25519 key from a file holding a base64 or hex
+// encoded 32-byte seed or 64-byte private key
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSpace(string(raw))
+	decoded, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		if decoded, err = hex.DecodeString(text); err != nil {
+			return nil, errors.New("signing key must be base64 or hex encoded")
+		}
+	}
+	switch len(decoded) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(decoded), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(decoded), nil
+	default:
+		return nil, fmt.Errorf("signing key must be %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(decoded))
+	}
+}
+
+// SigningEnabled reports whether checkpoints can be created
+func (s *LedgerIntegrityService) SigningEnabled() bool {
+	return s.key != nil
+}
+
+// VerifyWallet walks a wallet's ledger in posting order and reports the first broken link
+func (s *LedgerIntegrityService) VerifyWallet(ctx context.Context, walletID int) (*models.ChainVerification, error) {
+	wallet, err := s.wallets.GetWalletByID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, ErrWalletNotFound
+	}
+
+	result := &models.ChainVerification{WalletID: walletID, Valid: true}
+	started := false
+	err = s.wallets.WalkLedgerEntries(ctx, walletID, func(entry *models.LedgerEntry) error {
+		if entry.Hash == "" {
+			if started {
+				result.Break = &models.ChainBreak{EntryID: entry.ID, Reason: "entry has no hash but follows hashed entries"}
+				return errStopWalk
+			}
+			result.UnhashedEntries++ // Written before hashing was introduced
+			return nil
+		}
+		started = true
+
+		if entry.PrevHash != result.HeadHash {
+			result.Break = &models.ChainBreak{EntryID: entry.ID, Reason: "prev_hash does not match the previous entry's hash"}
+			return errStopWalk
+		}
+		if entry.ComputeHash() != entry.Hash {
+			result.Break = &models.ChainBreak{EntryID: entry.ID, Reason: "entry content does not match its hash"}
+			return errStopWalk
+		}
+		result.HeadHash = entry.Hash
+		result.EntriesChecked++
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopWalk) {
+		return nil, fmt.Errorf("failed to walk ledger entries: %w", err)
+	}
+	result.Valid = result.Break == nil
+	return result, nil
+}
+
+// VerifyAll verifies every wallet's chain and lists the wallets whose chain is broken
+func (s *LedgerIntegrityService) VerifyAll(ctx context.Context) (*dto.ChainVerificationReport, error) {
+	walletIDs, err := s.wallets.ListWalletIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+
+	report := &dto.ChainVerificationReport{Valid: true, Broken: []models.ChainVerification{}}
+	for _, walletID := range walletIDs {
+		result, err := s.VerifyWallet(ctx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		report.WalletsChecked++
+		report.EntriesChecked += result.EntriesChecked
+		report.UnhashedEntries += result.UnhashedEntries
+		if !result.Valid {
+			report.Valid = false
+			report.Broken = append(report.Broken, *result)
+		}
+	}
+	return report, nil
+}
+
+// CreateCheckpoint signs a snapshot of every wallet's chain head. It returns
+// nil without error when nothing was posted since the previous checkpoint.
+func (s *LedgerIntegrityService) CreateCheckpoint(ctx context.Context) (*models.LedgerCheckpoint, error) {
+	if s.key == nil {
+		return nil, fmt.Errorf("%w: no ledger signing key is configured", ErrInvalidRequest)
+	}
+
+	now := s.now().UTC().Truncate(time.Microsecond)
+	maxEntryID, err := s.repo.MaxSettledEntryID(ctx, now.Add(-checkpointSettleLag))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find checkpoint boundary: %w", err)
+	}
+	prev, err := s.repo.GetLatestCheckpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous checkpoint: %w", err)
+	}
+	if maxEntryID == 0 || (prev != nil && prev.MaxEntryID >= maxEntryID) {
+		return nil, nil
+	}
+
+	root, walletCount, err := s.headsRoot(ctx, maxEntryID)
+	if err != nil {
+		return nil, err
+	}
+	entryCount, err := s.repo.CountEntries(ctx, maxEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ledger entries: %w", err)
+	}
+
+	checkpoint := &models.LedgerCheckpoint{
+		MaxEntryID:  maxEntryID,
+		EntryCount:  entryCount,
+		WalletCount: walletCount,
+		HeadsRoot:   root,
+		KeyID:       s.keyID(),
+		CreatedAt:   now,
+	}
+	if prev != nil {
+		checkpoint.PrevCheckpointHash = prev.PayloadHash
+	}
+	payload := checkpoint.SigningPayload()
+	sum := sha256.Sum256(payload)
+	checkpoint.PayloadHash = hex.EncodeToString(sum[:])
+	checkpoint.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload))
+
+	if err := s.repo.CreateCheckpoint(ctx, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to store checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// RunCheckpoint is the background job wrapper around CreateCheckpoint
+func (s *LedgerIntegrityService) RunCheckpoint(ctx context.Context) error {
+	_, err := s.CreateCheckpoint(ctx)
+	return err
+}
+
+func (s *LedgerIntegrityService) ListCheckpoints(ctx context.Context, limit int) ([]models.LedgerCheckpoint, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	checkpoints, err := s.repo.ListCheckpoints(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	if checkpoints == nil {
+		checkpoints = []models.LedgerCheckpoint{}
+	}
+	return checkpoints, nil
+}
+
+// VerifyCheckpoint checks a stored checkpoint's signature and recomputes its
+// heads root from the current ledger to detect later tampering
+func (s *LedgerIntegrityService) VerifyCheckpoint(ctx context.Context, checkpointID int) (*dto.CheckpointVerification, error) {
+	checkpoint, err := s.repo.GetCheckpointByID(ctx, checkpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		return nil, ErrCheckpointNotFound
+	}
+
+	result := &dto.CheckpointVerification{CheckpointID: checkpoint.ID, ExpectedHeadsRoot: checkpoint.HeadsRoot}
+	if s.key != nil && checkpoint.KeyID == s.keyID() {
+		signature, err := base64.StdEncoding.DecodeString(checkpoint.Signature)
+		result.SignatureValid = err == nil && ed25519.Verify(s.key.Public().(ed25519.PublicKey), checkpoint.SigningPayload(), signature)
+	}
+	if result.ActualHeadsRoot, _, err = s.headsRoot(ctx, checkpoint.MaxEntryID); err != nil {
+		return nil, err
+	}
+	result.HeadsRootMatches = result.ActualHeadsRoot == checkpoint.HeadsRoot
+	result.Valid = result.SignatureValid && result.HeadsRootMatches
+	return result, nil
+}
+
+// PublicKey returns the key auditors use to verify checkpoint signatures
+func (s *LedgerIntegrityService) PublicKey() (*dto.SigningKeyResponse, error) {
+	if s.key == nil {
+		return nil, fmt.Errorf("%w: no ledger signing key is configured", ErrNotFound)
+	}
+	return &dto.SigningKeyResponse{
+		KeyID:     s.keyID(),
+		Algorithm: "Ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+// headsRoot hashes "<wallet_id>:<head_hash>\n" lines in wallet order
+func (s *LedgerIntegrityService) headsRoot(ctx context.Context, maxEntryID int) (string, int64, error) {
+	heads, err := s.repo.ListWalletHeads(ctx, maxEntryID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to list wallet chain heads: %w", err)
+	}
+	h := sha256.New()
+	for _, head := range heads {
+		h.Write([]byte(strconv.Itoa(head.WalletID) + ":" + head.Hash + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil)), int64(len(heads)), nil
+}
+
+// keyID is the first 8 bytes of the public key's SHA-256, hex encoded
+func (s *LedgerIntegrityService) keyID() string {
+	sum := sha256.Sum256(s.key.Public().(ed25519.PublicKey))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// Limit names. Period limits run over calendar periods in UTC: the day from
+// midnight, the week from Monday and the month from the 1st. Debit and credit
+// totals include fees and taxes charged to the wallet; counts are journals
+// touching the wallet in either direction.
+const (
+	LimitMaxBalance     = "max_balance"
+	LimitMaxTransaction = "max_transaction"
+	LimitDailyDebit     = "daily_debit"
+	LimitWeeklyDebit    = "weekly_debit"
+	LimitMonthlyDebit   = "monthly_debit"
+	LimitDailyCredit    = "daily_credit"
+	LimitWeeklyCredit   = "weekly_credit"
+	LimitMonthlyCredit  = "monthly_credit"
+	LimitDailyCount     = "daily_count"
+	LimitWeeklyCount    = "weekly_count"
+	LimitMonthlyCount   = "monthly_count"
+)
+
+// Limit periods
+const (
+	limitDaily   = "daily"
+	limitWeekly  = "weekly"
+	limitMonthly = "monthly"
+)
+
+// limitKinds describes each period limit; the order is the order limits are
+// checked and listed in
+var limitKinds = []struct {
+	name, period, measure string
+}{
+	{LimitDailyDebit, limitDaily, "debit"},
+	{LimitWeeklyDebit, limitWeekly, "debit"},
+	{LimitMonthlyDebit, limitMonthly, "debit"},
+	{LimitDailyCredit, limitDaily, "credit"},
+	{LimitWeeklyCredit, limitWeekly, "credit"},
+	{LimitMonthlyCredit, limitMonthly, "credit"},
+	{LimitDailyCount, limitDaily, "count"},
+	{LimitWeeklyCount, limitWeekly, "count"},
+	{LimitMonthlyCount, limitMonthly, "count"},
+}
+
+// ErrLimitExceeded is returned, wrapped in a *LimitError, when a posting
+// would break one of the wallet's limits
+var ErrLimitExceeded = fmt.Errorf("%w: limit exceeded", ErrConflict)
+
+// LimitError says which limit a posting would break and how much room the
+// wallet has left under it
+type LimitError struct {
+	WalletID int
+	Limit    string
+	Max      int64
+	Used     int64
+	Headroom int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: wallet %d %s is %d, used %d, headroom %d", ErrLimitExceeded, e.WalletID, e.Limit, e.Max, e.Used, e.Headroom)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// Limits maps limit names to amounts (counts for the *_count limits). A limit
+// that is not set does not apply.
+type Limits map[string]int64
+
+// validate rejects unknown limit names and negative limits
+func (l Limits) validate() error {
+	for name, value := range l {
+		if !isLimitName(name) {
+			return fmt.Errorf("unknown limit %q", name)
+		}
+		if value < 0 {
+			return fmt.Errorf("limit %s cannot be negative", name)
+		}
+	}
+	return nil
+}
+
+func isLimitName(name string) bool {
+	if name == LimitMaxBalance || name == LimitMaxTransaction {
+		return true
+	}
+	for _, kind := range limitKinds {
+		if kind.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LimitConfig lists the limits for each KYC tier. Wallets without a tier get
+// DefaultTier's limits.
+type LimitConfig struct {
+	DefaultTier string            `json:"default_tier"`
+	Tiers       map[string]Limits `json:"tiers"`
+}
+
+// LoadLimitConfig reads KYC tier limits from a JSON file
+func LoadLimitConfig(path string) (*LimitConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLimitConfig(raw)
+}
+
+// ParseLimitConfig decodes and validates JSON KYC tier limits
+func ParseLimitConfig(raw []byte) (*LimitConfig, error) {
+	var cfg LimitConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid limit config: %w", err)
+	}
+	for tier, limits := range cfg.Tiers {
+		if tier == "" {
+			return nil, errors.New("tier names cannot be empty")
+		}
+		if err := limits.validate(); err != nil {
+			return nil, fmt.Errorf("tier %s: %w", tier, err)
+		}
+	}
+	if _, ok := cfg.Tiers[cfg.DefaultTier]; cfg.DefaultTier != "" && !ok {
+		return nil, fmt.Errorf("default tier %q is not defined", cfg.DefaultTier)
+	}
+	return &cfg, nil
+}
+
+// LimitService evaluates wallets' spend and velocity limits, which come from
+// the wallet's KYC tier with any per-wallet override on top
+type LimitService struct {
+	cfg  *LimitConfig
+	repo repositories.LimitRepository
+	now  func() time.Time
+}
+
+// NewLimitService creates a new limit service. A nil config sets no tier
+// limits; per-wallet overrides still apply.
+func NewLimitService(cfg *LimitConfig, repo repositories.LimitRepository) *LimitService {
+	if cfg == nil {
+		cfg = &LimitConfig{}
+	}
+	return &LimitService{cfg: cfg, repo: repo, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *LimitService) WithClock(now func() time.Time) *LimitService {
+	s.now = now
+	return s
+}
+
+// tier returns the wallet's effective KYC tier
+func (s *LimitService) tier(wallet *models.Wallet) string {
+	if wallet.KYCTier != "" {
+		return wallet.KYCTier
+	}
+	return s.cfg.DefaultTier
+}
+
+// effective returns the limits in force for wallet and its override, if any
+func (s *LimitService) effective(ctx context.Context, wallet *models.Wallet) (Limits, *models.WalletLimitOverride, error) {
+	limits := Limits{}
+	for name, value := range s.cfg.Tiers[s.tier(wallet)] {
+		limits[name] = value
+	}
+	override, err := s.repo.GetOverride(ctx, wallet.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get limit override: %w", err)
+	}
+	if override != nil {
+		for name, value := range override.Limits {
+			limits[name] = value
+		}
+	}
+	return limits, override, nil
+}
+
+// periodStart returns the start of the calendar period containing now
+func periodStart(period string, now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case limitWeekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case limitMonthly:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// usage returns the wallet's usage for each period a limit is set for
+func (s *LimitService) usage(ctx context.Context, walletID int, limits Limits, now time.Time) (map[string]*models.LimitUsage, error) {
+	usage := map[string]*models.LimitUsage{}
+	for _, kind := range limitKinds {
+		if _, ok := limits[kind.name]; !ok || usage[kind.period] != nil {
+			continue
+		}
+		u, err := s.repo.Usage(ctx, walletID, periodStart(kind.period, now))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get limit usage: %w", err)
+		}
+		usage[kind.period] = u
+	}
+	return usage, nil
+}
+
+func usedBy(u *models.LimitUsage, measure string) int64 {
+	switch measure {
+	case "debit":
+		return u.Debit
+	case "credit":
+		return u.Credit
+	default:
+		return u.Count
+	}
+}
+
+// guard rejects a journal that would take walletID over one of its limits.
+// amount is the transaction amount; debit and credit are everything the
+// journal debits from and credits to the wallet, fees included. A nil
+// *LimitService checks nothing.
+func (s *LimitService) guard(walletID int, amount, debit, credit int64) journalGuard {
+	return func(ctx context.Context, locked map[int]*models.Wallet) error {
+		if s == nil {
+			return nil
+		}
+		wallet := locked[walletID]
+		limits, _, err := s.effective(ctx, wallet)
+		if err != nil {
+			return err
+		}
+		if len(limits) == 0 {
+			return nil
+		}
+
+		if max, ok := limits[LimitMaxTransaction]; ok && amount > max {
+			return &LimitError{WalletID: walletID, Limit: LimitMaxTransaction, Max: max, Headroom: max}
+		}
+		usage, err := s.usage(ctx, walletID, limits, s.now().UTC())
+		if err != nil {
+			return err
+		}
+		for _, kind := range limitKinds {
+			max, ok := limits[kind.name]
+			if !ok {
+				continue
+			}
+			var add int64
+			switch kind.measure {
+			case "debit":
+				add = debit
+			case "credit":
+				add = credit
+			default:
+				add = 1
+			}
+			if add == 0 {
+				continue
+			}
+			if used := usedBy(usage[kind.period], kind.measure); used+add > max {
+				return &LimitError{WalletID: walletID, Limit: kind.name, Max: max, Used: used, Headroom: headroom(max, used)}
+			}
+		}
+		if max, ok := limits[LimitMaxBalance]; ok && credit > debit && wallet.Balance+credit-debit > max {
+			return &LimitError{WalletID: walletID, Limit: LimitMaxBalance, Max: max, Used: wallet.Balance, Headroom: headroom(max, wallet.Balance)}
+		}
+		return nil
+	}
+}
+
+func headroom(max, used int64) int64 {
+	if used >= max {
+		return 0
+	}
+	return max - used
+}
+
+// GetWalletLimits returns the limits in force for a wallet with what it has
+// used of each
+func (s *WalletService) GetWalletLimits(ctx context.Context, walletID int) (*dto.WalletLimitsResponse, error) {
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.WalletLimitsResponse{WalletID: wallet.ID, Limits: []dto.LimitStatus{}}
+	if s.limits == nil {
+		return resp, nil
+	}
+	resp.KYCTier = s.limits.tier(wallet)
+
+	limits, override, err := s.limits.effective(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		resp.Override = override.Limits
+	}
+	usage, err := s.limits.usage(ctx, wallet.ID, limits, s.limits.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{LimitMaxBalance, LimitMaxTransaction} {
+		if max, ok := limits[name]; ok {
+			status := dto.LimitStatus{Name: name, Limit: max, Headroom: max}
+			if name == LimitMaxBalance {
+				status.Used, status.Headroom = wallet.Balance, headroom(max, wallet.Balance)
+			}
+			resp.Limits = append(resp.Limits, status)
+		}
+	}
+	for _, kind := range limitKinds {
+		if max, ok := limits[kind.name]; ok {
+			used := usedBy(usage[kind.period], kind.measure)
+			resp.Limits = append(resp.Limits, dto.LimitStatus{Name: kind.name, Limit: max, Used: used, Headroom: headroom(max, used)})
+		}
+	}
+	return resp, nil
+}
+
+// SetKYCTier moves a wallet to another KYC tier, changing its limits
+func (s *WalletService) SetKYCTier(ctx context.Context, walletID int, req dto.SetKYCTierRequest) (*dto.WalletResponse, error) {
+	if s.limits == nil {
+		return nil, fmt.Errorf("%w: limits are not enabled", ErrInvalidRequest)
+	}
+	if _, ok := s.limits.cfg.Tiers[req.Tier]; req.Tier != "" && !ok {
+		return nil, fmt.Errorf("%w: unknown KYC tier %q", ErrInvalidRequest, req.Tier)
+	}
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts have no KYC tier", ErrInvalidRequest)
+	}
+
+	var updated *models.Wallet
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateWalletKYCTier(ctx, walletID, req.Tier); err != nil {
+			return fmt.Errorf("failed to update KYC tier: %w", err)
+		}
+		if updated, err = s.repo.GetWalletByID(ctx, walletID); err != nil {
+			return fmt.Errorf("failed to get updated wallet: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditWalletKYCTier, "wallet", walletID, wallet, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toWalletResponse(updated), nil
+}
+
+// SetLimitOverride replaces the limits a wallet gets from its tier with the
+// given ones; limits not named keep the tier's values. An empty set removes
+// the override.
+func (s *WalletService) SetLimitOverride(ctx context.Context, walletID int, req dto.SetLimitOverrideRequest) (*dto.WalletLimitsResponse, error) {
+	if s.limits == nil {
+		return nil, fmt.Errorf("%w: limits are not enabled", ErrInvalidRequest)
+	}
+	if err := Limits(req.Limits).validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts have no limits", ErrInvalidRequest)
+	}
+
+	override := &models.WalletLimitOverride{WalletID: walletID, Limits: req.Limits, UpdatedAt: s.limits.now().UTC()}
+	override.UpdatedByType, override.UpdatedByID = actor(ctx)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.limits.repo.GetOverride(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to get limit override: %w", err)
+		}
+		if len(req.Limits) == 0 {
+			err = s.limits.repo.DeleteOverride(ctx, walletID)
+		} else {
+			err = s.limits.repo.UpsertOverride(ctx, override)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save limit override: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditWalletLimits, "wallet", walletID, before, override)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetWalletLimits(ctx, walletID)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		period string
+		now    time.Time
+		want   time.Time
+	}{
+		{name: "day", period: limitDaily, now: time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC), want: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "week from Wednesday", period: limitWeekly, now: time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC), want: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "week from Monday", period: limitWeekly, now: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "week from Sunday", period: limitWeekly, now: time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC), want: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "week across a month", period: limitWeekly, now: time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "week across a year", period: limitWeekly, now: time.Date(2027, 1, 2, 12, 0, 0, 0, time.UTC), want: time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "month", period: limitMonthly, now: time.Date(2026, 2, 28, 23, 59, 0, 0, time.UTC), want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := periodStart(tt.period, tt.now); !got.Equal(tt.want) {
+				t.Errorf("periodStart(%s, %s) = %s, want %s", tt.period, tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadroom(t *testing.T) {
+	tests := []struct {
+		max, used, want int64
+	}{
+		{max: 1000, used: 0, want: 1000},
+		{max: 1000, used: 400, want: 600},
+		{max: 1000, used: 1000, want: 0},
+		{max: 1000, used: 1500, want: 0}, // Over after the limit was lowered
+	}
+	for _, tt := range tests {
+		if got := headroom(tt.max, tt.used); got != tt.want {
+			t.Errorf("headroom(%d, %d) = %d, want %d", tt.max, tt.used, got, tt.want)
+		}
+	}
+}
+
+func TestLimitGuard(t *testing.T) {
+	now := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC) // A Wednesday
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                  string
+		amount, debit, credit int64
+
+		wantLimit    string // Empty when the journal is allowed
+		wantUsed     int64
+		wantHeadroom int64
+	}{
+		{name: "within every limit", amount: 1000, debit: 1000, wantLimit: ""},
+		{name: "transaction too large", amount: 5001, debit: 5001, wantLimit: LimitMaxTransaction, wantHeadroom: 5000},
+		{name: "weekly debit counts from Monday", amount: 2500, debit: 2500, wantLimit: LimitWeeklyDebit, wantUsed: 8000, wantHeadroom: 2000},
+		{name: "fees count towards debits", amount: 2000, debit: 2050, wantLimit: LimitWeeklyDebit, wantUsed: 8000, wantHeadroom: 2000},
+		{name: "credits are not debits", amount: 2500, credit: 2500, wantLimit: ""},
+		{name: "balance cap", amount: 5000, credit: 5000, wantLimit: LimitMaxBalance, wantUsed: 7000, wantHeadroom: 3000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeLimitRepository()
+			repo.usage[time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)] = models.LimitUsage{Debit: 1000, Count: 1}
+			repo.usage[monday] = models.LimitUsage{Debit: 8000, Count: 4}
+			svc := NewLimitService(&LimitConfig{
+				DefaultTier: "basic",
+				Tiers: map[string]Limits{"basic": {
+					LimitMaxBalance:     10000,
+					LimitMaxTransaction: 5000,
+					LimitDailyDebit:     5000,
+					LimitWeeklyDebit:    10000,
+				}},
+			}, repo).WithClock(func() time.Time { return now })
+			wallet := &models.Wallet{ID: 1, Balance: 7000}
+
+			err := svc.guard(wallet.ID, tt.amount, tt.debit, tt.credit)(context.Background(), map[int]*models.Wallet{wallet.ID: wallet})
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Errorf("err = %v, want none", err)
+				}
+				return
+			}
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || !errors.Is(err, ErrConflict) {
+				t.Fatalf("err = %v, want a limit error", err)
+			}
+			if limitErr.Limit != tt.wantLimit || limitErr.Used != tt.wantUsed || limitErr.Headroom != tt.wantHeadroom {
+				t.Errorf("broke %s with %d used and %d headroom, want %s with %d used and %d headroom", limitErr.Limit, limitErr.Used, limitErr.Headroom, tt.wantLimit, tt.wantUsed, tt.wantHeadroom)
+			}
+		})
+	}
+}
+
+func TestSetLimitOverrideUsesServiceClock(t *testing.T) {
+	now := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+	repo := newFakeLimitRepository()
+	wallets := newFakeWalletRepository()
+	svc := NewWalletService(wallets, fakeTransactor{}, WithLimits(NewLimitService(nil, repo).WithClock(func() time.Time { return now })))
+	wallet := wallets.addWallet(1, "GBP", 0)
+
+	resp, err := svc.SetLimitOverride(context.Background(), wallet.ID, dto.SetLimitOverrideRequest{Limits: map[string]int64{LimitDailyDebit: 500}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if override, _ := repo.GetOverride(context.Background(), wallet.ID); override == nil || !override.UpdatedAt.Equal(now) {
+		t.Errorf("override = %+v, want one updated at %s", override, now)
+	}
+	if len(resp.Limits) != 1 || resp.Limits[0].Name != LimitDailyDebit || resp.Limits[0].Headroom != 500 {
+		t.Errorf("limits = %+v, want the daily debit override with all of it left", resp.Limits)
+	}
+}
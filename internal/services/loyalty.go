@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// LoyaltyProgram configures loyalty points: how debits earn them, what they
+// redeem for and when they expire. It is loaded from JSON, e.g.
+//
+//	{
+//	  "currency": "PTS",
+//	  "expire_after": "8760h",
+//	  "min_redemption": 500,
+//	  "earn": [{"name": "usd-spend", "currency": "USD", "txn_types": ["debit"], "per": 100, "points": 1}],
+//	  "redeem": [{"currency": "USD", "points": 100, "amount": 50}]
+//	}
+//
+// which earns a point per dollar debited and redeems 100 points for 50 cents.
+type LoyaltyProgram struct {
+	Currency      string           `json:"currency"`     // The points unit; defaults to PTS
+	ExpireAfter   string           `json:"expire_after"` // Lifetime of earned points; empty never expires
+	MinRedemption int64            `json:"min_redemption"`
+	Earn          []EarnRule       `json:"earn"`
+	Redeem        []RedemptionRate `json:"redeem"`
+
+	expireAfter time.Duration
+}
+
+// EarnRule awards Points for every Per of a debit in Currency of at least
+// MinAmount
+type EarnRule struct {
+	Name      string   `json:"name"`
+	Currency  string   `json:"currency"`
+	TxnTypes  []string `json:"txn_types"` // "debit", "transfer"; empty matches both
+	Per       int64    `json:"per"`
+	Points    int64    `json:"points"`
+	MinAmount int64    `json:"min_amount"`
+}
+
+// RedemptionRate converts Points points into Amount of Currency
+type RedemptionRate struct {
+	Currency string `json:"currency"`
+	Points   int64  `json:"points"`
+	Amount   int64  `json:"amount"`
+}
+
+// LoadLoyaltyProgram reads a loyalty program JSON file
+func LoadLoyaltyProgram(path string, currencies *CurrencyRegistry) (*LoyaltyProgram, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLoyaltyProgram(data, currencies)
+}
+
+// ParseLoyaltyProgram parses and validates a loyalty program
+func ParseLoyaltyProgram(data []byte, currencies *CurrencyRegistry) (*LoyaltyProgram, error) {
+	var p LoyaltyProgram
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid loyalty program: %w", err)
+	}
+	if p.Currency == "" {
+		p.Currency = PointsCurrency.Code
+	}
+	unit, err := currencies.Lookup(p.Currency)
+	if err != nil || unit.IsMonetary() {
+		return nil, fmt.Errorf("currency %q is not a points unit", p.Currency)
+	}
+	if p.ExpireAfter != "" {
+		if p.expireAfter, err = time.ParseDuration(p.ExpireAfter); err != nil || p.expireAfter <= 0 {
+			return nil, errors.New("expire_after must be a positive duration")
+		}
+	}
+	if p.MinRedemption < 0 {
+		return nil, errors.New("min_redemption cannot be negative")
+	}
+	for i, rule := range p.Earn {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("earn rule %d: name is required", i)
+		}
+		if rule.Per <= 0 || rule.Points <= 0 || rule.MinAmount < 0 {
+			return nil, fmt.Errorf("earn rule %s: per and points must be positive and min_amount not negative", rule.Name)
+		}
+		if err := monetaryCurrency(currencies, rule.Currency); err != nil {
+			return nil, fmt.Errorf("earn rule %s: %w", rule.Name, err)
+		}
+		for _, txnType := range rule.TxnTypes {
+			if txnType != FeeTxnDebit && txnType != FeeTxnTransfer {
+				return nil, fmt.Errorf("earn rule %s: unknown txn type %q", rule.Name, txnType)
+			}
+		}
+	}
+	seen := map[string]bool{}
+	for _, rate := range p.Redeem {
+		if rate.Points <= 0 || rate.Amount <= 0 {
+			return nil, fmt.Errorf("redemption rate for %s: points and amount must be positive", rate.Currency)
+		}
+		if err := monetaryCurrency(currencies, rate.Currency); err != nil {
+			return nil, fmt.Errorf("redemption rate: %w", err)
+		}
+		if seen[rate.Currency] {
+			return nil, fmt.Errorf("more than one redemption rate for %s", rate.Currency)
+		}
+		seen[rate.Currency] = true
+	}
+	return &p, nil
+}
+
+func monetaryCurrency(currencies *CurrencyRegistry, code string) error {
+	unit, err := currencies.Lookup(code)
+	if err != nil {
+		return err
+	}
+	if !unit.IsMonetary() {
+		return fmt.Errorf("currency %s is not money", code)
+	}
+	return nil
+}
+
+// earned returns the first earn rule matching a txnType debit of amount in
+// currency and the points it earns
+func (p *LoyaltyProgram) earned(currency, txnType string, amount int64) (string, int64) {
+	for _, rule := range p.Earn {
+		if rule.Currency != currency || amount < rule.MinAmount {
+			continue
+		}
+		if len(rule.TxnTypes) > 0 && !slices.Contains(rule.TxnTypes, txnType) {
+			continue
+		}
+		return rule.Name, amount / rule.Per * rule.Points
+	}
+	return "", 0
+}
+
+// rate returns the redemption rate into currency, or nil when points cannot
+// be redeemed into it
+func (p *LoyaltyProgram) rate(currency string) *RedemptionRate {
+	for i := range p.Redeem {
+		if p.Redeem[i].Currency == currency {
+			return &p.Redeem[i]
+		}
+	}
+	return nil
+}
+
+// pointsEarn credits the points a debit earns to its holder's points wallet,
+// as extra legs of the debit's journal
+type pointsEarn struct {
+	svc    *WalletService
+	entry  *models.LedgerEntry // The debit earning the points
+	rule   string
+	credit *models.LedgerEntry // The points wallet's credit
+}
+
+// earnPoints returns the legs crediting wallet's holder with the points a
+// txnType debit entry earns. It returns no legs when the debit earns
+// nothing, or when the holder's points wallet cannot take a credit. It must
+// run inside a transaction, as it creates the points wallet on first use.
+func (s *WalletService) earnPoints(ctx context.Context, wallet *models.Wallet, entry *models.LedgerEntry, txnType string) (*pointsEarn, []*models.LedgerEntry, error) {
+	if s.loyalty == nil || entry.Type != "debit" {
+		return nil, nil, nil
+	}
+	rule, points := s.loyalty.earned(wallet.Currency, txnType, entry.Amount)
+	if points <= 0 {
+		return nil, nil, nil
+	}
+	pointsWallet, err := s.pointsWallet(ctx, wallet)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pointsWallet.Status != models.WalletStatusActive {
+		log.Printf("wallet %d: %d points not earned: points wallet %d is %s", wallet.ID, points, pointsWallet.ID, pointsWallet.Status)
+		return nil, nil, nil
+	}
+	liability, err := s.systemWallet(ctx, models.SystemPointsLiability, s.loyalty.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	credit := models.NewLedgerEntry(pointsWallet.ID, entry.Reference, "credit", points, 0, "Points earned: "+rule)
+	earn := &pointsEarn{svc: s, entry: entry, rule: rule, credit: credit}
+	return earn, []*models.LedgerEntry{credit, counterLeg(credit, liability.ID)}, nil
+}
+
+// pointsWallet returns the points wallet of wallet's holder, creating it if
+// needed
+func (s *WalletService) pointsWallet(ctx context.Context, wallet *models.Wallet) (*models.Wallet, error) {
+	pointsWallet, err := s.repo.GetWalletByUserIDAndCurrency(ctx, wallet.UserID, s.loyalty.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get points wallet: %w", err)
+	}
+	if pointsWallet != nil {
+		return pointsWallet, nil
+	}
+	pointsWallet = models.NewWallet(wallet.UserID, s.loyalty.Currency)
+	pointsWallet.AssetType, pointsWallet.HolderName = models.AssetPoints, wallet.HolderName
+	if err := s.repo.CreateWallet(ctx, pointsWallet); err != nil {
+		return nil, fmt.Errorf("failed to create points wallet: %w", err)
+	}
+	return pointsWallet, s.audit.Record(ctx, models.AuditWalletCreated, "wallet", pointsWallet.ID, nil, pointsWallet)
+}
+
+// record stores the earning once the journal is posted
+func (e *pointsEarn) record(ctx context.Context) error {
+	if e == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	earning := &models.PointsEarning{
+		WalletID:       e.credit.WalletID,
+		SourceWalletID: e.entry.WalletID,
+		SourceEntryID:  e.entry.ID,
+		Rule:           e.rule,
+		Points:         e.credit.Amount,
+		Remaining:      e.credit.Amount,
+		Status:         models.PointsEarningOpen,
+		JournalID:      e.credit.JournalID,
+		LedgerEntryID:  e.credit.ID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if expireAfter := e.svc.loyalty.expireAfter; expireAfter > 0 {
+		expiresAt := now.Add(expireAfter)
+		earning.ExpiresAt = &expiresAt
+	}
+	if err := e.svc.points.CreateEarning(ctx, earning); err != nil {
+		return fmt.Errorf("failed to record points earning: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+const (
+	// maxPointsEarningsPerTick bounds the work one worker tick does
+	maxPointsEarningsPerTick = 100
+	// pointsExpiryRetryDelay postpones a write-off the ledger refused, e.g.
+	// because the points wallet is frozen
+	pointsExpiryRetryDelay = time.Hour
+)
+
+// LoyaltyService redeems loyalty points into money and expires them.
+// Earning points is part of WalletService's debits and transfers. Points
+// outstanding are owed by the points liability account, in points; the
+// money they redeem for comes from the loyalty expense account.
+type LoyaltyService struct {
+	repo    repositories.LoyaltyRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	program *LoyaltyProgram
+	now     func() time.Time
+}
+
+// NewLoyaltyService creates a new loyalty service; a nil program disables
+// redemption
+func NewLoyaltyService(repo repositories.LoyaltyRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService, program *LoyaltyProgram) *LoyaltyService {
+	return &LoyaltyService{repo: repo, wallets: wallets, tx: tx, audit: audit, program: program, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *LoyaltyService) WithClock(now func() time.Time) *LoyaltyService {
+	s.now = now
+	return s
+}
+
+// Redeem converts points into money in a fiat wallet of the same holder at
+// the program's rate for that currency, spending the points that expire
+// first. Points must be a whole multiple of the rate's points.
+func (s *LoyaltyService) Redeem(ctx context.Context, req dto.RedeemPointsRequest) (*models.PointsRedemption, error) {
+	if s.program == nil {
+		return nil, fmt.Errorf("%w: loyalty points are not configured", ErrInvalidRequest)
+	}
+	if req.Points <= 0 {
+		return nil, fmt.Errorf("%w: points must be positive", ErrInvalidRequest)
+	}
+	if req.Points < s.program.MinRedemption {
+		return nil, fmt.Errorf("%w: at least %d points must be redeemed", ErrInvalidRequest, s.program.MinRedemption)
+	}
+	pointsWallet, err := s.wallets.getAuthorizedWallet(ctx, req.PointsWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if pointsWallet.IsSystem() || pointsWallet.Currency != s.program.Currency {
+		return nil, fmt.Errorf("%w: wallet %d is not a %s points wallet", ErrInvalidRequest, pointsWallet.ID, s.program.Currency)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, req.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() || wallet.UserID != pointsWallet.UserID {
+		return nil, fmt.Errorf("%w: points can only be redeemed into a wallet of the same holder", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+	rate := s.program.rate(wallet.Currency)
+	if rate == nil {
+		return nil, fmt.Errorf("%w: points cannot be redeemed into %s", ErrInvalidRequest, wallet.Currency)
+	}
+	if req.Points%rate.Points != 0 {
+		return nil, fmt.Errorf("%w: points must be a multiple of %d", ErrInvalidRequest, rate.Points)
+	}
+	description := req.Description
+	if description == "" {
+		description = fmt.Sprintf("Redemption of %d %s", req.Points, pointsWallet.Currency)
+	}
+
+	redemption := &models.PointsRedemption{
+		PointsWalletID: pointsWallet.ID,
+		WalletID:       wallet.ID,
+		Points:         req.Points,
+		Amount:         req.Points / rate.Points * rate.Amount,
+		Currency:       wallet.Currency,
+		Reference:      req.Reference,
+		CreatedAt:      s.now().UTC(),
+	}
+	redemption.CreatedByType, redemption.CreatedByID = actor(ctx)
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		liability, err := s.wallets.systemWallet(ctx, models.SystemPointsLiability, pointsWallet.Currency)
+		if err != nil {
+			return err
+		}
+		expense, err := s.wallets.systemWallet(ctx, models.SystemLoyaltyExpense, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		burn := models.NewLedgerEntry(pointsWallet.ID, req.Reference, "debit", redemption.Points, 0, description)
+		pay := models.NewLedgerEntry(wallet.ID, req.Reference, "credit", redemption.Amount, 0, description)
+		legs := []*models.LedgerEntry{burn, counterLeg(burn, liability.ID), pay, counterLeg(pay, expense.ID)}
+		posted, err := s.wallets.postJournal(ctx, legs, requireFunds(pointsWallet.ID, redemption.Points))
+		if err != nil {
+			return err
+		}
+		if err := s.spend(ctx, pointsWallet.ID, redemption.Points); err != nil {
+			return err
+		}
+		redemption.JournalID = posted.ID
+		if err := s.repo.CreateRedemption(ctx, redemption); err != nil {
+			return fmt.Errorf("failed to create points redemption: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditPointsRedeemed, "points_redemption", redemption.ID, nil, redemption)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redemption, nil
+}
+
+// spend takes points from a wallet's open earnings, earliest expiry first.
+// The wallet must already be locked.
+func (s *LoyaltyService) spend(ctx context.Context, walletID int, points int64) error {
+	earnings, err := s.repo.ListOpenEarningsForUpdate(ctx, walletID)
+	if err != nil {
+		return fmt.Errorf("failed to lock points earnings: %w", err)
+	}
+	now := s.now().UTC()
+	for _, earning := range earnings {
+		if points == 0 {
+			break
+		}
+		take := min(earning.Remaining, points)
+		earning.Remaining -= take
+		points -= take
+		if earning.Remaining == 0 {
+			earning.Status = models.PointsEarningRedeemed
+		}
+		earning.UpdatedAt = now
+		if err := s.repo.UpdateEarning(ctx, earning); err != nil {
+			return fmt.Errorf("failed to update points earning: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListEarnings returns a points wallet's earnings, newest first, optionally
+// narrowed to a status
+func (s *LoyaltyService) ListEarnings(ctx context.Context, walletID int, status string, limit int) ([]models.PointsEarning, error) {
+	switch status {
+	case "", models.PointsEarningOpen, models.PointsEarningRedeemed, models.PointsEarningExpired:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidRequest, status)
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	earnings, err := s.repo.ListEarnings(ctx, walletID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list points earnings: %w", err)
+	}
+	return earnings, nil
+}
+
+// ListRedemptions returns a points wallet's redemptions, newest first
+func (s *LoyaltyService) ListRedemptions(ctx context.Context, walletID, limit int) ([]models.PointsRedemption, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	redemptions, err := s.repo.ListRedemptions(ctx, walletID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list points redemptions: %w", err)
+	}
+	if redemptions == nil {
+		redemptions = []models.PointsRedemption{}
+	}
+	return redemptions, nil
+}
+
+// ExpireDue writes off the unredeemed points of expired earnings back to the
+// points liability account; run by a background worker
+func (s *LoyaltyService) ExpireDue(ctx context.Context) error {
+	for i := 0; i < maxPointsEarningsPerTick; i++ {
+		var claimed bool
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := s.now().UTC()
+			earning, err := s.repo.ClaimExpiredEarning(ctx, now)
+			if err != nil {
+				return fmt.Errorf("failed to claim points earning: %w", err)
+			}
+			if earning == nil {
+				return nil
+			}
+			claimed = true
+
+			err = s.expire(ctx, earning)
+			if errors.Is(err, ErrWalletNotActive) {
+				// Refused before anything was written; try again later
+				log.Printf("points earning %d: write-off postponed: %v", earning.ID, err)
+				retryAt := now.Add(pointsExpiryRetryDelay)
+				earning.ExpiryRetryAt, earning.UpdatedAt = &retryAt, now
+				if err := s.repo.UpdateEarning(ctx, earning); err != nil {
+					return fmt.Errorf("failed to postpone points write-off: %w", err)
+				}
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// expire writes off a claimed earning, never taking the points wallet below
+// zero
+func (s *LoyaltyService) expire(ctx context.Context, earning *models.PointsEarning) error {
+	before := *earning
+	wallet, err := s.wallets.repo.GetWalletByID(ctx, earning.WalletID)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if wallet == nil {
+		return ErrWalletNotFound
+	}
+	if writeOff := min(earning.Remaining, max(wallet.Balance, 0)); writeOff > 0 {
+		liability, err := s.wallets.systemWallet(ctx, models.SystemPointsLiability, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		entry := models.NewLedgerEntry(wallet.ID, earning.ID, "debit", writeOff, 0, fmt.Sprintf("Expired points: %s", earning.Rule))
+		if _, err := s.wallets.postJournal(ctx, []*models.LedgerEntry{entry, counterLeg(entry, liability.ID)}); err != nil {
+			return err
+		}
+	}
+	earning.Remaining, earning.Status, earning.ExpiryRetryAt, earning.UpdatedAt = 0, models.PointsEarningExpired, nil, s.now().UTC()
+	if err := s.repo.UpdateEarning(ctx, earning); err != nil {
+		return fmt.Errorf("failed to update points earning: %w", err)
+	}
+	return s.audit.Record(ctx, models.AuditPointsExpired, "points_earning", earning.ID, &before, earning)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// ErrPayoutRejected is returned by a PayoutProvider that refuses a payout
+// outright, e.g. for an invalid destination. Any other Submit error is
+// treated as temporary and retried.
+var ErrPayoutRejected = errors.New("payout rejected by provider")
+
+// PayoutProvider sends payouts to bank accounts and interprets the
+// provider's callbacks about their outcome
+type PayoutProvider interface {
+	// Name identifies the provider; it is stored on each payout
+	Name() string
+	// Submit asks the provider to pay out and returns the provider's
+	// reference for it. It is called again with the same idempotency key
+	// after a timeout or a crash, and must then return the reference of the
+	// payout already made rather than pay out twice.
+	Submit(ctx context.Context, idempotencyKey string, payout *models.Payout) (string, error)
+	// ParseCallback decodes and verifies a callback body sent by the provider
+	ParseCallback(body []byte) (*PayoutCallback, error)
+}
+
+// PayoutCallback is a provider's report of a payout's outcome
+type PayoutCallback struct {
+	ProviderReference string
+	Status            string // models.PayoutSucceeded, PayoutFailed or PayoutReturned
+	Reason            string
+}
+
+// FakePayoutProvider accepts every payout without sending money anywhere,
+// for tests and local development. Outcomes are reported by posting
+// {"provider_reference", "status", "reason"} to the callback endpoint.
+type FakePayoutProvider struct {
+	mu        sync.Mutex
+	submitted map[int]string
+	accepted  map[string]string // idempotency key -> reference
+	reject    map[string]string // destination -> reason
+}
+
+// NewFakePayoutProvider creates a fake payout provider
+func NewFakePayoutProvider() *FakePayoutProvider {
+	return &FakePayoutProvider{submitted: map[int]string{}, accepted: map[string]string{}, reject: map[string]string{}}
+}
+
+// Name implements PayoutProvider
+func (p *FakePayoutProvider) Name() string {
+	return "fake"
+}
+
+// Reject makes the provider refuse payouts to destination
+func (p *FakePayoutProvider) Reject(destination, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reject[destination] = reason
+}
+
+// Submitted returns the provider references issued so far, by payout ID
+func (p *FakePayoutProvider) Submitted() map[int]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	submitted := make(map[int]string, len(p.submitted))
+	for id, ref := range p.submitted {
+		submitted[id] = ref
+	}
+	return submitted
+}
+
+// Submit implements PayoutProvider
+func (p *FakePayoutProvider) Submit(ctx context.Context, idempotencyKey string, payout *models.Payout) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ref, ok := p.accepted[idempotencyKey]; ok {
+		return ref, nil
+	}
+	if reason, ok := p.reject[payout.Destination]; ok {
+		return "", fmt.Errorf("%w: %s", ErrPayoutRejected, reason)
+	}
+	ref := "fake-" + strconv.Itoa(payout.ID)
+	p.accepted[idempotencyKey] = ref
+	p.submitted[payout.ID] = ref
+	return ref, nil
+}
+
+// ParseCallback implements PayoutProvider
+func (p *FakePayoutProvider) ParseCallback(body []byte) (*PayoutCallback, error) {
+	var payload struct {
+		ProviderReference string `json:"provider_reference"`
+		Status            string `json:"status"`
+		Reason            string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: invalid callback body", ErrInvalidRequest)
+	}
+	return &PayoutCallback{ProviderReference: payload.ProviderReference, Status: payload.Status, Reason: payload.Reason}, nil
+}
@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// maxPayoutsPerTick bounds the work one worker tick does
+const maxPayoutsPerTick = 100
+
+// PayoutConfig controls submission of payouts to the provider
+type PayoutConfig struct {
+	MaxAttempts   int           // Submissions before a payout fails
+	RetryInterval time.Duration // Wait after a temporary submission error or a refused release, and before resuming an interrupted submission
+}
+
+// PayoutService moves wallet funds to bank accounts through a payout
+// provider. Funds are locked in the payouts in flight account when a payout
+// is requested, leave the ledger through external clearing when it succeeds,
+// and go back to the wallet when it fails or is returned. A wallet frozen or
+// closed in the meantime leaves the payout release pending until it can be
+// credited.
+type PayoutService struct {
+	repo     repositories.PayoutRepository
+	wallets  *WalletService
+	tx       repositories.Transactor
+	audit    *AuditService
+	provider PayoutProvider
+	cfg      PayoutConfig
+	now      func() time.Time
+}
+
+// NewPayoutService creates a new payout service
+func NewPayoutService(repo repositories.PayoutRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService, provider PayoutProvider, cfg PayoutConfig) *PayoutService {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = 5 * time.Minute
+	}
+	return &PayoutService{repo: repo, wallets: wallets, tx: tx, audit: audit, provider: provider, cfg: cfg, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *PayoutService) WithClock(now func() time.Time) *PayoutService {
+	s.now = now
+	return s
+}
+
+// RequestPayout locks funds in a wallet for a withdrawal to a bank account.
+// The lock is subject to the wallet's limits and the risk rules like any
+// other debit. The payout is sent to the provider in the background.
+func (s *PayoutService) RequestPayout(ctx context.Context, req dto.CreatePayoutRequest) (*models.Payout, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	if req.Destination == "" {
+		return nil, fmt.Errorf("%w: destination is required", ErrInvalidRequest)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, req.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot pay out", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	payout := &models.Payout{
+		WalletID:      wallet.ID,
+		Currency:      wallet.Currency,
+		Amount:        req.Amount,
+		Reference:     req.Reference,
+		Destination:   req.Destination,
+		Description:   req.Description,
+		Status:        models.PayoutRequested,
+		Provider:      s.provider.Name(),
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	payout.CreatedByType, payout.CreatedByID = actor(ctx)
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetPayoutByReference(ctx, wallet.ID, req.Reference)
+		if err != nil {
+			return fmt.Errorf("failed to check for existing payout: %w", err)
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: reference %d already used by payout %d", ErrConflict, req.Reference, existing.ID)
+		}
+		inFlight, err := s.wallets.systemWallet(ctx, models.SystemPayoutsInFlight, wallet.Currency)
+		if err != nil {
+			return err
+		}
+
+		// A payout is money leaving the wallet, so the limits and risk rules
+		// see it as a debit
+		lock := models.NewLedgerEntry(wallet.ID, req.Reference, "debit", req.Amount, 0, fmt.Sprintf("Payout: %s", req.Description))
+		risk := s.wallets.risk.check(lock, FeeTxnDebit)
+		guards := []journalGuard{s.wallets.requireCash(wallet.ID, req.Amount), s.wallets.limits.guard(wallet.ID, req.Amount, req.Amount, 0), risk.guard()}
+		if _, err := s.wallets.postJournalAs(ctx, spendCashOnly, []*models.LedgerEntry{lock, counterLeg(lock, inFlight.ID)}, guards...); err != nil {
+			return err
+		}
+		if err := risk.record(ctx); err != nil {
+			return err
+		}
+		payout.LockEntryID = lock.ID
+		if err := s.repo.CreatePayout(ctx, payout); err != nil {
+			return fmt.Errorf("failed to create payout: %w", err)
+		}
+		if err := s.audit.Record(ctx, models.AuditPayoutRequested, "payout", payout.ID, nil, payout); err != nil {
+			return err
+		}
+		return s.publish(ctx, wallet, payout)
+	})
+	if err != nil {
+		return nil, s.wallets.risk.recordBlocked(ctx, err)
+	}
+	return payout, nil
+}
+
+// GetPayout returns a payout
+func (s *PayoutService) GetPayout(ctx context.Context, payoutID int) (*models.Payout, error) {
+	payout, err := s.repo.GetPayoutByID(ctx, payoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout: %w", err)
+	}
+	if payout == nil {
+		return nil, ErrPayoutNotFound
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, payout.WalletID); err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// ListPayouts returns a wallet's payouts, newest first, optionally with one status
+func (s *PayoutService) ListPayouts(ctx context.Context, walletID int, status string, limit int) ([]models.Payout, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	payouts, err := s.repo.ListPayoutsByWalletID(ctx, walletID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payouts: %w", err)
+	}
+	if payouts == nil {
+		payouts = []models.Payout{}
+	}
+	return payouts, nil
+}
+
+// SubmitDue sends requested payouts to the provider and retries releases
+// that were refused; run by a background worker. Temporary provider errors
+// are retried until MaxAttempts, after which, like an outright rejection, the
+// payout fails and its funds are released.
+func (s *PayoutService) SubmitDue(ctx context.Context) error {
+	for i := 0; i < maxPayoutsPerTick; i++ {
+		var (
+			claimed bool
+			submit  *models.Payout
+		)
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := s.now().UTC()
+			payout, err := s.repo.ClaimDuePayout(ctx, now)
+			if err != nil {
+				return fmt.Errorf("failed to claim payout: %w", err)
+			}
+			if payout == nil {
+				return nil
+			}
+			claimed = true
+
+			if payout.Status == models.PayoutReleasePending {
+				before := *payout
+				payout.UpdatedAt = now
+				return s.finish(ctx, &before, payout, payout.PendingOutcome(), payout.FailureReason)
+			}
+
+			// The submission is committed before the provider is called, so a
+			// worker that stops before recording the result leaves the payout
+			// to be resent under the same idempotency key once the lease ends
+			payout.Status = models.PayoutSubmitting
+			payout.Attempts++
+			leaseUntil := now.Add(s.cfg.RetryInterval)
+			payout.NextAttemptAt = &leaseUntil
+			payout.UpdatedAt = now
+			if err := s.repo.UpdatePayout(ctx, payout); err != nil {
+				return fmt.Errorf("failed to update payout: %w", err)
+			}
+			submit = payout
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+		if submit != nil {
+			if err := s.submit(ctx, submit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// submit sends a payout claimed for submission to the provider and records
+// the result
+func (s *PayoutService) submit(ctx context.Context, claimed *models.Payout) error {
+	ref, submitErr := s.provider.Submit(ctx, claimed.IdempotencyKey(), claimed)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		payout, err := s.repo.GetPayoutByIDForUpdate(ctx, claimed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to lock payout: %w", err)
+		}
+		if payout == nil || payout.Status != models.PayoutSubmitting || payout.Attempts != claimed.Attempts {
+			// The lease ran out and another worker resent the payout
+			return nil
+		}
+		now := s.now().UTC()
+		before := *payout
+		payout.UpdatedAt = now
+
+		switch {
+		case submitErr == nil:
+			payout.Status = models.PayoutProcessing
+			payout.ProviderReference = ref
+			payout.NextAttemptAt = nil
+			if err := s.repo.UpdatePayout(ctx, payout); err != nil {
+				return fmt.Errorf("failed to update payout: %w", err)
+			}
+			return s.recordStatus(ctx, &before, payout)
+		case errors.Is(submitErr, ErrPayoutRejected) || payout.Attempts >= s.cfg.MaxAttempts:
+			log.Printf("payout %d: submission failed: %v", payout.ID, submitErr)
+			return s.finish(ctx, &before, payout, models.PayoutFailed, submitErr.Error())
+		default:
+			log.Printf("payout %d: submission attempt %d failed, will retry: %v", payout.ID, payout.Attempts, submitErr)
+			retryAt := now.Add(s.cfg.RetryInterval)
+			payout.Status = models.PayoutRequested
+			payout.NextAttemptAt = &retryAt
+			if err := s.repo.UpdatePayout(ctx, payout); err != nil {
+				return fmt.Errorf("failed to update payout: %w", err)
+			}
+			return nil
+		}
+	})
+}
+
+// HandleCallback applies a provider's report of a payout's outcome. Repeated
+// reports of the status a payout already has are accepted and ignored.
+func (s *PayoutService) HandleCallback(ctx context.Context, body []byte) (*models.Payout, error) {
+	cb, err := s.provider.ParseCallback(body)
+	if err != nil {
+		return nil, err
+	}
+	if cb.ProviderReference == "" {
+		return nil, fmt.Errorf("%w: provider_reference is required", ErrInvalidRequest)
+	}
+
+	var payout *models.Payout
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		payout, err = s.repo.GetPayoutByProviderReferenceForUpdate(ctx, s.provider.Name(), cb.ProviderReference)
+		if err != nil {
+			return fmt.Errorf("failed to lock payout: %w", err)
+		}
+		if payout == nil {
+			return ErrPayoutNotFound
+		}
+		if payout.Status == cb.Status || payout.Status == models.PayoutReleasePending && payout.PendingOutcome() == cb.Status {
+			return nil
+		}
+
+		var from string
+		switch cb.Status {
+		case models.PayoutSucceeded, models.PayoutFailed:
+			from = models.PayoutProcessing
+		case models.PayoutReturned:
+			from = models.PayoutSucceeded
+		default:
+			return fmt.Errorf("%w: unknown payout status %q", ErrInvalidRequest, cb.Status)
+		}
+		if payout.Status != from {
+			return fmt.Errorf("%w: payout %d is %s and cannot become %s", ErrConflict, payout.ID, payout.Status, cb.Status)
+		}
+		before := *payout
+		payout.UpdatedAt = s.now().UTC()
+		return s.finish(ctx, &before, payout, cb.Status, cb.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// finish posts the outcome of a payout and saves its new status. Success
+// moves the locked funds out to external clearing; failure releases them to
+// the wallet; a return brings them back from external clearing. When the
+// wallet cannot be credited the outcome is kept and the payout waits, release
+// pending, for the worker to retry the release.
+func (s *PayoutService) finish(ctx context.Context, before, payout *models.Payout, status, reason string) error {
+	inFlight, err := s.wallets.systemWallet(ctx, models.SystemPayoutsInFlight, payout.Currency)
+	if err != nil {
+		return err
+	}
+	clearing, err := s.wallets.systemWallet(ctx, models.SystemExternalClearing, payout.Currency)
+	if err != nil {
+		return err
+	}
+
+	var entry, counter *models.LedgerEntry
+	switch status {
+	case models.PayoutSucceeded:
+		entry = models.NewLedgerEntry(clearing.ID, payout.Reference, "credit", payout.Amount, 0, fmt.Sprintf("Payout %d paid", payout.ID))
+		counter = counterLeg(entry, inFlight.ID)
+	case models.PayoutFailed:
+		entry = models.NewLedgerEntry(payout.WalletID, payout.Reference, "credit", payout.Amount, 0, fmt.Sprintf("Payout %d failed", payout.ID))
+		counter = counterLeg(entry, inFlight.ID)
+	case models.PayoutReturned:
+		entry = models.NewLedgerEntry(payout.WalletID, payout.Reference, "credit", payout.Amount, 0, fmt.Sprintf("Payout %d returned", payout.ID))
+		counter = counterLeg(entry, clearing.ID)
+	default:
+		return fmt.Errorf("unknown payout outcome %q", status)
+	}
+
+	payout.FailureReason = reason
+	payout.NextAttemptAt = nil
+	_, err = s.wallets.postJournal(ctx, []*models.LedgerEntry{entry, counter})
+	switch {
+	case err == nil && status == models.PayoutSucceeded:
+		payout.Status = status
+		payout.FinalEntryID = &entry.ID
+	case err == nil:
+		payout.Status = status
+		payout.ReleaseEntryID = &entry.ID
+	case status != models.PayoutSucceeded && errors.Is(err, ErrWalletNotActive):
+		// Refused before anything was written. The payout must not be sent
+		// again, so only the release is retried.
+		retryAt := s.now().UTC().Add(s.cfg.RetryInterval)
+		log.Printf("payout %d: %s, release postponed to %s: %v", payout.ID, status, retryAt.Format(time.RFC3339), err)
+		payout.Status = models.PayoutReleasePending
+		payout.NextAttemptAt = &retryAt
+	default:
+		return err
+	}
+
+	if err := s.repo.UpdatePayout(ctx, payout); err != nil {
+		return fmt.Errorf("failed to update payout: %w", err)
+	}
+	if payout.Status == before.Status {
+		// A release refused again is not news
+		return nil
+	}
+	return s.recordStatus(ctx, before, payout)
+}
+
+// recordStatus audits a payout's status change and tells the wallet owner
+func (s *PayoutService) recordStatus(ctx context.Context, before, payout *models.Payout) error {
+	if err := s.audit.Record(ctx, models.AuditPayoutStatusChanged, "payout", payout.ID, before, payout); err != nil {
+		return err
+	}
+	wallet, err := s.wallets.repo.GetWalletByID(ctx, payout.WalletID)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if wallet == nil {
+		return ErrWalletNotFound
+	}
+	return s.publish(ctx, wallet, payout)
+}
+
+// publish queues a payout.updated webhook for the wallet owner
+func (s *PayoutService) publish(ctx context.Context, wallet *models.Wallet, payout *models.Payout) error {
+	if s.wallets.events == nil {
+		return nil
+	}
+	if err := s.wallets.events.Publish(ctx, wallet.UserID, models.EventPayoutUpdated, payout); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", models.EventPayoutUpdated, err)
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// startPayoutTest creates a payout service on provider, or the fake provider
+// when nil, and a GBP wallet holding 10000
+func startPayoutTest(t *testing.T, provider PayoutProvider) (*PayoutService, *fakePayoutRepository, *fakeWalletRepository, *fakeClock, int) {
+	t.Helper()
+	wallets := newFakeWalletRepository()
+	repo := newFakePayoutRepository()
+	if provider == nil {
+		provider = NewFakePayoutProvider()
+	}
+	clock := newFakeClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	svc := NewPayoutService(repo, NewWalletService(wallets, fakeTransactor{}).WithClock(clock.Now), fakeTransactor{}, nil, provider, PayoutConfig{
+		MaxAttempts:   3,
+		RetryInterval: time.Minute,
+	}).WithClock(clock.Now)
+	return svc, repo, wallets, clock, wallets.addWallet(1, "GBP", 10000).ID
+}
+
+// flakyPayoutProvider accepts payouts but loses the next failures responses,
+// as a provider that times out after taking the payout would
+type flakyPayoutProvider struct {
+	*FakePayoutProvider
+	failures int
+}
+
+func (p *flakyPayoutProvider) Submit(ctx context.Context, idempotencyKey string, payout *models.Payout) (string, error) {
+	ref, err := p.FakePayoutProvider.Submit(ctx, idempotencyKey, payout)
+	if err == nil && p.failures > 0 {
+		p.failures--
+		return "", errors.New("provider timed out")
+	}
+	return ref, err
+}
+
+func setWalletStatus(wallets *fakeWalletRepository, walletID int, status string) {
+	wallets.mu.Lock()
+	defer wallets.mu.Unlock()
+	wallets.wallets[walletID].Status = status
+}
+
+func requestPayout(svc *PayoutService, walletID int, amount int64, destination string) (*models.Payout, error) {
+	return svc.RequestPayout(context.Background(), dto.CreatePayoutRequest{
+		WalletID:    walletID,
+		Amount:      amount,
+		Reference:   1,
+		Destination: destination,
+		Description: "Withdrawal",
+	})
+}
+
+func payoutCallback(svc *PayoutService, ref, status, reason string) (*models.Payout, error) {
+	body := fmt.Sprintf(`{"provider_reference":%q,"status":%q,"reason":%q}`, ref, status, reason)
+	return svc.HandleCallback(context.Background(), []byte(body))
+}
+
+// payoutFunds returns the wallet, payouts in flight and external clearing balances
+func payoutFunds(wallets *fakeWalletRepository, walletID int) [3]int64 {
+	return [3]int64{
+		wallets.balance(walletID),
+		wallets.systemBalance(models.SystemPayoutsInFlight, "GBP"),
+		wallets.systemBalance(models.SystemExternalClearing, "GBP"),
+	}
+}
+
+func TestPayoutCallbacks(t *testing.T) {
+	type callback struct {
+		status  string
+		reason  string
+		wantErr error
+	}
+	tests := []struct {
+		name      string
+		callbacks []callback
+
+		wantStatus  string
+		wantFunds   [3]int64 // Wallet, payouts in flight, external clearing
+		wantFinal   bool
+		wantRelease bool
+	}{
+		{
+			name:       "processing",
+			wantStatus: models.PayoutProcessing,
+			wantFunds:  [3]int64{6000, 4000, 0},
+		},
+		{
+			name:       "succeeded",
+			callbacks:  []callback{{status: models.PayoutSucceeded}},
+			wantStatus: models.PayoutSucceeded,
+			wantFunds:  [3]int64{6000, 0, 4000},
+			wantFinal:  true,
+		},
+		{
+			name:       "success reported twice",
+			callbacks:  []callback{{status: models.PayoutSucceeded}, {status: models.PayoutSucceeded}},
+			wantStatus: models.PayoutSucceeded,
+			wantFunds:  [3]int64{6000, 0, 4000},
+			wantFinal:  true,
+		},
+		{
+			name:        "failed",
+			callbacks:   []callback{{status: models.PayoutFailed, reason: "account closed"}},
+			wantStatus:  models.PayoutFailed,
+			wantFunds:   [3]int64{10000, 0, 0},
+			wantRelease: true,
+		},
+		{
+			name:        "failure is final",
+			callbacks:   []callback{{status: models.PayoutFailed}, {status: models.PayoutReturned, wantErr: ErrConflict}},
+			wantStatus:  models.PayoutFailed,
+			wantFunds:   [3]int64{10000, 0, 0},
+			wantRelease: true,
+		},
+		{
+			name:       "returned before it succeeded",
+			callbacks:  []callback{{status: models.PayoutReturned, wantErr: ErrConflict}},
+			wantStatus: models.PayoutProcessing,
+			wantFunds:  [3]int64{6000, 4000, 0},
+		},
+		{
+			name:        "returned",
+			callbacks:   []callback{{status: models.PayoutSucceeded}, {status: models.PayoutReturned, reason: "beneficiary unknown"}},
+			wantStatus:  models.PayoutReturned,
+			wantFunds:   [3]int64{10000, 0, 0},
+			wantFinal:   true,
+			wantRelease: true,
+		},
+		{
+			name:       "unknown status",
+			callbacks:  []callback{{status: "settled", wantErr: ErrInvalidRequest}},
+			wantStatus: models.PayoutProcessing,
+			wantFunds:  [3]int64{6000, 4000, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakePayoutProvider()
+			svc, repo, wallets, _, walletID := startPayoutTest(t, provider)
+			ctx := context.Background()
+			requested, err := requestPayout(svc, walletID, 4000, "GB00TEST")
+			if err != nil {
+				t.Fatalf("RequestPayout: %v", err)
+			}
+			if err := svc.SubmitDue(ctx); err != nil {
+				t.Fatalf("SubmitDue: %v", err)
+			}
+			ref := provider.Submitted()[requested.ID]
+
+			for _, cb := range tt.callbacks {
+				if _, err := payoutCallback(svc, ref, cb.status, cb.reason); !errors.Is(err, cb.wantErr) {
+					t.Fatalf("%s callback: err = %v, want %v", cb.status, err, cb.wantErr)
+				}
+			}
+
+			payout, _ := repo.GetPayoutByID(ctx, requested.ID)
+			if payout.Status != tt.wantStatus || payout.ProviderReference != ref || payout.Attempts != 1 {
+				t.Errorf("payout = %s with reference %q after %d attempts, want %s with %q after 1", payout.Status, payout.ProviderReference, payout.Attempts, tt.wantStatus, ref)
+			}
+			if (payout.FinalEntryID != nil) != tt.wantFinal || (payout.ReleaseEntryID != nil) != tt.wantRelease {
+				t.Errorf("final entry %v, release entry %v, want final %t and release %t", payout.FinalEntryID, payout.ReleaseEntryID, tt.wantFinal, tt.wantRelease)
+			}
+			if got := payoutFunds(wallets, walletID); got != tt.wantFunds {
+				t.Errorf("funds = %v, want %v", got, tt.wantFunds)
+			}
+		})
+	}
+}
+
+func TestSubmitDueReleasesRejectedPayout(t *testing.T) {
+	provider := NewFakePayoutProvider()
+	svc, repo, wallets, _, walletID := startPayoutTest(t, provider)
+	provider.Reject("GB00BAD", "invalid account")
+	requested, err := requestPayout(svc, walletID, 4000, "GB00BAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SubmitDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	payout, _ := repo.GetPayoutByID(context.Background(), requested.ID)
+	if payout.Status != models.PayoutFailed || payout.ReleaseEntryID == nil || payout.FailureReason == "" {
+		t.Errorf("payout = %s, release entry %v, reason %q, want failed and released with a reason", payout.Status, payout.ReleaseEntryID, payout.FailureReason)
+	}
+	if got, want := payoutFunds(wallets, walletID), [3]int64{10000, 0, 0}; got != want {
+		t.Errorf("funds = %v, want %v", got, want)
+	}
+}
+
+func TestPayoutReleaseWaitsForFrozenWallet(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		callbacks   []string
+
+		wantStatus  string
+		wantPending [3]int64 // Wallet, payouts in flight, external clearing
+	}{
+		{
+			name:        "rejected submission",
+			destination: "GB00BAD",
+			wantStatus:  models.PayoutFailed,
+			wantPending: [3]int64{6000, 4000, 0},
+		},
+		{
+			name:        "failed",
+			destination: "GB00TEST",
+			callbacks:   []string{models.PayoutFailed},
+			wantStatus:  models.PayoutFailed,
+			wantPending: [3]int64{6000, 4000, 0},
+		},
+		{
+			name:        "returned",
+			destination: "GB00TEST",
+			callbacks:   []string{models.PayoutSucceeded, models.PayoutReturned},
+			wantStatus:  models.PayoutReturned,
+			wantPending: [3]int64{6000, 0, 4000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakePayoutProvider()
+			provider.Reject("GB00BAD", "invalid account")
+			svc, repo, wallets, clock, walletID := startPayoutTest(t, provider)
+			ctx := context.Background()
+			requested, err := requestPayout(svc, walletID, 4000, tt.destination)
+			if err != nil {
+				t.Fatal(err)
+			}
+			setWalletStatus(wallets, walletID, models.WalletStatusFrozen)
+			if err := svc.SubmitDue(ctx); err != nil {
+				t.Fatalf("SubmitDue: %v", err)
+			}
+			ref := provider.Submitted()[requested.ID]
+			for _, status := range tt.callbacks {
+				if _, err := payoutCallback(svc, ref, status, ""); err != nil {
+					t.Fatalf("%s callback: %v", status, err)
+				}
+			}
+			// A repeated report of the outcome is not an error while the release waits
+			if len(tt.callbacks) > 0 {
+				if _, err := payoutCallback(svc, ref, tt.wantStatus, ""); err != nil {
+					t.Fatalf("repeated %s callback: %v", tt.wantStatus, err)
+				}
+			}
+
+			payout, _ := repo.GetPayoutByID(ctx, requested.ID)
+			if payout.Status != models.PayoutReleasePending || payout.ReleaseEntryID != nil || payout.NextAttemptAt == nil {
+				t.Fatalf("payout = %s, release entry %v, next attempt %v, want release pending with a retry", payout.Status, payout.ReleaseEntryID, payout.NextAttemptAt)
+			}
+			if got := payoutFunds(wallets, walletID); got != tt.wantPending {
+				t.Errorf("funds = %v, want %v while frozen", got, tt.wantPending)
+			}
+
+			// Retries only try the release again; the payout is never resent
+			clock.Advance(time.Minute)
+			if err := svc.SubmitDue(ctx); err != nil {
+				t.Fatalf("SubmitDue while frozen: %v", err)
+			}
+			setWalletStatus(wallets, walletID, models.WalletStatusActive)
+			clock.Advance(time.Minute)
+			if err := svc.SubmitDue(ctx); err != nil {
+				t.Fatalf("SubmitDue after unfreezing: %v", err)
+			}
+
+			payout, _ = repo.GetPayoutByID(ctx, requested.ID)
+			if payout.Status != tt.wantStatus || payout.ReleaseEntryID == nil || payout.NextAttemptAt != nil || payout.Attempts != 1 {
+				t.Errorf("payout = %s, release entry %v, next attempt %v, %d attempts, want %s and released after 1 attempt", payout.Status, payout.ReleaseEntryID, payout.NextAttemptAt, payout.Attempts, tt.wantStatus)
+			}
+			if got, want := payoutFunds(wallets, walletID), [3]int64{10000, 0, 0}; got != want {
+				t.Errorf("funds = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSubmitDueResendsUnderTheSameKey(t *testing.T) {
+	provider := &flakyPayoutProvider{FakePayoutProvider: NewFakePayoutProvider(), failures: 1}
+	svc, repo, _, clock, walletID := startPayoutTest(t, provider)
+	ctx := context.Background()
+	requested, err := requestPayout(svc, walletID, 4000, "GB00TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SubmitDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	payout, _ := repo.GetPayoutByID(ctx, requested.ID)
+	if payout.Status != models.PayoutRequested || payout.ProviderReference != "" {
+		t.Fatalf("payout = %s with reference %q, want requested again without one", payout.Status, payout.ProviderReference)
+	}
+
+	clock.Advance(time.Minute)
+	if err := svc.SubmitDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	payout, _ = repo.GetPayoutByID(ctx, requested.ID)
+	ref := provider.Submitted()[requested.ID]
+	if payout.Status != models.PayoutProcessing || payout.ProviderReference != ref || payout.Attempts != 2 {
+		t.Errorf("payout = %s with reference %q after %d attempts, want processing with %q after 2", payout.Status, payout.ProviderReference, payout.Attempts, ref)
+	}
+	if got := len(provider.Submitted()); got != 1 {
+		t.Errorf("provider took %d payouts, want 1", got)
+	}
+}
+
+func TestSubmitDueResumesInterruptedSubmission(t *testing.T) {
+	svc, repo, _, clock, walletID := startPayoutTest(t, nil)
+	ctx := context.Background()
+	requested, err := requestPayout(svc, walletID, 4000, "GB00TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A worker claimed the payout and stopped before recording the result
+	leaseUntil := clock.Now().Add(time.Minute)
+	repo.payouts[requested.ID].Status = models.PayoutSubmitting
+	repo.payouts[requested.ID].Attempts = 1
+	repo.payouts[requested.ID].NextAttemptAt = &leaseUntil
+
+	if err := svc.SubmitDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if payout, _ := repo.GetPayoutByID(ctx, requested.ID); payout.Status != models.PayoutSubmitting {
+		t.Fatalf("payout = %s, want submitting until the lease ends", payout.Status)
+	}
+	clock.Advance(time.Minute)
+	if err := svc.SubmitDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if payout, _ := repo.GetPayoutByID(ctx, requested.ID); payout.Status != models.PayoutProcessing || payout.Attempts != 2 {
+		t.Errorf("payout = %s after %d attempts, want processing after 2", payout.Status, payout.Attempts)
+	}
+}
+
+func TestHandleCallbackForUnknownPayout(t *testing.T) {
+	svc, _, _, _, _ := startPayoutTest(t, nil)
+	if _, err := payoutCallback(svc, "fake-999", models.PayoutSucceeded, ""); !errors.Is(err, ErrPayoutNotFound) {
+		t.Errorf("err = %v, want ErrPayoutNotFound", err)
+	}
+}
+
+func TestRequestPayoutNeedsFunds(t *testing.T) {
+	svc, repo, wallets, _, walletID := startPayoutTest(t, nil)
+	if _, err := requestPayout(svc, walletID, 10001, "GB00TEST"); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("err = %v, want ErrInsufficientFunds", err)
+	}
+	if payouts, _ := repo.ListPayoutsByWalletID(context.Background(), walletID, "", 10); len(payouts) != 0 {
+		t.Errorf("payouts = %v, want none", payouts)
+	}
+	if got, want := payoutFunds(wallets, walletID), [3]int64{10000, 0, 0}; got != want {
+		t.Errorf("funds = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+const (
+	// maxPocketsPerWallet bounds the open pockets one wallet can have
+	maxPocketsPerWallet = 20
+	// pocketMovementsShown is how many movements a pocket response lists
+	pocketMovementsShown = 50
+	pocketDateFmt        = "2006-01-02"
+)
+
+// PocketService manages pockets: named sub-balances that set money aside
+// inside a wallet. Moving money between the main balance and a pocket is an
+// internal transfer that leaves wallets.balance and the ledger untouched.
+// Keeping pocket money out of debits, and the auto-sweep rules, are part of
+// WalletService.
+type PocketService struct {
+	repo    repositories.PocketRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	now     func() time.Time
+}
+
+// NewPocketService creates a new pocket service
+func NewPocketService(repo repositories.PocketRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService) *PocketService {
+	return &PocketService{repo: repo, wallets: wallets, tx: tx, audit: audit, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *PocketService) WithClock(now func() time.Time) *PocketService {
+	s.now = now
+	return s
+}
+
+// CreatePocket opens an empty pocket in a wallet
+func (s *PocketService) CreatePocket(ctx context.Context, walletID int, req dto.PocketRequest) (*models.Pocket, error) {
+	now := s.now().UTC()
+	pocket := &models.Pocket{Status: models.PocketOpen, CreatedAt: now, UpdatedAt: now}
+	if err := applyPocketRequest(pocket, req); err != nil {
+		return nil, err
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if pocket.TargetDate != nil && pocket.TargetDate.Before(today) {
+		return nil, fmt.Errorf("%w: target_date cannot be in the past", ErrInvalidRequest)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot have pockets", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+	pocket.WalletID = wallet.ID
+	pocket.CreatedByType, pocket.CreatedByID = actor(ctx)
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// The wallet lock serialises pocket changes within a wallet
+		locked, err := s.wallets.repo.GetWalletByIDForUpdate(ctx, wallet.ID)
+		if err != nil {
+			return fmt.Errorf("failed to lock wallet: %w", err)
+		}
+		if locked.Status == models.WalletStatusClosed {
+			return fmt.Errorf("%w: wallet %d is closed", ErrWalletNotActive, wallet.ID)
+		}
+		open, err := s.repo.ListPockets(ctx, wallet.ID, models.PocketOpen)
+		if err != nil {
+			return fmt.Errorf("failed to list pockets: %w", err)
+		}
+		if len(open) >= maxPocketsPerWallet {
+			return fmt.Errorf("%w: wallet %d already has %d pockets", ErrConflict, wallet.ID, maxPocketsPerWallet)
+		}
+		if err := requireUniquePocketName(open, pocket); err != nil {
+			return err
+		}
+		if err := s.repo.CreatePocket(ctx, pocket); err != nil {
+			return fmt.Errorf("failed to create pocket: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditPocketCreated, "pocket", pocket.ID, nil, pocket)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pocket, nil
+}
+
+// UpdatePocket replaces an open pocket's name, targets and auto-sweep rule
+func (s *PocketService) UpdatePocket(ctx context.Context, pocketID int, req dto.PocketRequest) (*models.Pocket, error) {
+	pocket, err := s.getAuthorizedPocket(ctx, pocketID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, pocket, err = s.lockPocket(ctx, pocket); err != nil {
+			return err
+		}
+		before := *pocket
+		if err := applyPocketRequest(pocket, req); err != nil {
+			return err
+		}
+		open, err := s.repo.ListPockets(ctx, pocket.WalletID, models.PocketOpen)
+		if err != nil {
+			return fmt.Errorf("failed to list pockets: %w", err)
+		}
+		if err := requireUniquePocketName(open, pocket); err != nil {
+			return err
+		}
+		pocket.UpdatedAt = s.now().UTC()
+		if err := s.repo.UpdatePocket(ctx, pocket); err != nil {
+			return fmt.Errorf("failed to update pocket: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditPocketUpdated, "pocket", pocket.ID, &before, pocket)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pocket, nil
+}
+
+// Deposit moves money from a wallet's main balance into one of its pockets
+func (s *PocketService) Deposit(ctx context.Context, pocketID int, req dto.PocketMoveRequest) (*dto.PocketMoveResponse, error) {
+	return s.move(ctx, pocketID, models.PocketMovementDeposit, req)
+}
+
+// Withdraw moves money from a pocket back to its wallet's main balance
+func (s *PocketService) Withdraw(ctx context.Context, pocketID int, req dto.PocketMoveRequest) (*dto.PocketMoveResponse, error) {
+	return s.move(ctx, pocketID, models.PocketMovementWithdrawal, req)
+}
+
+func (s *PocketService) move(ctx context.Context, pocketID int, movementType string, req dto.PocketMoveRequest) (*dto.PocketMoveResponse, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	pocket, err := s.getAuthorizedPocket(ctx, pocketID)
+	if err != nil {
+		return nil, err
+	}
+	var resp *dto.PocketMoveResponse
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		wallet, pocket, err := s.lockPocket(ctx, pocket)
+		if err != nil {
+			return err
+		}
+		if wallet.Status != models.WalletStatusActive {
+			return fmt.Errorf("%w: wallet %d is %s", ErrWalletNotActive, wallet.ID, wallet.Status)
+		}
+		before := *pocket
+		main, _, _, err := s.wallets.balanceBreakdown(ctx, wallet)
+		if err != nil {
+			return err
+		}
+		switch movementType {
+		case models.PocketMovementDeposit:
+			if main < req.Amount {
+				return fmt.Errorf("%w: wallet %d has %d in its main balance, needs %d", ErrInsufficientFunds, wallet.ID, main, req.Amount)
+			}
+			pocket.Balance, main = pocket.Balance+req.Amount, main-req.Amount
+		case models.PocketMovementWithdrawal:
+			if pocket.Balance < req.Amount {
+				return fmt.Errorf("%w: pocket %d has %d, needs %d", ErrInsufficientFunds, pocket.ID, pocket.Balance, req.Amount)
+			}
+			pocket.Balance, main = pocket.Balance-req.Amount, main+req.Amount
+		}
+		pocket.UpdatedAt = s.now().UTC()
+		if err := s.repo.UpdatePocket(ctx, pocket); err != nil {
+			return fmt.Errorf("failed to update pocket: %w", err)
+		}
+		movement, err := s.recordMovement(ctx, pocket, movementType, req.Amount, req.Description)
+		if err != nil {
+			return err
+		}
+		resp = &dto.PocketMoveResponse{Pocket: *pocket, Movement: *movement, Main: main}
+		action := models.AuditPocketDeposited
+		if movementType == models.PocketMovementWithdrawal {
+			action = models.AuditPocketWithdrawn
+		}
+		return s.audit.Record(ctx, action, "pocket", pocket.ID, &before, resp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ClosePocket moves whatever a pocket holds back to the main balance and
+// closes it
+func (s *PocketService) ClosePocket(ctx context.Context, pocketID int) (*models.Pocket, error) {
+	pocket, err := s.getAuthorizedPocket(ctx, pocketID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var wallet *models.Wallet
+		if wallet, pocket, err = s.lockPocket(ctx, pocket); err != nil {
+			return err
+		}
+		before := *pocket
+		now := s.now().UTC()
+		if amount := pocket.Balance; amount > 0 {
+			if wallet.Status != models.WalletStatusActive {
+				return fmt.Errorf("%w: wallet %d is %s", ErrWalletNotActive, wallet.ID, wallet.Status)
+			}
+			main, _, _, err := s.wallets.balanceBreakdown(ctx, wallet)
+			if err != nil {
+				return err
+			}
+			pocket.Balance = 0
+			movement, err := s.recordMovement(ctx, pocket, models.PocketMovementWithdrawal, amount, "Pocket closed")
+			if err != nil {
+				return err
+			}
+			moved := dto.PocketMoveResponse{Pocket: *pocket, Movement: *movement, Main: main + amount}
+			if err := s.audit.Record(ctx, models.AuditPocketWithdrawn, "pocket", pocket.ID, &before, moved); err != nil {
+				return err
+			}
+		}
+		pocket.Status, pocket.UpdatedAt, pocket.ClosedAt = models.PocketClosed, now, &now
+		if err := s.repo.UpdatePocket(ctx, pocket); err != nil {
+			return fmt.Errorf("failed to update pocket: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditPocketClosed, "pocket", pocket.ID, &before, pocket)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pocket, nil
+}
+
+// ListPockets returns a wallet's balance split into its main balance and
+// its pockets, optionally narrowed to a status
+func (s *PocketService) ListPockets(ctx context.Context, walletID int, status string) (*dto.PocketsResponse, error) {
+	switch status {
+	case "", models.PocketOpen, models.PocketClosed:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidRequest, status)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	pockets, err := s.repo.ListPockets(ctx, wallet.ID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pockets: %w", err)
+	}
+	main, _, _, err := s.wallets.balanceBreakdown(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PocketsResponse{
+		WalletID: wallet.ID,
+		Currency: wallet.Currency,
+		Balance:  wallet.Balance,
+		Main:     main,
+		Pockets:  pockets,
+	}, nil
+}
+
+// GetPocket returns a pocket and its latest movements
+func (s *PocketService) GetPocket(ctx context.Context, pocketID int) (*dto.PocketResponse, error) {
+	pocket, err := s.getAuthorizedPocket(ctx, pocketID)
+	if err != nil {
+		return nil, err
+	}
+	movements, err := s.repo.ListMovements(ctx, pocket.ID, pocketMovementsShown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pocket movements: %w", err)
+	}
+	return &dto.PocketResponse{Pocket: *pocket, Movements: movements}, nil
+}
+
+// getAuthorizedPocket returns a pocket the caller may access through its wallet
+func (s *PocketService) getAuthorizedPocket(ctx context.Context, pocketID int) (*models.Pocket, error) {
+	pocket, err := s.repo.GetPocketByID(ctx, pocketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pocket: %w", err)
+	}
+	if pocket == nil {
+		return nil, ErrPocketNotFound
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, pocket.WalletID); err != nil {
+		return nil, err
+	}
+	return pocket, nil
+}
+
+// lockPocket locks an open pocket's wallet and then the pocket, the order
+// postings lock them in
+func (s *PocketService) lockPocket(ctx context.Context, pocket *models.Pocket) (*models.Wallet, *models.Pocket, error) {
+	wallet, err := s.wallets.repo.GetWalletByIDForUpdate(ctx, pocket.WalletID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock wallet: %w", err)
+	}
+	locked, err := s.repo.GetPocketByIDForUpdate(ctx, pocket.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock pocket: %w", err)
+	}
+	if locked == nil {
+		return nil, nil, ErrPocketNotFound
+	}
+	if locked.Status != models.PocketOpen {
+		return nil, nil, fmt.Errorf("%w: pocket %d is %s", ErrConflict, locked.ID, locked.Status)
+	}
+	return wallet, locked, nil
+}
+
+func (s *PocketService) recordMovement(ctx context.Context, pocket *models.Pocket, movementType string, amount int64, description string) (*models.PocketMovement, error) {
+	movement := &models.PocketMovement{
+		PocketID:    pocket.ID,
+		Type:        movementType,
+		Amount:      amount,
+		Balance:     pocket.Balance,
+		Description: description,
+		CreatedAt:   s.now().UTC(),
+	}
+	movement.CreatedByType, movement.CreatedByID = actor(ctx)
+	if err := s.repo.CreateMovement(ctx, movement); err != nil {
+		return nil, fmt.Errorf("failed to record pocket movement: %w", err)
+	}
+	return movement, nil
+}
+
+// applyPocketRequest validates req and copies it onto pocket
+func applyPocketRequest(pocket *models.Pocket, req dto.PocketRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return fmt.Errorf("%w: name must be 1 to 100 characters", ErrInvalidRequest)
+	}
+	if req.TargetAmount != nil && *req.TargetAmount <= 0 {
+		return fmt.Errorf("%w: target_amount must be positive", ErrInvalidRequest)
+	}
+	var targetDate *time.Time
+	if req.TargetDate != "" {
+		day, err := time.Parse(pocketDateFmt, req.TargetDate)
+		if err != nil {
+			return fmt.Errorf("%w: target_date must be a YYYY-MM-DD date", ErrInvalidRequest)
+		}
+		targetDate = &day
+	}
+	if sweep := req.Sweep; sweep != nil {
+		switch sweep.Kind {
+		case models.PocketSweepRoundUp:
+			if sweep.Multiple <= 0 {
+				return fmt.Errorf("%w: sweep.multiple must be positive", ErrInvalidRequest)
+			}
+			sweep.PercentageBPS = 0
+		case models.PocketSweepCreditPercent:
+			if sweep.PercentageBPS <= 0 || sweep.PercentageBPS > 10000 {
+				return fmt.Errorf("%w: sweep.percentage_bps must be between 1 and 10000", ErrInvalidRequest)
+			}
+			sweep.Multiple = 0
+		default:
+			return fmt.Errorf("%w: sweep.kind must be %q or %q", ErrInvalidRequest, models.PocketSweepRoundUp, models.PocketSweepCreditPercent)
+		}
+	}
+	pocket.Name, pocket.TargetAmount, pocket.TargetDate, pocket.Sweep = name, req.TargetAmount, targetDate, req.Sweep
+	return nil
+}
+
+// requireUniquePocketName rejects pocket when another of the wallet's open
+// pockets has its name
+func requireUniquePocketName(open []models.Pocket, pocket *models.Pocket) error {
+	for _, other := range open {
+		if other.ID != pocket.ID && strings.EqualFold(other.Name, pocket.Name) {
+			return fmt.Errorf("%w: wallet already has a pocket named %q", ErrConflict, other.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// pocketedTotal returns how much of a wallet's balance is set aside in its
+// open pockets
+func (s *WalletService) pocketedTotal(ctx context.Context, walletID int) (int64, error) {
+	if s.pockets == nil {
+		return 0, nil
+	}
+	total, err := s.pockets.PocketedTotal(ctx, walletID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pocket totals: %w", err)
+	}
+	return total, nil
+}
+
+// sweepPockets runs the auto-sweep rules of a wallet's open pockets on a
+// posted entry, oldest pocket first. wallet is the wallet after the posting,
+// still locked by it. Sweeps stop at a pocket's target and never take the
+// main balance, the cash outside credit lots and pockets, below zero.
+func (s *WalletService) sweepPockets(ctx context.Context, wallet *models.Wallet, entry *models.LedgerEntry) error {
+	if s.pockets == nil {
+		return nil
+	}
+	pockets, err := s.pockets.ListOpenPocketsForUpdate(ctx, wallet.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock pockets: %w", err)
+	}
+	main, _, _, err := s.balanceBreakdown(ctx, wallet)
+	if err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	for _, pocket := range pockets {
+		amount := min(pocket.Sweep.Amount(entry.Type, entry.Amount), main)
+		if pocket.TargetAmount != nil {
+			amount = min(amount, *pocket.TargetAmount-pocket.Balance)
+		}
+		if amount <= 0 {
+			continue
+		}
+		pocket.Balance += amount
+		pocket.UpdatedAt = now
+		main -= amount
+		if err := s.pockets.UpdatePocket(ctx, pocket); err != nil {
+			return fmt.Errorf("failed to update pocket: %w", err)
+		}
+		entryID := entry.ID
+		movement := &models.PocketMovement{
+			PocketID:      pocket.ID,
+			Type:          models.PocketMovementSweep,
+			Amount:        amount,
+			Balance:       pocket.Balance,
+			JournalID:     entry.JournalID,
+			LedgerEntryID: &entryID,
+			Description:   "Auto-sweep: " + pocket.Sweep.Kind,
+			CreatedAt:     now,
+		}
+		movement.CreatedByType, movement.CreatedByID = actor(ctx)
+		if err := s.pockets.CreateMovement(ctx, movement); err != nil {
+			return fmt.Errorf("failed to record pocket movement: %w", err)
+		}
+	}
+	return nil
+}
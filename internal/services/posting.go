@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// postedJournal is the outcome of postJournal
+type postedJournal struct {
+	ID     string
+	Legs   []*models.LedgerEntry
+	Before map[int]*models.Wallet // Wallets as locked, before the journal was applied
+	After  map[int]*models.Wallet // Wallets after the journal was applied
+}
+
+// postJournal applies a set of ledger entries ("legs") as one double-entry
+// journal. It must run inside a transaction. The legs' signed amounts must sum
+// to zero per currency. Wallets are locked in ascending ID order so concurrent
+// journals touching the same wallets cannot deadlock. Guards run once every
+// wallet is locked, before anything is written, so an error from a guard (or
+// from the status and pocket checks) leaves the surrounding transaction
+// usable. What the journal takes out of each customer wallet is paid from
+// its credit lots and main balance (see planDebit) as a debit transaction,
+// and can never dip into the money set aside in the wallet's pockets.
+func (s *WalletService) postJournal(ctx context.Context, legs []*models.LedgerEntry, guards ...journalGuard) (*postedJournal, error) {
+	return s.postJournalAs(ctx, FeeTxnDebit, legs, guards...)
+}
+
+// postJournalAs is postJournal for a journal whose debits are spent as a
+// spend transaction, which decides the credit lots that can pay for them, or
+// spendCashOnly to leave credit lots untouched
+func (s *WalletService) postJournalAs(ctx context.Context, spend string, legs []*models.LedgerEntry, guards ...journalGuard) (*postedJournal, error) {
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("%w: journal has no entries", ErrInvalidRequest)
+	}
+
+	walletIDs := make([]int, 0, len(legs))
+	seen := map[int]bool{}
+	for _, leg := range legs {
+		if leg.Amount <= 0 {
+			return nil, fmt.Errorf("%w: ledger entry amounts must be positive", ErrInvalidRequest)
+		}
+		if !seen[leg.WalletID] {
+			seen[leg.WalletID] = true
+			walletIDs = append(walletIDs, leg.WalletID)
+		}
+	}
+	sort.Ints(walletIDs)
+
+	posted := &postedJournal{
+		ID:     uuid.NewString(),
+		Legs:   legs,
+		Before: make(map[int]*models.Wallet, len(walletIDs)),
+		After:  make(map[int]*models.Wallet, len(walletIDs)),
+	}
+	balances := make(map[int]int64, len(walletIDs))
+	heads := make(map[int]string, len(walletIDs))
+	for _, id := range walletIDs {
+		wallet, err := s.repo.GetWalletByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock wallet: %w", err)
+		}
+		if wallet == nil {
+			return nil, fmt.Errorf("%w: %d", ErrWalletNotFound, id)
+		}
+		if wallet.Status != models.WalletStatusActive {
+			return nil, fmt.Errorf("%w: wallet %d is %s", ErrWalletNotActive, wallet.ID, wallet.Status)
+		}
+		head, err := s.repo.GetLatestLedgerEntry(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ledger chain head: %w", err)
+		}
+		if head != nil {
+			heads[id] = head.Hash
+		}
+		posted.Before[id] = wallet
+		balances[id] = wallet.Balance
+	}
+
+	for _, guard := range guards {
+		if err := guard(ctx, posted.Before); err != nil {
+			return nil, err
+		}
+	}
+	debits := journalDebits(legs)
+	for _, id := range walletIDs {
+		if wallet := posted.Before[id]; debits[id] != nil && !wallet.IsSystem() {
+			if err := s.planDebit(ctx, wallet, debits[id], spend); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	net := map[string]int64{}
+	for _, leg := range legs {
+		net[posted.Before[leg.WalletID].Currency] += leg.SignedAmount()
+	}
+	for currency, sum := range net {
+		if sum != 0 {
+			return nil, fmt.Errorf("unbalanced journal: %s legs sum to %d", currency, sum)
+		}
+	}
+
+	for _, leg := range legs {
+		leg.JournalID = posted.ID
+		if err := s.repo.UpdateWalletBalance(ctx, leg.WalletID, leg.SignedAmount()); err != nil {
+			return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+		}
+		balances[leg.WalletID] += leg.SignedAmount()
+
+		// Chain the entry to the wallet's previous entry
+		leg.Balance = balances[leg.WalletID]
+		leg.PrevHash = heads[leg.WalletID]
+		leg.Hash = leg.ComputeHash()
+		if err := s.repo.CreateLedgerEntry(ctx, leg); err != nil {
+			return nil, fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+		heads[leg.WalletID] = leg.Hash
+	}
+	for _, id := range walletIDs {
+		if debit := debits[id]; debit != nil {
+			if err := s.recordLotTakes(ctx, debit); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, id := range walletIDs {
+		wallet, err := s.repo.GetWalletByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get updated wallet: %w", err)
+		}
+		posted.After[id] = wallet
+	}
+	for _, leg := range legs {
+		if wallet := posted.After[leg.WalletID]; !wallet.IsSystem() {
+			if err := s.publishBalanceEvent(ctx, wallet, leg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return posted, nil
+}
+
+// journalDebits returns what a journal takes out of each wallet, net of what
+// it pays in, for the wallets it takes money out of
+func journalDebits(legs []*models.LedgerEntry) map[int]*walletDebit {
+	net := map[int]int64{}
+	first := map[int]*models.LedgerEntry{}
+	for _, leg := range legs {
+		net[leg.WalletID] -= leg.SignedAmount()
+		if leg.Type == "debit" && first[leg.WalletID] == nil {
+			first[leg.WalletID] = leg
+		}
+	}
+	debits := map[int]*walletDebit{}
+	for walletID, total := range net {
+		if total > 0 {
+			debits[walletID] = &walletDebit{entry: first[walletID], total: total}
+		}
+	}
+	return debits
+}
+
+// journalGuard checks a journal against its locked wallets before it is posted
+type journalGuard func(ctx context.Context, locked map[int]*models.Wallet) error
+
+// requireFunds rejects the journal when walletID holds less than amount
+func requireFunds(walletID int, amount int64) journalGuard {
+	return func(_ context.Context, locked map[int]*models.Wallet) error {
+		if wallet := locked[walletID]; wallet.Balance < amount {
+			return fmt.Errorf("%w: wallet %d has %d, needs %d", ErrInsufficientFunds, walletID, wallet.Balance, amount)
+		}
+		return nil
+	}
+}
+
+// systemWallet returns the system account for code in the given currency
+func (s *WalletService) systemWallet(ctx context.Context, code, currency string) (*models.Wallet, error) {
+	unit, err := s.currencies.Lookup(currency)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := s.repo.GetOrCreateSystemWallet(ctx, code, currency, unit.AssetType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s system account: %w", code, err)
+	}
+	return wallet, nil
+}
+
+// feeLegs returns the legs charging fee and its taxes to wallet. The fee
+// debit comes first; the net fee goes to the fee revenue account and each tax
+// to the tax payable account. taxLegs holds the tax payable credit for each
+// entry in fee.Taxes.
+func (s *WalletService) feeLegs(ctx context.Context, wallet *models.Wallet, reference int, fee *dto.FeeBreakdown) (legs, taxLegs []*models.LedgerEntry, err error) {
+	revenue, err := s.systemWallet(ctx, models.SystemFeeRevenue, wallet.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	description := "Fee"
+	if fee.Rule != "" {
+		description += ": " + fee.Rule
+	}
+	charge := models.NewLedgerEntry(wallet.ID, reference, "debit", fee.Total, 0, description)
+	legs = []*models.LedgerEntry{charge}
+	if len(fee.Taxes) == 0 {
+		return append(legs, counterLeg(charge, revenue.ID)), nil, nil
+	}
+
+	taxPayable, err := s.systemWallet(ctx, models.SystemTaxPayable, wallet.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	net := fee.Total
+	for _, tax := range fee.Taxes {
+		taxDescription := fmt.Sprintf("%s (%s) on fee", tax.Name, tax.Jurisdiction)
+		if tax.Inclusive {
+			net -= tax.Amount
+		} else {
+			legs = append(legs, models.NewLedgerEntry(wallet.ID, reference, "debit", tax.Amount, 0, taxDescription))
+		}
+		credit := models.NewLedgerEntry(taxPayable.ID, reference, "credit", tax.Amount, 0, taxDescription)
+		legs = append(legs, credit)
+		taxLegs = append(taxLegs, credit)
+	}
+	if net > 0 {
+		legs = append(legs, models.NewLedgerEntry(revenue.ID, reference, "credit", net, 0, description))
+	}
+	return legs, taxLegs, nil
+}
+
+// counterLeg returns the entry that balances leg against another wallet
+func counterLeg(leg *models.LedgerEntry, walletID int) *models.LedgerEntry {
+	entryType := "credit"
+	if leg.Type == "credit" {
+		entryType = "debit"
+	}
+	return models.NewLedgerEntry(walletID, leg.Reference, entryType, leg.Amount, 0, leg.Description)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
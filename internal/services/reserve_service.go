@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+const (
+	// maxReservesPerTick bounds the work one worker tick does
+	maxReservesPerTick = 100
+	// reserveReleaseRetryDelay postpones a timed release the ledger refused,
+	// e.g. because the merchant's wallet is frozen
+	reserveReleaseRetryDelay = time.Hour
+)
+
+// ReserveService holds back part of each merchant settlement in the reserve
+// system account under the merchant's rolling reserve policy, and returns it
+// to the merchant's wallet when the hold matures or an operator releases it
+type ReserveService struct {
+	repo    repositories.ReserveRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	now     func() time.Time
+}
+
+// NewReserveService creates a new reserve service
+func NewReserveService(repo repositories.ReserveRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService) *ReserveService {
+	return &ReserveService{repo: repo, wallets: wallets, tx: tx, audit: audit, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *ReserveService) WithClock(now func() time.Time) *ReserveService {
+	s.now = now
+	return s
+}
+
+// GetPolicy returns a merchant wallet's reserve policy; a wallet without one
+// gets a zero policy
+func (s *ReserveService) GetPolicy(ctx context.Context, walletID int) (*models.ReservePolicy, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	policy, err := s.repo.GetPolicy(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserve policy: %w", err)
+	}
+	if policy == nil {
+		return &models.ReservePolicy{WalletID: walletID}, nil
+	}
+	return policy, nil
+}
+
+// SetPolicy sets or, with a zero percentage, removes a merchant's reserve
+// policy. It applies to settlements from now on; existing reserves keep
+// their release times.
+func (s *ReserveService) SetPolicy(ctx context.Context, walletID int, req dto.SetReservePolicyRequest) (*models.ReservePolicy, error) {
+	if req.PercentageBPS < 0 || req.PercentageBPS > 10000 {
+		return nil, fmt.Errorf("%w: percentage_bps must be between 0 and 10000", ErrInvalidRequest)
+	}
+	if req.PercentageBPS > 0 && req.HoldDays <= 0 {
+		return nil, fmt.Errorf("%w: hold_days must be positive", ErrInvalidRequest)
+	}
+	if req.HoldDays < 0 || req.Cap < 0 {
+		return nil, fmt.Errorf("%w: hold_days and cap cannot be negative", ErrInvalidRequest)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot have reserves", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+
+	policy := &models.ReservePolicy{
+		WalletID:      walletID,
+		PercentageBPS: req.PercentageBPS,
+		HoldDays:      req.HoldDays,
+		Cap:           req.Cap,
+		UpdatedAt:     s.now().UTC(),
+	}
+	policy.UpdatedByType, policy.UpdatedByID = actor(ctx)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetPolicy(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to get reserve policy: %w", err)
+		}
+		if policy.PercentageBPS == 0 {
+			err = s.repo.DeletePolicy(ctx, walletID)
+		} else {
+			err = s.repo.UpsertPolicy(ctx, policy)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save reserve policy: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditReservePolicy, "wallet", walletID, before, policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// CreateReserve holds back funds from a merchant's wallet by hand
+func (s *ReserveService) CreateReserve(ctx context.Context, req dto.CreateReserveRequest) (*dto.ReserveResponse, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, req.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot have reserves", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	reserve := &models.Reserve{
+		WalletID:  wallet.ID,
+		Currency:  wallet.Currency,
+		Amount:    req.Amount,
+		Status:    models.ReserveHeld,
+		Source:    models.ReserveSourceManual,
+		Reason:    req.Reason,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.ReleaseAt != nil {
+		if !req.ReleaseAt.After(now) {
+			return nil, fmt.Errorf("%w: release_at must be in the future", ErrInvalidRequest)
+		}
+		releaseAt := req.ReleaseAt.UTC()
+		reserve.ReleaseAt = &releaseAt
+	}
+	reserve.CreatedByType, reserve.CreatedByID = actor(ctx)
+
+	var movement *models.ReserveMovement
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateReserve(ctx, reserve); err != nil {
+			return fmt.Errorf("failed to create reserve: %w", err)
+		}
+		movement, err = s.post(ctx, reserve, models.ReserveMovementHold, req.Amount, req.Reference, req.Reason)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, models.AuditReserveHeld, "reserve", reserve.ID, nil, reserve)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toReserveResponse(reserve, []models.ReserveMovement{*movement}), nil
+}
+
+// GetReserve returns a reserve and its postings to the merchant's wallet
+func (s *ReserveService) GetReserve(ctx context.Context, reserveID int) (*dto.ReserveResponse, error) {
+	reserve, err := s.repo.GetReserveByID(ctx, reserveID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserve: %w", err)
+	}
+	if reserve == nil {
+		return nil, ErrReserveNotFound
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, reserve.WalletID); err != nil {
+		return nil, err
+	}
+	movements, err := s.repo.ListMovements(ctx, reserve.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reserve movements: %w", err)
+	}
+	return toReserveResponse(reserve, movements), nil
+}
+
+// ListReserves returns a wallet's reserves, newest first
+func (s *ReserveService) ListReserves(ctx context.Context, walletID int, openOnly bool, limit int) ([]dto.ReserveResponse, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	reserves, err := s.repo.ListReservesByWalletID(ctx, walletID, openOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reserves: %w", err)
+	}
+
+	resp := make([]dto.ReserveResponse, 0, len(reserves))
+	for i := range reserves {
+		resp = append(resp, *toReserveResponse(&reserves[i], nil))
+	}
+	return resp, nil
+}
+
+// Release returns some or all of a reserve to the merchant ahead of its
+// release time
+func (s *ReserveService) Release(ctx context.Context, reserveID int, req dto.ReleaseReserveRequest) (*dto.ReserveResponse, error) {
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("%w: amount cannot be negative", ErrInvalidRequest)
+	}
+	return s.update(ctx, reserveID, func(ctx context.Context, reserve *models.Reserve) error {
+		amount := req.Amount
+		if amount == 0 {
+			amount = reserve.Remaining()
+		}
+		if amount > reserve.Remaining() {
+			return fmt.Errorf("%w: only %d is held", ErrInvalidRequest, reserve.Remaining())
+		}
+		return s.release(ctx, reserve, amount, req.Reference, req.Reason)
+	})
+}
+
+// Adjust changes the amount a reserve holds, releasing the difference to the
+// merchant or holding more from the merchant's wallet, and/or its release time
+func (s *ReserveService) Adjust(ctx context.Context, reserveID int, req dto.AdjustReserveRequest) (*dto.ReserveResponse, error) {
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("%w: amount cannot be negative", ErrInvalidRequest)
+	}
+	if req.Amount == 0 && req.ReleaseAt == nil {
+		return nil, fmt.Errorf("%w: amount or release_at is required", ErrInvalidRequest)
+	}
+	return s.update(ctx, reserveID, func(ctx context.Context, reserve *models.Reserve) error {
+		now := s.now().UTC()
+		before := *reserve
+		if req.ReleaseAt != nil {
+			if !req.ReleaseAt.After(now) {
+				return fmt.Errorf("%w: release_at must be in the future", ErrInvalidRequest)
+			}
+			releaseAt := req.ReleaseAt.UTC()
+			reserve.ReleaseAt = &releaseAt
+		}
+
+		switch remaining := reserve.Remaining(); {
+		case req.Amount == 0 || req.Amount == remaining:
+		case req.Amount < remaining:
+			if err := s.releaseFunds(ctx, reserve, remaining-req.Amount, req.Reference, req.Reason); err != nil {
+				return err
+			}
+		default:
+			extra := req.Amount - remaining
+			if _, err := s.post(ctx, reserve, models.ReserveMovementHold, extra, req.Reference, req.Reason); err != nil {
+				return err
+			}
+			reserve.Amount += extra
+		}
+		reserve.UpdatedAt = now
+		if err := s.repo.UpdateReserve(ctx, reserve); err != nil {
+			return fmt.Errorf("failed to update reserve: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditReserveAdjusted, "reserve", reserve.ID, before, reserve)
+	})
+}
+
+// ReleaseDue returns reserves whose release time has passed to their
+// merchants; run by a background worker
+func (s *ReserveService) ReleaseDue(ctx context.Context) error {
+	for i := 0; i < maxReservesPerTick; i++ {
+		var claimed bool
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := s.now().UTC()
+			reserve, err := s.repo.ClaimDueReserve(ctx, now)
+			if err != nil {
+				return fmt.Errorf("failed to claim reserve: %w", err)
+			}
+			if reserve == nil {
+				return nil
+			}
+			claimed = true
+
+			err = s.release(ctx, reserve, reserve.Remaining(), 0, "release time reached")
+			if errors.Is(err, ErrWalletNotActive) {
+				// Refused before anything was written; try again later
+				log.Printf("reserve %d: timed release postponed: %v", reserve.ID, err)
+				retryAt := now.Add(reserveReleaseRetryDelay)
+				reserve.ReleaseAt = &retryAt
+				reserve.UpdatedAt = now
+				if err := s.repo.UpdateReserve(ctx, reserve); err != nil {
+					return fmt.Errorf("failed to postpone reserve release: %w", err)
+				}
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// holdFromSettlement holds back part of a settlement batch just paid to a
+// merchant's wallet under the wallet's policy, in the caller's transaction.
+// It returns nil when nothing is held. A nil *ReserveService holds nothing.
+func (s *ReserveService) holdFromSettlement(ctx context.Context, batch *models.SettlementBatch) (*models.Reserve, error) {
+	if s == nil {
+		return nil, nil
+	}
+	policy, err := s.repo.GetPolicy(ctx, batch.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserve policy: %w", err)
+	}
+	if policy == nil || policy.PercentageBPS == 0 {
+		return nil, nil
+	}
+
+	amount := (batch.Amount*policy.PercentageBPS + 5000) / 10000
+	if policy.Cap > 0 {
+		held, err := s.repo.HeldTotal(ctx, batch.WalletID, models.ReserveSourceSettlement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reserved funds: %w", err)
+		}
+		if amount > policy.Cap-held {
+			amount = policy.Cap - held
+		}
+	}
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	now := s.now().UTC()
+	releaseAt := now.AddDate(0, 0, policy.HoldDays)
+	reserve := &models.Reserve{
+		WalletID:  batch.WalletID,
+		Currency:  batch.Currency,
+		Amount:    amount,
+		Status:    models.ReserveHeld,
+		Source:    models.ReserveSourceSettlement,
+		BatchID:   &batch.ID,
+		Reason:    fmt.Sprintf("Rolling reserve of %d bps for %d days", policy.PercentageBPS, policy.HoldDays),
+		ReleaseAt: &releaseAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	reserve.CreatedByType, reserve.CreatedByID = actor(ctx)
+	if err := s.repo.CreateReserve(ctx, reserve); err != nil {
+		return nil, fmt.Errorf("failed to create reserve: %w", err)
+	}
+	if _, err := s.post(ctx, reserve, models.ReserveMovementHold, amount, batch.ID, ""); err != nil {
+		return nil, err
+	}
+	return reserve, s.audit.Record(ctx, models.AuditReserveHeld, "reserve", reserve.ID, nil, reserve)
+}
+
+// heldTotal returns the funds a wallet has in reserve. A nil *ReserveService
+// holds nothing.
+func (s *ReserveService) heldTotal(ctx context.Context, walletID int) (int64, error) {
+	if s == nil {
+		return 0, nil
+	}
+	total, err := s.repo.HeldTotal(ctx, walletID, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reserved funds: %w", err)
+	}
+	return total, nil
+}
+
+// update locks an open reserve the caller may act on and applies fn
+func (s *ReserveService) update(ctx context.Context, reserveID int, fn func(ctx context.Context, reserve *models.Reserve) error) (*dto.ReserveResponse, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		reserve, err := s.repo.GetReserveByIDForUpdate(ctx, reserveID)
+		if err != nil {
+			return fmt.Errorf("failed to lock reserve: %w", err)
+		}
+		if reserve == nil {
+			return ErrReserveNotFound
+		}
+		if _, err := s.wallets.getAuthorizedWallet(ctx, reserve.WalletID); err != nil {
+			return err
+		}
+		if !reserve.IsOpen() {
+			return fmt.Errorf("%w: reserve is %s", ErrConflict, reserve.Status)
+		}
+		return fn(ctx, reserve)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetReserve(ctx, reserveID)
+}
+
+// release returns amount to the merchant and saves the reserve's new state
+func (s *ReserveService) release(ctx context.Context, reserve *models.Reserve, amount int64, reference int, reason string) error {
+	before := *reserve
+	if err := s.releaseFunds(ctx, reserve, amount, reference, reason); err != nil {
+		return err
+	}
+	reserve.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateReserve(ctx, reserve); err != nil {
+		return fmt.Errorf("failed to update reserve: %w", err)
+	}
+	return s.audit.Record(ctx, models.AuditReserveReleased, "reserve", reserve.ID, before, reserve)
+}
+
+// releaseFunds posts amount back to the merchant and updates reserve in
+// memory; the caller saves it
+func (s *ReserveService) releaseFunds(ctx context.Context, reserve *models.Reserve, amount int64, reference int, reason string) error {
+	if _, err := s.post(ctx, reserve, models.ReserveMovementRelease, amount, reference, reason); err != nil {
+		return err
+	}
+	reserve.ReleasedAmount += amount
+	reserve.Status = models.ReservePartiallyReleased
+	if reserve.Remaining() == 0 {
+		reserve.Status = models.ReserveReleased
+		reserve.ReleaseAt = nil
+	}
+	return nil
+}
+
+// post moves amount between the merchant's wallet and the reserve account as
+// one journal and records the movement. Holds debit the merchant and releases
+// credit it.
+func (s *ReserveService) post(ctx context.Context, reserve *models.Reserve, movementType string, amount int64, reference int, reason string) (*models.ReserveMovement, error) {
+	account, err := s.wallets.systemWallet(ctx, models.SystemMerchantReserve, reserve.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if reference == 0 {
+		reference = reserve.ID
+	}
+
+	var (
+		merchant *models.LedgerEntry
+		guards   []journalGuard
+	)
+	switch movementType {
+	case models.ReserveMovementHold:
+		merchant = models.NewLedgerEntry(reserve.WalletID, reference, "debit", amount, 0, fmt.Sprintf("Reserve %d hold", reserve.ID))
+		guards = append(guards, requireFunds(reserve.WalletID, amount))
+	case models.ReserveMovementRelease:
+		merchant = models.NewLedgerEntry(reserve.WalletID, reference, "credit", amount, 0, fmt.Sprintf("Reserve %d release", reserve.ID))
+	default:
+		return nil, fmt.Errorf("unknown reserve movement %q", movementType)
+	}
+
+	posted, err := s.wallets.postJournal(ctx, []*models.LedgerEntry{merchant, counterLeg(merchant, account.ID)}, guards...)
+	if err != nil {
+		return nil, err
+	}
+	movement := &models.ReserveMovement{
+		ReserveID:     reserve.ID,
+		Type:          movementType,
+		Amount:        amount,
+		JournalID:     posted.ID,
+		LedgerEntryID: merchant.ID,
+		Reason:        reason,
+		CreatedAt:     s.now().UTC(),
+	}
+	movement.ActorType, movement.ActorID = actor(ctx)
+	if err := s.repo.CreateMovement(ctx, movement); err != nil {
+		return nil, fmt.Errorf("failed to record reserve movement: %w", err)
+	}
+	return movement, nil
+}
+
+func toReserveResponse(reserve *models.Reserve, movements []models.ReserveMovement) *dto.ReserveResponse {
+	return &dto.ReserveResponse{Reserve: *reserve, Remaining: reserve.Remaining(), Movements: movements}
+}
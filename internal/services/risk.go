@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// ErrRiskBlocked is returned, wrapped in a *RiskBlockedError, when a risk rule
+// blocks a posting
+var ErrRiskBlocked = fmt.Errorf("%w: blocked by risk rules", ErrConflict)
+
+// RiskBlockedError names the rules that blocked a posting
+type RiskBlockedError struct {
+	WalletID int
+	Rules    []string
+	check    *riskCheck
+}
+
+func (e *RiskBlockedError) Error() string {
+	return fmt.Sprintf("%v: wallet %d, rules %v", ErrRiskBlocked, e.WalletID, e.Rules)
+}
+
+func (e *RiskBlockedError) Unwrap() error {
+	return ErrRiskBlocked
+}
+
+// RiskTransaction is the posting a rule is asked about, seen from one wallet
+type RiskTransaction struct {
+	Wallet    *models.Wallet // Locked, as it is before the posting
+	TxnType   string         // "credit", "debit" or "transfer"
+	Direction string         // The wallet's side: "credit" or "debit"
+	Amount    int64
+	Reference int
+	At        time.Time
+}
+
+// RuleResult is a rule's decision with the reason for anything but allow
+type RuleResult struct {
+	Decision string
+	Reason   string
+}
+
+// Rule is one risk check. Evaluate runs inside the posting transaction with
+// the wallet locked; it returns a nil result or an allow decision when the
+// posting looks normal.
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, txn *RiskTransaction) (*RuleResult, error)
+}
+
+// RuleConfig configures one rule. Which fields apply depends on the type;
+// durations are Go duration strings such as "72h".
+type RuleConfig struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Decision     string `json:"decision"` // "review" (default) or "block"
+	Currency     string `json:"currency"` // Optional; the rule only applies to wallets in this currency
+	MinAmount    int64  `json:"min_amount"`
+	MaxWalletAge string `json:"max_wallet_age"`
+	Window       string `json:"window"`
+	Multiple     int64  `json:"multiple"`
+	MinCount     int64  `json:"min_count"`
+	RatioBPS     int64  `json:"ratio_bps"`
+}
+
+// RuleFactory builds a rule of one type from its config
+type RuleFactory func(cfg RuleConfig, repo repositories.RiskRepository) (Rule, error)
+
+var ruleTypes = map[string]RuleFactory{}
+
+// RegisterRuleType makes a rule type available to risk rule configs
+func RegisterRuleType(name string, factory RuleFactory) {
+	ruleTypes[name] = factory
+}
+
+// LoadRiskRules reads risk rules from a JSON file holding a list of rule
+// configs
+func LoadRiskRules(path string, repo repositories.RiskRepository) ([]Rule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRiskRules(raw, repo)
+}
+
+// ParseRiskRules decodes, validates and builds JSON risk rule configs
+func ParseRiskRules(raw []byte, repo repositories.RiskRepository) ([]Rule, error) {
+	var configs []RuleConfig
+	if err := json.Unmarshal(raw, &configs); err != nil {
+		return nil, fmt.Errorf("invalid risk rules: %w", err)
+	}
+	rules := make([]Rule, 0, len(configs))
+	seen := map[string]bool{}
+	for i, cfg := range configs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("risk rule %d has no name", i)
+		}
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("duplicate risk rule %q", cfg.Name)
+		}
+		seen[cfg.Name] = true
+		switch cfg.Decision {
+		case "":
+			cfg.Decision = models.RiskReview
+		case models.RiskReview, models.RiskBlock:
+		default:
+			return nil, fmt.Errorf("risk rule %s: decision must be 'review' or 'block'", cfg.Name)
+		}
+		factory, ok := ruleTypes[cfg.Type]
+		if !ok {
+			return nil, fmt.Errorf("risk rule %s: unknown type %q", cfg.Name, cfg.Type)
+		}
+		rule, err := factory(cfg, repo)
+		if err != nil {
+			return nil, fmt.Errorf("risk rule %s: %w", cfg.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// riskSeverity orders decisions so the most severe one wins
+var riskSeverity = map[string]int{models.RiskAllow: 0, models.RiskReview: 1, models.RiskBlock: 2}
+
+// RiskService runs the risk rules on postings before they commit and keeps
+// the queue of cases the rules flagged
+type RiskService struct {
+	rules []Rule
+	repo  repositories.RiskRepository
+	tx    repositories.Transactor
+	audit *AuditService
+	now   func() time.Time
+}
+
+// NewRiskService creates a new risk service
+func NewRiskService(rules []Rule, repo repositories.RiskRepository, tx repositories.Transactor, audit *AuditService) *RiskService {
+	return &RiskService{rules: rules, repo: repo, tx: tx, audit: audit, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *RiskService) WithClock(now func() time.Time) *RiskService {
+	s.now = now
+	return s
+}
+
+// riskCheck runs the rules on one wallet's side of a posting and remembers
+// the outcome until it can be recorded against the ledger entry
+type riskCheck struct {
+	svc         *RiskService
+	entry       *models.LedgerEntry // The wallet's leg; its ID is set once posted
+	txnType     string
+	currency    string
+	decision    string
+	evaluations []models.RiskEvaluation
+}
+
+// check prepares a risk check of entry, the wallet's leg of a posting of the
+// given type. A nil *RiskService checks nothing.
+func (s *RiskService) check(entry *models.LedgerEntry, txnType string) *riskCheck {
+	if s == nil {
+		return nil
+	}
+	return &riskCheck{svc: s, entry: entry, txnType: txnType}
+}
+
+// guard evaluates every rule and rejects the journal with a
+// *RiskBlockedError when one of them blocks it
+func (c *riskCheck) guard() journalGuard {
+	return func(ctx context.Context, locked map[int]*models.Wallet) error {
+		if c == nil {
+			return nil
+		}
+		wallet := locked[c.entry.WalletID]
+		txn := &RiskTransaction{
+			Wallet:    wallet,
+			TxnType:   c.txnType,
+			Direction: c.entry.Type,
+			Amount:    c.entry.Amount,
+			Reference: c.entry.Reference,
+			At:        c.svc.now().UTC(),
+		}
+		c.currency, c.decision, c.evaluations = wallet.Currency, models.RiskAllow, nil
+		var blockedBy []string
+		for _, rule := range c.svc.rules {
+			result, err := rule.Evaluate(ctx, txn)
+			if err != nil {
+				return fmt.Errorf("risk rule %s: %w", rule.Name(), err)
+			}
+			if result == nil {
+				result = &RuleResult{Decision: models.RiskAllow}
+			}
+			c.evaluations = append(c.evaluations, models.RiskEvaluation{
+				WalletID: wallet.ID, Rule: rule.Name(), Decision: result.Decision, Reason: result.Reason, CreatedAt: txn.At,
+			})
+			if riskSeverity[result.Decision] > riskSeverity[c.decision] {
+				c.decision = result.Decision
+			}
+			if result.Decision == models.RiskBlock {
+				blockedBy = append(blockedBy, rule.Name())
+			}
+		}
+		if c.decision == models.RiskBlock {
+			return &RiskBlockedError{WalletID: wallet.ID, Rules: blockedBy, check: c}
+		}
+		return nil
+	}
+}
+
+// record saves the evaluations of a posted journal against the wallet's
+// ledger entry, opening a case when a rule flagged it for review
+func (c *riskCheck) record(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	entryID := c.entry.ID
+	return c.save(ctx, c.entry.JournalID, &entryID)
+}
+
+func (c *riskCheck) save(ctx context.Context, journalID string, entryID *int) error {
+	var caseID *int
+	if c.decision != models.RiskAllow {
+		riskCase := &models.RiskCase{
+			WalletID:      c.entry.WalletID,
+			Decision:      c.decision,
+			TxnType:       c.txnType,
+			Direction:     c.entry.Type,
+			Amount:        c.entry.Amount,
+			Currency:      c.currency,
+			Reference:     c.entry.Reference,
+			JournalID:     journalID,
+			LedgerEntryID: entryID,
+			Status:        models.RiskCaseOpen,
+			CreatedAt:     c.svc.now().UTC(),
+		}
+		if err := c.svc.repo.CreateCase(ctx, riskCase); err != nil {
+			return fmt.Errorf("failed to create risk case: %w", err)
+		}
+		caseID = &riskCase.ID
+		if err := c.svc.audit.Record(ctx, models.AuditRiskCaseOpened, "risk_case", riskCase.ID, nil, riskCase); err != nil {
+			return err
+		}
+	}
+	for i := range c.evaluations {
+		evaluation := &c.evaluations[i]
+		evaluation.JournalID, evaluation.LedgerEntryID, evaluation.CaseID = journalID, entryID, caseID
+		if err := c.svc.repo.CreateEvaluation(ctx, evaluation); err != nil {
+			return fmt.Errorf("failed to record risk evaluation: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordBlocked opens a case for a posting the risk rules blocked. The
+// posting's own transaction has rolled back, so this runs after it and
+// returns err unchanged; failing to record is logged rather than hiding the
+// block from the caller.
+func (s *RiskService) recordBlocked(ctx context.Context, err error) error {
+	var blocked *RiskBlockedError
+	if s == nil || !errors.As(err, &blocked) {
+		return err
+	}
+	if recErr := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return blocked.check.save(ctx, "", nil)
+	}); recErr != nil {
+		log.Printf("failed to record blocked posting for wallet %d: %v", blocked.WalletID, recErr)
+	}
+	return err
+}
+
+// ListCases returns risk cases, newest first, optionally narrowed to a status
+// and a wallet
+func (s *RiskService) ListCases(ctx context.Context, status string, walletID, limit int) ([]models.RiskCase, error) {
+	switch status {
+	case "", models.RiskCaseOpen, models.RiskCaseCleared, models.RiskCaseConfirmed:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidRequest, status)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	cases, err := s.repo.ListCases(ctx, status, walletID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk cases: %w", err)
+	}
+	if cases == nil {
+		cases = []models.RiskCase{}
+	}
+	return cases, nil
+}
+
+// GetCase returns a risk case with every rule evaluation behind it
+func (s *RiskService) GetCase(ctx context.Context, id int) (*dto.RiskCaseResponse, error) {
+	riskCase, err := s.repo.GetCaseByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk case: %w", err)
+	}
+	if riskCase == nil {
+		return nil, ErrRiskCaseNotFound
+	}
+	evaluations, err := s.repo.ListEvaluationsByCaseID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk evaluations: %w", err)
+	}
+	return &dto.RiskCaseResponse{RiskCase: *riskCase, Evaluations: evaluations}, nil
+}
+
+// ListEntryEvaluations returns the rule evaluations recorded against a
+// ledger entry
+func (s *RiskService) ListEntryEvaluations(ctx context.Context, entryID int) ([]models.RiskEvaluation, error) {
+	evaluations, err := s.repo.ListEvaluationsByLedgerEntryID(ctx, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk evaluations: %w", err)
+	}
+	if evaluations == nil {
+		evaluations = []models.RiskEvaluation{}
+	}
+	return evaluations, nil
+}
+
+// ResolveCase closes an open case as cleared (legitimate) or confirmed
+// (suspicious). Resolving does not move money; reversing a confirmed posting
+// is a separate ledger operation.
+func (s *RiskService) ResolveCase(ctx context.Context, id int, req dto.ResolveRiskCaseRequest) (*dto.RiskCaseResponse, error) {
+	if req.Status != models.RiskCaseCleared && req.Status != models.RiskCaseConfirmed {
+		return nil, fmt.Errorf("%w: status must be 'cleared' or 'confirmed'", ErrInvalidRequest)
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		riskCase, err := s.repo.GetCaseByIDForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get risk case: %w", err)
+		}
+		if riskCase == nil {
+			return ErrRiskCaseNotFound
+		}
+		if riskCase.Status != models.RiskCaseOpen {
+			return fmt.Errorf("%w: risk case is already %s", ErrConflict, riskCase.Status)
+		}
+		before := *riskCase
+		now := s.now().UTC()
+		riskCase.Status, riskCase.ResolutionNote, riskCase.ResolvedAt = req.Status, req.Note, &now
+		riskCase.ResolvedByType, riskCase.ResolvedByID = actor(ctx)
+		if err := s.repo.UpdateCase(ctx, riskCase); err != nil {
+			return fmt.Errorf("failed to update risk case: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditRiskCaseResolved, "risk_case", id, before, riskCase)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCase(ctx, id)
+}
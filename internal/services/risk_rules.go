@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// Built-in risk rule types
+const (
+	RuleNewWalletLargeCredit = "new_wallet_large_credit"
+	RuleRapidInOut           = "rapid_in_out"
+	RuleRoundAmount          = "round_amount"
+)
+
+func init() {
+	RegisterRuleType(RuleNewWalletLargeCredit, newWalletLargeCreditRule)
+	RegisterRuleType(RuleRapidInOut, newRapidInOutRule)
+	RegisterRuleType(RuleRoundAmount, newRoundAmountRule)
+}
+
+// ruleBase holds what every built-in rule shares
+type ruleBase struct {
+	name, decision, currency string
+}
+
+func (r ruleBase) Name() string { return r.name }
+
+// applies reports whether the rule covers txn's currency
+func (r ruleBase) applies(txn *RiskTransaction) bool {
+	return r.currency == "" || r.currency == txn.Wallet.Currency
+}
+
+func (r ruleBase) hit(format string, args ...interface{}) *RuleResult {
+	return &RuleResult{Decision: r.decision, Reason: fmt.Sprintf(format, args...)}
+}
+
+func parseRuleDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("%s is required", field)
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration", field)
+	}
+	return d, nil
+}
+
+// newWalletLargeCredit flags credits of at least MinAmount to wallets younger
+// than MaxWalletAge
+type newWalletLargeCredit struct {
+	ruleBase
+	minAmount int64
+	maxAge    time.Duration
+}
+
+func newWalletLargeCreditRule(cfg RuleConfig, _ repositories.RiskRepository) (Rule, error) {
+	maxAge, err := parseRuleDuration("max_wallet_age", cfg.MaxWalletAge)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MinAmount <= 0 {
+		return nil, errors.New("min_amount must be positive")
+	}
+	return &newWalletLargeCredit{ruleBase: ruleBase{cfg.Name, cfg.Decision, cfg.Currency}, minAmount: cfg.MinAmount, maxAge: maxAge}, nil
+}
+
+func (r *newWalletLargeCredit) Evaluate(_ context.Context, txn *RiskTransaction) (*RuleResult, error) {
+	if !r.applies(txn) || txn.Direction != "credit" || txn.Amount < r.minAmount {
+		return nil, nil
+	}
+	if age := txn.At.Sub(txn.Wallet.CreatedAt); age < r.maxAge {
+		return r.hit("credit of %d to a wallet %s old", txn.Amount, age.Round(time.Minute)), nil
+	}
+	return nil, nil
+}
+
+// rapidInOut flags debits that, within Window, take out at least RatioBPS of
+// what came in, once at least MinAmount has come in: money passing straight
+// through the wallet
+type rapidInOut struct {
+	ruleBase
+	repo      repositories.RiskRepository
+	window    time.Duration
+	minAmount int64
+	ratioBPS  int64
+}
+
+func newRapidInOutRule(cfg RuleConfig, repo repositories.RiskRepository) (Rule, error) {
+	window, err := parseRuleDuration("window", cfg.Window)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MinAmount <= 0 {
+		return nil, errors.New("min_amount must be positive")
+	}
+	ratio := cfg.RatioBPS
+	if ratio == 0 {
+		ratio = 8000
+	}
+	if ratio < 0 || ratio > 10000 {
+		return nil, errors.New("ratio_bps must be between 0 and 10000")
+	}
+	return &rapidInOut{ruleBase: ruleBase{cfg.Name, cfg.Decision, cfg.Currency}, repo: repo, window: window, minAmount: cfg.MinAmount, ratioBPS: ratio}, nil
+}
+
+func (r *rapidInOut) Evaluate(ctx context.Context, txn *RiskTransaction) (*RuleResult, error) {
+	if !r.applies(txn) || txn.Direction != "debit" {
+		return nil, nil
+	}
+	activity, err := r.repo.Activity(ctx, txn.Wallet.ID, txn.At.Add(-r.window))
+	if err != nil {
+		return nil, err
+	}
+	if activity.Credit < r.minAmount {
+		return nil, nil
+	}
+	if out := activity.Debit + txn.Amount; out*10000 >= activity.Credit*r.ratioBPS {
+		return r.hit("%d out against %d in within %s", out, activity.Credit, r.window), nil
+	}
+	return nil, nil
+}
+
+// roundAmount flags postings of at least MinAmount that are a multiple of
+// Multiple once the wallet has MinCount of them in the same direction within
+// Window, counting this one: amounts split to stay under reporting thresholds
+type roundAmount struct {
+	ruleBase
+	repo      repositories.RiskRepository
+	window    time.Duration
+	multiple  int64
+	minAmount int64
+	minCount  int64
+}
+
+func newRoundAmountRule(cfg RuleConfig, repo repositories.RiskRepository) (Rule, error) {
+	window, err := parseRuleDuration("window", cfg.Window)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Multiple <= 0 {
+		return nil, errors.New("multiple must be positive")
+	}
+	if cfg.MinAmount < 0 || cfg.MinCount < 0 {
+		return nil, errors.New("min_amount and min_count cannot be negative")
+	}
+	minCount := cfg.MinCount
+	if minCount == 0 {
+		minCount = 1
+	}
+	return &roundAmount{ruleBase: ruleBase{cfg.Name, cfg.Decision, cfg.Currency}, repo: repo, window: window, multiple: cfg.Multiple,
+		minAmount: cfg.MinAmount, minCount: minCount}, nil
+}
+
+func (r *roundAmount) Evaluate(ctx context.Context, txn *RiskTransaction) (*RuleResult, error) {
+	if !r.applies(txn) || txn.Amount < r.minAmount || txn.Amount%r.multiple != 0 {
+		return nil, nil
+	}
+	count := int64(1)
+	if r.minCount > 1 {
+		previous, err := r.repo.CountRoundEntries(ctx, txn.Wallet.ID, txn.Direction, txn.At.Add(-r.window), r.minAmount, r.multiple)
+		if err != nil {
+			return nil, err
+		}
+		count += previous
+	}
+	if count >= r.minCount {
+		return r.hit("%d round %s amounts (multiples of %d) within %s", count, txn.Direction, r.multiple, r.window), nil
+	}
+	return nil, nil
+}
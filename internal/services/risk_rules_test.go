@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+func TestParseRiskRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{name: "new wallet large credit", rule: `{"name":"r","type":"new_wallet_large_credit","min_amount":100000,"max_wallet_age":"72h"}`},
+		{name: "rapid in out", rule: `{"name":"r","type":"rapid_in_out","decision":"block","window":"24h","min_amount":50000}`},
+		{name: "round amount", rule: `{"name":"r","type":"round_amount","window":"24h","multiple":10000,"min_count":3}`},
+		{name: "no name", rule: `{"type":"round_amount","window":"24h","multiple":10000}`, wantErr: true},
+		{name: "unknown type", rule: `{"name":"r","type":"velocity"}`, wantErr: true},
+		{name: "unknown decision", rule: `{"name":"r","type":"round_amount","decision":"deny","window":"24h","multiple":10000}`, wantErr: true},
+		{name: "missing window", rule: `{"name":"r","type":"rapid_in_out","min_amount":50000}`, wantErr: true},
+		{name: "negative window", rule: `{"name":"r","type":"rapid_in_out","window":"-1h","min_amount":50000}`, wantErr: true},
+		{name: "ratio over 100%", rule: `{"name":"r","type":"rapid_in_out","window":"24h","min_amount":50000,"ratio_bps":10001}`, wantErr: true},
+		{name: "zero multiple", rule: `{"name":"r","type":"round_amount","window":"24h"}`, wantErr: true},
+		{name: "zero min amount", rule: `{"name":"r","type":"new_wallet_large_credit","max_wallet_age":"72h"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRiskRules([]byte(`[`+tt.rule+`]`), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := ParseRiskRules([]byte(`[{"name":"r","type":"round_amount","window":"1h","multiple":1},{"name":"r","type":"round_amount","window":"1h","multiple":1}]`), nil); err == nil {
+		t.Error("duplicate rule names accepted")
+	}
+}
+
+func TestRiskRuleDecisions(t *testing.T) {
+	const (
+		newWallet = `{"name":"new","type":"new_wallet_large_credit","currency":"GBP","min_amount":100000,"max_wallet_age":"72h"}`
+		rapid     = `{"name":"rapid","type":"rapid_in_out","decision":"block","window":"24h","min_amount":50000}`
+		round     = `{"name":"round","type":"round_amount","window":"24h","multiple":10000,"min_amount":50000,"min_count":3}`
+	)
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		rule       string
+		direction  string
+		amount     int64
+		walletAge  time.Duration
+		currency   string
+		activity   models.RiskActivity
+		roundCount int64
+
+		want string // Empty when the rule lets the posting through
+	}{
+		{name: "large credit to a new wallet", rule: newWallet, direction: "credit", amount: 100000, walletAge: 71 * time.Hour, want: models.RiskReview},
+		{name: "small credit to a new wallet", rule: newWallet, direction: "credit", amount: 99999, walletAge: time.Hour},
+		{name: "large credit to an old wallet", rule: newWallet, direction: "credit", amount: 100000, walletAge: 72 * time.Hour},
+		{name: "large debit from a new wallet", rule: newWallet, direction: "debit", amount: 100000, walletAge: time.Hour},
+		{name: "large credit in another currency", rule: newWallet, direction: "credit", amount: 100000, walletAge: time.Hour, currency: "EUR"},
+		{name: "money passing through", rule: rapid, direction: "debit", amount: 30000, activity: models.RiskActivity{Credit: 60000, Debit: 18000}, want: models.RiskBlock},
+		{name: "most of it kept", rule: rapid, direction: "debit", amount: 29999, activity: models.RiskActivity{Credit: 60000, Debit: 18000}},
+		{name: "too little came in", rule: rapid, direction: "debit", amount: 49999, activity: models.RiskActivity{Credit: 49999}},
+		{name: "credits do not pass through", rule: rapid, direction: "credit", amount: 60000, activity: models.RiskActivity{Credit: 60000}},
+		{name: "third round amount", rule: round, direction: "credit", amount: 50000, roundCount: 2, want: models.RiskReview},
+		{name: "second round amount", rule: round, direction: "credit", amount: 50000, roundCount: 1},
+		{name: "not round", rule: round, direction: "credit", amount: 50001, roundCount: 5},
+		{name: "round but small", rule: round, direction: "credit", amount: 40000, roundCount: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRiskRepository{activity: tt.activity, roundCount: tt.roundCount}
+			rules, err := ParseRiskRules([]byte(`[`+tt.rule+`]`), repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			currency := tt.currency
+			if currency == "" {
+				currency = "GBP"
+			}
+			txn := &RiskTransaction{
+				Wallet:    &models.Wallet{ID: 1, Currency: currency, CreatedAt: at.Add(-tt.walletAge)},
+				Direction: tt.direction,
+				Amount:    tt.amount,
+				At:        at,
+			}
+			result, err := rules[0].Evaluate(context.Background(), txn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if result != nil && result.Decision != models.RiskAllow {
+				got = result.Decision
+				if result.Reason == "" {
+					t.Error("decision has no reason")
+				}
+			}
+			if got != tt.want {
+				t.Errorf("decision = %q, want %q", got, tt.want)
+			}
+			if !repo.since.IsZero() && !repo.since.Equal(at.Add(-24*time.Hour)) {
+				t.Errorf("window from %s, want the 24 hours before %s", repo.since, at)
+			}
+		})
+	}
+}
+
+// stubRule returns a fixed decision
+type stubRule struct {
+	name, decision string
+}
+
+func (r stubRule) Name() string { return r.name }
+
+func (r stubRule) Evaluate(context.Context, *RiskTransaction) (*RuleResult, error) {
+	if r.decision == "" {
+		return nil, nil
+	}
+	return &RuleResult{Decision: r.decision, Reason: r.name}, nil
+}
+
+func TestRiskGuardTakesMostSevereDecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		decisions []string
+
+		want        string
+		wantBlocked []string
+	}{
+		{name: "no rules", want: models.RiskAllow},
+		{name: "nothing flagged", decisions: []string{"", models.RiskAllow}, want: models.RiskAllow},
+		{name: "review", decisions: []string{models.RiskAllow, models.RiskReview}, want: models.RiskReview},
+		{name: "block beats review", decisions: []string{models.RiskBlock, models.RiskReview, models.RiskBlock}, want: models.RiskBlock, wantBlocked: []string{"rule0", "rule2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []Rule
+			for i, decision := range tt.decisions {
+				rules = append(rules, stubRule{name: fmt.Sprintf("rule%d", i), decision: decision})
+			}
+			svc := NewRiskService(rules, nil, nil, nil)
+			wallet := &models.Wallet{ID: 1, Currency: "GBP"}
+			check := svc.check(&models.LedgerEntry{WalletID: wallet.ID, Type: "debit", Amount: 1000}, FeeTxnDebit)
+
+			err := check.guard()(context.Background(), map[int]*models.Wallet{wallet.ID: wallet})
+			var blocked *RiskBlockedError
+			if errors.As(err, &blocked) {
+				if !reflect.DeepEqual(blocked.Rules, tt.wantBlocked) || !errors.Is(err, ErrConflict) {
+					t.Errorf("blocked by %v (%v), want %v", blocked.Rules, err, tt.wantBlocked)
+				}
+			} else if err != nil || tt.wantBlocked != nil {
+				t.Errorf("err = %v, want blocked by %v", err, tt.wantBlocked)
+			}
+			if check.decision != tt.want || len(check.evaluations) != len(tt.decisions) {
+				t.Errorf("decision = %s from %d evaluations, want %s from %d", check.decision, len(check.evaluations), tt.want, len(tt.decisions))
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// screenName screens one holder name; without a screener, or for an empty
+// name, nothing matches
+func (s *WalletService) screenName(ctx context.Context, name string) ([]models.ScreeningMatch, error) {
+	if s.screener == nil || name == "" {
+		return nil, nil
+	}
+	matches, err := s.screener.Screen(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen %q: %w", name, err)
+	}
+	return matches, nil
+}
+
+// screenWallets screens the holders of wallets, returning every match tagged
+// with the wallet it came from
+func (s *WalletService) screenWallets(ctx context.Context, wallets ...*models.Wallet) ([]models.ScreeningMatch, error) {
+	var all []models.ScreeningMatch
+	for _, wallet := range wallets {
+		matches, err := s.screenName(ctx, wallet.HolderName)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			match.WalletID = wallet.ID
+			all = append(all, match)
+		}
+	}
+	return all, nil
+}
+
+// openScreeningReview records a pending review. It must run inside a
+// transaction.
+func (s *WalletService) openScreeningReview(ctx context.Context, review *models.ScreeningReview) (*models.ScreeningReview, error) {
+	review.Status = models.ScreeningPending
+	review.CreatedByType, review.CreatedByID = actor(ctx)
+	review.CreatedAt = s.now().UTC()
+	if err := s.screening.CreateReview(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to create screening review: %w", err)
+	}
+	return review, s.audit.Record(ctx, models.AuditScreeningOpened, "screening_review", review.ID, nil, review)
+}
+
+// holdTransfer holds a transfer whose parties matched a watchlist for review
+// instead of posting it
+func (s *WalletService) holdTransfer(ctx context.Context, req dto.TransferRequest, matches []models.ScreeningMatch) (*dto.TransferResponse, error) {
+	toWalletID := req.ToWalletID
+	var review *models.ScreeningReview
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.openScreeningReview(ctx, &models.ScreeningReview{
+			SubjectType: models.ScreeningSubjectTransfer,
+			WalletID:    req.FromWalletID,
+			ToWalletID:  &toWalletID,
+			Amount:      req.Amount,
+			Reference:   req.Reference,
+			Description: req.Description,
+			Matches:     matches,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TransferResponse{Status: dto.TransferPendingReview, ScreeningReviewID: review.ID}, nil
+}
+
+// holdSplit holds a split payment whose parties matched a watchlist for
+// review instead of posting it. The review is filed against the source
+// wallet, or against the first matched destination of an external payment.
+func (s *WalletService) holdSplit(ctx context.Context, req dto.SplitPaymentRequest, matches []models.ScreeningMatch) (*dto.SplitPaymentResponse, error) {
+	split, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode split payment: %w", err)
+	}
+	walletID := req.SourceWalletID
+	if walletID == 0 {
+		walletID = matches[0].WalletID
+	}
+	var review *models.ScreeningReview
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.openScreeningReview(ctx, &models.ScreeningReview{
+			SubjectType: models.ScreeningSubjectSplit,
+			WalletID:    walletID,
+			Amount:      req.Amount,
+			Reference:   req.Reference,
+			Description: req.Description,
+			Split:       split,
+			Matches:     matches,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SplitPaymentResponse{Status: dto.TransferPendingReview, ScreeningReviewID: review.ID}, nil
+}
+
+// ListScreeningReviews returns screening reviews, newest first, optionally
+// narrowed to a status and a wallet on either side
+func (s *WalletService) ListScreeningReviews(ctx context.Context, status string, walletID, limit int) ([]models.ScreeningReview, error) {
+	if s.screening == nil {
+		return []models.ScreeningReview{}, nil
+	}
+	switch status {
+	case "", models.ScreeningPending, models.ScreeningCleared, models.ScreeningConfirmed:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidRequest, status)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	reviews, err := s.screening.ListReviews(ctx, status, walletID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list screening reviews: %w", err)
+	}
+	if reviews == nil {
+		reviews = []models.ScreeningReview{}
+	}
+	return reviews, nil
+}
+
+// GetScreeningReview returns a screening review with its watchlist matches
+func (s *WalletService) GetScreeningReview(ctx context.Context, id int) (*models.ScreeningReview, error) {
+	if s.screening == nil {
+		return nil, ErrScreeningReviewNotFound
+	}
+	review, err := s.screening.GetReviewByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screening review: %w", err)
+	}
+	if review == nil {
+		return nil, ErrScreeningReviewNotFound
+	}
+	return review, nil
+}
+
+// ResolveScreeningReview clears or confirms a pending review. Clearing a
+// wallet activates it and clearing a transfer or split payment posts it; if
+// the payment can no longer be posted (e.g. for lack of funds) the review
+// stays pending. Confirming a wallet freezes it and confirming a payment
+// drops it.
+func (s *WalletService) ResolveScreeningReview(ctx context.Context, id int, req dto.ResolveScreeningReviewRequest) (*models.ScreeningReview, error) {
+	if req.Status != models.ScreeningCleared && req.Status != models.ScreeningConfirmed {
+		return nil, fmt.Errorf("%w: status must be 'cleared' or 'confirmed'", ErrInvalidRequest)
+	}
+	if s.screening == nil {
+		return nil, ErrScreeningReviewNotFound
+	}
+
+	var review *models.ScreeningReview
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.screening.GetReviewByIDForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get screening review: %w", err)
+		}
+		if review == nil {
+			return ErrScreeningReviewNotFound
+		}
+		if review.Status != models.ScreeningPending {
+			return fmt.Errorf("%w: screening review is already %s", ErrConflict, review.Status)
+		}
+		before := *review
+
+		switch review.SubjectType {
+		case models.ScreeningSubjectWallet:
+			status := models.WalletStatusActive
+			if req.Status == models.ScreeningConfirmed {
+				status = models.WalletStatusFrozen
+			}
+			if err := s.resolveWalletScreening(ctx, review.WalletID, status, fmt.Sprintf("screening review %d %s", id, req.Status)); err != nil {
+				return err
+			}
+		case models.ScreeningSubjectTransfer:
+			if req.Status == models.ScreeningCleared {
+				journalID, err := s.postHeldTransfer(ctx, review)
+				if err != nil {
+					return err
+				}
+				review.JournalID = journalID
+			}
+		case models.ScreeningSubjectSplit:
+			if req.Status == models.ScreeningCleared {
+				journalID, err := s.postHeldSplit(ctx, review)
+				if err != nil {
+					return err
+				}
+				review.JournalID = journalID
+			}
+		}
+
+		now := s.now().UTC()
+		review.Status, review.ResolutionNote, review.ResolvedAt = req.Status, req.Note, &now
+		review.ResolvedByType, review.ResolvedByID = actor(ctx)
+		if err := s.screening.UpdateReview(ctx, review); err != nil {
+			return fmt.Errorf("failed to update screening review: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditScreeningResolved, "screening_review", id, before, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// resolveWalletScreening moves a wallet held for screening to status
+func (s *WalletService) resolveWalletScreening(ctx context.Context, walletID int, status, reason string) error {
+	wallet, err := s.repo.GetWalletByIDForUpdate(ctx, walletID)
+	if err != nil {
+		return fmt.Errorf("failed to lock wallet: %w", err)
+	}
+	if wallet == nil {
+		return ErrWalletNotFound
+	}
+	if wallet.Status != models.WalletStatusPendingReview {
+		return nil // No longer held
+	}
+	if err := s.repo.UpdateWalletStatus(ctx, wallet.ID, status); err != nil {
+		return fmt.Errorf("failed to update wallet status: %w", err)
+	}
+	after := *wallet
+	after.Status = status
+	return s.audit.Record(ctx, models.AuditWalletStatusChanged, "wallet", wallet.ID, wallet, statusAuditState{Wallet: &after, Reason: reason})
+}
+
+// postHeldTransfer posts a transfer held for screening, returning its journal
+func (s *WalletService) postHeldTransfer(ctx context.Context, review *models.ScreeningReview) (string, error) {
+	req := dto.TransferRequest{
+		FromWalletID: review.WalletID,
+		ToWalletID:   *review.ToWalletID,
+		Amount:       review.Amount,
+		Reference:    review.Reference,
+		Description:  review.Description,
+	}
+	from, err := s.repo.GetWalletByID(ctx, req.FromWalletID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get wallet: %w", err)
+	}
+	to, err := s.repo.GetWalletByID(ctx, req.ToWalletID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if from == nil || to == nil {
+		return "", ErrWalletNotFound
+	}
+	resp, err := s.transfer(ctx, req, from, to)
+	if err != nil {
+		return "", err
+	}
+	return resp.JournalID, nil
+}
+
+// postHeldSplit posts a split payment held for screening, returning its journal
+func (s *WalletService) postHeldSplit(ctx context.Context, review *models.ScreeningReview) (string, error) {
+	var req dto.SplitPaymentRequest
+	if err := json.Unmarshal(review.Split, &req); err != nil {
+		return "", fmt.Errorf("failed to decode held split payment: %w", err)
+	}
+	amounts, err := allocateSplit(req.Amount, req.Destinations)
+	if err != nil {
+		return "", err
+	}
+	currency := req.Currency
+	if req.SourceWalletID != 0 {
+		source, err := s.repo.GetWalletByID(ctx, req.SourceWalletID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get wallet: %w", err)
+		}
+		if source == nil {
+			return "", ErrWalletNotFound
+		}
+		currency = source.Currency
+	}
+	resp, err := s.split(ctx, req, amounts, currency)
+	if err != nil {
+		return "", err
+	}
+	return resp.JournalID, nil
+}
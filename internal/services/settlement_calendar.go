@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Settlement schedule kinds
+const (
+	SettlementTPlusN  = "t+n"    // N business days after capture
+	SettlementWeekly  = "weekly" // On a fixed weekday, or the next business day after it
+	SettlementManual  = "manual" // Only when settlement is requested through the API
+	settlementDateFmt = "2006-01-02"
+)
+
+// SettlementCalendar decides which days are business days: weekdays that are
+// not listed holidays, in the calendar's time zone
+type SettlementCalendar struct {
+	loc      *time.Location
+	holidays map[string]bool
+}
+
+// NewSettlementCalendar creates a calendar with the given holiday dates
+func NewSettlementCalendar(loc *time.Location, holidays []time.Time) *SettlementCalendar {
+	cal := &SettlementCalendar{loc: loc, holidays: make(map[string]bool, len(holidays))}
+	for _, day := range holidays {
+		cal.holidays[day.Format(settlementDateFmt)] = true
+	}
+	return cal
+}
+
+// LoadHolidays reads a holiday calendar file: one YYYY-MM-DD date per line,
+// optionally followed by a name. Blank lines and lines starting with # are ignored.
+func LoadHolidays(path string) ([]time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseHolidays(f)
+}
+
+// ParseHolidays parses a holiday calendar; see LoadHolidays
+func ParseHolidays(r io.Reader) ([]time.Time, error) {
+	var holidays []time.Time
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		day, err := time.Parse(settlementDateFmt, strings.Fields(text)[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: expected a YYYY-MM-DD date", line)
+		}
+		holidays = append(holidays, day)
+	}
+	return holidays, scanner.Err()
+}
+
+// Date returns the calendar day t falls on, as midnight UTC
+func (c *SettlementCalendar) Date(t time.Time) time.Time {
+	y, m, d := t.In(c.loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// IsBusinessDay reports whether day (a Date) is a weekday and not a holiday
+func (c *SettlementCalendar) IsBusinessDay(day time.Time) bool {
+	if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	return !c.holidays[day.Format(settlementDateFmt)]
+}
+
+// NextBusinessDay returns day if it is a business day, or the first one after it
+func (c *SettlementCalendar) NextBusinessDay(day time.Time) time.Time {
+	for !c.IsBusinessDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// AddBusinessDays returns the nth business day after day
+func (c *SettlementCalendar) AddBusinessDays(day time.Time, n int) time.Time {
+	for n > 0 {
+		day = day.AddDate(0, 0, 1)
+		if c.IsBusinessDay(day) {
+			n--
+		}
+	}
+	return day
+}
+
+// SettlementSchedule says when a merchant's captured funds become available.
+// It is written "T+N", "weekly:friday" or "manual".
+type SettlementSchedule struct {
+	Kind    string
+	Days    int          // For T+N
+	Weekday time.Weekday // For weekly
+}
+
+// ParseSettlementSchedule parses a schedule such as "T+1", "weekly:friday" or "manual"
+func ParseSettlementSchedule(value string) (SettlementSchedule, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == SettlementManual:
+		return SettlementSchedule{Kind: SettlementManual}, nil
+	case strings.HasPrefix(value, "t+"):
+		days, err := strconv.Atoi(value[2:])
+		if err != nil || days < 0 || days > 30 {
+			return SettlementSchedule{}, fmt.Errorf("%q: T+N needs N between 0 and 30", value)
+		}
+		return SettlementSchedule{Kind: SettlementTPlusN, Days: days}, nil
+	case strings.HasPrefix(value, "weekly:"):
+		name := value[len("weekly:"):]
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if strings.ToLower(wd.String()) == name {
+				return SettlementSchedule{Kind: SettlementWeekly, Weekday: wd}, nil
+			}
+		}
+		return SettlementSchedule{}, fmt.Errorf("%q: unknown weekday", value)
+	default:
+		return SettlementSchedule{}, fmt.Errorf("%q: expected T+N, weekly:DAY or manual", value)
+	}
+}
+
+// String returns the schedule's canonical form
+func (s SettlementSchedule) String() string {
+	switch s.Kind {
+	case SettlementTPlusN:
+		return fmt.Sprintf("T+%d", s.Days)
+	case SettlementWeekly:
+		return "weekly:" + strings.ToLower(s.Weekday.String())
+	default:
+		return s.Kind
+	}
+}
+
+// EligibleOn returns the day funds captured at capturedAt may be settled.
+// T+N counts business days from the capture day; weekly picks the next
+// settlement weekday after the capture day, moved forward past holidays.
+// Manual funds are eligible on the capture day but only settle on request.
+func (s SettlementSchedule) EligibleOn(cal *SettlementCalendar, capturedAt time.Time) time.Time {
+	day := cal.Date(capturedAt)
+	switch s.Kind {
+	case SettlementTPlusN:
+		if s.Days == 0 {
+			return cal.NextBusinessDay(day)
+		}
+		return cal.AddBusinessDays(day, s.Days)
+	case SettlementWeekly:
+		ahead := (int(s.Weekday) - int(day.Weekday()) + 7) % 7
+		if ahead == 0 {
+			ahead = 7
+		}
+		return cal.NextBusinessDay(day.AddDate(0, 0, ahead))
+	default:
+		return day
+	}
+}
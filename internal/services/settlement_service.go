@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// SettlementConfig controls merchant settlement
+type SettlementConfig struct {
+	DefaultSchedule SettlementSchedule // For merchants without their own schedule
+	Calendar        *SettlementCalendar
+	Reserves        *ReserveService // Holds back part of each batch; nil holds nothing
+}
+
+// SettlementService captures merchant payments into the merchant pending
+// account and settles them to the merchant's wallet on the merchant's schedule
+type SettlementService struct {
+	repo    repositories.SettlementRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	cfg     SettlementConfig
+	now     func() time.Time
+}
+
+// NewSettlementService creates a new settlement service
+func NewSettlementService(repo repositories.SettlementRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService, cfg SettlementConfig) *SettlementService {
+	return &SettlementService{repo: repo, wallets: wallets, tx: tx, audit: audit, cfg: cfg, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *SettlementService) WithClock(now func() time.Time) *SettlementService {
+	s.now = now
+	return s
+}
+
+// Capture books a merchant payment into pending. It becomes available in the
+// merchant's wallet once settled under the schedule in force at capture time.
+func (s *SettlementService) Capture(ctx context.Context, req dto.CaptureRequest) (*models.SettlementCapture, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	wallet, err := s.merchantWallet(ctx, req.WalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	var capture *models.SettlementCapture
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		schedule, err := s.schedule(ctx, wallet.ID)
+		if err != nil {
+			return err
+		}
+		clearing, err := s.wallets.systemWallet(ctx, models.SystemExternalClearing, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		pending, err := s.wallets.systemWallet(ctx, models.SystemMerchantPending, wallet.Currency)
+		if err != nil {
+			return err
+		}
+
+		credit := models.NewLedgerEntry(pending.ID, req.Reference, "credit", req.Amount, 0, fmt.Sprintf("Capture for wallet %d: %s", wallet.ID, req.Description))
+		posted, err := s.wallets.postJournal(ctx, []*models.LedgerEntry{credit, counterLeg(credit, clearing.ID)})
+		if err != nil {
+			return err
+		}
+
+		now := s.now().UTC()
+		capture = &models.SettlementCapture{
+			WalletID:      wallet.ID,
+			Currency:      wallet.Currency,
+			Amount:        req.Amount,
+			Reference:     req.Reference,
+			Description:   req.Description,
+			Schedule:      schedule.String(),
+			CapturedAt:    now,
+			EligibleOn:    schedule.EligibleOn(s.cfg.Calendar, now),
+			Status:        models.CapturePending,
+			JournalID:     posted.ID,
+			LedgerEntryID: credit.ID,
+		}
+		if err := s.repo.CreateCapture(ctx, capture); err != nil {
+			return fmt.Errorf("failed to record capture: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditFundsCaptured, "wallet", wallet.ID, nil, capture)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return capture, nil
+}
+
+// GetBalance returns a wallet's available, pending and reserved funds, with the
+// available funds split into cash and credit lots
+func (s *SettlementService) GetBalance(ctx context.Context, walletID int) (*dto.BalanceResponse, error) {
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.repo.PendingTotal(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending funds: %w", err)
+	}
+	reserved, err := s.cfg.Reserves.heldTotal(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	cash, credits, pocketed, err := s.wallets.balanceBreakdown(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BalanceResponse{
+		WalletID:   wallet.ID,
+		MerchantID: wallet.UserID,
+		Available:  wallet.Balance,
+		Pending:    pending,
+		Reserved:   reserved,
+		Currency:   wallet.Currency,
+		Cash:       cash,
+		Credits:    credits,
+		Pocketed:   pocketed,
+	}, nil
+}
+
+// GetSchedule returns the settlement schedule in force for a merchant wallet
+func (s *SettlementService) GetSchedule(ctx context.Context, walletID int) (*models.MerchantSettlementSchedule, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	stored, err := s.repo.GetSchedule(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement schedule: %w", err)
+	}
+	if stored == nil {
+		return &models.MerchantSettlementSchedule{WalletID: walletID, Schedule: s.cfg.DefaultSchedule.String()}, nil
+	}
+	return stored, nil
+}
+
+// SetSchedule changes a merchant's settlement schedule. Funds already
+// captured keep the schedule they were captured under.
+func (s *SettlementService) SetSchedule(ctx context.Context, walletID int, req dto.SetSettlementScheduleRequest) (*models.MerchantSettlementSchedule, error) {
+	schedule, err := ParseSettlementSchedule(req.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if _, err := s.merchantWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+
+	updated := &models.MerchantSettlementSchedule{WalletID: walletID, Schedule: schedule.String(), UpdatedAt: s.now().UTC()}
+	updated.UpdatedByType, updated.UpdatedByID = actor(ctx)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetSchedule(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to get settlement schedule: %w", err)
+		}
+		if err := s.repo.UpsertSchedule(ctx, updated); err != nil {
+			return fmt.Errorf("failed to save settlement schedule: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditSettlementSchedule, "wallet", walletID, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Settle pays a merchant's due funds to its wallet now, whatever its schedule
+func (s *SettlementService) Settle(ctx context.Context, req dto.SettleRequest) (*dto.SettlementBatchResponse, error) {
+	if _, err := s.merchantWallet(ctx, req.WalletID); err != nil {
+		return nil, err
+	}
+	var batch *dto.SettlementBatchResponse
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		batch, err = s.settleWallet(ctx, req.WalletID, s.cfg.Calendar.Date(s.now()), models.SettlementTriggerManual)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, fmt.Errorf("%w: no captured funds are due for settlement", ErrConflict)
+	}
+	return batch, nil
+}
+
+// RunDue settles every merchant with due funds; run by a background worker.
+// Nothing settles on weekends and holidays; funds due then settle on the
+// next business day. A merchant whose settlement fails is skipped until the
+// next run.
+func (s *SettlementService) RunDue(ctx context.Context) error {
+	today := s.cfg.Calendar.Date(s.now())
+	if !s.cfg.Calendar.IsBusinessDay(today) {
+		return nil
+	}
+	walletIDs, err := s.repo.ListDueWalletIDs(ctx, today)
+	if err != nil {
+		return fmt.Errorf("failed to list wallets due for settlement: %w", err)
+	}
+	for _, walletID := range walletIDs {
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			batch, err := s.settleWallet(ctx, walletID, today, models.SettlementTriggerScheduled)
+			if err == nil && batch != nil {
+				log.Printf("settlement: batch %d paid %d %s to wallet %d (%d items)", batch.ID, batch.Amount, batch.Currency, walletID, batch.ItemCount)
+			}
+			return err
+		})
+		if err != nil {
+			log.Printf("settlement: wallet %d: %v", walletID, err)
+		}
+	}
+	return nil
+}
+
+// settleWallet moves a wallet's due captures from pending to the wallet as
+// one batch, less any reserve held back. It returns nil when nothing is due. Scheduled runs leave
+// captures on a manual schedule alone.
+func (s *SettlementService) settleWallet(ctx context.Context, walletID int, day time.Time, trigger string) (*dto.SettlementBatchResponse, error) {
+	locked, err := s.repo.LockDueCaptures(ctx, walletID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock captures: %w", err)
+	}
+	var (
+		items []models.SettlementCapture
+		ids   []int
+		total int64
+	)
+	for _, capture := range locked {
+		if trigger == models.SettlementTriggerScheduled && capture.Schedule == SettlementManual {
+			continue
+		}
+		items = append(items, capture)
+		ids = append(ids, capture.ID)
+		total += capture.Amount
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	now := s.now().UTC()
+	batch := &models.SettlementBatch{
+		WalletID:       walletID,
+		Currency:       items[0].Currency,
+		Amount:         total,
+		ItemCount:      len(items),
+		SettlementDate: day,
+		Trigger:        trigger,
+		CreatedAt:      now,
+	}
+	batch.CreatedByType, batch.CreatedByID = actor(ctx)
+	if err := s.repo.CreateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to create settlement batch: %w", err)
+	}
+
+	pending, err := s.wallets.systemWallet(ctx, models.SystemMerchantPending, batch.Currency)
+	if err != nil {
+		return nil, err
+	}
+	credit := models.NewLedgerEntry(walletID, batch.ID, "credit", total, 0, fmt.Sprintf("Settlement batch %d (%d items)", batch.ID, len(items)))
+	posted, err := s.wallets.postJournal(ctx, []*models.LedgerEntry{credit, counterLeg(credit, pending.ID)})
+	if err != nil {
+		return nil, err
+	}
+	batch.JournalID, batch.LedgerEntryID = posted.ID, credit.ID
+	reserve, err := s.cfg.Reserves.holdFromSettlement(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if reserve != nil {
+		batch.ReserveAmount = reserve.Amount
+	}
+	if err := s.repo.SetBatchPosting(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to record settlement posting: %w", err)
+	}
+	if err := s.repo.MarkCapturesSettled(ctx, batch.ID, ids); err != nil {
+		return nil, fmt.Errorf("failed to mark captures settled: %w", err)
+	}
+	for i := range items {
+		items[i].Status, items[i].BatchID = models.CaptureSettled, &batch.ID
+	}
+	if err := s.audit.Record(ctx, models.AuditSettlementBatch, "settlement_batch", batch.ID, nil, batch); err != nil {
+		return nil, err
+	}
+	return &dto.SettlementBatchResponse{SettlementBatch: *batch, Items: items}, nil
+}
+
+// GetBatch returns a settlement batch with its line items
+func (s *SettlementService) GetBatch(ctx context.Context, batchID int) (*dto.SettlementBatchResponse, error) {
+	batch, err := s.repo.GetBatchByID(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement batch: %w", err)
+	}
+	if batch == nil {
+		return nil, ErrSettlementBatchNotFound
+	}
+	if _, err := s.wallets.getAuthorizedWallet(ctx, batch.WalletID); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListBatchCaptures(ctx, batch.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settlement items: %w", err)
+	}
+	return &dto.SettlementBatchResponse{SettlementBatch: *batch, Items: items}, nil
+}
+
+// ListBatches returns a merchant wallet's settlement batches, newest first
+func (s *SettlementService) ListBatches(ctx context.Context, walletID, limit int) ([]dto.SettlementBatchResponse, error) {
+	if _, err := s.wallets.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	batches, err := s.repo.ListBatchesByWalletID(ctx, walletID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settlement batches: %w", err)
+	}
+
+	resp := make([]dto.SettlementBatchResponse, 0, len(batches))
+	for i := range batches {
+		resp = append(resp, dto.SettlementBatchResponse{SettlementBatch: batches[i]})
+	}
+	return resp, nil
+}
+
+// merchantWallet loads a customer wallet the caller may act on
+func (s *SettlementService) merchantWallet(ctx context.Context, walletID int) (*models.Wallet, error) {
+	wallet, err := s.wallets.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts do not settle", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// schedule returns the settlement schedule in force for a wallet
+func (s *SettlementService) schedule(ctx context.Context, walletID int) (SettlementSchedule, error) {
+	stored, err := s.repo.GetSchedule(ctx, walletID)
+	if err != nil {
+		return SettlementSchedule{}, fmt.Errorf("failed to get settlement schedule: %w", err)
+	}
+	if stored == nil {
+		return s.cfg.DefaultSchedule, nil
+	}
+	return ParseSettlementSchedule(stored.Schedule)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// SplitPayment posts one payment from a source wallet, or from outside the
+// ledger through external clearing, to several destination wallets as a
+// single balanced journal. Any invalid or inactive destination rejects the
+// whole payment. Limits and risk rules apply to every party as they do to
+// transfers, and when any holder matches a watchlist the payment is held for
+// screening review instead of posted.
+func (s *WalletService) SplitPayment(ctx context.Context, req dto.SplitPaymentRequest) (*dto.SplitPaymentResponse, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	if len(req.Destinations) == 0 {
+		return nil, fmt.Errorf("%w: at least one destination is required", ErrInvalidRequest)
+	}
+	amounts, err := allocateSplit(req.Amount, req.Destinations)
+	if err != nil {
+		return nil, err
+	}
+
+	var source *models.Wallet
+	currency := req.Currency
+	if req.SourceWalletID != 0 {
+		if source, err = s.getAuthorizedWallet(ctx, req.SourceWalletID); err != nil {
+			return nil, err
+		}
+		if source.IsSystem() {
+			return nil, fmt.Errorf("%w: system accounts cannot be split from directly", ErrInvalidRequest)
+		}
+		if err := requireMonetary(source); err != nil {
+			return nil, err
+		}
+		if currency != "" && currency != source.Currency {
+			return nil, fmt.Errorf("%w: currency does not match the source wallet", ErrInvalidRequest)
+		}
+		currency = source.Currency
+	} else {
+		// Bringing money into other users' wallets is reserved for services
+		if _, restricted := callerOwnerID(ctx); restricted {
+			return nil, ErrForbidden
+		}
+		if currency == "" {
+			return nil, fmt.Errorf("%w: currency is required for an external payment", ErrInvalidRequest)
+		}
+	}
+
+	destinations := make([]*models.Wallet, len(req.Destinations))
+	for i, dest := range req.Destinations {
+		wallet, err := s.repo.GetWalletByID(ctx, dest.WalletID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wallet: %w", err)
+		}
+		switch {
+		case wallet == nil:
+			return nil, fmt.Errorf("%w: destination %d (%d)", ErrWalletNotFound, i, dest.WalletID)
+		case wallet.IsSystem():
+			return nil, fmt.Errorf("%w: destination %d is a system account", ErrInvalidRequest, i)
+		case !wallet.IsMonetary():
+			return nil, fmt.Errorf("%w: destination %d holds %s, not money", ErrInvalidRequest, i, wallet.Currency)
+		case wallet.Currency != currency:
+			return nil, fmt.Errorf("%w: destination %d is in %s, not %s", ErrInvalidRequest, i, wallet.Currency, currency)
+		case source != nil && wallet.ID == source.ID:
+			return nil, fmt.Errorf("%w: destination %d is the source wallet", ErrInvalidRequest, i)
+		}
+		destinations[i] = wallet
+	}
+
+	parties := destinations
+	if source != nil {
+		parties = append([]*models.Wallet{source}, destinations...)
+	}
+	matches, err := s.screenWallets(ctx, parties...)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) > 0 {
+		return s.holdSplit(ctx, req, matches)
+	}
+	return s.split(ctx, req, amounts, currency)
+}
+
+// split posts a validated split payment whose destinations get amounts
+func (s *WalletService) split(ctx context.Context, req dto.SplitPaymentRequest, amounts []int64, currency string) (*dto.SplitPaymentResponse, error) {
+	var resp *dto.SplitPaymentResponse
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var guards []journalGuard
+		sourceID := req.SourceWalletID
+		if sourceID == 0 {
+			clearing, err := s.systemWallet(ctx, models.SystemExternalClearing, currency)
+			if err != nil {
+				return err
+			}
+			sourceID = clearing.ID
+		}
+
+		debit := models.NewLedgerEntry(sourceID, req.Reference, "debit", req.Amount, 0, req.Description)
+		legs := []*models.LedgerEntry{debit}
+		var checks []*riskCheck
+		if req.SourceWalletID != 0 {
+			checks = append(checks, s.risk.check(debit, FeeTxnTransfer))
+			guards = append(guards, requireFunds(sourceID, req.Amount), s.limits.guard(sourceID, req.Amount, req.Amount, 0))
+		}
+		credited := map[int]int64{}
+		for i, dest := range req.Destinations {
+			if amounts[i] == 0 {
+				// A percentage share too small to receive anything is not posted
+				continue
+			}
+			description := dest.Description
+			if description == "" {
+				description = req.Description
+			}
+			credit := models.NewLedgerEntry(dest.WalletID, req.Reference, "credit", amounts[i], 0, description)
+			legs = append(legs, credit)
+			checks = append(checks, s.risk.check(credit, FeeTxnTransfer))
+			credited[dest.WalletID] += amounts[i]
+		}
+		for walletID, amount := range credited {
+			guards = append(guards, s.limits.guard(walletID, amount, 0, amount))
+		}
+		for _, check := range checks {
+			guards = append(guards, check.guard())
+		}
+
+		posted, err := s.postJournalAs(ctx, FeeTxnTransfer, legs, guards...)
+		if err != nil {
+			return err
+		}
+		for _, check := range checks {
+			if err := check.record(ctx); err != nil {
+				return err
+			}
+		}
+		source := toLedgerEntryResponse(debit)
+		resp = &dto.SplitPaymentResponse{Status: dto.TransferPosted, JournalID: posted.ID, Source: &source}
+		for _, leg := range legs[1:] {
+			resp.Destinations = append(resp.Destinations, toLedgerEntryResponse(leg))
+		}
+		// An external payment is debited from the clearing account, so that is
+		// the wallet whose after-state is recorded
+		return s.audit.Record(ctx, models.AuditPaymentSplit, "ledger_entry", debit.ID, nil, postingAuditState{Wallet: posted.After[sourceID], Entries: posted.Legs})
+	})
+	if err != nil {
+		return nil, s.risk.recordBlocked(ctx, err)
+	}
+	return resp, nil
+}
+
+// maxPercentageSplit is the largest amount percentage shares can divide
+// without overflowing
+const maxPercentageSplit = math.MaxInt64 / 10000
+
+// allocateSplit divides amount between destinations. Fixed shares come off
+// the top; percentage shares divide the rest by the largest remainder method,
+// so the shares always add up to amount exactly. Leftover units go to the
+// shares with the largest fractional parts, ties to the earlier destination.
+func allocateSplit(amount int64, destinations []dto.SplitDestination) ([]int64, error) {
+	amounts := make([]int64, len(destinations))
+	rest := amount
+	var totalBPS int64
+	for i, dest := range destinations {
+		switch {
+		case dest.WalletID == 0:
+			return nil, fmt.Errorf("%w: destination %d has no wallet_id", ErrInvalidRequest, i)
+		case dest.Amount < 0 || dest.PercentageBPS < 0:
+			return nil, fmt.Errorf("%w: destination %d has a negative share", ErrInvalidRequest, i)
+		case (dest.Amount > 0) == (dest.PercentageBPS > 0):
+			return nil, fmt.Errorf("%w: destination %d needs exactly one of amount or percentage_bps", ErrInvalidRequest, i)
+		}
+		if dest.Amount > rest {
+			return nil, fmt.Errorf("%w: fixed shares exceed the amount", ErrInvalidRequest)
+		}
+		if dest.PercentageBPS > 10000 {
+			return nil, fmt.Errorf("%w: destination %d has more than 10000 basis points", ErrInvalidRequest, i)
+		}
+		amounts[i] = dest.Amount
+		rest -= dest.Amount
+		totalBPS += dest.PercentageBPS
+	}
+	if totalBPS == 0 {
+		if rest != 0 {
+			return nil, fmt.Errorf("%w: shares add up to %d, not %d", ErrInvalidRequest, amount-rest, amount)
+		}
+		return amounts, nil
+	}
+	if totalBPS != 10000 {
+		return nil, fmt.Errorf("%w: percentage shares add up to %d basis points, not 10000", ErrInvalidRequest, totalBPS)
+	}
+	if rest > maxPercentageSplit {
+		// rest * PercentageBPS would overflow
+		return nil, fmt.Errorf("%w: amounts over %d cannot be split by percentage", ErrInvalidRequest, maxPercentageSplit)
+	}
+
+	type remainder struct {
+		index int
+		value int64
+	}
+	var remainders []remainder
+	allocated := int64(0)
+	for i, dest := range destinations {
+		if dest.PercentageBPS == 0 {
+			continue
+		}
+		amounts[i] = rest * dest.PercentageBPS / 10000
+		allocated += amounts[i]
+		remainders = append(remainders, remainder{index: i, value: rest * dest.PercentageBPS % 10000})
+	}
+	sort.SliceStable(remainders, func(a, b int) bool { return remainders[a].value > remainders[b].value })
+	for i := int64(0); i < rest-allocated; i++ {
+		amounts[remainders[i].index]++
+	}
+	return amounts, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+)
+
+func TestAllocateSplit(t *testing.T) {
+	fixed := func(amount int64) dto.SplitDestination { return dto.SplitDestination{WalletID: 1, Amount: amount} }
+	share := func(bps int64) dto.SplitDestination { return dto.SplitDestination{WalletID: 1, PercentageBPS: bps} }
+	tests := []struct {
+		name         string
+		amount       int64
+		destinations []dto.SplitDestination
+
+		want    []int64
+		wantErr bool
+	}{
+		{name: "fixed shares", amount: 1000, destinations: []dto.SplitDestination{fixed(600), fixed(400)}, want: []int64{600, 400}},
+		{name: "even percentages", amount: 1000, destinations: []dto.SplitDestination{share(2500), share(7500)}, want: []int64{250, 750}},
+		{name: "largest remainder gets the leftover", amount: 100, destinations: []dto.SplitDestination{share(3333), share(3333), share(3334)}, want: []int64{33, 33, 34}},
+		{name: "remainder ties go to the earlier share", amount: 1, destinations: []dto.SplitDestination{share(5000), share(5000)}, want: []int64{1, 0}},
+		{name: "leftover spread by remainder then order", amount: 2, destinations: []dto.SplitDestination{share(3333), share(3333), share(3334)}, want: []int64{1, 0, 1}},
+		{name: "percentages divide what fixed shares leave", amount: 1000, destinations: []dto.SplitDestination{share(5000), fixed(250), share(5000)}, want: []int64{375, 250, 375}},
+		{name: "mixed shares with a leftover", amount: 1001, destinations: []dto.SplitDestination{fixed(250), share(5000), share(5000)}, want: []int64{250, 376, 375}},
+		{name: "largest amount split by percentage", amount: maxPercentageSplit, destinations: []dto.SplitDestination{share(10000)}, want: []int64{maxPercentageSplit}},
+		{name: "fixed share brings the rest under the overflow guard", amount: maxPercentageSplit + 1000, destinations: []dto.SplitDestination{fixed(1000), share(10000)}, want: []int64{1000, maxPercentageSplit}},
+
+		{name: "overflow guard", amount: maxPercentageSplit + 1, destinations: []dto.SplitDestination{share(10000)}, wantErr: true},
+		{name: "fixed shares short of the amount", amount: 1000, destinations: []dto.SplitDestination{fixed(600), fixed(300)}, wantErr: true},
+		{name: "fixed shares over the amount", amount: 1000, destinations: []dto.SplitDestination{fixed(600), fixed(500)}, wantErr: true},
+		{name: "percentages short of 100%", amount: 1000, destinations: []dto.SplitDestination{share(5000), share(4000)}, wantErr: true},
+		{name: "percentage over 100%", amount: 1000, destinations: []dto.SplitDestination{share(10001)}, wantErr: true},
+		{name: "amount and percentage", amount: 1000, destinations: []dto.SplitDestination{{WalletID: 1, Amount: 500, PercentageBPS: 5000}}, wantErr: true},
+		{name: "neither amount nor percentage", amount: 1000, destinations: []dto.SplitDestination{{WalletID: 1}}, wantErr: true},
+		{name: "negative share", amount: 1000, destinations: []dto.SplitDestination{fixed(1100), fixed(-100)}, wantErr: true},
+		{name: "missing wallet", amount: 1000, destinations: []dto.SplitDestination{{Amount: 1000}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := allocateSplit(tt.amount, tt.destinations)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRequest) {
+					t.Errorf("err = %v, want ErrInvalidRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateSplit = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// TaxRules lists the taxes levied on fees. Every rule for the fee's currency
+// that is in effect when the fee is charged applies.
+type TaxRules struct {
+	Rules []TaxRule `json:"rules"`
+}
+
+// TaxRule is one tax, such as Nigerian VAT at 7.5%. Rates are in basis points
+// (750 = 7.5%). An inclusive tax is carved out of the fee; an exclusive tax is
+// charged on top of it. Effective dates are YYYY-MM-DD or RFC 3339; the rule
+// applies from EffectiveFrom up to but excluding EffectiveTo, either of which
+// may be omitted.
+type TaxRule struct {
+	Name          string `json:"name"`
+	Jurisdiction  string `json:"jurisdiction"`
+	Currency      string `json:"currency"`
+	RateBPS       int64  `json:"rate_bps"`
+	Inclusive     bool   `json:"inclusive"`
+	EffectiveFrom string `json:"effective_from"`
+	EffectiveTo   string `json:"effective_to"`
+
+	from, to time.Time
+}
+
+// LoadTaxRules reads tax rules from a JSON file
+func LoadTaxRules(path string) (*TaxRules, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTaxRules(raw)
+}
+
+// ParseTaxRules decodes and validates JSON tax rules
+func ParseTaxRules(raw []byte) (*TaxRules, error) {
+	var rules TaxRules
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("invalid tax rules: %w", err)
+	}
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if err := rule.parse(); err != nil {
+			return nil, fmt.Errorf("tax rule %d (%s): %w", i, rule.Name, err)
+		}
+		for _, other := range rules.Rules[:i] {
+			if other.Name == rule.Name && other.Jurisdiction == rule.Jurisdiction && other.Currency == rule.Currency && rule.overlaps(other) {
+				return nil, fmt.Errorf("tax rule %d (%s): overlaps an earlier rule for the same tax", i, rule.Name)
+			}
+		}
+	}
+	return &rules, nil
+}
+
+func (r *TaxRule) parse() error {
+	if r.Name == "" || r.Jurisdiction == "" || r.Currency == "" {
+		return errors.New("name, jurisdiction and currency are required")
+	}
+	if r.RateBPS <= 0 {
+		return errors.New("rate_bps must be positive")
+	}
+	if r.RateBPS > maxFeeBPS {
+		return fmt.Errorf("rate_bps cannot exceed %d", maxFeeBPS)
+	}
+	var err error
+	if r.from, err = parseEffectiveDate(r.EffectiveFrom); err != nil {
+		return fmt.Errorf("effective_from: %w", err)
+	}
+	if r.to, err = parseEffectiveDate(r.EffectiveTo); err != nil {
+		return fmt.Errorf("effective_to: %w", err)
+	}
+	if !r.to.IsZero() && !r.to.After(r.from) {
+		return errors.New("effective_to must be after effective_from")
+	}
+	return nil
+}
+
+func parseEffectiveDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Time{}, errors.New("expected YYYY-MM-DD or an RFC 3339 timestamp")
+}
+
+// inEffect reports whether the rule applies at t
+func (r TaxRule) inEffect(t time.Time) bool {
+	return !t.Before(r.from) && (r.to.IsZero() || t.Before(r.to))
+}
+
+func (r TaxRule) overlaps(other TaxRule) bool {
+	startsBeforeOtherEnds := other.to.IsZero() || r.from.Before(other.to)
+	endsAfterOtherStarts := r.to.IsZero() || other.from.Before(r.to)
+	return startsBeforeOtherEnds && endsAfterOtherStarts
+}
+
+// TaxService levies taxes on fees, records them for filing and reports on them
+type TaxService struct {
+	rules *TaxRules
+	repo  repositories.TaxRepository
+	now   func() time.Time
+}
+
+// NewTaxService creates a new tax service. Nil rules levy no tax.
+func NewTaxService(rules *TaxRules, repo repositories.TaxRepository) *TaxService {
+	return &TaxService{rules: rules, repo: repo, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *TaxService) WithClock(now func() time.Time) *TaxService {
+	s.now = now
+	return s
+}
+
+// Apply adds the taxes on fee to its breakdown and sets fee.Charged, the
+// total taken from the payer. Inclusive taxes are carved out of fee.Total
+// from a single net amount so that they add up exactly; exclusive taxes are
+// levied on that net amount and added on top.
+func (s *TaxService) Apply(fee *dto.FeeBreakdown, currency string) error {
+	fee.Taxes = nil
+	fee.Charged = fee.Total
+	if s == nil || s.rules == nil {
+		return nil
+	}
+
+	now := s.now().UTC()
+	var applicable []TaxRule
+	var inclusiveBPS int64
+	for _, rule := range s.rules.Rules {
+		if rule.Currency == currency && rule.inEffect(now) {
+			applicable = append(applicable, rule)
+			if rule.Inclusive {
+				inclusiveBPS += rule.RateBPS
+			}
+		}
+	}
+	if len(applicable) == 0 {
+		return nil
+	}
+
+	// fee.Total*10000 below must not overflow; rates are capped at 10000 bps
+	// so the tax amounts then cannot either
+	if fee.Total > (math.MaxInt64-(10000+inclusiveBPS)/2)/10000 {
+		return fmt.Errorf("%w: fee of %d is too large to tax", ErrInvalidRequest, fee.Total)
+	}
+	net := (fee.Total*10000 + (10000+inclusiveBPS)/2) / (10000 + inclusiveBPS)
+	carved := int64(0)
+	lastInclusive := -1
+	for _, rule := range applicable {
+		tax := dto.TaxBreakdown{
+			Name:         rule.Name,
+			Jurisdiction: rule.Jurisdiction,
+			RateBPS:      rule.RateBPS,
+			Inclusive:    rule.Inclusive,
+			Base:         net,
+			Amount:       (net*rule.RateBPS + 5000) / 10000,
+		}
+		if rule.Inclusive {
+			carved += tax.Amount
+			lastInclusive = len(fee.Taxes)
+		} else {
+			fee.Charged += tax.Amount
+		}
+		fee.Taxes = append(fee.Taxes, tax)
+	}
+	// Absorb rounding so the inclusive taxes and the net fee add up to the fee
+	if lastInclusive >= 0 {
+		fee.Taxes[lastInclusive].Amount += fee.Total - net - carved
+	}
+
+	// Taxes that round to nothing on a tiny fee are not posted
+	taxes := fee.Taxes[:0]
+	for _, tax := range fee.Taxes {
+		if tax.Amount > 0 {
+			taxes = append(taxes, tax)
+		}
+	}
+	fee.Taxes = taxes
+	return nil
+}
+
+// record stores the taxes of a posted fee. taxLegs holds the tax payable
+// credit for each entry in fee.Taxes.
+func (s *TaxService) record(ctx context.Context, journalID, currency string, fee *dto.FeeBreakdown, taxLegs []*models.LedgerEntry) error {
+	for i := range fee.Taxes {
+		tax := &fee.Taxes[i]
+		tax.LedgerEntryID = taxLegs[i].ID
+		err := s.repo.CreateTaxPosting(ctx, &models.TaxPosting{
+			JournalID:     journalID,
+			LedgerEntryID: taxLegs[i].ID,
+			Jurisdiction:  tax.Jurisdiction,
+			TaxName:       tax.Name,
+			Currency:      currency,
+			RateBPS:       tax.RateBPS,
+			Inclusive:     tax.Inclusive,
+			BaseAmount:    tax.Base,
+			TaxAmount:     tax.Amount,
+			CreatedAt:     s.now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record tax posting: %w", err)
+		}
+	}
+	return nil
+}
+
+// reverse records negative postings for any tax collected in a reversed journal
+func (s *TaxService) reverse(ctx context.Context, journalID, reversalJournalID string) error {
+	if s == nil {
+		return nil
+	}
+	if _, err := s.repo.ReverseTaxPostings(ctx, journalID, reversalJournalID, s.now().UTC()); err != nil {
+		return fmt.Errorf("failed to reverse tax postings: %w", err)
+	}
+	return nil
+}
+
+// Summary totals the tax collected over [from, to) by jurisdiction, tax,
+// currency and rate, net of reversals
+func (s *TaxService) Summary(ctx context.Context, from, to time.Time) (*models.TaxSummary, error) {
+	if from.IsZero() || to.IsZero() {
+		return nil, fmt.Errorf("%w: from and to are required", ErrInvalidRequest)
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidRequest)
+	}
+	from, to = from.UTC(), to.UTC()
+	lines, err := s.repo.SummarizeTaxPostings(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize tax postings: %w", err)
+	}
+	if lines == nil {
+		lines = []models.TaxSummaryLine{}
+	}
+	return &models.TaxSummary{From: from, To: to, GeneratedAt: s.now().UTC(), Lines: lines}, nil
+}
+
+// WriteTaxSummaryCSV writes a tax summary as CSV, one row per line
+func WriteTaxSummaryCSV(w io.Writer, summary *models.TaxSummary) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"period_from", "period_to", "jurisdiction", "tax_name", "currency", "rate_bps", "inclusive", "postings", "base_amount", "tax_amount"}); err != nil {
+		return err
+	}
+	for _, l := range summary.Lines {
+		row := []string{summary.From.Format(time.RFC3339), summary.To.Format(time.RFC3339), l.Jurisdiction, l.TaxName, l.Currency,
+			strconv.FormatInt(l.RateBPS, 10), strconv.FormatBool(l.Inclusive), strconv.Itoa(l.Postings),
+			strconv.FormatInt(l.BaseAmount, 10), strconv.FormatInt(l.TaxAmount, 10)}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// taxServiceAt parses rules and levies them as of 2 March 2026
+func taxServiceAt(t *testing.T, rules string) *TaxService {
+	t.Helper()
+	parsed, err := ParseTaxRules([]byte(`{"rules":[` + rules + `]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTaxService(parsed, nil).WithClock(func() time.Time { return time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) })
+}
+
+func TestParseTaxRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{name: "valid", rules: `{"name":"VAT","jurisdiction":"GB","currency":"GBP","rate_bps":2000,"effective_from":"2026-01-01"}`},
+		{name: "missing jurisdiction", rules: `{"name":"VAT","currency":"GBP","rate_bps":2000}`, wantErr: true},
+		{name: "zero rate", rules: `{"name":"VAT","jurisdiction":"GB","currency":"GBP"}`, wantErr: true},
+		{name: "rate over 100%", rules: `{"name":"VAT","jurisdiction":"GB","currency":"GBP","rate_bps":10001}`, wantErr: true},
+		{name: "ends before it starts", rules: `{"name":"VAT","jurisdiction":"GB","currency":"GBP","rate_bps":2000,"effective_from":"2026-02-01","effective_to":"2026-01-01"}`, wantErr: true},
+		{
+			name: "rate change on a date",
+			rules: `{"name":"VAT","jurisdiction":"NG","currency":"NGN","rate_bps":500,"effective_to":"2020-02-01"},
+				{"name":"VAT","jurisdiction":"NG","currency":"NGN","rate_bps":750,"effective_from":"2020-02-01"}`,
+		},
+		{
+			name: "overlapping rates",
+			rules: `{"name":"VAT","jurisdiction":"NG","currency":"NGN","rate_bps":500,"effective_to":"2020-03-01"},
+				{"name":"VAT","jurisdiction":"NG","currency":"NGN","rate_bps":750,"effective_from":"2020-02-01"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTaxRules([]byte(`{"rules":[` + tt.rules + `]}`))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaxServiceApply(t *testing.T) {
+	const (
+		vat      = `{"name":"VAT","jurisdiction":"GB","currency":"GBP","rate_bps":2000,"inclusive":true}`
+		levy     = `{"name":"Levy","jurisdiction":"GB","currency":"GBP","rate_bps":500}`
+		stampTax = `{"name":"Stamp","jurisdiction":"NG","currency":"GBP","rate_bps":750,"inclusive":true}`
+		expired  = `{"name":"Old levy","jurisdiction":"GB","currency":"GBP","rate_bps":300,"effective_to":"2026-01-01"}`
+	)
+	type tax struct {
+		name   string
+		base   int64
+		amount int64
+	}
+	tests := []struct {
+		name  string
+		rules string
+		total int64
+
+		wantTaxes   []tax
+		wantCharged int64
+	}{
+		{
+			name:        "exclusive added on top",
+			rules:       levy,
+			total:       250,
+			wantTaxes:   []tax{{"Levy", 250, 13}}, // 12.5 rounds up
+			wantCharged: 263,
+		},
+		{
+			name:        "exclusive rounds to nearest",
+			rules:       levy,
+			total:       249,
+			wantTaxes:   []tax{{"Levy", 249, 12}}, // 12.45
+			wantCharged: 261,
+		},
+		{
+			name:        "inclusive carved out",
+			rules:       vat,
+			total:       120,
+			wantTaxes:   []tax{{"VAT", 100, 20}},
+			wantCharged: 120,
+		},
+		{
+			name:        "inclusive net rounds to nearest",
+			rules:       vat,
+			total:       125,
+			wantTaxes:   []tax{{"VAT", 104, 21}}, // Net 104.17; the tax takes the rest
+			wantCharged: 125,
+		},
+		{
+			name:        "last inclusive tax absorbs the rounding",
+			rules:       stampTax + `,` + vat,
+			total:       7,
+			wantTaxes:   []tax{{"VAT", 5, 2}}, // Net 5.49; stamp duty of 0.375 rounds away
+			wantCharged: 7,
+		},
+		{
+			name:        "exclusive on the inclusive net",
+			rules:       vat + `,` + levy,
+			total:       120,
+			wantTaxes:   []tax{{"VAT", 100, 20}, {"Levy", 100, 5}},
+			wantCharged: 125,
+		},
+		{
+			name:        "rules out of effect are ignored",
+			rules:       expired,
+			total:       250,
+			wantCharged: 250,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := &dto.FeeBreakdown{Total: tt.total}
+			if err := taxServiceAt(t, tt.rules).Apply(fee, "GBP"); err != nil {
+				t.Fatal(err)
+			}
+			var got []tax
+			for _, levied := range fee.Taxes {
+				got = append(got, tax{levied.Name, levied.Base, levied.Amount})
+			}
+			if !reflect.DeepEqual(got, tt.wantTaxes) || fee.Charged != tt.wantCharged {
+				t.Errorf("taxes = %v charging %d, want %v charging %d", got, fee.Charged, tt.wantTaxes, tt.wantCharged)
+			}
+		})
+	}
+}
+
+func TestTaxServiceApplyRejectsOverflowingFee(t *testing.T) {
+	svc := taxServiceAt(t, `{"name":"VAT","jurisdiction":"GB","currency":"GBP","rate_bps":2000,"inclusive":true}`)
+	if err := svc.Apply(&dto.FeeBreakdown{Total: maxFeeAmount - 1}, "GBP"); err != nil {
+		t.Errorf("Apply(max-1): %v", err)
+	}
+	if err := svc.Apply(&dto.FeeBreakdown{Total: maxFeeAmount}, "GBP"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Apply(max): err = %v, want ErrInvalidRequest", err)
+	}
+	// Fees in other currencies are not taxed, so they cannot overflow
+	if err := svc.Apply(&dto.FeeBreakdown{Total: maxFeeAmount}, "EUR"); err != nil {
+		t.Errorf("Apply(EUR): %v", err)
+	}
+}
+
+func TestQuoteFeeCapsTaxedCreditAtAmount(t *testing.T) {
+	svc := NewWalletService(nil, nil,
+		WithFeeSchedule(&FeeSchedule{Rules: []FeeRule{{Name: "credits", TransactionType: FeeTxnCredit, Flat: 75}}}),
+		WithTaxService(taxServiceAt(t, `{"name":"Levy","jurisdiction":"GB","currency":"GBP","rate_bps":2000}`)),
+	)
+
+	// 33 plus 20% comes to 40 exactly; 34 would charge 41
+	fee, err := svc.quoteFee(&models.Wallet{Currency: "GBP"}, FeeTxnCredit, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.Total != 33 || fee.Charged != 40 || fee.Limit != "amount" {
+		t.Errorf("fee = %d charged %d limited by %q, want 33 charged 40 limited by the amount", fee.Total, fee.Charged, fee.Limit)
+	}
+}
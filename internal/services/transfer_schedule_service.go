@@ -0,0 +1,460 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+	"github.com/kodra-pay/wallet-ledger-service/internal/schedule"
+)
+
+const (
+	// maxSchedulesPerTick bounds the work one worker tick does
+	maxSchedulesPerTick = 100
+
+	// transferFailureRetryDelay postpones a schedule whose run failed with an
+	// unexpected error, doubling with each failure in a row up to
+	// maxTransferFailureRetryDelay
+	transferFailureRetryDelay    = time.Hour
+	maxTransferFailureRetryDelay = 24 * time.Hour
+)
+
+// TransferScheduleConfig controls standing order execution
+type TransferScheduleConfig struct {
+	MaxRetries    int           // Default retries of an occurrence after insufficient funds
+	RetryInterval time.Duration // Wait between retries
+}
+
+// TransferScheduleService stores standing orders and executes them when due
+type TransferScheduleService struct {
+	repo    repositories.TransferScheduleRepository
+	wallets *WalletService
+	tx      repositories.Transactor
+	audit   *AuditService
+	cfg     TransferScheduleConfig
+	now     func() time.Time
+}
+
+// NewTransferScheduleService creates a new transfer schedule service
+func NewTransferScheduleService(repo repositories.TransferScheduleRepository, wallets *WalletService, tx repositories.Transactor, audit *AuditService, cfg TransferScheduleConfig) *TransferScheduleService {
+	return &TransferScheduleService{repo: repo, wallets: wallets, tx: tx, audit: audit, cfg: cfg, now: time.Now}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *TransferScheduleService) WithClock(now func() time.Time) *TransferScheduleService {
+	s.now = now
+	return s
+}
+
+// rule parses a schedule's recurrence rule in its time zone
+func rule(sched *models.TransferSchedule) (schedule.Rule, error) {
+	loc, err := time.LoadLocation(sched.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", sched.Timezone)
+	}
+	return schedule.Parse(sched.RuleKind, sched.Rule, sched.StartAt, loc)
+}
+
+// nextOccurrence returns the first occurrence after t within the schedule's
+// end date, or nil when there is none
+func nextOccurrence(r schedule.Rule, sched *models.TransferSchedule, t time.Time) *time.Time {
+	next := r.Next(t)
+	if next.IsZero() || (sched.EndAt != nil && next.After(*sched.EndAt)) {
+		return nil
+	}
+	next = next.UTC()
+	return &next
+}
+
+// CreateSchedule validates and stores a standing order from a wallet the caller owns
+func (s *TransferScheduleService) CreateSchedule(ctx context.Context, req dto.CreateTransferScheduleRequest) (*dto.TransferScheduleResponse, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	if req.FromWalletID == req.ToWalletID {
+		return nil, fmt.Errorf("%w: cannot transfer to the same wallet", ErrInvalidRequest)
+	}
+	from, err := s.wallets.GetWalletByID(ctx, req.FromWalletID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.wallets.repo.GetWalletByID(ctx, req.ToWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if to == nil {
+		return nil, fmt.Errorf("%w: destination %d", ErrWalletNotFound, req.ToWalletID)
+	}
+	if from.Type == models.WalletTypeSystem || to.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot take part in transfers", ErrInvalidRequest)
+	}
+	if from.AssetType == models.AssetPoints || !to.IsMonetary() {
+		return nil, fmt.Errorf("%w: points cannot be transferred", ErrInvalidRequest)
+	}
+	if from.Currency != to.Currency {
+		return nil, fmt.Errorf("%w: wallets have different currencies", ErrInvalidRequest)
+	}
+
+	now := s.now().UTC().Truncate(time.Microsecond)
+	sched := &models.TransferSchedule{
+		FromWalletID: req.FromWalletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       req.Amount,
+		Description:  req.Description,
+		RuleKind:     req.RuleKind,
+		Rule:         req.Rule,
+		Timezone:     req.Timezone,
+		StartAt:      now,
+		Status:       models.TransferScheduleActive,
+		MaxRetries:   s.cfg.MaxRetries,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if sched.Timezone == "" {
+		sched.Timezone = "UTC"
+	}
+	if req.StartAt != nil {
+		if req.StartAt.Before(now.Add(-time.Minute)) {
+			return nil, fmt.Errorf("%w: start_at is in the past", ErrInvalidRequest)
+		}
+		sched.StartAt = req.StartAt.UTC()
+	}
+	if req.EndAt != nil {
+		if !req.EndAt.After(sched.StartAt) {
+			return nil, fmt.Errorf("%w: end_at must be after start_at", ErrInvalidRequest)
+		}
+		endAt := req.EndAt.UTC()
+		sched.EndAt = &endAt
+	}
+	if req.MaxRetries != nil {
+		if *req.MaxRetries < 0 {
+			return nil, fmt.Errorf("%w: max_retries cannot be negative", ErrInvalidRequest)
+		}
+		sched.MaxRetries = *req.MaxRetries
+	}
+
+	r, err := rule(sched)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	sched.NextRunAt = nextOccurrence(r, sched, sched.StartAt.Add(-time.Nanosecond))
+	if sched.NextRunAt == nil {
+		return nil, fmt.Errorf("%w: the rule has no occurrences before end_at", ErrInvalidRequest)
+	}
+	sched.NextAttemptAt = sched.NextRunAt
+
+	sched.CreatedByType, sched.CreatedByID = actor(ctx)
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateSchedule(ctx, sched); err != nil {
+			return fmt.Errorf("failed to create transfer schedule: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditScheduleCreated, "transfer_schedule", sched.ID, nil, sched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TransferScheduleResponse{TransferSchedule: *sched}, nil
+}
+
+// GetSchedule returns a standing order the caller may see
+func (s *TransferScheduleService) GetSchedule(ctx context.Context, scheduleID int) (*dto.TransferScheduleResponse, error) {
+	sched, err := s.getAuthorizedSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TransferScheduleResponse{TransferSchedule: *sched}, nil
+}
+
+// ListSchedules returns the standing orders paying from or into a wallet
+func (s *TransferScheduleService) ListSchedules(ctx context.Context, walletID int) ([]dto.TransferScheduleResponse, error) {
+	if _, err := s.wallets.GetWalletByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.ListSchedulesByWalletID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer schedules: %w", err)
+	}
+
+	resp := make([]dto.TransferScheduleResponse, 0, len(schedules))
+	for i := range schedules {
+		resp = append(resp, dto.TransferScheduleResponse{TransferSchedule: schedules[i]})
+	}
+	return resp, nil
+}
+
+// ListRuns returns a schedule's execution history, newest first
+func (s *TransferScheduleService) ListRuns(ctx context.Context, scheduleID, limit int) ([]models.TransferRun, error) {
+	if _, err := s.getAuthorizedSchedule(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	runs, err := s.repo.ListRuns(ctx, scheduleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer runs: %w", err)
+	}
+	if runs == nil {
+		runs = []models.TransferRun{}
+	}
+	return runs, nil
+}
+
+// PauseSchedule stops a schedule from running until it is resumed
+func (s *TransferScheduleService) PauseSchedule(ctx context.Context, scheduleID int) (*dto.TransferScheduleResponse, error) {
+	return s.setStatus(ctx, scheduleID, models.TransferSchedulePaused)
+}
+
+// ResumeSchedule restarts a paused schedule. Occurrences missed while paused are skipped.
+func (s *TransferScheduleService) ResumeSchedule(ctx context.Context, scheduleID int) (*dto.TransferScheduleResponse, error) {
+	return s.setStatus(ctx, scheduleID, models.TransferScheduleActive)
+}
+
+// CancelSchedule permanently stops a schedule
+func (s *TransferScheduleService) CancelSchedule(ctx context.Context, scheduleID int) (*dto.TransferScheduleResponse, error) {
+	return s.setStatus(ctx, scheduleID, models.TransferScheduleCancelled)
+}
+
+func (s *TransferScheduleService) setStatus(ctx context.Context, scheduleID int, status string) (*dto.TransferScheduleResponse, error) {
+	if _, err := s.getAuthorizedSchedule(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+
+	var updated *models.TransferSchedule
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sched, err := s.repo.GetScheduleByIDForUpdate(ctx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("failed to lock transfer schedule: %w", err)
+		}
+		if sched == nil {
+			return ErrTransferScheduleNotFound
+		}
+		switch {
+		case sched.Status == status:
+			updated = sched
+			return nil
+		case sched.Status == models.TransferScheduleCancelled || sched.Status == models.TransferScheduleCompleted:
+			return fmt.Errorf("%w: schedule is %s", ErrConflict, sched.Status)
+		}
+
+		before := *sched
+		now := s.now().UTC()
+		sched.Status = status
+		sched.UpdatedAt = now
+		switch status {
+		case models.TransferScheduleActive:
+			r, err := rule(sched)
+			if err != nil {
+				return err
+			}
+			if sched.NextRunAt != nil && sched.NextRunAt.Before(now) {
+				sched.NextRunAt = nextOccurrence(r, sched, now)
+				sched.Attempts = 0
+			}
+			sched.NextAttemptAt = sched.NextRunAt
+			if sched.NextRunAt == nil {
+				sched.Status = models.TransferScheduleCompleted
+			}
+		case models.TransferScheduleCancelled:
+			sched.NextRunAt, sched.NextAttemptAt = nil, nil
+		}
+		if err := s.repo.UpdateSchedule(ctx, sched); err != nil {
+			return fmt.Errorf("failed to update transfer schedule: %w", err)
+		}
+		updated = sched
+		return s.audit.Record(ctx, models.AuditScheduleStatus, "transfer_schedule", sched.ID, before, sched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TransferScheduleResponse{TransferSchedule: *updated}, nil
+}
+
+// getAuthorizedSchedule loads a schedule and checks the caller owns its source wallet
+func (s *TransferScheduleService) getAuthorizedSchedule(ctx context.Context, scheduleID int) (*models.TransferSchedule, error) {
+	sched, err := s.repo.GetScheduleByID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer schedule: %w", err)
+	}
+	if sched == nil {
+		return nil, ErrTransferScheduleNotFound
+	}
+	if _, err := s.wallets.GetWalletByID(ctx, sched.FromWalletID); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// RunDue executes every schedule that is due; run by a background worker.
+// Each occurrence is claimed, executed and advanced in one transaction, and a
+// unique index on succeeded runs makes a second execution impossible.
+func (s *TransferScheduleService) RunDue(ctx context.Context) error {
+	for i := 0; i < maxSchedulesPerTick; i++ {
+		var claimedID int
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := s.now().UTC()
+			sched, err := s.repo.ClaimDueSchedule(ctx, now)
+			if err != nil {
+				return fmt.Errorf("failed to claim transfer schedule: %w", err)
+			}
+			if sched == nil {
+				return nil
+			}
+			claimedID = sched.ID
+			return s.execute(ctx, sched, now)
+		})
+		if err != nil && claimedID != 0 {
+			// The claim rolled back with the failure; record it separately and
+			// back off so the schedule does not hold up the ones due after it
+			if recordErr := s.recordFailure(ctx, claimedID, err); recordErr != nil {
+				return fmt.Errorf("%w (recording the failure: %v)", err, recordErr)
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if claimedID == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// recordFailure records a run that failed with an unexpected error and
+// postpones the schedule's next attempt. The failure does not count against
+// the occurrence's retries, which are for refusals such as a lack of funds.
+func (s *TransferScheduleService) recordFailure(ctx context.Context, scheduleID int, cause error) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		now := s.now().UTC()
+		sched, err := s.repo.GetScheduleByIDForUpdate(ctx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("failed to get transfer schedule: %w", err)
+		}
+		if sched == nil || sched.NextRunAt == nil {
+			return ErrTransferScheduleNotFound
+		}
+
+		run := &models.TransferRun{
+			ScheduleID:   sched.ID,
+			OccurrenceAt: *sched.NextRunAt,
+			Attempt:      sched.Attempts + 1,
+			Status:       models.TransferRunFailed,
+			Error:        cause.Error(),
+			CreatedAt:    now,
+		}
+		if err := s.repo.CreateRun(ctx, run); err != nil {
+			return fmt.Errorf("failed to record transfer run: %w", err)
+		}
+		if err := s.repo.UpdateRun(ctx, run); err != nil {
+			return fmt.Errorf("failed to update transfer run: %w", err)
+		}
+
+		sched.Failures++
+		retryAt := now.Add(transferFailureBackoff(sched.Failures))
+		sched.NextAttemptAt = &retryAt
+		sched.UpdatedAt = now
+		log.Printf("transfer schedule %d: occurrence %s failure %d, retrying at %s: %v", sched.ID, run.OccurrenceAt.Format(time.RFC3339), sched.Failures, retryAt.Format(time.RFC3339), cause)
+		if err := s.repo.UpdateSchedule(ctx, sched); err != nil {
+			return fmt.Errorf("failed to postpone transfer schedule: %w", err)
+		}
+		return nil
+	})
+}
+
+// transferFailureBackoff returns how long to wait after the given number of
+// unexpected failures in a row
+func transferFailureBackoff(failures int) time.Duration {
+	delay := transferFailureRetryDelay
+	for i := 1; i < failures && delay < maxTransferFailureRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxTransferFailureRetryDelay)
+}
+
+// execute runs the schedule's current occurrence inside the claiming transaction
+func (s *TransferScheduleService) execute(ctx context.Context, sched *models.TransferSchedule, now time.Time) error {
+	r, err := rule(sched)
+	if err != nil {
+		return fmt.Errorf("transfer schedule %d: %w", sched.ID, err)
+	}
+
+	run := &models.TransferRun{
+		ScheduleID:   sched.ID,
+		OccurrenceAt: *sched.NextRunAt,
+		Attempt:      sched.Attempts + 1,
+		Status:       models.TransferRunRunning,
+		CreatedAt:    now,
+	}
+	if err := s.repo.CreateRun(ctx, run); err != nil {
+		return fmt.Errorf("failed to record transfer run: %w", err)
+	}
+
+	// The run ID is the transfer's reference, tying ledger entries to the run
+	transfer, err := s.wallets.Transfer(ctx, dto.TransferRequest{
+		FromWalletID: sched.FromWalletID,
+		ToWalletID:   sched.ToWalletID,
+		Amount:       sched.Amount,
+		Reference:    run.ID,
+		Description:  sched.Description,
+	})
+	switch {
+	case err == nil && transfer.Status == dto.TransferPendingReview:
+		run.Status = models.TransferRunHeld
+		run.Error = fmt.Sprintf("held for screening review %d", transfer.ScreeningReviewID)
+		s.advance(r, sched, now)
+	case err == nil:
+		run.Status = models.TransferRunSucceeded
+		run.JournalID = transfer.JournalID
+		run.LedgerEntryID = &transfer.Debit.ID
+		s.advance(r, sched, now)
+	case isRejection(err):
+		// Rejections come from checks made before any ledger entry or balance
+		// is written, so the transaction is still usable: the run commits with
+		// any risk case the transfer recorded and system accounts it created
+		// on the way. Conflicts such as a lack of funds or a frozen wallet may
+		// clear up and are retried; invalid requests are not.
+		run.Status = models.TransferRunFailed
+		run.Error = err.Error()
+		sched.Attempts++
+		retryAt := now.Add(s.cfg.RetryInterval)
+		next := nextOccurrence(r, sched, *sched.NextRunAt)
+		if !errors.Is(err, ErrConflict) || sched.Attempts > sched.MaxRetries || (next != nil && !retryAt.Before(*next)) {
+			run.Status = models.TransferRunAbandoned
+			s.advance(r, sched, now)
+		} else {
+			sched.NextAttemptAt = &retryAt
+			sched.UpdatedAt = now
+		}
+		log.Printf("transfer schedule %d: occurrence %s attempt %d %s: %v", sched.ID, run.OccurrenceAt.Format(time.RFC3339), run.Attempt, run.Status, err)
+	default:
+		return fmt.Errorf("transfer schedule %d: %w", sched.ID, err)
+	}
+	sched.Failures = 0
+
+	if err := s.repo.UpdateRun(ctx, run); err != nil {
+		return fmt.Errorf("failed to update transfer run: %w", err)
+	}
+	if err := s.repo.UpdateSchedule(ctx, sched); err != nil {
+		return fmt.Errorf("failed to advance transfer schedule: %w", err)
+	}
+	return nil
+}
+
+// advance moves a schedule to its next occurrence after the current one,
+// completing it when there is none
+func (s *TransferScheduleService) advance(r schedule.Rule, sched *models.TransferSchedule, now time.Time) {
+	sched.NextRunAt = nextOccurrence(r, sched, *sched.NextRunAt)
+	sched.NextAttemptAt = sched.NextRunAt
+	sched.Attempts = 0
+	sched.UpdatedAt = now
+	if sched.NextRunAt == nil {
+		sched.Status = models.TransferScheduleCompleted
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// startScheduleTest creates a daily standing order of 2500 GBP between two
+// wallets, the source holding balance, first due at start
+func startScheduleTest(t *testing.T, wallets *fakeWalletRepository, balance int64, maxRetries int, start time.Time) (*TransferScheduleService, *fakeTransferScheduleRepository, *fakeClock, *models.TransferSchedule) {
+	t.Helper()
+	from := wallets.addWallet(1, "GBP", balance)
+	to := wallets.addWallet(2, "GBP", 0)
+	repo := newFakeTransferScheduleRepository()
+	clock := newFakeClock(start)
+	svc := NewTransferScheduleService(repo, NewWalletService(wallets, fakeTransactor{}).WithClock(clock.Now), fakeTransactor{}, nil, TransferScheduleConfig{
+		MaxRetries:    maxRetries,
+		RetryInterval: time.Hour,
+	}).WithClock(clock.Now)
+
+	resp, err := svc.CreateSchedule(context.Background(), dto.CreateTransferScheduleRequest{
+		FromWalletID: from.ID,
+		ToWalletID:   to.ID,
+		Amount:       2500,
+		Description:  "Rent",
+		RuleKind:     "interval",
+		Rule:         "1d",
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	return svc, repo, clock, &resp.TransferSchedule
+}
+
+// runStatuses lists the statuses of a schedule's runs, oldest first
+func runStatuses(repo *fakeTransferScheduleRepository, scheduleID int) []string {
+	runs, _ := repo.ListRuns(context.Background(), scheduleID, 100)
+	statuses := []string{}
+	for i := len(runs) - 1; i >= 0; i-- {
+		statuses = append(statuses, runs[i].Status)
+	}
+	return statuses
+}
+
+func TestRunDue(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	type tick struct {
+		at    time.Duration // After the first occurrence
+		topUp int64         // Credited to the source wallet before the run
+	}
+	tests := []struct {
+		name       string
+		balance    int64
+		maxRetries int
+		ticks      []tick
+
+		wantRuns        []string
+		wantFrom        int64
+		wantTo          int64
+		wantAttempts    int
+		wantNextRun     time.Duration
+		wantNextAttempt time.Duration
+	}{
+		{
+			name:            "not yet due",
+			balance:         10000,
+			maxRetries:      3,
+			ticks:           []tick{{at: -time.Minute}},
+			wantRuns:        []string{},
+			wantFrom:        10000,
+			wantNextRun:     0,
+			wantNextAttempt: 0,
+		},
+		{
+			name:            "runs when due",
+			balance:         10000,
+			maxRetries:      3,
+			ticks:           []tick{{at: 0}},
+			wantRuns:        []string{models.TransferRunSucceeded},
+			wantFrom:        7500,
+			wantTo:          2500,
+			wantNextRun:     24 * time.Hour,
+			wantNextAttempt: 24 * time.Hour,
+		},
+		{
+			name:            "runs each occurrence once",
+			balance:         10000,
+			maxRetries:      3,
+			ticks:           []tick{{at: 0}, {at: 0}, {at: 23 * time.Hour}, {at: 24 * time.Hour}},
+			wantRuns:        []string{models.TransferRunSucceeded, models.TransferRunSucceeded},
+			wantFrom:        5000,
+			wantTo:          5000,
+			wantNextRun:     48 * time.Hour,
+			wantNextAttempt: 48 * time.Hour,
+		},
+		{
+			name:            "waits to retry after insufficient funds",
+			balance:         1000,
+			maxRetries:      3,
+			ticks:           []tick{{at: 0}, {at: 30 * time.Minute}},
+			wantRuns:        []string{models.TransferRunFailed},
+			wantFrom:        1000,
+			wantAttempts:    1,
+			wantNextRun:     0,
+			wantNextAttempt: time.Hour,
+		},
+		{
+			name:            "retry succeeds once funded",
+			balance:         1000,
+			maxRetries:      3,
+			ticks:           []tick{{at: 0}, {at: time.Hour, topUp: 4000}},
+			wantRuns:        []string{models.TransferRunFailed, models.TransferRunSucceeded},
+			wantFrom:        2500,
+			wantTo:          2500,
+			wantNextRun:     24 * time.Hour,
+			wantNextAttempt: 24 * time.Hour,
+		},
+		{
+			name:            "abandons the occurrence after max retries",
+			balance:         1000,
+			maxRetries:      2,
+			ticks:           []tick{{at: 0}, {at: time.Hour}, {at: 2 * time.Hour}, {at: 3 * time.Hour}},
+			wantRuns:        []string{models.TransferRunFailed, models.TransferRunFailed, models.TransferRunAbandoned},
+			wantFrom:        1000,
+			wantNextRun:     24 * time.Hour,
+			wantNextAttempt: 24 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallets := newFakeWalletRepository()
+			svc, repo, clock, sched := startScheduleTest(t, wallets, tt.balance, tt.maxRetries, start)
+			ctx := context.Background()
+
+			for _, tick := range tt.ticks {
+				if err := wallets.UpdateWalletBalance(ctx, sched.FromWalletID, tick.topUp); err != nil {
+					t.Fatal(err)
+				}
+				clock.Set(start.Add(tick.at))
+				if err := svc.RunDue(ctx); err != nil {
+					t.Fatalf("RunDue at +%v: %v", tick.at, err)
+				}
+			}
+
+			if got := runStatuses(repo, sched.ID); !reflect.DeepEqual(got, tt.wantRuns) {
+				t.Errorf("runs = %v, want %v", got, tt.wantRuns)
+			}
+			if from, to := wallets.balance(sched.FromWalletID), wallets.balance(sched.ToWalletID); from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("balances = %d and %d, want %d and %d", from, to, tt.wantFrom, tt.wantTo)
+			}
+			got, _ := repo.GetScheduleByID(ctx, sched.ID)
+			if got.Status != models.TransferScheduleActive || got.Attempts != tt.wantAttempts {
+				t.Errorf("schedule = %s with %d attempts, want active with %d", got.Status, got.Attempts, tt.wantAttempts)
+			}
+			if !got.NextRunAt.Equal(start.Add(tt.wantNextRun)) || !got.NextAttemptAt.Equal(start.Add(tt.wantNextAttempt)) {
+				t.Errorf("next run %v, next attempt %v, want %v and %v", got.NextRunAt, got.NextAttemptAt, start.Add(tt.wantNextRun), start.Add(tt.wantNextAttempt))
+			}
+		})
+	}
+}
+
+func TestRunDueRecordsTheOccurrence(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	svc, repo, clock, sched := startScheduleTest(t, newFakeWalletRepository(), 1000, 3, start)
+	ctx := context.Background()
+
+	// A late retry still belongs to the occurrence that was due
+	clock.Set(start.Add(90 * time.Minute))
+	if err := svc.RunDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	runs, _ := repo.ListRuns(ctx, sched.ID, 1)
+	if run := runs[0]; run.Attempt != 1 || !run.OccurrenceAt.Equal(start) || run.Error == "" {
+		t.Errorf("run = attempt %d of %v with error %q, want attempt 1 of %v with the refusal", run.Attempt, run.OccurrenceAt, run.Error, start)
+	}
+}
+
+func TestRunDueSkipsOccurrenceThatCannotSucceed(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	wallets := newFakeWalletRepository()
+	svc, repo, _, sched := startScheduleTest(t, wallets, 10000, 3, start)
+	wallets.mu.Lock()
+	delete(wallets.wallets, sched.ToWalletID)
+	wallets.mu.Unlock()
+
+	// A missing wallet will not come back, so the occurrence is abandoned at
+	// once rather than retried and the schedule moves on
+	if err := svc.RunDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := runStatuses(repo, sched.ID); !reflect.DeepEqual(got, []string{models.TransferRunAbandoned}) {
+		t.Errorf("runs = %v, want one abandoned run", got)
+	}
+	if got, _ := repo.GetScheduleByID(context.Background(), sched.ID); got.Attempts != 0 || !got.NextAttemptAt.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("schedule has %d attempts, next attempt %v, want none and the next day", got.Attempts, got.NextAttemptAt)
+	}
+}
+
+func TestRunDueBacksOffAfterUnexpectedFailure(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	wallets := newFakeWalletRepository()
+	svc, repo, clock, broken := startScheduleTest(t, wallets, 10000, 3, start)
+	healthy, err := svc.CreateSchedule(context.Background(), dto.CreateTransferScheduleRequest{
+		FromWalletID: broken.FromWalletID,
+		ToWalletID:   broken.ToWalletID,
+		Amount:       1000,
+		RuleKind:     "interval",
+		Rule:         "1d",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.mu.Lock()
+	repo.schedules[broken.ID].Rule = "fortnightly"
+	repo.mu.Unlock()
+
+	// The broken schedule is claimed first but must not hold up the other
+	ctx := context.Background()
+	for _, at := range []time.Duration{0, time.Hour, 2 * time.Hour} {
+		clock.Set(start.Add(at))
+		if err := svc.RunDue(ctx); err != nil {
+			t.Fatalf("RunDue at +%v: %v", at, err)
+		}
+	}
+
+	if got := runStatuses(repo, broken.ID); !reflect.DeepEqual(got, []string{models.TransferRunFailed, models.TransferRunFailed}) {
+		t.Errorf("broken runs = %v, want two failures", got)
+	}
+	got, _ := repo.GetScheduleByID(ctx, broken.ID)
+	if got.Failures != 2 || got.Attempts != 0 || !got.NextAttemptAt.Equal(start.Add(3*time.Hour)) {
+		t.Errorf("broken schedule = %d failures, %d attempts, next attempt %v, want 2, 0 and %v", got.Failures, got.Attempts, got.NextAttemptAt, start.Add(3*time.Hour))
+	}
+	if got := runStatuses(repo, healthy.ID); !reflect.DeepEqual(got, []string{models.TransferRunSucceeded}) {
+		t.Errorf("healthy runs = %v, want one success", got)
+	}
+}
@@ -2,8 +2,9 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
 	"github.com/kodra-pay/wallet-ledger-service/internal/models"
@@ -12,150 +13,738 @@ import (
 
 // WalletService defines the business logic for wallet and ledger operations
 type WalletService struct {
-	repo repositories.WalletRepository
+	repo   repositories.WalletRepository
+	tx     repositories.Transactor
+	audit  *AuditService
+	events EventPublisher
+	fees   *FeeSchedule
+	taxes  *TaxService
+	limits *LimitService
+	risk   *RiskService
+
+	screener  Screener
+	screening repositories.ScreeningRepository
+
+	lots     repositories.CreditLotRepository
+	lotOrder []string
+
+	currencies *CurrencyRegistry
+	loyalty    *LoyaltyProgram
+	points     repositories.LoyaltyRepository
+
+	pockets repositories.PocketRepository
+
+	now func() time.Time
+}
+
+// WalletServiceOption configures optional WalletService collaborators
+type WalletServiceOption func(*WalletService)
+
+// WithEventPublisher makes the wallet service emit balance events to p.
+// Events are published inside the posting transaction (transactional outbox).
+func WithEventPublisher(p EventPublisher) WalletServiceOption {
+	return func(s *WalletService) { s.events = p }
+}
+
+// WithAuditService records every wallet mutation in the audit trail
+func WithAuditService(a *AuditService) WalletServiceOption {
+	return func(s *WalletService) { s.audit = a }
+}
+
+// WithFeeSchedule charges fees on credits, debits and transfers
+func WithFeeSchedule(f *FeeSchedule) WalletServiceOption {
+	return func(s *WalletService) { s.fees = f }
+}
+
+// WithTaxService levies tax on the fees the wallet service charges
+func WithTaxService(t *TaxService) WalletServiceOption {
+	return func(s *WalletService) { s.taxes = t }
+}
+
+// WithLimits enforces spend and velocity limits on credits, debits and transfers
+func WithLimits(l *LimitService) WalletServiceOption {
+	return func(s *WalletService) { s.limits = l }
+}
+
+// WithRiskService runs the risk rules on credits, debits and transfers
+func WithRiskService(r *RiskService) WalletServiceOption {
+	return func(s *WalletService) { s.risk = r }
+}
+
+// WithScreening screens wallet holders against sanctions watchlists when
+// wallets are created and on every transfer, holding hits for review
+func WithScreening(screener Screener, repo repositories.ScreeningRepository) WalletServiceOption {
+	return func(s *WalletService) { s.screener, s.screening = screener, repo }
+}
+
+// WithCreditLots spends wallets' promotional credit lots on the debits of
+// every posting in the given spend order (see ParseLotSpendOrder)
+func WithCreditLots(repo repositories.CreditLotRepository, order []string) WalletServiceOption {
+	return func(s *WalletService) { s.lots, s.lotOrder = repo, order }
+}
+
+// WithCurrencies restricts new wallets to the registry's currencies and
+// records whether each holds money or points
+func WithCurrencies(r *CurrencyRegistry) WalletServiceOption {
+	return func(s *WalletService) { s.currencies = r }
+}
+
+// WithLoyalty earns loyalty points on debits and transfers under program
+func WithLoyalty(program *LoyaltyProgram, repo repositories.LoyaltyRepository) WalletServiceOption {
+	return func(s *WalletService) { s.loyalty, s.points = program, repo }
+}
+
+// WithPockets keeps money set aside in wallets' pockets out of every
+// posting's debits, and runs the pockets' auto-sweep rules on credits,
+// debits and transfers
+func WithPockets(repo repositories.PocketRepository) WalletServiceOption {
+	return func(s *WalletService) { s.pockets = repo }
 }
 
 // NewWalletService creates a new wallet service
-func NewWalletService(repo repositories.WalletRepository) *WalletService {
-	return &WalletService{repo: repo}
+func NewWalletService(repo repositories.WalletRepository, tx repositories.Transactor, opts ...WalletServiceOption) *WalletService {
+	s := &WalletService{repo: repo, tx: tx, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *WalletService) WithClock(now func() time.Time) *WalletService {
+	s.now = now
+	return s
 }
 
 func (s *WalletService) CreateWallet(ctx context.Context, req dto.CreateWalletRequest) (*dto.WalletResponse, error) {
+	if err := authorizeOwner(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
 	// Check if wallet already exists for this user and currency
 	existingWallet, err := s.repo.GetWalletByUserIDAndCurrency(ctx, req.UserID, req.Currency) // int
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for existing wallet: %w", err)
 	}
 	if existingWallet != nil {
-		return nil, errors.New("wallet already exists for this user and currency")
+		return nil, fmt.Errorf("%w: wallet already exists for this user and currency", ErrConflict)
+	}
+
+	currency, err := s.currencies.Lookup(req.Currency)
+	if err != nil {
+		return nil, err
 	}
 
 	wallet := models.NewWallet(req.UserID, req.Currency) // int
-	if err := s.repo.CreateWallet(ctx, wallet); err != nil {
-		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	wallet.AssetType = currency.AssetType
+	wallet.HolderName = strings.TrimSpace(req.HolderName)
+	matches, err := s.screenName(ctx, wallet.HolderName)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) > 0 {
+		wallet.Status = models.WalletStatusPendingReview
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateWallet(ctx, wallet); err != nil {
+			return fmt.Errorf("failed to create wallet: %w", err)
+		}
+		if err := s.audit.Record(ctx, models.AuditWalletCreated, "wallet", wallet.ID, nil, wallet); err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		for i := range matches {
+			matches[i].WalletID = wallet.ID
+		}
+		_, err := s.openScreeningReview(ctx, &models.ScreeningReview{SubjectType: models.ScreeningSubjectWallet, WalletID: wallet.ID, Matches: matches})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &dto.WalletResponse{
-		ID:        wallet.ID,        // int
-		UserID:    wallet.UserID,    // int
-		Currency:  wallet.Currency,
-		Balance:   wallet.Balance,
-		CreatedAt: wallet.CreatedAt,
-		UpdatedAt: wallet.UpdatedAt,
-	}, nil
+	return toWalletResponse(wallet), nil
 }
 
 func (s *WalletService) GetWalletByID(ctx context.Context, walletID int) (*dto.WalletResponse, error) { // int
-	wallet, err := s.repo.GetWalletByID(ctx, walletID) // int
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get wallet: %w", err)
+		return nil, err
 	}
-	if wallet == nil {
-		return nil, errors.New("wallet not found")
-	}
-
-	return &dto.WalletResponse{
-		ID:        wallet.ID,        // int
-		UserID:    wallet.UserID,    // int
-		Currency:  wallet.Currency,
-		Balance:   wallet.Balance,
-		CreatedAt: wallet.CreatedAt,
-		UpdatedAt: wallet.UpdatedAt,
-	}, nil
+	return toWalletResponse(wallet), nil
 }
 
 func (s *WalletService) GetWalletByUserIDAndCurrency(ctx context.Context, userID int, currency string) (*dto.WalletResponse, error) { // int
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	wallet, err := s.repo.GetWalletByUserIDAndCurrency(ctx, userID, currency) // int
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet by user ID and currency: %w", err)
 	}
 	if wallet == nil {
-		return nil, errors.New("wallet not found for user and currency")
+		return nil, fmt.Errorf("%w for user and currency", ErrWalletNotFound)
 	}
+	return toWalletResponse(wallet), nil
+}
 
-	return &dto.WalletResponse{
-		ID:        wallet.ID,        // int
-		UserID:    wallet.UserID,    // int
-		Currency:  wallet.Currency,
-		Balance:   wallet.Balance,
-		CreatedAt: wallet.CreatedAt,
-		UpdatedAt: wallet.UpdatedAt,
-	}, nil
+func (s *WalletService) UpdateWalletBalance(ctx context.Context, walletID int, req dto.UpdateBalanceRequest) (*dto.BalanceUpdateResponse, error) { // int
+	if req.Type != "credit" && req.Type != "debit" {
+		return nil, fmt.Errorf("%w: invalid transaction type, must be 'credit' or 'debit'", ErrInvalidRequest)
+	}
+	// Credits here are for payment integrations; a person crediting a wallet
+	// needs a second operator's approval
+	if req.Type == "credit" {
+		if err := requireIntegration(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot be credited or debited directly", ErrInvalidRequest)
+	}
+	if err := requireMonetary(wallet); err != nil {
+		return nil, err
+	}
+
+	fee, err := s.quoteFee(wallet, req.Type, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedWallet *models.Wallet
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Money enters or leaves the ledger through the external clearing account
+		clearing, err := s.systemWallet(ctx, models.SystemExternalClearing, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		entry := models.NewLedgerEntry(walletID, req.Reference, req.Type, req.Amount, 0, req.Description)
+		legs := []*models.LedgerEntry{entry, counterLeg(entry, clearing.ID)}
+		var taxLegs []*models.LedgerEntry
+		if fee != nil {
+			feeLegs, feeTaxLegs, err := s.feeLegs(ctx, wallet, req.Reference, fee)
+			if err != nil {
+				return err
+			}
+			legs, taxLegs = append(legs, feeLegs...), feeTaxLegs
+		}
+		earn, earnLegs, err := s.earnPoints(ctx, wallet, entry, req.Type)
+		if err != nil {
+			return err
+		}
+		legs = append(legs, earnLegs...)
+		debit, credit := req.Amount, int64(0)
+		if req.Type == "credit" {
+			debit, credit = 0, req.Amount
+		}
+		if fee != nil {
+			debit += fee.Charged
+		}
+		risk := s.risk.check(entry, req.Type)
+		posted, err := s.postJournalAs(ctx, req.Type, legs, s.limits.guard(walletID, req.Amount, debit, credit), risk.guard())
+		if err != nil {
+			return err
+		}
+		if err := risk.record(ctx); err != nil {
+			return err
+		}
+		if err := earn.record(ctx); err != nil {
+			return err
+		}
+		if err := s.sweepPockets(ctx, posted.After[walletID], entry); err != nil {
+			return err
+		}
+		if fee != nil {
+			fee.LedgerEntryID = legs[2].ID
+			if err := s.taxes.record(ctx, posted.ID, wallet.Currency, fee, taxLegs); err != nil {
+				return err
+			}
+		}
+		updatedWallet = posted.After[walletID]
+		return s.audit.Record(ctx, models.AuditWalletBalanceUpdated, "wallet", walletID, posted.Before[walletID], postingAuditState{Wallet: updatedWallet, Entries: posted.Legs})
+	})
+	if err != nil {
+		return nil, s.risk.recordBlocked(ctx, err)
+	}
+	return &dto.BalanceUpdateResponse{WalletResponse: *toWalletResponse(updatedWallet), Fee: fee}, nil
 }
 
-func (s *WalletService) UpdateWalletBalance(ctx context.Context, walletID int, req dto.UpdateBalanceRequest) (*dto.WalletResponse, error) { // int
-	wallet, err := s.repo.GetWalletByID(ctx, walletID) // int
+// AdjustBalance posts a manual correction against the adjustments system
+// account and returns the wallet's entry. Unlike regular postings it requires
+// a reason, which is audited. Operators reach it through AdjustmentService,
+// which enforces maker-checker approval first.
+func (s *WalletService) AdjustBalance(ctx context.Context, walletID int, req dto.AdjustBalanceRequest) (*dto.LedgerEntryResponse, error) {
+	if req.Type != "credit" && req.Type != "debit" {
+		return nil, fmt.Errorf("%w: invalid adjustment type, must be 'credit' or 'debit'", ErrInvalidRequest)
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidRequest)
+	}
+
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get wallet for update: %w", err)
+		return nil, err
 	}
-	if wallet == nil {
-		return nil, errors.New("wallet not found")
+	if wallet.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot be adjusted directly", ErrInvalidRequest)
 	}
 
-	var amountChange int64
-	if req.Type == "credit" {
-		amountChange = req.Amount
-	} else if req.Type == "debit" {
-		amountChange = -req.Amount
-	} else {
-		return nil, errors.New("invalid transaction type, must be 'credit' or 'debit'")
+	var entry *models.LedgerEntry
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		adjustments, err := s.systemWallet(ctx, models.SystemAdjustments, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		entry = models.NewLedgerEntry(walletID, req.Reference, req.Type, req.Amount, 0, "Manual adjustment: "+req.Reason)
+		posted, err := s.postJournalAs(ctx, spendCashOnly, []*models.LedgerEntry{entry, counterLeg(entry, adjustments.ID)})
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, models.AuditWalletAdjusted, "wallet", walletID, posted.Before[walletID],
+			adjustmentAuditState{postingAuditState: postingAuditState{Wallet: posted.After[walletID], Entries: posted.Legs}, Reason: req.Reason})
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := toLedgerEntryResponse(entry)
+	return &resp, nil
+}
+
+// Transfer moves money between two customer wallets of the same currency as
+// one journal. It fails with ErrInsufficientFunds rather than overdrawing the
+// source wallet. When either holder matches a watchlist the transfer is held
+// for screening review instead of posted.
+func (s *WalletService) Transfer(ctx context.Context, req dto.TransferRequest) (*dto.TransferResponse, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	if req.FromWalletID == req.ToWalletID {
+		return nil, fmt.Errorf("%w: cannot transfer to the same wallet", ErrInvalidRequest)
+	}
+
+	from, err := s.getAuthorizedWallet(ctx, req.FromWalletID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.repo.GetWalletByID(ctx, req.ToWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if to == nil {
+		return nil, fmt.Errorf("%w: destination %d", ErrWalletNotFound, req.ToWalletID)
+	}
+	if from.IsSystem() || to.IsSystem() {
+		return nil, fmt.Errorf("%w: system accounts cannot take part in transfers", ErrInvalidRequest)
+	}
+	if err := requireMonetary(from, to); err != nil {
+		return nil, err
+	}
+	if from.Currency != to.Currency {
+		return nil, fmt.Errorf("%w: wallets have different currencies", ErrInvalidRequest)
 	}
 
-	// Update wallet balance
-	if err := s.repo.UpdateWalletBalance(ctx, walletID, amountChange); err != nil { // int
-		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	matches, err := s.screenWallets(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) > 0 {
+		return s.holdTransfer(ctx, req, matches)
 	}
+	return s.transfer(ctx, req, from, to)
+}
 
-	// Get updated wallet to record current balance in ledger
-	updatedWallet, err := s.repo.GetWalletByID(ctx, walletID) // int
+// transfer posts a validated transfer between from and to
+func (s *WalletService) transfer(ctx context.Context, req dto.TransferRequest, from, to *models.Wallet) (*dto.TransferResponse, error) {
+	fee, err := s.quoteFee(from, FeeTxnTransfer, req.Amount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get updated wallet: %w", err)
+		return nil, err
 	}
-	if updatedWallet == nil {
-		return nil, errors.New("updated wallet not found after balance change")
+	debit := models.NewLedgerEntry(from.ID, req.Reference, "debit", req.Amount, 0, req.Description)
+	credit := models.NewLedgerEntry(to.ID, req.Reference, "credit", req.Amount, 0, req.Description)
+	var resp *dto.TransferResponse
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		legs := []*models.LedgerEntry{debit, credit}
+		var taxLegs []*models.LedgerEntry
+		required := req.Amount
+		if fee != nil {
+			feeLegs, feeTaxLegs, err := s.feeLegs(ctx, from, req.Reference, fee)
+			if err != nil {
+				return err
+			}
+			legs, taxLegs = append(legs, feeLegs...), feeTaxLegs
+			required += fee.Charged
+		}
+		earn, earnLegs, err := s.earnPoints(ctx, from, debit, FeeTxnTransfer)
+		if err != nil {
+			return err
+		}
+		legs = append(legs, earnLegs...)
+		senderRisk, receiverRisk := s.risk.check(debit, FeeTxnTransfer), s.risk.check(credit, FeeTxnTransfer)
+		posted, err := s.postJournalAs(ctx, FeeTxnTransfer, legs, requireFunds(from.ID, required),
+			s.limits.guard(from.ID, req.Amount, required, 0), s.limits.guard(to.ID, req.Amount, 0, req.Amount),
+			senderRisk.guard(), receiverRisk.guard())
+		if err != nil {
+			return err
+		}
+		if err := senderRisk.record(ctx); err != nil {
+			return err
+		}
+		if err := receiverRisk.record(ctx); err != nil {
+			return err
+		}
+		if err := earn.record(ctx); err != nil {
+			return err
+		}
+		if err := s.sweepPockets(ctx, posted.After[from.ID], debit); err != nil {
+			return err
+		}
+		if err := s.sweepPockets(ctx, posted.After[to.ID], credit); err != nil {
+			return err
+		}
+		if fee != nil {
+			fee.LedgerEntryID = legs[2].ID
+			if err := s.taxes.record(ctx, posted.ID, from.Currency, fee, taxLegs); err != nil {
+				return err
+			}
+		}
+		debitResp, creditResp := toLedgerEntryResponse(debit), toLedgerEntryResponse(credit)
+		resp = &dto.TransferResponse{
+			Status:    dto.TransferPosted,
+			JournalID: posted.ID,
+			Debit:     &debitResp,
+			Credit:    &creditResp,
+			Fee:       fee,
+		}
+		return s.audit.Record(ctx, models.AuditWalletTransferred, "wallet", from.ID, posted.Before[from.ID], postingAuditState{Wallet: posted.After[from.ID], Entries: posted.Legs})
+	})
+	if err != nil {
+		return nil, s.risk.recordBlocked(ctx, err)
 	}
+	return resp, nil
+}
 
-	// Create ledger entry
-	ledgerEntry := models.NewLedgerEntry(
-		walletID,        // int
-		req.Reference,   // int
-		req.Type,
-		req.Amount,
-		updatedWallet.Balance, // Current balance after update
-		req.Description,
-	)
-	if err := s.repo.CreateLedgerEntry(ctx, ledgerEntry); err != nil {
-		return nil, fmt.Errorf("failed to create ledger entry: %w", err)
+// SetWalletStatus freezes, unfreezes or closes a wallet. Closed wallets cannot
+// be reopened and must have a zero balance.
+func (s *WalletService) SetWalletStatus(ctx context.Context, walletID int, req dto.UpdateWalletStatusRequest) (*dto.WalletResponse, error) {
+	switch req.Status {
+	case models.WalletStatusActive, models.WalletStatusFrozen, models.WalletStatusClosed:
+	default:
+		return nil, fmt.Errorf("%w: status must be 'active', 'frozen' or 'closed'", ErrInvalidRequest)
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidRequest)
+	}
+	if _, err := s.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
 	}
 
-	return &dto.WalletResponse{
-		ID:        updatedWallet.ID,        // int
-		UserID:    updatedWallet.UserID,    // int
-		Currency:  updatedWallet.Currency,
-		Balance:   updatedWallet.Balance,
-		CreatedAt: updatedWallet.CreatedAt,
-		UpdatedAt: updatedWallet.UpdatedAt,
-	}, nil
+	var updated *models.Wallet
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.GetWalletByIDForUpdate(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to lock wallet: %w", err)
+		}
+		if wallet == nil {
+			return ErrWalletNotFound
+		}
+		if wallet.Status == models.WalletStatusClosed {
+			return fmt.Errorf("%w: wallet is closed", ErrConflict)
+		}
+		if wallet.Status == models.WalletStatusPendingReview {
+			return fmt.Errorf("%w: wallet is pending screening review", ErrConflict)
+		}
+		if req.Status == models.WalletStatusClosed && wallet.Balance != 0 {
+			return fmt.Errorf("%w: wallet balance must be zero before closing", ErrConflict)
+		}
+		if wallet.Status == req.Status {
+			updated = wallet
+			return nil
+		}
+
+		if err := s.repo.UpdateWalletStatus(ctx, walletID, req.Status); err != nil {
+			return fmt.Errorf("failed to update wallet status: %w", err)
+		}
+		after := *wallet
+		after.Status = req.Status
+		updated = &after
+		return s.audit.Record(ctx, models.AuditWalletStatusChanged, "wallet", walletID, wallet, statusAuditState{Wallet: &after, Reason: req.Reason})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toWalletResponse(updated), nil
+}
+
+// ReverseLedgerEntry reverses the journal a posted entry belongs to by posting
+// an equal and opposite entry for each of its legs. Each entry can be reversed
+// at most once, and reversals themselves cannot be reversed.
+func (s *WalletService) ReverseLedgerEntry(ctx context.Context, walletID, entryID int, req dto.ReverseEntryRequest) (*dto.LedgerEntryResponse, error) {
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidRequest)
+	}
+
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.repo.GetLedgerEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entry: %w", err)
+	}
+	if original == nil || original.WalletID != walletID {
+		return nil, ErrLedgerEntryNotFound
+	}
+	if original.ReversalOf != nil {
+		return nil, fmt.Errorf("%w: reversal entries cannot be reversed", ErrInvalidRequest)
+	}
+
+	reference := req.Reference
+	if reference == 0 {
+		reference = original.Reference
+	}
+	description := fmt.Sprintf("Reversal of entry %d: %s", original.ID, req.Reason)
+
+	var reversal *models.LedgerEntry
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		originals := []models.LedgerEntry{*original}
+		if original.JournalID != "" {
+			journal, err := s.repo.GetLedgerEntriesByJournalID(ctx, original.JournalID)
+			if err != nil {
+				return fmt.Errorf("failed to get journal entries: %w", err)
+			}
+			originals = journal
+		}
+
+		var (
+			legs []*models.LedgerEntry
+			net  int64
+		)
+		for i := range originals {
+			leg := counterLeg(&originals[i], originals[i].WalletID)
+			leg.Reference = reference
+			leg.Description = description
+			leg.ReversalOf = &originals[i].ID
+			legs = append(legs, leg)
+			net += leg.SignedAmount()
+			if originals[i].ID == original.ID {
+				reversal = leg
+			}
+		}
+		// Reversing a journal debits every wallet it credited, not only the
+		// caller's, e.g. the recipient of a transfer, so each customer wallet
+		// must still hold what it gives back. System accounts may go negative.
+		debited := map[int]int64{}
+		for _, leg := range legs {
+			debited[leg.WalletID] -= leg.SignedAmount()
+		}
+		// Single-sided entries posted before double-entry bookkeeping are balanced against clearing
+		if net != 0 {
+			clearing, err := s.systemWallet(ctx, models.SystemExternalClearing, wallet.Currency)
+			if err != nil {
+				return err
+			}
+			entryType := "credit"
+			if net > 0 {
+				entryType = "debit"
+			}
+			legs = append(legs, models.NewLedgerEntry(clearing.ID, reference, entryType, abs(net), 0, description))
+		}
+
+		// postJournal locks the wallets, which serializes concurrent reversals of the same entry
+		posted, err := s.postJournalAs(ctx, spendCashOnly, legs, func(ctx context.Context, _ map[int]*models.Wallet) error {
+			for i := range originals {
+				existing, err := s.repo.GetReversalOf(ctx, originals[i].ID)
+				if err != nil {
+					return fmt.Errorf("failed to check for existing reversal: %w", err)
+				}
+				if existing != nil {
+					return fmt.Errorf("%w: ledger entry %d was already reversed by entry %d", ErrConflict, originals[i].ID, existing.ID)
+				}
+			}
+			return nil
+		}, func(ctx context.Context, locked map[int]*models.Wallet) error {
+			for id, amount := range debited {
+				if amount <= 0 || locked[id].IsSystem() {
+					continue
+				}
+				if err := requireFunds(id, amount)(ctx, locked); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if original.JournalID != "" {
+			if err := s.taxes.reverse(ctx, original.JournalID, posted.ID); err != nil {
+				return err
+			}
+		}
+		return s.audit.Record(ctx, models.AuditLedgerEntryReversed, "ledger_entry", original.ID, original, postingAuditState{Wallet: posted.After[walletID], Entries: posted.Legs})
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := toLedgerEntryResponse(reversal)
+	return &resp, nil
 }
 
 func (s *WalletService) GetWalletLedger(ctx context.Context, walletID int) ([]dto.LedgerEntryResponse, error) { // int
+	if _, err := s.getAuthorizedWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+
 	entries, err := s.repo.GetLedgerEntriesByWalletID(ctx, walletID) // int
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
 	}
 
 	var resp []dto.LedgerEntryResponse
-	for _, entry := range entries {
-		resp = append(resp, dto.LedgerEntryResponse{
-			ID:          entry.ID,          // int
-			WalletID:    entry.WalletID,    // int
-			Reference:   entry.Reference,   // int
-			Type:        entry.Type,
-			Amount:      entry.Amount,
-			Balance:     entry.Balance,
-			Description: entry.Description,
-			CreatedAt:   entry.CreatedAt,
-		})
+	for i := range entries {
+		resp = append(resp, toLedgerEntryResponse(&entries[i]))
 	}
 	return resp, nil
 }
+
+// GetStatement returns a wallet's entries posted in [from, to) with the
+// balances either side. Zero times leave that end of the period open.
+func (s *WalletService) GetStatement(ctx context.Context, walletID int, from, to time.Time) (*dto.StatementResponse, error) {
+	wallet, err := s.getAuthorizedWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.GetLedgerEntriesByWalletID(ctx, walletID) // Newest first
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+
+	statement := &dto.StatementResponse{Wallet: toWalletResponse(wallet), Entries: []dto.LedgerEntryResponse{}}
+	if !from.IsZero() {
+		statement.From = &from
+	}
+	if !to.IsZero() {
+		statement.To = &to
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := &entries[i]
+		switch {
+		case !from.IsZero() && entry.CreatedAt.Before(from):
+			statement.OpeningBalance = entry.Balance
+		case !to.IsZero() && !entry.CreatedAt.Before(to):
+		default:
+			statement.Entries = append(statement.Entries, toLedgerEntryResponse(entry))
+		}
+	}
+	statement.ClosingBalance = statement.OpeningBalance
+	if n := len(statement.Entries); n > 0 {
+		statement.ClosingBalance = statement.Entries[n-1].Balance
+	}
+	return statement, nil
+}
+
+// getAuthorizedWallet loads a wallet and checks the caller may act on it
+func (s *WalletService) getAuthorizedWallet(ctx context.Context, walletID int) (*models.Wallet, error) {
+	wallet, err := s.repo.GetWalletByID(ctx, walletID) // int
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if wallet == nil {
+		return nil, ErrWalletNotFound
+	}
+	if err := authorizeOwner(ctx, wallet.UserID); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// adjustmentAuditState is the after-state recorded for manual adjustments
+type adjustmentAuditState struct {
+	postingAuditState
+	Reason string `json:"reason"`
+}
+
+// postingAuditState is the after-state recorded for money movements
+type postingAuditState struct {
+	Wallet  *models.Wallet        `json:"wallet"`
+	Entries []*models.LedgerEntry `json:"entries"`
+}
+
+// statusAuditState is the after-state recorded for wallet status changes
+type statusAuditState struct {
+	Wallet *models.Wallet `json:"wallet"`
+	Reason string         `json:"reason"`
+}
+
+// publishBalanceEvent queues webhook deliveries about a posted entry for the wallet owner
+func (s *WalletService) publishBalanceEvent(ctx context.Context, wallet *models.Wallet, entry *models.LedgerEntry) error {
+	if s.events == nil {
+		return nil
+	}
+	eventType := models.EventBalanceCredited
+	if entry.Type == "debit" {
+		eventType = models.EventBalanceDebited
+	}
+	event := dto.BalanceChangedEvent{
+		WalletID:      wallet.ID,
+		UserID:        wallet.UserID,
+		Currency:      wallet.Currency,
+		LedgerEntryID: entry.ID,
+		Reference:     entry.Reference,
+		Type:          entry.Type,
+		Amount:        entry.Amount,
+		Balance:       entry.Balance,
+		Description:   entry.Description,
+		OccurredAt:    entry.CreatedAt,
+	}
+	if err := s.events.Publish(ctx, wallet.UserID, eventType, event); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", eventType, err)
+	}
+	return nil
+}
+
+func toWalletResponse(wallet *models.Wallet) *dto.WalletResponse {
+	return &dto.WalletResponse{
+		ID:         wallet.ID,     // int
+		UserID:     wallet.UserID, // int
+		Type:       wallet.Type,
+		SystemCode: wallet.SystemCode,
+		KYCTier:    wallet.KYCTier,
+		Segment:    wallet.Segment,
+		HolderName: wallet.HolderName,
+		Currency:   wallet.Currency,
+		AssetType:  wallet.AssetType,
+		Balance:    wallet.Balance,
+		Status:     wallet.Status,
+		CreatedAt:  wallet.CreatedAt,
+		UpdatedAt:  wallet.UpdatedAt,
+	}
+}
+
+func toLedgerEntryResponse(entry *models.LedgerEntry) dto.LedgerEntryResponse {
+	return dto.LedgerEntryResponse{
+		ID:          entry.ID,       // int
+		WalletID:    entry.WalletID, // int
+		JournalID:   entry.JournalID,
+		Reference:   entry.Reference, // int
+		Type:        entry.Type,
+		Amount:      entry.Amount,
+		Balance:     entry.Balance,
+		Description: entry.Description,
+		ReversalOf:  entry.ReversalOf,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
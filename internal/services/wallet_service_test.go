@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+)
+
+func TestReverseLedgerEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		spent int64 // Spent by the recipient before the reversal
+
+		wantErr  error
+		wantFrom int64
+		wantTo   int64
+	}{
+		{name: "recipient still holds the funds", wantFrom: 10000, wantTo: 0},
+		{name: "recipient spent part of them", spent: 1000, wantErr: ErrInsufficientFunds, wantFrom: 6000, wantTo: 3000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallets := newFakeWalletRepository()
+			svc := NewWalletService(wallets, fakeTransactor{})
+			from := wallets.addWallet(1, "GBP", 10000)
+			to := wallets.addWallet(2, "GBP", 0)
+			ctx := context.Background()
+			transfer, err := svc.Transfer(ctx, dto.TransferRequest{FromWalletID: from.ID, ToWalletID: to.ID, Amount: 4000, Reference: 1})
+			if err != nil {
+				t.Fatalf("Transfer: %v", err)
+			}
+			if err := wallets.UpdateWalletBalance(ctx, to.ID, -tt.spent); err != nil {
+				t.Fatal(err)
+			}
+
+			// The sender reverses its debit, which also takes the credit back
+			// from the recipient's wallet
+			_, err = svc.ReverseLedgerEntry(ctx, from.ID, transfer.Debit.ID, dto.ReverseEntryRequest{Reason: "sent in error"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if gotFrom, gotTo := wallets.balance(from.ID), wallets.balance(to.ID); gotFrom != tt.wantFrom || gotTo != tt.wantTo {
+				t.Errorf("balances = %d and %d, want %d and %d", gotFrom, gotTo, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
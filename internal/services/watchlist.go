@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// Screener checks a name against sanctions and watchlists. It returns the
+// entries the name matches, or none when it is clear.
+type Screener interface {
+	Screen(ctx context.Context, name string) ([]models.ScreeningMatch, error)
+}
+
+// WatchlistEntry is one name on a watchlist
+type WatchlistEntry struct {
+	ID      string
+	Name    string
+	Program string
+	tokens  []string
+}
+
+// LoadWatchlist reads a watchlist CSV file; see ParseWatchlist
+func LoadWatchlist(path string) ([]WatchlistEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseWatchlist(f)
+}
+
+// ParseWatchlist reads watchlist entries from CSV. A file whose first row
+// names a "name" column is read by header, taking the optional "id" and
+// "program" columns too. Anything else is read as an OFAC SDN file
+// (ent_num, SDN_Name, SDN_Type, Program, ...), which has no header.
+func ParseWatchlist(r io.Reader) ([]WatchlistEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid watchlist: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("watchlist is empty")
+	}
+
+	idCol, nameCol, programCol := 0, 1, 3
+	if header := headerColumns(records[0]); header != nil {
+		if _, ok := header["name"]; !ok {
+			return nil, errors.New("watchlist header has no name column")
+		}
+		idCol, nameCol, programCol = -1, header["name"], -1
+		if col, ok := header["id"]; ok {
+			idCol = col
+		}
+		if col, ok := header["program"]; ok {
+			programCol = col
+		}
+		records = records[1:]
+	}
+
+	field := func(record []string, col int) string {
+		if col < 0 || col >= len(record) {
+			return ""
+		}
+		if value := strings.TrimSpace(record[col]); value != "-0-" { // OFAC's empty value
+			return value
+		}
+		return ""
+	}
+	entries := make([]WatchlistEntry, 0, len(records))
+	for i, record := range records {
+		name := field(record, nameCol)
+		if name == "" {
+			continue
+		}
+		id := field(record, idCol)
+		if id == "" {
+			id = fmt.Sprint(i + 1)
+		}
+		entries = append(entries, WatchlistEntry{ID: id, Name: name, Program: field(record, programCol), tokens: nameTokens(name)})
+	}
+	return entries, nil
+}
+
+// headerColumns maps lower-case column names to indexes when record is a
+// header row naming a "name" column, and returns nil otherwise
+func headerColumns(record []string) map[string]int {
+	columns := make(map[string]int, len(record))
+	for i, column := range record {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil
+	}
+	return columns
+}
+
+// WatchlistScreener fuzzy-matches names against a watchlist held in memory.
+// Names are compared as sets of words, ignoring case, punctuation and word
+// order, so "Doe, John" matches "John Doe"; each word may differ by a typo or
+// two. A name matches an entry when it scores at least the threshold, out of
+// 100.
+type WatchlistScreener struct {
+	entries   []WatchlistEntry
+	threshold int
+}
+
+// NewWatchlistScreener creates a screener over entries. threshold defaults
+// to 85.
+func NewWatchlistScreener(entries []WatchlistEntry, threshold int) *WatchlistScreener {
+	if threshold <= 0 || threshold > 100 {
+		threshold = 85
+	}
+	for i := range entries {
+		if entries[i].tokens == nil {
+			entries[i].tokens = nameTokens(entries[i].Name)
+		}
+	}
+	return &WatchlistScreener{entries: entries, threshold: threshold}
+}
+
+func (s *WatchlistScreener) Screen(_ context.Context, name string) ([]models.ScreeningMatch, error) {
+	tokens := nameTokens(name)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	var matches []models.ScreeningMatch
+	for _, entry := range s.entries {
+		if score := nameScore(tokens, entry.tokens); score >= s.threshold {
+			matches = append(matches, models.ScreeningMatch{
+				Name: name, ListEntryID: entry.ID, ListName: entry.Name, Program: entry.Program, Score: score,
+			})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches, nil
+}
+
+// nameTokens lower-cases name and splits it into words, dropping punctuation
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// nameScore scores how well a screened name matches a listed one, from 0 to
+// 100. Every word of the screened name is paired with its closest listed
+// word and the score is the average similarity, so a partial name such as
+// "John Doe" fully matches "DOE, John Michael". A single-word name has to
+// match the whole listed name, so "John" alone does not.
+func nameScore(name, listed []string) int {
+	if len(listed) == 0 {
+		return 0
+	}
+	if len(name) == 1 {
+		sorted := append([]string(nil), listed...)
+		sort.Strings(sorted)
+		return similarity(name[0], strings.Join(sorted, " "))
+	}
+	total := 0
+	for _, word := range name {
+		best := 0
+		for _, candidate := range listed {
+			if score := similarity(word, candidate); score > best {
+				best = score
+			}
+		}
+		total += best
+	}
+	return total / len(name)
+}
+
+// similarity is the edit-distance similarity of a and b, from 0 to 100
+func similarity(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 100
+	}
+	return 100 * (longest - editDistance(ra, rb)) / longest
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and swaps of adjacent letters each
+// count as one edit
+func editDistance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
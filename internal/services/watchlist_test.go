@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseWatchlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []WatchlistEntry
+		wantErr bool
+	}{
+		{
+			name: "OFAC SDN",
+			csv:  "36,\"AEROCARIBBEAN AIRLINES\",-0-,\"CUBA\",-0-\n173,\"DOE, John\",\"individual\",\"SDGT\",-0-\n",
+			want: []WatchlistEntry{{ID: "36", Name: "AEROCARIBBEAN AIRLINES", Program: "CUBA"}, {ID: "173", Name: "DOE, John", Program: "SDGT"}},
+		},
+		{
+			name: "header with a name column",
+			csv:  "Program,Name\nSDGT,John Doe\n,\n",
+			want: []WatchlistEntry{{ID: "1", Name: "John Doe", Program: "SDGT"}}, // Rows without a name are skipped
+		},
+		{name: "empty", csv: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ParseWatchlist(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %t", err, tt.wantErr)
+			}
+			for i := range entries {
+				entries[i].tokens = nil
+			}
+			if !tt.wantErr && !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("entries = %+v, want %+v", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "doe", b: "", want: 3},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "jonh", b: "john", want: 1}, // An adjacent swap is one edit
+		{a: "ca", b: "abc", want: 3},    // A swapped pair is not edited again
+		{a: "müller", b: "muller", want: 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestWatchlistScreener(t *testing.T) {
+	screener := NewWatchlistScreener([]WatchlistEntry{
+		{ID: "1", Name: "DOE, John Michael", Program: "SDGT"},
+		{ID: "2", Name: "Ivan PETROV"},
+		{ID: "3", Name: "Jon Doe"},
+	}, 0)
+	tests := []struct {
+		name string
+
+		wantIDs    []string // Best match first
+		wantScores []int
+	}{
+		{name: "John Doe", wantIDs: []string{"1", "3"}, wantScores: []int{100, 87}},
+		{name: "doe, john", wantIDs: []string{"1", "3"}, wantScores: []int{100, 87}},
+		{name: "Jonh Doe", wantIDs: []string{"1", "3"}, wantScores: []int{87, 87}},
+		{name: "Petrov Ivan", wantIDs: []string{"2"}, wantScores: []int{100}},
+		{name: "Ivan Petrova", wantIDs: []string{"2"}, wantScores: []int{92}},
+		{name: "Jane Doe"},
+		{name: "Petrov"}, // One word must match the whole listed name
+		{name: "ivan petrov", wantIDs: []string{"2"}, wantScores: []int{100}},
+		{name: "Ivan-Petrov!", wantIDs: []string{"2"}, wantScores: []int{100}},
+		{name: "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := screener.Screen(context.Background(), tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			var scores []int
+			for _, match := range matches {
+				if match.Name != tt.name {
+					t.Errorf("match for %q, want %q", match.Name, tt.name)
+				}
+				ids = append(ids, match.ListEntryID)
+				scores = append(scores, match.Score)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || !reflect.DeepEqual(scores, tt.wantScores) {
+				t.Errorf("matches = %v scoring %v, want %v scoring %v", ids, scores, tt.wantIDs, tt.wantScores)
+			}
+		})
+	}
+}
+
+func TestWatchlistScreenerThreshold(t *testing.T) {
+	entries := []WatchlistEntry{{ID: "1", Name: "John Doe"}}
+	if matches, _ := NewWatchlistScreener(entries, 80).Screen(context.Background(), "Jane Doe"); len(matches) != 0 {
+		t.Errorf("Jane Doe matched %v at 80", matches)
+	}
+	if matches, _ := NewWatchlistScreener(entries, 60).Screen(context.Background(), "Jane Doe"); len(matches) != 1 {
+		t.Errorf("Jane Doe matched %v at 60, want John Doe", matches)
+	}
+}
@@ -0,0 +1,461 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+	"github.com/kodra-pay/wallet-ledger-service/internal/repositories"
+)
+
+// Headers set on every webhook request
+const (
+	WebhookSignatureHeader = "X-Kodra-Signature"
+	WebhookEventIDHeader   = "X-Kodra-Event-ID"
+	WebhookEventTypeHeader = "X-Kodra-Event-Type"
+)
+
+var (
+	errWebhookEndpointInactive = errors.New("endpoint is no longer active")
+	errWebhookPrivateReceiver  = errors.New("receiver address is not public")
+)
+
+// webhookClaimBatch is the number of due deliveries processed per poll
+const webhookClaimBatch = 50
+
+// EventPublisher receives domain events emitted by services
+type EventPublisher interface {
+	Publish(ctx context.Context, merchantID int, eventType string, data interface{}) error
+}
+
+// WebhookConfig controls webhook delivery and retries
+type WebhookConfig struct {
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Timeout     time.Duration
+
+	// AllowPrivateReceivers lets endpoints resolve to loopback, link-local
+	// and private addresses. Only for local development and tests; otherwise
+	// merchants could make the service call its own network.
+	AllowPrivateReceivers bool
+}
+
+// WebhookService manages merchant webhook endpoints and delivers signed events to them
+type WebhookService struct {
+	repo   repositories.WebhookRepository
+	tx     repositories.Transactor
+	audit  *AuditService
+	client *http.Client
+	cfg    WebhookConfig
+	now    func() time.Time
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo repositories.WebhookRepository, tx repositories.Transactor, audit *AuditService, cfg WebhookConfig) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		tx:     tx,
+		audit:  audit,
+		client: newWebhookClient(cfg),
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// WithClock replaces the service's clock, e.g. with a fake in tests
+func (s *WebhookService) WithClock(now func() time.Time) *WebhookService {
+	s.now = now
+	return s
+}
+
+// SignWebhookPayload returns the X-Kodra-Signature header value for a payload.
+// Receivers recompute HMAC-SHA256(secret, "<timestamp>.<body>") and compare it to v1.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func (s *WebhookService) CreateEndpoint(ctx context.Context, req dto.CreateWebhookEndpointRequest) (*dto.WebhookEndpointResponse, error) {
+	if err := authorizeOwner(ctx, req.MerchantID); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidRequest)
+	}
+	if !s.cfg.AllowPrivateReceivers {
+		if err := checkWebhookHost(ctx, u.Hostname()); err != nil {
+			return nil, fmt.Errorf("%w: url %v", ErrInvalidRequest, err)
+		}
+	}
+	if len(req.EventTypes) == 0 {
+		req.EventTypes = []string{models.EventWildcard}
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := s.now()
+	endpoint := &models.WebhookEndpoint{
+		MerchantID: req.MerchantID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateEndpoint(ctx, endpoint); err != nil {
+			return fmt.Errorf("failed to create webhook endpoint: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditWebhookCreated, "webhook_endpoint", endpoint.ID, nil, endpoint)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toWebhookEndpointResponse(endpoint)
+	resp.Secret = endpoint.Secret // Only revealed once, at creation time
+	return resp, nil
+}
+
+func (s *WebhookService) ListEndpoints(ctx context.Context, merchantID int) ([]dto.WebhookEndpointResponse, error) {
+	if err := authorizeOwner(ctx, merchantID); err != nil {
+		return nil, err
+	}
+	endpoints, err := s.repo.ListEndpointsByMerchantID(ctx, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	resp := make([]dto.WebhookEndpointResponse, 0, len(endpoints))
+	for i := range endpoints {
+		resp = append(resp, *toWebhookEndpointResponse(&endpoints[i]))
+	}
+	return resp, nil
+}
+
+func (s *WebhookService) DeactivateEndpoint(ctx context.Context, endpointID int) error {
+	endpoint, err := s.repo.GetEndpointByID(ctx, endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+	if endpoint == nil {
+		return ErrWebhookEndpointNotFound
+	}
+	if err := authorizeOwner(ctx, endpoint.MerchantID); err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeactivateEndpoint(ctx, endpointID); err != nil {
+			return fmt.Errorf("failed to deactivate webhook endpoint: %w", err)
+		}
+		after := *endpoint
+		after.Active = false
+		return s.audit.Record(ctx, models.AuditWebhookDeactivated, "webhook_endpoint", endpoint.ID, endpoint, &after)
+	})
+}
+
+// Publish queues a delivery of the event for every active endpoint of the
+// merchant that subscribes to eventType. Delivery itself happens asynchronously.
+func (s *WebhookService) Publish(ctx context.Context, merchantID int, eventType string, data interface{}) error {
+	endpoints, err := s.repo.ListEndpointsByMerchantID(ctx, merchantID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	var event *models.WebhookEvent
+	var payload []byte
+	for i := range endpoints {
+		if !endpoints[i].Subscribes(eventType) {
+			continue
+		}
+		if event == nil {
+			raw, err := json.Marshal(data)
+			if err != nil {
+				return fmt.Errorf("failed to encode event data: %w", err)
+			}
+			event = &models.WebhookEvent{
+				ID:         uuid.NewString(),
+				Type:       eventType,
+				MerchantID: merchantID,
+				CreatedAt:  s.now().UTC(),
+				Data:       raw,
+			}
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("failed to encode event: %w", err)
+			}
+		}
+		if err := s.repo.CreateDelivery(ctx, models.NewWebhookDelivery(endpoints[i].ID, event, payload, s.now())); err != nil {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]dto.WebhookDeliveryResponse, error) {
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+	if ownerID, restricted := callerOwnerID(ctx); restricted {
+		if filter.MerchantID != 0 && filter.MerchantID != ownerID {
+			return nil, ErrForbidden
+		}
+		filter.MerchantID = ownerID
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	resp := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		resp = append(resp, toWebhookDeliveryResponse(&deliveries[i]))
+	}
+	return resp, nil
+}
+
+// Redeliver resets a delivery so the worker sends it again with a fresh retry budget
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID int) (*dto.WebhookDeliveryResponse, error) {
+	delivery, err := s.repo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	endpoint, err := s.repo.GetEndpointByID(ctx, delivery.EndpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+	if endpoint == nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	if err := authorizeOwner(ctx, endpoint.MerchantID); err != nil {
+		return nil, err
+	}
+
+	before := *delivery
+	now := s.now()
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to schedule redelivery: %w", err)
+		}
+		return s.audit.Record(ctx, models.AuditWebhookRedelivered, "webhook_delivery", delivery.ID, &before, delivery)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toWebhookDeliveryResponse(delivery)
+	return &resp, nil
+}
+
+// ProcessDueDeliveries sends every delivery whose next attempt is due
+func (s *WebhookService) ProcessDueDeliveries(ctx context.Context) error {
+	now := s.now()
+	// Lease the batch for longer than the sends can take so another replica
+	// does not pick the same deliveries up mid-flight.
+	lease := now.Add(time.Duration(webhookClaimBatch+1) * s.cfg.Timeout)
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, now, lease, webhookClaimBatch)
+	if err != nil {
+		return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	for i := range deliveries {
+		if err := s.deliver(ctx, &deliveries[i]); err != nil {
+			log.Printf("webhook delivery %d: %v", deliveries[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	endpoint, err := s.repo.GetEndpointByID(ctx, delivery.EndpointID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	now := s.now()
+	if endpoint == nil || !endpoint.Active {
+		// Nobody is listening any more; park it so it can be redelivered if the endpoint returns
+		delivery.Status = models.WebhookDeliveryDeadLetter
+		delivery.LastError = errWebhookEndpointInactive.Error()
+		delivery.UpdatedAt = now
+		return s.repo.UpdateDelivery(ctx, delivery)
+	}
+
+	statusCode, sendErr := s.send(ctx, endpoint, delivery)
+
+	now = s.now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.UpdatedAt = now
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= s.cfg.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryDeadLetter
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+	}
+	return s.repo.UpdateDelivery(ctx, delivery)
+}
+
+func (s *WebhookService) send(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventIDHeader, delivery.EventID)
+	req.Header.Set(WebhookEventTypeHeader, delivery.EventType)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, s.now().Unix(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the exponential delay before the next attempt, capped at BackoffMax
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	delay := s.cfg.BackoffBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.cfg.BackoffMax {
+			return s.cfg.BackoffMax
+		}
+	}
+	return delay
+}
+
+// newWebhookClient returns the HTTP client used for deliveries. Unless private
+// receivers are allowed, every connection is checked at dial time, after DNS
+// resolution and on redirects, so a hostname cannot be repointed at an
+// internal address once the endpoint has been registered.
+func newWebhookClient(cfg WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateReceivers {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", errWebhookPrivateReceiver, host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would dial on our behalf, past the check
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: cfg.Timeout, Transport: transport}
+}
+
+// checkWebhookHost rejects a receiver host that is, or resolves to, an
+// address that is not public
+func checkWebhookHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("%w: %s", errWebhookPrivateReceiver, host)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("host %s does not resolve", host)
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", errWebhookPrivateReceiver, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// isPublicIP reports whether ip is a unicast address outside the loopback,
+// link-local (including cloud metadata at 169.254.169.254) and private ranges
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast()
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func toWebhookEndpointResponse(endpoint *models.WebhookEndpoint) *dto.WebhookEndpointResponse {
+	return &dto.WebhookEndpointResponse{
+		ID:         endpoint.ID,
+		MerchantID: endpoint.MerchantID,
+		URL:        endpoint.URL,
+		EventTypes: endpoint.EventTypes,
+		Active:     endpoint.Active,
+		CreatedAt:  endpoint.CreatedAt,
+		UpdatedAt:  endpoint.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery *models.WebhookDelivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EndpointID:     delivery.EndpointID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/wallet-ledger-service/internal/dto"
+	"github.com/kodra-pay/wallet-ledger-service/internal/models"
+)
+
+// webhookReceiver is a merchant endpoint answering each request with the next
+// of its status codes, repeating the last one
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := r.statuses[len(r.statuses)-1]
+	if len(r.headers) < len(r.statuses) {
+		status = r.statuses[len(r.headers)]
+	}
+	r.headers = append(r.headers, req.Header.Clone())
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(status)
+}
+
+func (r *webhookReceiver) requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.headers)
+}
+
+// startWebhookTest serves receiver over HTTP and queues one balance event for
+// it, due at start
+func startWebhookTest(t *testing.T, receiver *webhookReceiver, start time.Time) (*WebhookService, *fakeWebhookRepository, *fakeClock, int) {
+	t.Helper()
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	repo := newFakeWebhookRepository()
+	clock := newFakeClock(start)
+	svc := NewWebhookService(repo, fakeTransactor{}, nil, WebhookConfig{
+		MaxAttempts: 4,
+		BackoffBase: time.Minute,
+		BackoffMax:  3 * time.Minute,
+		Timeout:     5 * time.Second,
+
+		AllowPrivateReceivers: true, // The receiver listens on loopback
+	}).WithClock(clock.Now)
+
+	ctx := context.Background()
+	endpoint := &models.WebhookEndpoint{MerchantID: 7, URL: server.URL, Secret: "whsec_test", EventTypes: []string{models.EventWildcard}, Active: true}
+	if err := repo.CreateEndpoint(ctx, endpoint); err != nil {
+		t.Fatal(err)
+	}
+	delivery := &models.WebhookDelivery{
+		EndpointID:    endpoint.ID,
+		EventID:       "evt_1",
+		EventType:     models.EventBalanceCredited,
+		Payload:       []byte(`{"id":"evt_1","type":"balance.credited"}`),
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: start,
+	}
+	if err := repo.CreateDelivery(ctx, delivery); err != nil {
+		t.Fatal(err)
+	}
+	return svc, repo, clock, delivery.ID
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	payload := []byte(`{"id":"evt_1"}`)
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte(`1772442000.{"id":"evt_1"}`))
+	want := "t=1772442000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhookPayload("whsec_test", 1772442000, payload); got != want {
+		t.Errorf("SignWebhookPayload = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	svc := NewWebhookService(nil, nil, nil, WebhookConfig{BackoffBase: time.Minute, BackoffMax: 3 * time.Minute})
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 3 * time.Minute}, // Capped
+		{10, 3 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := svc.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestProcessDueDeliveries(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		statuses []int           // Receiver replies, the last one repeating
+		ticks    []time.Duration // When the worker runs, after start
+		inactive bool
+
+		wantStatus   string
+		wantAttempts int
+		wantNext     time.Duration // Next attempt after start, for failed deliveries
+	}{
+		{
+			name:         "delivered",
+			statuses:     []int{http.StatusOK},
+			ticks:        []time.Duration{0},
+			wantStatus:   models.WebhookDeliverySucceeded,
+			wantAttempts: 1,
+		},
+		{
+			name:         "waits out the backoff",
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			ticks:        []time.Duration{0, 59 * time.Second},
+			wantStatus:   models.WebhookDeliveryFailed,
+			wantAttempts: 1,
+			wantNext:     time.Minute,
+		},
+		{
+			name:         "doubles the backoff",
+			statuses:     []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			ticks:        []time.Duration{0, time.Minute},
+			wantStatus:   models.WebhookDeliveryFailed,
+			wantAttempts: 2,
+			wantNext:     3 * time.Minute,
+		},
+		{
+			name:         "delivered on retry",
+			statuses:     []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			ticks:        []time.Duration{0, time.Minute, 3 * time.Minute},
+			wantStatus:   models.WebhookDeliverySucceeded,
+			wantAttempts: 3,
+		},
+		{
+			name:         "dead-lettered after max attempts",
+			statuses:     []int{http.StatusServiceUnavailable},
+			ticks:        []time.Duration{0, time.Hour, 2 * time.Hour, 3 * time.Hour, 24 * time.Hour},
+			wantStatus:   models.WebhookDeliveryDeadLetter,
+			wantAttempts: 4,
+		},
+		{
+			name:         "endpoint deactivated",
+			statuses:     []int{http.StatusOK},
+			ticks:        []time.Duration{0},
+			inactive:     true,
+			wantStatus:   models.WebhookDeliveryDeadLetter,
+			wantAttempts: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &webhookReceiver{statuses: tt.statuses}
+			svc, repo, clock, id := startWebhookTest(t, receiver, start)
+			ctx := context.Background()
+			if tt.inactive {
+				delivery, _ := repo.GetDeliveryByID(ctx, id)
+				if err := repo.DeactivateEndpoint(ctx, delivery.EndpointID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, tick := range tt.ticks {
+				clock.Set(start.Add(tick))
+				if err := svc.ProcessDueDeliveries(ctx); err != nil {
+					t.Fatalf("ProcessDueDeliveries at +%v: %v", tick, err)
+				}
+			}
+
+			delivery, _ := repo.GetDeliveryByID(ctx, id)
+			if delivery.Status != tt.wantStatus || delivery.Attempts != tt.wantAttempts {
+				t.Fatalf("delivery = %s after %d attempts, want %s after %d", delivery.Status, delivery.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			if got := receiver.requests(); got != tt.wantAttempts {
+				t.Errorf("receiver got %d requests, want %d", got, tt.wantAttempts)
+			}
+			if tt.wantStatus == models.WebhookDeliveryFailed && !delivery.NextAttemptAt.Equal(start.Add(tt.wantNext)) {
+				t.Errorf("next attempt = %v, want %v", delivery.NextAttemptAt, start.Add(tt.wantNext))
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryRequest(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	receiver := &webhookReceiver{statuses: []int{http.StatusNoContent}}
+	svc, repo, _, id := startWebhookTest(t, receiver, start)
+	if err := svc.ProcessDueDeliveries(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery, _ := repo.GetDeliveryByID(context.Background(), id)
+	if delivery.DeliveredAt == nil || !delivery.DeliveredAt.Equal(start) || delivery.LastStatusCode != http.StatusNoContent {
+		t.Errorf("delivered at %v with status %d, want %v with 204", delivery.DeliveredAt, delivery.LastStatusCode, start)
+	}
+	header, body := receiver.headers[0], receiver.bodies[0]
+	if string(body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", body, delivery.Payload)
+	}
+	if got := header.Get(WebhookEventIDHeader); got != "evt_1" {
+		t.Errorf("%s = %q, want evt_1", WebhookEventIDHeader, got)
+	}
+	if got := header.Get(WebhookEventTypeHeader); got != models.EventBalanceCredited {
+		t.Errorf("%s = %q, want %s", WebhookEventTypeHeader, got, models.EventBalanceCredited)
+	}
+	if got, want := header.Get(WebhookSignatureHeader), SignWebhookPayload("whsec_test", start.Unix(), body); got != want {
+		t.Errorf("%s = %q, want %q", WebhookSignatureHeader, got, want)
+	}
+}
+
+func TestRedeliverDeadLetter(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	receiver := &webhookReceiver{statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK}}
+	svc, repo, clock, id := startWebhookTest(t, receiver, start)
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if err := svc.ProcessDueDeliveries(ctx); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(time.Hour)
+	}
+
+	// A redelivery starts over with a fresh retry budget
+	resp, err := svc.Redeliver(ctx, id)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if resp.Status != models.WebhookDeliveryPending || resp.Attempts != 0 {
+		t.Fatalf("redelivery = %s after %d attempts, want pending with none", resp.Status, resp.Attempts)
+	}
+	if err := svc.ProcessDueDeliveries(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if delivery, _ := repo.GetDeliveryByID(ctx, id); delivery.Status != models.WebhookDeliverySucceeded || delivery.Attempts != 1 {
+		t.Errorf("delivery = %s after %d attempts, want succeeded after 1", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestCreateEndpointRejectsPrivateReceivers(t *testing.T) {
+	svc := NewWebhookService(newFakeWebhookRepository(), fakeTransactor{}, nil, WebhookConfig{})
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{"https://93.184.216.34/hooks", nil},
+		{"http://127.0.0.1:8080/hooks", ErrInvalidRequest},
+		{"http://localhost/hooks", ErrInvalidRequest},
+		{"http://[::1]/hooks", ErrInvalidRequest},
+		{"http://169.254.169.254/latest/meta-data", ErrInvalidRequest},
+		{"https://10.0.0.5/hooks", ErrInvalidRequest},
+		{"https://192.168.1.20/hooks", ErrInvalidRequest},
+		{"http://0.0.0.0/hooks", ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		_, err := svc.CreateEndpoint(context.Background(), dto.CreateWebhookEndpointRequest{MerchantID: 7, URL: tt.url})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("CreateEndpoint(%s): err = %v, want %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDeliveryRefusesPrivateReceiver(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	receiver := &webhookReceiver{statuses: []int{http.StatusOK}}
+	_, repo, clock, id := startWebhookTest(t, receiver, start)
+
+	// An endpoint that passed registration can still be repointed at an
+	// internal address through DNS, so the address is checked again on dial
+	svc := NewWebhookService(repo, fakeTransactor{}, nil, WebhookConfig{
+		MaxAttempts: 4,
+		BackoffBase: time.Minute,
+		BackoffMax:  3 * time.Minute,
+		Timeout:     5 * time.Second,
+	}).WithClock(clock.Now)
+	if err := svc.ProcessDueDeliveries(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery, _ := repo.GetDeliveryByID(context.Background(), id)
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.Attempts != 1 {
+		t.Errorf("delivery = %s after %d attempts, want failed after 1", delivery.Status, delivery.Attempts)
+	}
+	if got := receiver.requests(); got != 0 {
+		t.Errorf("receiver got %d requests, want none", got)
+	}
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Run calls fn every interval until ctx is cancelled. Errors are logged and
+// the job keeps running, so one bad tick does not stop background processing.
+func Run(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("worker %s started (every %s)", name, interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("worker %s stopped", name)
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("worker %s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+-- Create webhook_endpoints table
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    merchant_id BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,           -- HMAC-SHA256 signing secret shared with the merchant
+    event_types TEXT[] NOT NULL,    -- e.g., 'balance.credited', or '*' for everything
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_merchant ON webhook_endpoints (merchant_id) WHERE active;
+
+-- Create webhook_deliveries table (delivery log and retry queue)
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    endpoint_id BIGINT NOT NULL REFERENCES webhook_endpoints(id),
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,    -- 'pending', 'failed', 'succeeded', 'dead_letter'
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_status_code INT,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_endpoint_event UNIQUE (endpoint_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status IN ('pending', 'failed');
@@ -0,0 +1,13 @@
+-- Create api_keys table (only hashes of keys are stored)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE, -- Public lookup part of the key
+    key_hash CHAR(64) NOT NULL,         -- Hex SHA-256 of the full key
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP,               -- Grace-period cutoff after rotation
+    revoked_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    rotated_to BIGINT REFERENCES api_keys(id)
+);
@@ -0,0 +1,5 @@
+-- Link reversal entries to the entry they reverse
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS reversal_of BIGINT REFERENCES ledger_entries(id);
+
+-- An entry can only be reversed once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_reversal_of ON ledger_entries (reversal_of) WHERE reversal_of IS NOT NULL;
@@ -0,0 +1,33 @@
+-- Create audit_log table (append-only)
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,      -- e.g., 'wallet.created', 'wallet.balance_updated'
+    entity_type VARCHAR(50) NOT NULL,  -- e.g., 'wallet', 'api_key'
+    entity_id VARCHAR(100) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,   -- 'api_key', 'user' or 'system'
+    actor_id VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255),
+    source_ip VARCHAR(64),
+    before_state JSONB,
+    after_state JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor_type, actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+
+-- Reject any attempt to rewrite history
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_update ON audit_log;
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
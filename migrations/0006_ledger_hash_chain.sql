@@ -0,0 +1,19 @@
+-- Per-wallet hash chain over ledger entries. Entries written before this
+-- migration keep NULL hashes; each wallet's chain starts at its first hashed entry.
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS prev_hash CHAR(64);
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS hash CHAR(64);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_wallet_id_id ON ledger_entries (wallet_id, id);
+
+-- Create ledger_checkpoints table (signed snapshots of every wallet's chain head)
+CREATE TABLE IF NOT EXISTS ledger_checkpoints (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    max_entry_id BIGINT NOT NULL,      -- Highest ledger entry covered
+    entry_count BIGINT NOT NULL,
+    wallet_count BIGINT NOT NULL,
+    heads_root CHAR(64) NOT NULL,      -- SHA-256 over every wallet's head hash
+    prev_checkpoint_hash CHAR(64),     -- Chains checkpoints together
+    payload_hash CHAR(64) NOT NULL,    -- SHA-256 of the signed payload
+    signature TEXT NOT NULL,           -- Base64 Ed25519 signature of the payload
+    key_id VARCHAR(32) NOT NULL,       -- Identifies the signing key
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,34 @@
+-- Group the ledger entries of one money movement
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS journal_id UUID;
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_journal ON ledger_entries (journal_id);
+
+-- Distinguish customer wallets from internal system accounts (e.g. external clearing)
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'standard'; -- 'standard', 'system'
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS system_code VARCHAR(50);                       -- Set for system accounts only
+
+-- One customer wallet per user and currency; one system account per code and currency
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS unique_user_currency;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallets_user_currency ON wallets (user_id, currency) WHERE type <> 'system';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallets_system_code_currency ON wallets (system_code, currency) WHERE type = 'system';
+
+-- Create consistency_reports table (results of ledger-vs-wallet checks)
+CREATE TABLE IF NOT EXISTS consistency_reports (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    ok BOOLEAN NOT NULL,
+    wallets_checked INT NOT NULL,
+    entries_checked BIGINT NOT NULL,
+    discrepancy_count INT NOT NULL,
+    report JSONB NOT NULL
+);
+
+-- Entries up to this ID were posted single-sided, before double-entry journals.
+-- The consistency checker does not expect their journals to balance.
+CREATE TABLE IF NOT EXISTS double_entry_cutover (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id), -- Single row
+    max_legacy_entry_id BIGINT NOT NULL
+);
+INSERT INTO double_entry_cutover (max_legacy_entry_id)
+SELECT COALESCE(MAX(id), 0) FROM ledger_entries
+ON CONFLICT (id) DO NOTHING;
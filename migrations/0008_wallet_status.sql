@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Wallet lifecycle status
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'; -- 'active', 'frozen', 'closed'
+
+-- +migrate Down
+ALTER TABLE wallets DROP COLUMN IF EXISTS status;
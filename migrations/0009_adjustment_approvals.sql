@@ -0,0 +1,38 @@
+-- +migrate Up
+-- Create adjustment_requests table (manual credits/debits awaiting approval)
+CREATE TABLE IF NOT EXISTS adjustment_requests (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    type VARCHAR(10) NOT NULL,               -- 'credit', 'debit'
+    amount BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    reference BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,             -- 'pending', 'approved', 'rejected', 'expired'
+    required_approvals INT NOT NULL,
+    proposed_by_type VARCHAR(20) NOT NULL,   -- Maker principal
+    proposed_by_id VARCHAR(255) NOT NULL,
+    ledger_entry_id BIGINT REFERENCES ledger_entries(id), -- Set once approved and posted
+    expires_at TIMESTAMP NOT NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_adjustment_requests_pending ON adjustment_requests (expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_adjustment_requests_wallet ON adjustment_requests (wallet_id);
+
+-- Create adjustment_decisions table (the approval chain; one decision per checker)
+CREATE TABLE IF NOT EXISTS adjustment_decisions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    adjustment_id BIGINT NOT NULL REFERENCES adjustment_requests(id),
+    decision VARCHAR(10) NOT NULL,           -- 'approve', 'reject'
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_adjustment_actor UNIQUE (adjustment_id, actor_type, actor_id)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS adjustment_decisions;
+DROP TABLE IF EXISTS adjustment_requests;
@@ -0,0 +1,48 @@
+-- +migrate Up
+-- Create transfer_schedules table (standing orders between wallets)
+CREATE TABLE IF NOT EXISTS transfer_schedules (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    from_wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    to_wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    amount BIGINT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    rule_kind VARCHAR(20) NOT NULL,         -- 'interval', 'cron'
+    rule VARCHAR(100) NOT NULL,             -- e.g. '1mo', '0 9 1 * *'
+    timezone VARCHAR(64) NOT NULL,          -- IANA zone the rule is evaluated in
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP,
+    status VARCHAR(20) NOT NULL,            -- 'active', 'paused', 'cancelled', 'completed'
+    next_run_at TIMESTAMP,                  -- Next occurrence; NULL once there are none
+    next_attempt_at TIMESTAMP,              -- When the worker acts; later than next_run_at while retrying
+    attempts INT NOT NULL DEFAULT 0,        -- Failed attempts at the current occurrence
+    max_retries INT NOT NULL,
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_schedules_due ON transfer_schedules (next_attempt_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_transfer_schedules_from_wallet ON transfer_schedules (from_wallet_id);
+
+-- Create transfer_runs table (execution history of each schedule)
+CREATE TABLE IF NOT EXISTS transfer_runs (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    schedule_id BIGINT NOT NULL REFERENCES transfer_schedules(id),
+    occurrence_at TIMESTAMP NOT NULL,
+    attempt INT NOT NULL,
+    status VARCHAR(20) NOT NULL,            -- 'running', 'succeeded', 'failed', 'abandoned'
+    journal_id UUID,
+    ledger_entry_id BIGINT REFERENCES ledger_entries(id),
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_runs_schedule ON transfer_runs (schedule_id, id);
+
+-- Idempotency key: each occurrence of a schedule succeeds at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_runs_once ON transfer_runs (schedule_id, occurrence_at) WHERE status = 'succeeded';
+
+-- +migrate Down
+DROP TABLE IF EXISTS transfer_runs;
+DROP TABLE IF EXISTS transfer_schedules;
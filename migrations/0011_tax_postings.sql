@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Create tax_postings table (tax collected on fees, for filing)
+CREATE TABLE IF NOT EXISTS tax_postings (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id), -- The credit to the tax payable account
+    jurisdiction VARCHAR(20) NOT NULL,
+    tax_name VARCHAR(50) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    rate_bps BIGINT NOT NULL,
+    inclusive BOOLEAN NOT NULL,
+    base_amount BIGINT NOT NULL,           -- Fee net of tax; negative for reversals
+    tax_amount BIGINT NOT NULL,            -- Negative for reversals
+    reversal_of BIGINT REFERENCES tax_postings(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tax_postings_created_at ON tax_postings (created_at);
+CREATE INDEX IF NOT EXISTS idx_tax_postings_journal ON tax_postings (journal_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS tax_postings;
@@ -0,0 +1,44 @@
+-- +migrate Up
+-- Create escrows table (buyer funds held against a contract until release or refund)
+CREATE TABLE IF NOT EXISTS escrows (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    contract_id VARCHAR(255) NOT NULL,
+    buyer_wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    seller_wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,                 -- Held at creation
+    released_amount BIGINT NOT NULL DEFAULT 0,
+    refunded_amount BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,            -- 'held', 'partially_released', 'released', 'refunded'
+    description TEXT NOT NULL DEFAULT '',
+    release_at TIMESTAMP,                   -- Remaining funds go to the seller at this time
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_escrow_contract UNIQUE (contract_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_escrows_release_due ON escrows (release_at) WHERE status IN ('held', 'partially_released');
+CREATE INDEX IF NOT EXISTS idx_escrows_buyer_wallet ON escrows (buyer_wallet_id);
+CREATE INDEX IF NOT EXISTS idx_escrows_seller_wallet ON escrows (seller_wallet_id);
+
+-- Create escrow_movements table (every hold, release and refund with its journal)
+CREATE TABLE IF NOT EXISTS escrow_movements (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    escrow_id BIGINT NOT NULL REFERENCES escrows(id),
+    type VARCHAR(20) NOT NULL,              -- 'hold', 'release', 'refund'
+    amount BIGINT NOT NULL,
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id), -- The entry on the buyer or seller wallet
+    reason TEXT,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_escrow_movements_escrow ON escrow_movements (escrow_id, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS escrow_movements;
+DROP TABLE IF EXISTS escrows;
@@ -0,0 +1,52 @@
+-- +migrate Up
+-- Create settlement_schedules table (per-merchant wallet settlement schedule)
+CREATE TABLE IF NOT EXISTS settlement_schedules (
+    wallet_id BIGINT PRIMARY KEY REFERENCES wallets(id),
+    schedule VARCHAR(30) NOT NULL,          -- 'T+N', 'weekly:<day>', 'manual'
+    updated_by_type VARCHAR(20) NOT NULL,
+    updated_by_id VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create settlement_batches table (one payout of pending funds to a merchant wallet)
+CREATE TABLE IF NOT EXISTS settlement_batches (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,
+    item_count INT NOT NULL,
+    settlement_date DATE NOT NULL,
+    trigger VARCHAR(20) NOT NULL,           -- 'scheduled', 'manual'
+    journal_id UUID,                        -- Set once posted, in the same transaction
+    ledger_entry_id BIGINT REFERENCES ledger_entries(id), -- The credit to the merchant wallet
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_batches_wallet ON settlement_batches (wallet_id, id);
+
+-- Create settlement_captures table (captured funds waiting in pending; the batch line items)
+CREATE TABLE IF NOT EXISTS settlement_captures (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,
+    reference BIGINT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    schedule VARCHAR(30) NOT NULL,          -- The wallet's schedule when captured
+    captured_at TIMESTAMP NOT NULL,
+    eligible_on DATE NOT NULL,
+    status VARCHAR(20) NOT NULL,            -- 'pending', 'settled'
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id), -- The credit to the merchant pending account
+    batch_id BIGINT REFERENCES settlement_batches(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_captures_due ON settlement_captures (eligible_on, wallet_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_settlement_captures_batch ON settlement_captures (batch_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS settlement_captures;
+DROP TABLE IF EXISTS settlement_batches;
+DROP TABLE IF EXISTS settlement_schedules;
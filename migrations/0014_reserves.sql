@@ -0,0 +1,57 @@
+-- +migrate Up
+-- Create reserve_policies table (per-merchant wallet rolling reserve)
+CREATE TABLE IF NOT EXISTS reserve_policies (
+    wallet_id BIGINT PRIMARY KEY REFERENCES wallets(id),
+    percentage_bps BIGINT NOT NULL,         -- Share of each settlement held back
+    hold_days INT NOT NULL,                 -- Days each hold is kept before release
+    cap BIGINT NOT NULL DEFAULT 0,          -- Most held from settlements at once; 0 is uncapped
+    updated_by_type VARCHAR(20) NOT NULL,
+    updated_by_id VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create reserves table (merchant funds held back in the reserve account)
+CREATE TABLE IF NOT EXISTS reserves (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,                 -- Held at creation
+    released_amount BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,            -- 'held', 'partially_released', 'released'
+    source VARCHAR(20) NOT NULL,            -- 'settlement', 'manual'
+    batch_id BIGINT REFERENCES settlement_batches(id),
+    reason TEXT NOT NULL DEFAULT '',
+    release_at TIMESTAMP,                   -- Remaining funds go back to the merchant at this time
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reserves_release_due ON reserves (release_at) WHERE status IN ('held', 'partially_released');
+CREATE INDEX IF NOT EXISTS idx_reserves_wallet ON reserves (wallet_id, id);
+
+-- Create reserve_movements table (every hold and release with its journal)
+CREATE TABLE IF NOT EXISTS reserve_movements (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    reserve_id BIGINT NOT NULL REFERENCES reserves(id),
+    type VARCHAR(20) NOT NULL,              -- 'hold', 'release'
+    amount BIGINT NOT NULL,
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id), -- The entry on the merchant wallet
+    reason TEXT,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reserve_movements_reserve ON reserve_movements (reserve_id, id);
+
+-- Record how much of each settlement batch was held back
+ALTER TABLE settlement_batches ADD COLUMN IF NOT EXISTS reserve_amount BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE settlement_batches DROP COLUMN IF EXISTS reserve_amount;
+DROP TABLE IF EXISTS reserve_movements;
+DROP TABLE IF EXISTS reserves;
+DROP TABLE IF EXISTS reserve_policies;
@@ -0,0 +1,32 @@
+-- +migrate Up
+-- Create payouts table (withdrawals to bank accounts through a payout provider)
+CREATE TABLE IF NOT EXISTS payouts (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,
+    reference BIGINT NOT NULL,
+    destination TEXT NOT NULL,              -- Bank account as understood by the provider
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,            -- 'requested', 'processing', 'succeeded', 'failed', 'returned'
+    provider VARCHAR(50) NOT NULL,
+    provider_reference VARCHAR(255),        -- Set once the provider accepts the payout
+    failure_reason TEXT,
+    attempts INT NOT NULL DEFAULT 0,        -- Submissions to the provider
+    next_attempt_at TIMESTAMP,              -- Set while requested
+    lock_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id),  -- The debit moving funds into payouts in flight
+    final_entry_id BIGINT REFERENCES ledger_entries(id),          -- Success: the credit to external clearing
+    release_entry_id BIGINT REFERENCES ledger_entries(id),        -- Failure or return: the credit back to the wallet
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_payout_wallet_reference UNIQUE (wallet_id, reference)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payouts_provider_reference ON payouts (provider, provider_reference) WHERE provider_reference IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_payouts_submit_due ON payouts (next_attempt_at) WHERE status = 'requested';
+CREATE INDEX IF NOT EXISTS idx_payouts_wallet ON payouts (wallet_id, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS payouts;
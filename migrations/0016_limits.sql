@@ -0,0 +1,20 @@
+-- +migrate Up
+-- KYC tier that selects a wallet's spend and velocity limits; empty uses the default tier
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS kyc_tier VARCHAR(30) NOT NULL DEFAULT '';
+
+-- Create wallet_limit_overrides table (per-wallet limits replacing the tier's)
+CREATE TABLE IF NOT EXISTS wallet_limit_overrides (
+    wallet_id BIGINT PRIMARY KEY REFERENCES wallets(id),
+    limits JSONB NOT NULL,                  -- Limit name -> amount or count
+    updated_by_type VARCHAR(20) NOT NULL,
+    updated_by_id VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Period totals are summed from a wallet's recent entries
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_wallet_created ON ledger_entries (wallet_id, created_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_ledger_entries_wallet_created;
+DROP TABLE IF EXISTS wallet_limit_overrides;
+ALTER TABLE wallets DROP COLUMN IF EXISTS kyc_tier;
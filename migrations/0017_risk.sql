@@ -0,0 +1,43 @@
+-- +migrate Up
+-- Create risk_cases table (postings flagged for review or blocked by risk rules)
+CREATE TABLE IF NOT EXISTS risk_cases (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    decision VARCHAR(20) NOT NULL,          -- 'review', 'block'
+    txn_type VARCHAR(20) NOT NULL,          -- 'credit', 'debit', 'transfer'
+    direction VARCHAR(10) NOT NULL,         -- The wallet's side: 'credit' or 'debit'
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    reference BIGINT NOT NULL,
+    journal_id UUID,                        -- Empty for blocked postings, which never commit
+    ledger_entry_id BIGINT REFERENCES ledger_entries(id),
+    status VARCHAR(20) NOT NULL,            -- 'open', 'cleared', 'confirmed'
+    resolution_note TEXT,
+    resolved_by_type VARCHAR(20),
+    resolved_by_id VARCHAR(255),
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_cases_status ON risk_cases (status, id);
+CREATE INDEX IF NOT EXISTS idx_risk_cases_wallet ON risk_cases (wallet_id, id);
+
+-- Create risk_evaluations table (every rule's decision on every checked posting)
+CREATE TABLE IF NOT EXISTS risk_evaluations (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    journal_id UUID,
+    ledger_entry_id BIGINT REFERENCES ledger_entries(id),
+    case_id BIGINT REFERENCES risk_cases(id),
+    rule VARCHAR(100) NOT NULL,
+    decision VARCHAR(20) NOT NULL,          -- 'allow', 'review', 'block'
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_evaluations_entry ON risk_evaluations (ledger_entry_id);
+CREATE INDEX IF NOT EXISTS idx_risk_evaluations_case ON risk_evaluations (case_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS risk_evaluations;
+DROP TABLE IF EXISTS risk_cases;
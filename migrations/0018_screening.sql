@@ -0,0 +1,31 @@
+-- +migrate Up
+-- Wallet holder names are what sanctions screening matches against
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS holder_name VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Create screening_reviews table (wallets and transfers held on a watchlist hit)
+CREATE TABLE IF NOT EXISTS screening_reviews (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    subject_type VARCHAR(20) NOT NULL,      -- 'wallet', 'transfer'
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id), -- The screened wallet, or the transfer's sender
+    to_wallet_id BIGINT REFERENCES wallets(id),       -- Transfers only
+    amount BIGINT,                          -- Transfers only
+    reference BIGINT,                       -- Transfers only
+    description TEXT,                       -- Transfers only
+    matches JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,            -- 'pending', 'cleared', 'confirmed'
+    journal_id UUID,                        -- The transfer, once cleared and posted
+    resolution_note TEXT,
+    resolved_by_type VARCHAR(20),
+    resolved_by_id VARCHAR(255),
+    resolved_at TIMESTAMP,
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_screening_reviews_status ON screening_reviews (status, id);
+CREATE INDEX IF NOT EXISTS idx_screening_reviews_wallet ON screening_reviews (wallet_id, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS screening_reviews;
+ALTER TABLE wallets DROP COLUMN IF EXISTS holder_name;
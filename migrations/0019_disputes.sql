@@ -0,0 +1,45 @@
+-- +migrate Up
+-- Create disputes table (customer disputes of debits)
+CREATE TABLE IF NOT EXISTS disputes (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id), -- The disputed debit
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,
+    provisional_amount BIGINT NOT NULL DEFAULT 0, -- Provisionally credited and not reversed
+    status VARCHAR(30) NOT NULL,          -- 'opened', 'provisional_credit', 'won', 'lost', 'withdrawn'
+    reason TEXT NOT NULL,
+    resolution_note TEXT,
+    provisional_credit_due_at TIMESTAMP,  -- Credited automatically if still opened by then
+    resolve_by TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_disputes_wallet ON disputes (wallet_id, id);
+CREATE INDEX IF NOT EXISTS idx_disputes_entry ON disputes (ledger_entry_id);
+CREATE INDEX IF NOT EXISTS idx_disputes_provisional_due ON disputes (provisional_credit_due_at) WHERE status = 'opened';
+CREATE INDEX IF NOT EXISTS idx_disputes_resolve_by ON disputes (resolve_by) WHERE status IN ('opened', 'provisional_credit');
+
+-- Create dispute_movements table (postings a dispute made)
+CREATE TABLE IF NOT EXISTS dispute_movements (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    dispute_id BIGINT NOT NULL REFERENCES disputes(id),
+    type VARCHAR(30) NOT NULL,            -- 'provisional_credit', 'provisional_reversal', 'recovery', 'final_credit'
+    amount BIGINT NOT NULL,
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id),
+    reason TEXT,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dispute_movements_dispute ON dispute_movements (dispute_id, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS dispute_movements;
+DROP TABLE IF EXISTS disputes;
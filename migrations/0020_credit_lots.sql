@@ -0,0 +1,42 @@
+-- +migrate Up
+-- Create credit_lots table (promotional credit held inside a wallet's balance)
+CREATE TABLE IF NOT EXISTS credit_lots (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    type VARCHAR(30) NOT NULL,            -- 'promo', 'cashback'
+    source VARCHAR(255) NOT NULL,         -- e.g. the campaign that issued it
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,
+    remaining BIGINT NOT NULL,
+    restrictions JSONB NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP,                 -- NULL never expires
+    status VARCHAR(20) NOT NULL,          -- 'open', 'spent', 'expired'
+    expiry_retry_at TIMESTAMP,            -- Set when a write-off had to be postponed
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id), -- The issuing credit
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_lots_wallet ON credit_lots (wallet_id, id);
+CREATE INDEX IF NOT EXISTS idx_credit_lots_expiry ON credit_lots (COALESCE(expiry_retry_at, expires_at)) WHERE status = 'open';
+
+-- Create credit_lot_movements table (issues, spends and write-offs of lots)
+CREATE TABLE IF NOT EXISTS credit_lot_movements (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    lot_id BIGINT NOT NULL REFERENCES credit_lots(id),
+    type VARCHAR(20) NOT NULL,            -- 'issue', 'spend', 'expiry'
+    amount BIGINT NOT NULL,
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_lot_movements_lot ON credit_lot_movements (lot_id, id);
+CREATE INDEX IF NOT EXISTS idx_credit_lot_movements_entry ON credit_lot_movements (ledger_entry_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS credit_lot_movements;
+DROP TABLE IF EXISTS credit_lots;
@@ -0,0 +1,66 @@
+-- +migrate Up
+-- Wallets can hold non-monetary units such as loyalty points
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS asset_type VARCHAR(10) NOT NULL DEFAULT 'fiat'; -- 'fiat', 'points'
+
+-- Registered currency units may be longer than ISO 4217's three letters
+ALTER TABLE tax_postings ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE escrows ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE settlement_batches ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE settlement_captures ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE reserves ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE payouts ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE risk_cases ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE disputes ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE credit_lots ALTER COLUMN currency TYPE VARCHAR(10);
+
+-- Create points_earnings table (loyalty points earned on debits, redeemed oldest first)
+CREATE TABLE IF NOT EXISTS points_earnings (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),        -- The points wallet
+    source_wallet_id BIGINT NOT NULL REFERENCES wallets(id), -- The debited wallet
+    source_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id),
+    rule VARCHAR(100) NOT NULL,
+    points BIGINT NOT NULL,
+    remaining BIGINT NOT NULL,
+    expires_at TIMESTAMP,                 -- NULL never expires
+    status VARCHAR(20) NOT NULL,          -- 'open', 'redeemed', 'expired'
+    expiry_retry_at TIMESTAMP,            -- Set when a write-off had to be postponed
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_points_earnings_wallet ON points_earnings (wallet_id, id);
+CREATE INDEX IF NOT EXISTS idx_points_earnings_expiry ON points_earnings (COALESCE(expiry_retry_at, expires_at)) WHERE status = 'open';
+
+-- Create points_redemptions table (points converted into money)
+CREATE TABLE IF NOT EXISTS points_redemptions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    points_wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id), -- The fiat wallet credited
+    points BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    reference BIGINT NOT NULL,
+    journal_id UUID NOT NULL,
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_points_redemptions_wallet ON points_redemptions (points_wallet_id, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS points_redemptions;
+DROP TABLE IF EXISTS points_earnings;
+ALTER TABLE tax_postings ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE escrows ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE settlement_batches ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE settlement_captures ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE reserves ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE payouts ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE risk_cases ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE disputes ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE credit_lots ALTER COLUMN currency TYPE VARCHAR(3);
+ALTER TABLE wallets DROP COLUMN IF EXISTS asset_type;
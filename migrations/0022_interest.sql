@@ -0,0 +1,46 @@
+-- +migrate Up
+-- Create interest_accounts table (savings wallets enrolled in an interest rate plan)
+CREATE TABLE IF NOT EXISTS interest_accounts (
+    wallet_id BIGINT PRIMARY KEY REFERENCES wallets(id),
+    plan VARCHAR(50) NOT NULL,
+    accrued NUMERIC(30, 12) NOT NULL DEFAULT 0,  -- Uncapitalised interest in minor units, fractions carried forward
+    accrued_through DATE NOT NULL,               -- Last day accrued; accrual resumes the day after
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The accrual worker picks the accounts furthest behind first
+CREATE INDEX IF NOT EXISTS idx_interest_accounts_accrued_through ON interest_accounts (accrued_through);
+
+-- Create interest_accruals table (one row per wallet per accrued day)
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES interest_accounts(wallet_id),
+    accrual_date DATE NOT NULL,
+    plan VARCHAR(50) NOT NULL,
+    balance BIGINT NOT NULL,                     -- End-of-day balance the day's interest is on
+    amount NUMERIC(30, 12) NOT NULL,             -- The day's interest in minor units
+    accrued NUMERIC(30, 12) NOT NULL,            -- Uncapitalised interest after the day, before any capitalisation
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (wallet_id, accrual_date)
+);
+
+-- Create interest_capitalisations table (monthly postings of accrued interest)
+CREATE TABLE IF NOT EXISTS interest_capitalisations (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES interest_accounts(wallet_id),
+    period DATE NOT NULL,                        -- First day of the month capitalised
+    amount BIGINT NOT NULL,                      -- Whole minor units credited
+    carried NUMERIC(30, 12) NOT NULL,            -- Fraction carried into the next month
+    journal_id UUID NOT NULL,
+    ledger_entry_id BIGINT NOT NULL REFERENCES ledger_entries(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (wallet_id, period)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS interest_capitalisations;
+DROP TABLE IF EXISTS interest_accruals;
+DROP TABLE IF EXISTS interest_accounts;
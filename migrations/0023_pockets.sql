@@ -0,0 +1,41 @@
+-- +migrate Up
+-- Create pockets table (named sub-balances set aside inside a wallet's balance)
+CREATE TABLE IF NOT EXISTS pockets (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    wallet_id BIGINT NOT NULL REFERENCES wallets(id),
+    name VARCHAR(100) NOT NULL,
+    target_amount BIGINT,                 -- NULL has no savings target
+    target_date DATE,
+    balance BIGINT NOT NULL DEFAULT 0,    -- Part of wallets.balance set aside in the pocket
+    sweep JSONB,                          -- Auto-sweep rule; NULL sweeps nothing
+    status VARCHAR(20) NOT NULL,          -- 'open', 'closed'
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pockets_wallet ON pockets (wallet_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pockets_wallet_name ON pockets (wallet_id, LOWER(name)) WHERE status = 'open';
+
+-- Create pocket_movements table (internal transfers between a wallet's main balance and its pockets)
+CREATE TABLE IF NOT EXISTS pocket_movements (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    pocket_id BIGINT NOT NULL REFERENCES pockets(id),
+    type VARCHAR(20) NOT NULL,            -- 'deposit', 'withdrawal', 'sweep'
+    amount BIGINT NOT NULL,
+    balance BIGINT NOT NULL,              -- Pocket balance after the movement
+    journal_id UUID,                      -- The posting a sweep ran on
+    ledger_entry_id BIGINT REFERENCES ledger_entries(id),
+    description TEXT NOT NULL DEFAULT '',
+    created_by_type VARCHAR(20) NOT NULL,
+    created_by_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pocket_movements_pocket ON pocket_movements (pocket_id, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS pocket_movements;
+DROP TABLE IF EXISTS pockets;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Timed releases the ledger refuses are retried with a growing delay; the
+-- escrow keeps the number of failed attempts and the last error
+ALTER TABLE escrows ADD COLUMN IF NOT EXISTS release_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE escrows ADD COLUMN IF NOT EXISTS release_error TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE escrows DROP COLUMN IF EXISTS release_error;
+ALTER TABLE escrows DROP COLUMN IF EXISTS release_attempts;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Split payments can be held for screening review like transfers; the held
+-- request is kept whole since it has any number of destinations
+ALTER TABLE screening_reviews ADD COLUMN IF NOT EXISTS split JSONB; -- Split payments only
+
+-- +migrate Down
+ALTER TABLE screening_reviews DROP COLUMN IF EXISTS split;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Customer segment, e.g. 'personal' or 'merchant', that fee rules can match on
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS segment VARCHAR(30) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE wallets DROP COLUMN IF EXISTS segment;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Standing orders that fail with an unexpected error are retried with a
+-- growing delay instead of blocking the ones due after them
+ALTER TABLE transfer_schedules ADD COLUMN IF NOT EXISTS failures INT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE transfer_schedules DROP COLUMN IF EXISTS failures;
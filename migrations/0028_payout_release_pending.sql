@@ -0,0 +1,9 @@
+-- +migrate Up
+-- The payout worker also resumes submissions it was interrupted in and
+-- retries releases a frozen or closed wallet refused
+DROP INDEX IF EXISTS idx_payouts_submit_due;
+CREATE INDEX IF NOT EXISTS idx_payouts_due ON payouts (next_attempt_at) WHERE status IN ('requested', 'submitting', 'release_pending');
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_payouts_due;
+CREATE INDEX IF NOT EXISTS idx_payouts_submit_due ON payouts (next_attempt_at) WHERE status = 'requested';
@@ -0,0 +1,13 @@
+// Package migrations embeds the SQL schema migrations so the service binary
+// can apply them itself. Each file is named NNNN_description.sql and holds a
+// "-- +migrate Up" section followed by an optional "-- +migrate Down" section.
+// Files 0001 to 0007 predate the markers and are left untouched so databases
+// that already applied them keep matching checksums; they cannot be rolled back.
+package migrations
+
+import "embed"
+
+// FS holds every migration file
+//
+//go:embed *.sql
+var FS embed.FS